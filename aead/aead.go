@@ -24,6 +24,7 @@ import (
 	_ "github.com/tink-crypto/tink-go/v2/aead/aesgcm"                       // To register the AES-GCM key manager, parsers and serializers.
 	_ "github.com/tink-crypto/tink-go/v2/aead/aesgcmsiv"                 // To register the AES-GCM-SIV key manager, parsers and serializers.
 	_ "github.com/tink-crypto/tink-go/v2/aead/chacha20poly1305"   // To register the ChaCha20Poly1305 key manager, parsers and serializers.
+	_ "github.com/tink-crypto/tink-go/v2/aead/kmsenvelope"         // To register the KMS envelope AEAD parsers and serializers.
 	_ "github.com/tink-crypto/tink-go/v2/aead/xaesgcm"                     // To register the X-AES-GCM key manager, parsers and serializers.
 	_ "github.com/tink-crypto/tink-go/v2/aead/xchacha20poly1305" // To register the XChaCha20Poly1305 key manager.
 	"github.com/tink-crypto/tink-go/v2/core/registry"
@@ -37,6 +37,9 @@ const (
 	xChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key"
 	aesCTRHMACAEADTypeURL    = "type.googleapis.com/google.crypto.tink.AesCtrHmacAeadKey"
 	aesGCMSIVTypeURL         = "type.googleapis.com/google.crypto.tink.AesGcmSivKey"
+	// aesSIVTypeURL is only used as a KMS envelope DEK type; the AES-SIV key
+	// manager is registered by the daead package.
+	aesSIVTypeURL = "type.googleapis.com/google.crypto.tink.AesSivKey"
 )
 
 // This file contains pre-generated KeyTemplates for AEAD keys. One can use these templates
@@ -169,6 +172,7 @@ func XChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
 //   - ChaCha20Poly1305Key
 //   - XChaCha20Poly1305
 //   - AesGcmSivKey
+//   - AesSivKey (requires the daead package to be linked in)
 //
 // DEKs generated by this key template use the RAW output prefix to make them
 // compatible with remote KMS encrypt/decrypt operations.
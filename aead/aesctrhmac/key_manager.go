@@ -118,6 +118,10 @@ func (km *keyManager) DoesSupport(typeURL string) bool { return km.TypeURL() ==
 // TypeURL returns the key type of keys managed by this key manager.
 func (km *keyManager) TypeURL() string { return typeURL }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *keyManager) FIPSCompatible() bool { return true }
+
 // validateKey validates the given [aeadpb.AesCtrHmacAeadKey] proto.
 func (km *keyManager) validateKey(key *aeadpb.AesCtrHmacAeadKey) error {
 	if err := keyset.ValidateKeyVersion(key.GetVersion(), keyVersion); err != nil {
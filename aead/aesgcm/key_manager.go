@@ -113,6 +113,10 @@ func (km *keyManager) DoesSupport(typeURL string) bool { return typeURL == km.Ty
 // TypeURL returns the key type of keys managed by this key manager.
 func (km *keyManager) TypeURL() string { return typeURL }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *keyManager) FIPSCompatible() bool { return true }
+
 // KeyMaterialType returns the key material type of the key manager.
 func (km *keyManager) KeyMaterialType() tinkpb.KeyData_KeyMaterialType {
 	return tinkpb.KeyData_SYMMETRIC
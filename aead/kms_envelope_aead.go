@@ -46,6 +46,7 @@ var tinkAEADKeyTypes map[string]bool = map[string]bool{
 	chaCha20Poly1305TypeURL:  true,
 	xChaCha20Poly1305TypeURL: true,
 	aesGCMSIVTypeURL:         true,
+	aesSIVTypeURL:            true,
 }
 
 func isSupporedKMSEnvelopeDEK(dekKeyTypeURL string) bool {
@@ -53,11 +54,21 @@ func isSupporedKMSEnvelopeDEK(dekKeyTypeURL string) bool {
 	return found
 }
 
+// isDeterministicDEK returns true if DEKs of type dekKeyTypeURL encrypt
+// deterministically, so that they must not be reused.
+func isDeterministicDEK(dekKeyTypeURL string) bool {
+	return dekKeyTypeURL == aesSIVTypeURL
+}
+
 // KMSEnvelopeAEADWithContext represents an instance of KMS Envelope AEAD that implements
 // the [tink.AEADWithContext] interface.
 type KMSEnvelopeAEADWithContext struct {
 	dekTemplate *tinkpb.KeyTemplate
 	kekAEAD     tink.AEADWithContext
+	// dekCache is nil unless WithDEKCache is used.
+	dekCache *dekCache
+	// dekReuser is nil unless WithDEKReuse is used.
+	dekReuser *dekReuser
 }
 
 // NewKMSEnvelopeAEADWithContext creates an new instance of [KMSEnvelopeAEADWithContext].
@@ -69,17 +80,43 @@ type KMSEnvelopeAEADWithContext struct {
 //   - ChaCha20Poly1305Key
 //   - XChaCha20Poly1305
 //   - AesGcmSivKey
+//   - AesSivKey (requires the daead package to be linked in)
 //
 // keyEncryptionAEAD is used to encrypt the DEK, and is usually a remote AEAD
 // provided by a KMS.
-func NewKMSEnvelopeAEADWithContext(dekTemplate *tinkpb.KeyTemplate, keyEncryptionAEAD tink.AEADWithContext) (*KMSEnvelopeAEADWithContext, error) {
+//
+// By default, every encryption generates a new DEK and every decryption calls
+// keyEncryptionAEAD. Use [WithDEKCache] and [WithDEKReuse] to reduce the number
+// of calls to the KMS. [WithDEKReuse] can't be used with AesSivKey DEKs.
+func NewKMSEnvelopeAEADWithContext(dekTemplate *tinkpb.KeyTemplate, keyEncryptionAEAD tink.AEADWithContext, opts ...KMSEnvelopeOption) (*KMSEnvelopeAEADWithContext, error) {
 	if !isSupporedKMSEnvelopeDEK(dekTemplate.GetTypeUrl()) {
 		return nil, errors.New("unsupported DEK key type")
 	}
-	return &KMSEnvelopeAEADWithContext{
+	options := new(kmsEnvelopeOptions)
+	for _, opt := range opts {
+		if err := opt(options); err != nil {
+			return nil, fmt.Errorf("kms_envelope_aead: %v", err)
+		}
+	}
+	a := &KMSEnvelopeAEADWithContext{
 		dekTemplate: dekTemplate,
 		kekAEAD:     keyEncryptionAEAD,
-	}, nil
+	}
+	if options.dekCacheSize > 0 {
+		a.dekCache = newDEKCache(options.dekCacheSize, options.dekCacheTTL)
+	}
+	if options.dekReuseMessages > 0 || options.dekReuseDuration > 0 {
+		// A reused deterministic DEK would encrypt equal plaintexts to equal
+		// ciphertexts.
+		if isDeterministicDEK(dekTemplate.GetTypeUrl()) {
+			return nil, fmt.Errorf("kms_envelope_aead: DEK reuse is not supported for deterministic DEK key type %s", dekTemplate.GetTypeUrl())
+		}
+		a.dekReuser = &dekReuser{
+			maxMessages: options.dekReuseMessages,
+			maxAge:      options.dekReuseDuration,
+		}
+	}
+	return a, nil
 }
 
 // NewKMSEnvelopeAEAD2 creates an new instance of [KMSEnvelopeAEAD].
@@ -92,6 +129,7 @@ func NewKMSEnvelopeAEADWithContext(dekTemplate *tinkpb.KeyTemplate, keyEncryptio
 //   - ChaCha20Poly1305Key
 //   - XChaCha20Poly1305
 //   - AesGcmSivKey
+//   - AesSivKey (requires the daead package to be linked in)
 //
 // keyEncryptionAEAD is used to encrypt the DEK, and is usually a remote AEAD
 // provided by a KMS. It is preferable to use [NewKMSEnvelopeAEADWithContext] instead.
@@ -118,19 +156,47 @@ func newDEK(template *tinkpb.KeyTemplate) ([]byte, error) {
 	return dekKeyData.GetValue(), nil
 }
 
-func encryptDataAndSerializeEnvelope(dekTypeURL string, dek, encryptedDEK []byte, plaintext, associatedData []byte) ([]byte, error) {
-	if len(encryptedDEK) == 0 {
-		return nil, errors.New("encrypted dek is empty")
-	}
+// deterministicDEK adapts a deterministic AEAD DEK primitive, such as
+// AES-SIV, to [tink.AEAD]. Deterministic DEKs are never reused, see
+// NewKMSEnvelopeAEADWithContext, so every envelope ciphertext uses a fresh DEK
+// and deterministic encryption under the DEK does not leak plaintext equality.
+type deterministicDEK struct {
+	daead tink.DeterministicAEAD
+}
+
+func (d *deterministicDEK) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	return d.daead.EncryptDeterministically(plaintext, associatedData)
+}
+
+func (d *deterministicDEK) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	return d.daead.DecryptDeterministically(ciphertext, associatedData)
+}
+
+// dekPrimitive returns the AEAD primitive for the serialized DEK of type
+// dekTypeURL.
+func dekPrimitive(dekTypeURL string, dek []byte) (tink.AEAD, error) {
 	p, err := registry.Primitive(dekTypeURL, dek)
 	if err != nil {
 		return nil, err
 	}
-	dekAEAD, ok := p.(tink.AEAD)
-	if !ok {
+	switch dekPrimitive := p.(type) {
+	case tink.AEAD:
+		return dekPrimitive, nil
+	case tink.DeterministicAEAD:
+		return &deterministicDEK{daead: dekPrimitive}, nil
+	default:
 		return nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
 	}
+}
 
+func encryptDataAndSerializeEnvelope(dekTypeURL string, dek, encryptedDEK []byte, plaintext, associatedData []byte) ([]byte, error) {
+	if len(encryptedDEK) == 0 {
+		return nil, errors.New("encrypted dek is empty")
+	}
+	dekAEAD, err := dekPrimitive(dekTypeURL, dek)
+	if err != nil {
+		return nil, err
+	}
 	payload, err := dekAEAD.Encrypt(plaintext, associatedData)
 	if err != nil {
 		return nil, err
@@ -184,15 +250,10 @@ func parseEnvelope(ciphertext []byte) ([]byte, []byte, error) {
 
 func decryptDataWithDEK(dekTypeURL string, dek []byte, payload, associatedData []byte) ([]byte, error) {
 	// Get an AEAD primitive corresponding to the DEK.
-	p, err := registry.Primitive(dekTypeURL, dek)
+	dekAEAD, err := dekPrimitive(dekTypeURL, dek)
 	if err != nil {
 		return nil, fmt.Errorf("kms_envelope_aead: %s", err)
 	}
-	dekAEAD, ok := p.(tink.AEAD)
-	if !ok {
-		return nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
-	}
-
 	return dekAEAD.Decrypt(payload, associatedData)
 }
 
@@ -217,11 +278,27 @@ func (a *KMSEnvelopeAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, er
 
 // EncryptWithContext implements the [tink.AEADWithContext] interface for encryption.
 func (a *KMSEnvelopeAEADWithContext) EncryptWithContext(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
-	dek, err := newDEK(a.dekTemplate)
-	if err != nil {
-		return nil, err
+	newEncryptedDEK := func(ctx context.Context) ([]byte, []byte, error) {
+		dek, err := newDEK(a.dekTemplate)
+		if err != nil {
+			return nil, nil, err
+		}
+		encryptedDEK, err := a.kekAEAD.EncryptWithContext(ctx, dek, []byte{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if a.dekCache != nil {
+			a.dekCache.put(encryptedDEK, dek)
+		}
+		return dek, encryptedDEK, nil
+	}
+	var dek, encryptedDEK []byte
+	var err error
+	if a.dekReuser != nil {
+		dek, encryptedDEK, err = a.dekReuser.get(ctx, newEncryptedDEK)
+	} else {
+		dek, encryptedDEK, err = newEncryptedDEK(ctx)
 	}
-	encryptedDEK, err := a.kekAEAD.EncryptWithContext(ctx, dek, []byte{})
 	if err != nil {
 		return nil, err
 	}
@@ -235,10 +312,18 @@ func (a *KMSEnvelopeAEADWithContext) DecryptWithContext(ctx context.Context, cip
 		return nil, err
 	}
 
+	if a.dekCache != nil {
+		if dek, ok := a.dekCache.get(encryptedDEK); ok {
+			return decryptDataWithDEK(a.dekTemplate.GetTypeUrl(), dek, payload, associatedData)
+		}
+	}
 	dek, err := a.kekAEAD.DecryptWithContext(ctx, encryptedDEK, []byte{})
 	if err != nil {
 		return nil, err
 	}
+	if a.dekCache != nil {
+		a.dekCache.put(encryptedDEK, dek)
+	}
 
 	return decryptDataWithDEK(a.dekTemplate.GetTypeUrl(), dek, payload, associatedData)
 }
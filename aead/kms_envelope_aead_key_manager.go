@@ -59,6 +59,26 @@ func (km *kmsEnvelopeAEADKeyManager) Primitive(serializedKey []byte) (any, error
 	return NewKMSEnvelopeAEAD2(key.GetParams().GetDekTemplate(), backend), nil
 }
 
+// NewKMSEnvelopeAEADWithContextFromURI creates a new instance of
+// [KMSEnvelopeAEADWithContext] whose key encryption key is identified by
+// kekURI.
+//
+// The KEK is obtained from the registered KMS clients with
+// [registry.GetKMSAEADWithContext], so the context of each call is passed to
+// the KMS by clients that implement [registry.KMSClientWithContext].
+// dekTemplate and opts have the same meaning as in
+// [NewKMSEnvelopeAEADWithContext].
+func NewKMSEnvelopeAEADWithContextFromURI(dekTemplate *tinkpb.KeyTemplate, kekURI string, opts ...KMSEnvelopeOption) (*KMSEnvelopeAEADWithContext, error) {
+	if !isSupporedKMSEnvelopeDEK(dekTemplate.GetTypeUrl()) {
+		return nil, fmt.Errorf("kms_envelope_aead_key_manager: unsupported DEK key type %s", dekTemplate.GetTypeUrl())
+	}
+	backend, err := registry.GetKMSAEADWithContext(kekURI)
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_aead_key_manager: invalid aead backend: %v", err)
+	}
+	return NewKMSEnvelopeAEADWithContext(dekTemplate, backend, opts...)
+}
+
 // NewKey creates a new key according to specification the given serialized KMSEnvelopeAEADKeyFormat.
 func (km *kmsEnvelopeAEADKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
 	if len(serializedKeyFormat) == 0 {
@@ -16,6 +16,7 @@ package aead_test
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"google.golang.org/protobuf/proto"
@@ -178,3 +179,55 @@ func TestKMSEnvelopeAEADWithTinkPrefix(t *testing.T) {
 	}
 
 }
+
+func TestNewKMSEnvelopeAEADWithContextFromURI(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v", err)
+	}
+	client, err := fakekms.NewClient(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() err = %v", err)
+	}
+	registry.RegisterKMSClient(client)
+	defer registry.ClearKMSClients()
+
+	a, err := aead.NewKMSEnvelopeAEADWithContextFromURI(aead.AES128GCMKeyTemplate(), keyURI)
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContextFromURI() err = %v, want nil", err)
+	}
+	ctx := context.Background()
+	plaintext := []byte("plaintext")
+	associatedData := []byte("associatedData")
+	ciphertext, err := a.EncryptWithContext(ctx, plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("a.EncryptWithContext() err = %v, want nil", err)
+	}
+	decrypted, err := a.DecryptWithContext(ctx, ciphertext, associatedData)
+	if err != nil {
+		t.Fatalf("a.DecryptWithContext() err = %v, want nil", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("a.DecryptWithContext() = %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestNewKMSEnvelopeAEADWithContextFromURIFails(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v", err)
+	}
+	client, err := fakekms.NewClient(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() err = %v", err)
+	}
+	registry.RegisterKMSClient(client)
+	defer registry.ClearKMSClients()
+
+	if _, err := aead.NewKMSEnvelopeAEADWithContextFromURI(mac.HMACSHA256Tag256KeyTemplate(), keyURI); err == nil {
+		t.Error("aead.NewKMSEnvelopeAEADWithContextFromURI() with MAC DEK template err = nil, want error")
+	}
+	if _, err := aead.NewKMSEnvelopeAEADWithContextFromURI(aead.AES128GCMKeyTemplate(), "unknown-kms://key"); err == nil {
+		t.Error("aead.NewKMSEnvelopeAEADWithContextFromURI() with unknown URI err = nil, want error")
+	}
+}
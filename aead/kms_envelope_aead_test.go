@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/daead"
 	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
 	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
 )
@@ -73,6 +74,9 @@ func TestKMSEnvelopeWorksWithTinkKeyTemplatesAsDekTemplate(t *testing.T) {
 		}, {
 			name:        "XCHACHA20_POLY1305",
 			dekTemplate: aead.XChaCha20Poly1305KeyTemplate(),
+		}, {
+			name:        "AES256_SIV",
+			dekTemplate: daead.AESSIVKeyTemplate(),
 		},
 	}
 	for _, tc := range kmsEnvelopeAeadDekTestCases {
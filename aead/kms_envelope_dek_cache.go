@@ -0,0 +1,237 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aead
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+// kmsEnvelopeOptions holds the optional configuration of a
+// [KMSEnvelopeAEADWithContext].
+type kmsEnvelopeOptions struct {
+	dekCacheSize     int
+	dekCacheTTL      time.Duration
+	dekReuseMessages int
+	dekReuseDuration time.Duration
+}
+
+// KMSEnvelopeOption configures a [KMSEnvelopeAEADWithContext].
+type KMSEnvelopeOption func(*kmsEnvelopeOptions) error
+
+// WithDEKCache enables a cache of decrypted data encryption keys (DEKs), keyed
+// by the encrypted DEK found in the ciphertext.
+//
+// Ciphertexts sharing an encrypted DEK are then decrypted with at most one
+// call to the KEK per ttl. At most maxEntries DEKs are kept; the least recently
+// used one is evicted first. DEKs generated on encryption are also cached.
+//
+// Cached DEKs stay in memory until they expire or are evicted, and revoking
+// access to the KEK does not take effect for cached DEKs before they expire.
+func WithDEKCache(maxEntries int, ttl time.Duration) KMSEnvelopeOption {
+	return func(o *kmsEnvelopeOptions) error {
+		if maxEntries <= 0 {
+			return errors.New("DEK cache size must be positive")
+		}
+		if ttl <= 0 {
+			return errors.New("DEK cache TTL must be positive")
+		}
+		o.dekCacheSize = maxEntries
+		o.dekCacheTTL = ttl
+		return nil
+	}
+}
+
+// WithDEKReuse makes encryption reuse the same data encryption key (DEK) for
+// up to maxMessages messages or for up to maxAge, whichever limit is reached
+// first. A zero value disables the corresponding limit, but at least one of
+// them must be set.
+//
+// Reusing a DEK saves one call to the KEK per message, but all messages
+// encrypted with the same DEK share the DEK's usage limits. For example, AES-GCM
+// keys should not encrypt more than 2^32 messages.
+//
+// DEK reuse is rejected for deterministic DEK key types such as AesSivKey,
+// since equal messages would then have equal ciphertexts.
+func WithDEKReuse(maxMessages int, maxAge time.Duration) KMSEnvelopeOption {
+	return func(o *kmsEnvelopeOptions) error {
+		if maxMessages < 0 || maxAge < 0 {
+			return errors.New("DEK reuse limits must not be negative")
+		}
+		if maxMessages == 0 && maxAge == 0 {
+			return errors.New("at least one DEK reuse limit must be set")
+		}
+		o.dekReuseMessages = maxMessages
+		o.dekReuseDuration = maxAge
+		return nil
+	}
+}
+
+// dekCacheEntry is a decrypted DEK held by a dekCache.
+type dekCacheEntry struct {
+	encryptedDEK string
+	dek          secretdata.Bytes
+	expiry       time.Time
+}
+
+// dekCache is a bounded LRU cache of decrypted DEKs with a fixed TTL.
+type dekCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	lru     *list.List // Most recently used entries first.
+	entries map[string]*list.Element
+}
+
+func newDEKCache(maxSize int, ttl time.Duration) *dekCache {
+	return &dekCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the DEK cached for encryptedDEK, if present and not expired.
+func (c *dekCache) get(encryptedDEK []byte) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[string(encryptedDEK)]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*dekCacheEntry)
+	if !time.Now().Before(entry.expiry) {
+		c.lru.Remove(elem)
+		delete(c.entries, entry.encryptedDEK)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.dek.Data(insecuresecretdataaccess.Token{}), true
+}
+
+// put adds dek to the cache, evicting the least recently used entry if the
+// cache is full.
+func (c *dekCache) put(encryptedDEK, dek []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &dekCacheEntry{
+		encryptedDEK: string(encryptedDEK),
+		dek:          secretdata.NewBytesFromData(dek, insecuresecretdataaccess.Token{}),
+		expiry:       time.Now().Add(c.ttl),
+	}
+	if elem, ok := c.entries[entry.encryptedDEK]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	for c.lru.Len() >= c.maxSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*dekCacheEntry).encryptedDEK)
+	}
+	c.entries[entry.encryptedDEK] = c.lru.PushFront(entry)
+}
+
+// reusableDEK is a DEK used to encrypt several messages.
+type reusableDEK struct {
+	dek          secretdata.Bytes
+	encryptedDEK []byte
+	uses         int
+	created      time.Time
+}
+
+// dekCreation is an in-flight creation of a new DEK, shared by the callers of
+// dekReuser.get that need it.
+type dekCreation struct {
+	done chan struct{}
+}
+
+// dekReuser hands out the current DEK until one of its limits is reached.
+type dekReuser struct {
+	mu          sync.Mutex
+	maxMessages int
+	maxAge      time.Duration
+	current     *reusableDEK
+	creation    *dekCreation // Non-nil while a new DEK is being created.
+}
+
+// usable reports whether the current DEK can encrypt one more message.
+// r.mu must be held.
+func (r *dekReuser) usable() bool {
+	if r.current == nil {
+		return false
+	}
+	if r.maxMessages > 0 && r.current.uses >= r.maxMessages {
+		return false
+	}
+	if r.maxAge > 0 && time.Since(r.current.created) >= r.maxAge {
+		return false
+	}
+	return true
+}
+
+// get returns the current DEK and its encryption, creating a new one with
+// newDEK if the current one is exhausted.
+//
+// newDEK is called without holding the lock. Concurrent callers wait for the
+// DEK being created instead of calling the KEK themselves, but they stop
+// waiting when their own ctx is done. If creating the DEK fails, waiters try
+// again with their own ctx.
+func (r *dekReuser) get(ctx context.Context, newDEK func(ctx context.Context) (dek, encryptedDEK []byte, err error)) ([]byte, []byte, error) {
+	for {
+		r.mu.Lock()
+		if r.usable() {
+			r.current.uses++
+			dek, encryptedDEK := r.current.dek.Data(insecuresecretdataaccess.Token{}), r.current.encryptedDEK
+			r.mu.Unlock()
+			return dek, encryptedDEK, nil
+		}
+		if creation := r.creation; creation != nil {
+			r.mu.Unlock()
+			select {
+			case <-creation.done:
+				continue
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+		creation := &dekCreation{done: make(chan struct{})}
+		r.creation = creation
+		r.mu.Unlock()
+
+		dek, encryptedDEK, err := newDEK(ctx)
+
+		r.mu.Lock()
+		if err == nil {
+			r.current = &reusableDEK{
+				dek:          secretdata.NewBytesFromData(dek, insecuresecretdataaccess.Token{}),
+				encryptedDEK: encryptedDEK,
+				uses:         1,
+				created:      time.Now(),
+			}
+		}
+		r.creation = nil
+		close(creation.done)
+		r.mu.Unlock()
+		return dek, encryptedDEK, err
+	}
+}
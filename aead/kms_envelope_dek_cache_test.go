@@ -0,0 +1,296 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aead_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/daead"
+	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// countingKEK counts the calls made to the wrapped KEK.
+type countingKEK struct {
+	kek      tink.AEADWithContext
+	encrypts atomic.Int32
+	decrypts atomic.Int32
+}
+
+func (c *countingKEK) EncryptWithContext(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	c.encrypts.Add(1)
+	return c.kek.EncryptWithContext(ctx, plaintext, associatedData)
+}
+
+func (c *countingKEK) DecryptWithContext(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error) {
+	c.decrypts.Add(1)
+	return c.kek.DecryptWithContext(ctx, ciphertext, associatedData)
+}
+
+func newCountingKEK(t *testing.T) *countingKEK {
+	t.Helper()
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	kek, err := fakekms.NewAEADWithContext(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewAEADWithContext() err = %v, want nil", err)
+	}
+	return &countingKEK{kek: kek}
+}
+
+func mustEncrypt(t *testing.T, a *aead.KMSEnvelopeAEADWithContext, plaintext, associatedData []byte) []byte {
+	t.Helper()
+	ciphertext, err := a.EncryptWithContext(context.Background(), plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("a.EncryptWithContext() err = %v, want nil", err)
+	}
+	return ciphertext
+}
+
+func mustDecrypt(t *testing.T, a *aead.KMSEnvelopeAEADWithContext, ciphertext, associatedData, want []byte) {
+	t.Helper()
+	got, err := a.DecryptWithContext(context.Background(), ciphertext, associatedData)
+	if err != nil {
+		t.Fatalf("a.DecryptWithContext() err = %v, want nil", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("a.DecryptWithContext() = %q, want %q", got, want)
+	}
+}
+
+func TestKMSEnvelopeDEKCacheAvoidsKEKCalls(t *testing.T) {
+	kek := newCountingKEK(t)
+	plaintext := []byte("plaintext")
+	associatedData := []byte("associatedData")
+	encrypter, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek)
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	ciphertext := mustEncrypt(t, encrypter, plaintext, associatedData)
+
+	decrypter, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek, aead.WithDEKCache(10, time.Hour))
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	for i := 0; i < 5; i++ {
+		mustDecrypt(t, decrypter, ciphertext, associatedData, plaintext)
+	}
+	if got := kek.decrypts.Load(); got != 1 {
+		t.Errorf("KEK decryptions = %d, want 1", got)
+	}
+	// A wrong associated data must still fail when the DEK is cached.
+	if _, err := decrypter.DecryptWithContext(context.Background(), ciphertext, []byte("invalid")); err == nil {
+		t.Error("decrypter.DecryptWithContext() with invalid associated data err = nil, want error")
+	}
+}
+
+func TestKMSEnvelopeDEKCacheExpires(t *testing.T) {
+	kek := newCountingKEK(t)
+	plaintext := []byte("plaintext")
+	a, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek, aead.WithDEKCache(10, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	ciphertext := mustEncrypt(t, a, plaintext, nil)
+	// The DEK generated on encryption is cached.
+	mustDecrypt(t, a, ciphertext, nil, plaintext)
+	if got := kek.decrypts.Load(); got != 0 {
+		t.Errorf("KEK decryptions = %d, want 0", got)
+	}
+	time.Sleep(20 * time.Millisecond)
+	mustDecrypt(t, a, ciphertext, nil, plaintext)
+	if got := kek.decrypts.Load(); got != 1 {
+		t.Errorf("KEK decryptions after expiry = %d, want 1", got)
+	}
+}
+
+func TestKMSEnvelopeDEKCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	kek := newCountingKEK(t)
+	plaintext := []byte("plaintext")
+	encrypter, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek)
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	c1 := mustEncrypt(t, encrypter, plaintext, nil)
+	c2 := mustEncrypt(t, encrypter, plaintext, nil)
+	c3 := mustEncrypt(t, encrypter, plaintext, nil)
+
+	decrypter, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek, aead.WithDEKCache(2, time.Hour))
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	mustDecrypt(t, decrypter, c1, nil, plaintext)
+	mustDecrypt(t, decrypter, c2, nil, plaintext)
+	mustDecrypt(t, decrypter, c1, nil, plaintext) // c1 is now more recent than c2.
+	mustDecrypt(t, decrypter, c3, nil, plaintext) // Evicts c2.
+	if got := kek.decrypts.Load(); got != 3 {
+		t.Fatalf("KEK decryptions = %d, want 3", got)
+	}
+	mustDecrypt(t, decrypter, c1, nil, plaintext)
+	if got := kek.decrypts.Load(); got != 3 {
+		t.Errorf("KEK decryptions = %d, want 3", got)
+	}
+	mustDecrypt(t, decrypter, c2, nil, plaintext)
+	if got := kek.decrypts.Load(); got != 4 {
+		t.Errorf("KEK decryptions = %d, want 4", got)
+	}
+}
+
+func TestKMSEnvelopeDEKReuseByMessageCount(t *testing.T) {
+	kek := newCountingKEK(t)
+	plaintext := []byte("plaintext")
+	a, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek, aead.WithDEKReuse(3, 0))
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	var ciphertexts [][]byte
+	for i := 0; i < 7; i++ {
+		ciphertexts = append(ciphertexts, mustEncrypt(t, a, plaintext, nil))
+	}
+	if got := kek.encrypts.Load(); got != 3 {
+		t.Errorf("KEK encryptions = %d, want 3", got)
+	}
+	for _, ciphertext := range ciphertexts {
+		mustDecrypt(t, a, ciphertext, nil, plaintext)
+	}
+}
+
+func TestKMSEnvelopeDEKReuseByAge(t *testing.T) {
+	kek := newCountingKEK(t)
+	a, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek, aead.WithDEKReuse(0, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	mustEncrypt(t, a, []byte("plaintext"), nil)
+	mustEncrypt(t, a, []byte("plaintext"), nil)
+	if got := kek.encrypts.Load(); got != 1 {
+		t.Errorf("KEK encryptions = %d, want 1", got)
+	}
+	time.Sleep(20 * time.Millisecond)
+	mustEncrypt(t, a, []byte("plaintext"), nil)
+	if got := kek.encrypts.Load(); got != 2 {
+		t.Errorf("KEK encryptions after max age = %d, want 2", got)
+	}
+}
+
+// blockingKEK blocks encryptions until release is closed.
+type blockingKEK struct {
+	*countingKEK
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingKEK) EncryptWithContext(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	b.entered <- struct{}{}
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return b.countingKEK.EncryptWithContext(ctx, plaintext, associatedData)
+}
+
+func TestKMSEnvelopeDEKReuseWaitersUseTheirOwnContext(t *testing.T) {
+	kek := &blockingKEK{
+		countingKEK: newCountingKEK(t),
+		entered:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+	}
+	plaintext := []byte("plaintext")
+	a, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek, aead.WithDEKReuse(100, 0))
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	var wg sync.WaitGroup
+	ciphertexts := make([][]byte, 5)
+	errs := make([]error, 5)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ciphertexts[0], errs[0] = a.EncryptWithContext(context.Background(), plaintext, nil)
+	}()
+	<-kek.entered
+
+	// A caller waiting for the new DEK stops waiting when its context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := a.EncryptWithContext(ctx, plaintext, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("a.EncryptWithContext() err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	for i := 1; i < len(ciphertexts); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ciphertexts[i], errs[i] = a.EncryptWithContext(context.Background(), plaintext, nil)
+		}(i)
+	}
+	close(kek.release)
+	wg.Wait()
+	for i, ciphertext := range ciphertexts {
+		if errs[i] != nil {
+			t.Fatalf("a.EncryptWithContext() err = %v, want nil", errs[i])
+		}
+		mustDecrypt(t, a, ciphertext, nil, plaintext)
+	}
+	if got := kek.encrypts.Load(); got != 1 {
+		t.Errorf("KEK encryptions = %d, want 1", got)
+	}
+}
+
+func TestKMSEnvelopeInvalidOptionsFail(t *testing.T) {
+	kek := newCountingKEK(t)
+	for _, tc := range []struct {
+		name string
+		opt  aead.KMSEnvelopeOption
+	}{
+		{"zero cache size", aead.WithDEKCache(0, time.Hour)},
+		{"zero cache TTL", aead.WithDEKCache(10, 0)},
+		{"no reuse limit", aead.WithDEKReuse(0, 0)},
+		{"negative reuse count", aead.WithDEKReuse(-1, time.Hour)},
+		{"negative reuse age", aead.WithDEKReuse(10, -time.Hour)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek, tc.opt); err == nil {
+				t.Error("aead.NewKMSEnvelopeAEADWithContext() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestKMSEnvelopeDEKReuseWithDeterministicDEKFails(t *testing.T) {
+	kek := newCountingKEK(t)
+	if _, err := aead.NewKMSEnvelopeAEADWithContext(daead.AESSIVKeyTemplate(), kek, aead.WithDEKReuse(10, time.Hour)); err == nil {
+		t.Error("aead.NewKMSEnvelopeAEADWithContext() with AES-SIV DEK and DEK reuse err = nil, want error")
+	}
+	// Caching decrypted DEKs doesn't reuse them for encryption.
+	a, err := aead.NewKMSEnvelopeAEADWithContext(daead.AESSIVKeyTemplate(), kek, aead.WithDEKCache(10, time.Hour))
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() with AES-SIV DEK and DEK cache err = %v, want nil", err)
+	}
+	plaintext := []byte("plaintext")
+	if bytes.Equal(mustEncrypt(t, a, plaintext, nil), mustEncrypt(t, a, plaintext, nil)) {
+		t.Error("encrypting the same plaintext twice returned equal ciphertexts")
+	}
+}
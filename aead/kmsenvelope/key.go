@@ -0,0 +1,228 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmsenvelope
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/outputprefix"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+)
+
+// Variant is the prefix variant of KMS envelope AEAD keys.
+//
+// It describes how the prefix of the ciphertext is constructed. For KMS
+// envelope AEAD there are two options:
+//
+// * TINK: prepends '0x01<big endian key id>' to the ciphertext.
+// * NO_PREFIX: adds no prefix to the ciphertext.
+type Variant int
+
+const (
+	// VariantUnknown is the default and invalid value of Variant.
+	VariantUnknown Variant = iota
+	// VariantTink prefixes '0x01<big endian key id>' to the ciphertext.
+	VariantTink
+	// VariantNoPrefix adds no prefix to the ciphertext.
+	VariantNoPrefix
+)
+
+func (variant Variant) String() string {
+	switch variant {
+	case VariantTink:
+		return "TINK"
+	case VariantNoPrefix:
+		return "NO_PREFIX"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// calculateOutputPrefix calculates the output prefix from keyID.
+func calculateOutputPrefix(variant Variant, keyID uint32) ([]byte, error) {
+	switch variant {
+	case VariantTink:
+		return outputprefix.Tink(keyID), nil
+	case VariantNoPrefix:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid output prefix variant: %v", variant)
+	}
+}
+
+// supportedDEKTypeURLs are the key types that can be used as DEKs.
+var supportedDEKTypeURLs = map[string]bool{
+	"type.googleapis.com/google.crypto.tink.AesCtrHmacAeadKey":    true,
+	"type.googleapis.com/google.crypto.tink.AesGcmKey":            true,
+	"type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key":  true,
+	"type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key": true,
+	"type.googleapis.com/google.crypto.tink.AesGcmSivKey":         true,
+	"type.googleapis.com/google.crypto.tink.AesSivKey":            true,
+}
+
+// Parameters specifies a KMS envelope AEAD key.
+type Parameters struct {
+	kekURI        string
+	dekParameters key.Parameters
+	variant       Variant
+}
+
+var _ key.Parameters = (*Parameters)(nil)
+
+// KEKURI returns the URI of the key encryption key.
+func (p *Parameters) KEKURI() string { return p.kekURI }
+
+// DEKParameters returns the parameters of the data encryption keys.
+func (p *Parameters) DEKParameters() key.Parameters { return p.dekParameters }
+
+// Variant returns the variant of the key.
+func (p *Parameters) Variant() Variant { return p.variant }
+
+// ParametersOpts specifies options for creating KMS envelope AEAD parameters.
+type ParametersOpts struct {
+	// KEKURI is the URI of the key encryption key in the remote KMS.
+	KEKURI string
+	// DEKParameters are the parameters of the data encryption keys, for
+	// example *aesgcmsiv.Parameters. The output prefix of DEKs is ignored;
+	// the DEK ciphertext is never prefixed.
+	DEKParameters key.Parameters
+	Variant       Variant
+}
+
+func validateOpts(opts *ParametersOpts) error {
+	if opts.KEKURI == "" {
+		return fmt.Errorf("KEK URI is empty")
+	}
+	if opts.DEKParameters == nil {
+		return fmt.Errorf("DEK parameters are nil")
+	}
+	dekTemplate, err := protoserialization.SerializeParameters(opts.DEKParameters)
+	if err != nil {
+		return err
+	}
+	if !supportedDEKTypeURLs[dekTemplate.GetTypeUrl()] {
+		return fmt.Errorf("unsupported DEK key type %s", dekTemplate.GetTypeUrl())
+	}
+	if opts.Variant != VariantTink && opts.Variant != VariantNoPrefix {
+		return fmt.Errorf("unsupported variant: %v", opts.Variant)
+	}
+	return nil
+}
+
+// NewParameters creates a new KMS envelope AEAD Parameters object.
+func NewParameters(opts ParametersOpts) (*Parameters, error) {
+	if err := validateOpts(&opts); err != nil {
+		return nil, fmt.Errorf("kmsenvelope.NewParameters: %v", err)
+	}
+	return &Parameters{
+		kekURI:        opts.KEKURI,
+		dekParameters: opts.DEKParameters,
+		variant:       opts.Variant,
+	}, nil
+}
+
+// HasIDRequirement returns whether the key has an ID requirement.
+func (p *Parameters) HasIDRequirement() bool { return p.variant != VariantNoPrefix }
+
+// Equal returns whether this Parameters object is equal to other.
+func (p *Parameters) Equal(other key.Parameters) bool {
+	actualParams, ok := other.(*Parameters)
+	return ok && p.kekURI == actualParams.kekURI &&
+		p.dekParameters.Equal(actualParams.dekParameters) &&
+		p.variant == actualParams.variant
+}
+
+// Key represents a KMS envelope AEAD key.
+//
+// The key only references the KEK by its URI and does not contain any
+// secret key material.
+type Key struct {
+	// idRequirement is the ID requirement to be included in the output of the
+	// AEAD function. If the key is in a keyset and the key has an ID
+	// requirement, this matches the keyset key ID.
+	idRequirement uint32
+	outputPrefix  []byte
+	parameters    *Parameters
+}
+
+var _ key.Key = (*Key)(nil)
+
+// NewKey creates a new KMS envelope AEAD key with idRequirement and
+// parameters.
+//
+// If parameters.HasIDRequirement() == false, idRequirement must be zero.
+func NewKey(idRequirement uint32, parameters *Parameters) (*Key, error) {
+	if parameters == nil {
+		return nil, fmt.Errorf("kmsenvelope.NewKey: parameters is nil")
+	}
+	opts := &ParametersOpts{
+		KEKURI:        parameters.KEKURI(),
+		DEKParameters: parameters.DEKParameters(),
+		Variant:       parameters.Variant(),
+	}
+	if err := validateOpts(opts); err != nil {
+		return nil, fmt.Errorf("kmsenvelope.NewKey: %v", err)
+	}
+	if !parameters.HasIDRequirement() && idRequirement != 0 {
+		return nil, fmt.Errorf("kmsenvelope.NewKey: idRequirement = %v and parameters.HasIDRequirement() = false, want 0", idRequirement)
+	}
+	outputPrefix, err := calculateOutputPrefix(parameters.Variant(), idRequirement)
+	if err != nil {
+		return nil, fmt.Errorf("kmsenvelope.NewKey: %v", err)
+	}
+	return &Key{
+		idRequirement: idRequirement,
+		outputPrefix:  outputPrefix,
+		parameters:    parameters,
+	}, nil
+}
+
+// Parameters returns the parameters of this key.
+func (k *Key) Parameters() key.Parameters { return k.parameters }
+
+// IDRequirement returns required to indicate if this key requires an
+// identifier. If it does, id will contain that identifier.
+func (k *Key) IDRequirement() (uint32, bool) {
+	return k.idRequirement, k.Parameters().HasIDRequirement()
+}
+
+// OutputPrefix returns the output prefix.
+func (k *Key) OutputPrefix() []byte { return bytes.Clone(k.outputPrefix) }
+
+// Equal returns whether this key object is equal to other.
+func (k *Key) Equal(other key.Key) bool {
+	that, ok := other.(*Key)
+	return ok && k.Parameters().Equal(that.Parameters()) &&
+		k.idRequirement == that.idRequirement &&
+		bytes.Equal(k.outputPrefix, that.outputPrefix)
+}
+
+func createKey(p key.Parameters, idRequirement uint32) (key.Key, error) {
+	kmsEnvelopeParams, ok := p.(*Parameters)
+	if !ok {
+		return nil, fmt.Errorf("key is of type %T; needed *kmsenvelope.Parameters", p)
+	}
+	return NewKey(idRequirement, kmsEnvelopeParams)
+}
+
+// KeyCreator returns a key creator function.
+//
+// It is *NOT* part of the public API.
+func KeyCreator(t internalapi.Token) func(p key.Parameters, idRequirement uint32) (key.Key, error) {
+	return createKey
+}
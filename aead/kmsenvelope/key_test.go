@@ -0,0 +1,323 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmsenvelope_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/aead/aesgcm"
+	"github.com/tink-crypto/tink-go/v2/aead/aesgcmsiv"
+	"github.com/tink-crypto/tink-go/v2/aead/kmsenvelope"
+	"github.com/tink-crypto/tink-go/v2/core/cryptofmt"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/kwp"
+	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
+)
+
+const kekURI = "fake-kms://CM2b3_MDElQKSAowdHlwZS5nb29nbGVhcGlzLmNvbS9nb29nbGUuY3J5cHRvLnRpbmsuQWVzR2NtS2V5EhIaEIK75t5L-adlUwVhWvRuWUwYARABGM2b3_MDIAE"
+
+func mustCreateAESGCMSIVParameters(t *testing.T, variant aesgcmsiv.Variant) *aesgcmsiv.Parameters {
+	t.Helper()
+	params, err := aesgcmsiv.NewParameters(32, variant)
+	if err != nil {
+		t.Fatalf("aesgcmsiv.NewParameters() err = %v, want nil", err)
+	}
+	return params
+}
+
+func mustCreateParameters(t *testing.T, opts kmsenvelope.ParametersOpts) *kmsenvelope.Parameters {
+	t.Helper()
+	params, err := kmsenvelope.NewParameters(opts)
+	if err != nil {
+		t.Fatalf("kmsenvelope.NewParameters(%v) err = %v, want nil", opts, err)
+	}
+	return params
+}
+
+func TestNewParametersFails(t *testing.T) {
+	kwpParams, err := kwp.NewParameters(32)
+	if err != nil {
+		t.Fatalf("kwp.NewParameters() err = %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		name string
+		opts kmsenvelope.ParametersOpts
+	}{
+		{
+			name: "empty KEK URI",
+			opts: kmsenvelope.ParametersOpts{
+				DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+				Variant:       kmsenvelope.VariantTink,
+			},
+		},
+		{
+			name: "nil DEK parameters",
+			opts: kmsenvelope.ParametersOpts{
+				KEKURI:  kekURI,
+				Variant: kmsenvelope.VariantTink,
+			},
+		},
+		{
+			name: "non-AEAD DEK parameters",
+			opts: kmsenvelope.ParametersOpts{
+				KEKURI:        kekURI,
+				DEKParameters: kwpParams,
+				Variant:       kmsenvelope.VariantTink,
+			},
+		},
+		{
+			name: "unknown variant",
+			opts: kmsenvelope.ParametersOpts{
+				KEKURI:        kekURI,
+				DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+				Variant:       kmsenvelope.VariantUnknown,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := kmsenvelope.NewParameters(tc.opts); err == nil {
+				t.Errorf("kmsenvelope.NewParameters(%v) err = nil, want error", tc.opts)
+			}
+		})
+	}
+}
+
+func TestParametersValues(t *testing.T) {
+	dekParams := mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix)
+	params := mustCreateParameters(t, kmsenvelope.ParametersOpts{
+		KEKURI:        kekURI,
+		DEKParameters: dekParams,
+		Variant:       kmsenvelope.VariantTink,
+	})
+	if got, want := params.KEKURI(), kekURI; got != want {
+		t.Errorf("params.KEKURI() = %q, want %q", got, want)
+	}
+	if !params.DEKParameters().Equal(dekParams) {
+		t.Errorf("params.DEKParameters() = %v, want %v", params.DEKParameters(), dekParams)
+	}
+	if got, want := params.Variant(), kmsenvelope.VariantTink; got != want {
+		t.Errorf("params.Variant() = %v, want %v", got, want)
+	}
+	if !params.HasIDRequirement() {
+		t.Errorf("params.HasIDRequirement() = false, want true")
+	}
+}
+
+func TestParametersEqual(t *testing.T) {
+	params := mustCreateParameters(t, kmsenvelope.ParametersOpts{
+		KEKURI:        kekURI,
+		DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+		Variant:       kmsenvelope.VariantTink,
+	})
+	same := mustCreateParameters(t, kmsenvelope.ParametersOpts{
+		KEKURI:        kekURI,
+		DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+		Variant:       kmsenvelope.VariantTink,
+	})
+	if !params.Equal(same) {
+		t.Errorf("params.Equal(same) = false, want true")
+	}
+	aesGCMParams, err := aesgcm.NewParameters(aesgcm.ParametersOpts{
+		KeySizeInBytes: 32,
+		IVSizeInBytes:  12,
+		TagSizeInBytes: 16,
+		Variant:        aesgcm.VariantNoPrefix,
+	})
+	if err != nil {
+		t.Fatalf("aesgcm.NewParameters() err = %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		name string
+		opts kmsenvelope.ParametersOpts
+	}{
+		{
+			name: "different KEK URI",
+			opts: kmsenvelope.ParametersOpts{
+				KEKURI:        "fake-kms://other",
+				DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+				Variant:       kmsenvelope.VariantTink,
+			},
+		},
+		{
+			name: "different DEK parameters",
+			opts: kmsenvelope.ParametersOpts{
+				KEKURI:        kekURI,
+				DEKParameters: aesGCMParams,
+				Variant:       kmsenvelope.VariantTink,
+			},
+		},
+		{
+			name: "different variant",
+			opts: kmsenvelope.ParametersOpts{
+				KEKURI:        kekURI,
+				DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+				Variant:       kmsenvelope.VariantNoPrefix,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			other := mustCreateParameters(t, tc.opts)
+			if params.Equal(other) {
+				t.Errorf("params.Equal(other) = true, want false")
+			}
+		})
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		variant       kmsenvelope.Variant
+		idRequirement uint32
+		wantPrefix    []byte
+	}{
+		{
+			name:          "TINK",
+			variant:       kmsenvelope.VariantTink,
+			idRequirement: 0x01020304,
+			wantPrefix:    []byte{cryptofmt.TinkStartByte, 0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			name:       "NO_PREFIX",
+			variant:    kmsenvelope.VariantNoPrefix,
+			wantPrefix: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params := mustCreateParameters(t, kmsenvelope.ParametersOpts{
+				KEKURI:        kekURI,
+				DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+				Variant:       tc.variant,
+			})
+			key, err := kmsenvelope.NewKey(tc.idRequirement, params)
+			if err != nil {
+				t.Fatalf("kmsenvelope.NewKey() err = %v, want nil", err)
+			}
+			if !key.Parameters().Equal(params) {
+				t.Errorf("key.Parameters() = %v, want %v", key.Parameters(), params)
+			}
+			if got := key.OutputPrefix(); !bytes.Equal(got, tc.wantPrefix) {
+				t.Errorf("key.OutputPrefix() = %x, want %x", got, tc.wantPrefix)
+			}
+			id, required := key.IDRequirement()
+			if id != tc.idRequirement || required != params.HasIDRequirement() {
+				t.Errorf("key.IDRequirement() = (%v, %v), want (%v, %v)", id, required, tc.idRequirement, params.HasIDRequirement())
+			}
+			otherKey, err := kmsenvelope.NewKey(tc.idRequirement, params)
+			if err != nil {
+				t.Fatalf("kmsenvelope.NewKey() err = %v, want nil", err)
+			}
+			if !key.Equal(otherKey) {
+				t.Errorf("key.Equal(otherKey) = false, want true")
+			}
+		})
+	}
+}
+
+func TestNewKeyFails(t *testing.T) {
+	if _, err := kmsenvelope.NewKey(0, nil); err == nil {
+		t.Errorf("kmsenvelope.NewKey(0, nil) err = nil, want error")
+	}
+	params := mustCreateParameters(t, kmsenvelope.ParametersOpts{
+		KEKURI:        kekURI,
+		DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+		Variant:       kmsenvelope.VariantNoPrefix,
+	})
+	if _, err := kmsenvelope.NewKey(123, params); err == nil {
+		t.Errorf("kmsenvelope.NewKey(123, params) err = nil, want error")
+	}
+}
+
+func TestKeyCreator(t *testing.T) {
+	params := mustCreateParameters(t, kmsenvelope.ParametersOpts{
+		KEKURI:        kekURI,
+		DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+		Variant:       kmsenvelope.VariantTink,
+	})
+	key, err := kmsenvelope.KeyCreator(internalapi.Token{})(params, 123)
+	if err != nil {
+		t.Fatalf("kmsenvelope.KeyCreator(params, 123) err = %v, want nil", err)
+	}
+	want, err := kmsenvelope.NewKey(123, params)
+	if err != nil {
+		t.Fatalf("kmsenvelope.NewKey(123, params) err = %v, want nil", err)
+	}
+	if !key.Equal(want) {
+		t.Errorf("key.Equal(want) = false, want true")
+	}
+}
+
+func TestAddNewKeyFromParametersEncryptDecrypt(t *testing.T) {
+	client, err := fakekms.NewClient(kekURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() err = %v, want nil", err)
+	}
+	registry.RegisterKMSClient(client)
+	defer registry.ClearKMSClients()
+
+	for _, variant := range []kmsenvelope.Variant{kmsenvelope.VariantTink, kmsenvelope.VariantNoPrefix} {
+		t.Run(variant.String(), func(t *testing.T) {
+			params := mustCreateParameters(t, kmsenvelope.ParametersOpts{
+				KEKURI:        kekURI,
+				DEKParameters: mustCreateAESGCMSIVParameters(t, aesgcmsiv.VariantNoPrefix),
+				Variant:       variant,
+			})
+			km := keyset.NewManager()
+			keyID, err := km.AddNewKeyFromParameters(params)
+			if err != nil {
+				t.Fatalf("km.AddNewKeyFromParameters() err = %v, want nil", err)
+			}
+			if err := km.SetPrimary(keyID); err != nil {
+				t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+			}
+			handle, err := km.Handle()
+			if err != nil {
+				t.Fatalf("km.Handle() err = %v, want nil", err)
+			}
+			entry, err := handle.Primary()
+			if err != nil {
+				t.Fatalf("handle.Primary() err = %v, want nil", err)
+			}
+			if !entry.Key().Parameters().Equal(params) {
+				t.Errorf("entry.Key().Parameters() = %v, want %v", entry.Key().Parameters(), params)
+			}
+
+			a, err := aead.New(handle)
+			if err != nil {
+				t.Fatalf("aead.New() err = %v, want nil", err)
+			}
+			plaintext := []byte("plaintext")
+			associatedData := []byte("associatedData")
+			ciphertext, err := a.Encrypt(plaintext, associatedData)
+			if err != nil {
+				t.Fatalf("a.Encrypt() err = %v, want nil", err)
+			}
+			if got, want := ciphertext[:len(entry.Key().(*kmsenvelope.Key).OutputPrefix())], entry.Key().(*kmsenvelope.Key).OutputPrefix(); !bytes.Equal(got, want) {
+				t.Errorf("ciphertext prefix = %x, want %x", got, want)
+			}
+			decrypted, err := a.Decrypt(ciphertext, associatedData)
+			if err != nil {
+				t.Fatalf("a.Decrypt() err = %v, want nil", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("a.Decrypt() = %q, want %q", decrypted, plaintext)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kmsenvelope implements KMS envelope AEAD parameters and keys.
+//
+// A KMS envelope AEAD key is a reference to a key encryption key (KEK) in a
+// remote key management service, together with the parameters of the data
+// encryption keys (DEKs) generated for each ciphertext. The key itself holds
+// no secret key material.
+//
+// The primitive of these keys is provided by the aead package, which must be
+// linked in together with a KMS client for the KEK URI.
+package kmsenvelope
+
+import (
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+)
+
+func init() {
+	if err := protoserialization.RegisterKeySerializer[*Key](&keySerializer{}); err != nil {
+		panic(fmt.Sprintf("kmsenvelope.init() failed: %v", err))
+	}
+	if err := protoserialization.RegisterKeyParser(typeURL, &keyParser{}); err != nil {
+		panic(fmt.Sprintf("kmsenvelope.init() failed: %v", err))
+	}
+	if err := protoserialization.RegisterParametersSerializer[*Parameters](&parametersSerializer{}); err != nil {
+		panic(fmt.Sprintf("kmsenvelope.init() failed: %v", err))
+	}
+	if err := protoserialization.RegisterParametersParser(typeURL, &parametersParser{}); err != nil {
+		panic(fmt.Sprintf("kmsenvelope.init() failed: %v", err))
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmsenvelope
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	kmsepb "github.com/tink-crypto/tink-go/v2/proto/kms_envelope_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const (
+	// protoVersion is the accepted [kmsepb.KmsEnvelopeAeadKey] proto version.
+	//
+	// Currently, only version 0 is supported; other versions are rejected.
+	protoVersion = 0
+	typeURL      = "type.googleapis.com/google.crypto.tink.KmsEnvelopeAeadKey"
+)
+
+func protoOutputPrefixTypeFromVariant(variant Variant) (tinkpb.OutputPrefixType, error) {
+	switch variant {
+	case VariantTink:
+		return tinkpb.OutputPrefixType_TINK, nil
+	case VariantNoPrefix:
+		return tinkpb.OutputPrefixType_RAW, nil
+	default:
+		return tinkpb.OutputPrefixType_UNKNOWN_PREFIX, fmt.Errorf("unknown output prefix variant: %v", variant)
+	}
+}
+
+func variantFromProto(prefixType tinkpb.OutputPrefixType) (Variant, error) {
+	switch prefixType {
+	case tinkpb.OutputPrefixType_TINK:
+		return VariantTink, nil
+	case tinkpb.OutputPrefixType_RAW:
+		return VariantNoPrefix, nil
+	default:
+		return VariantUnknown, fmt.Errorf("unsupported output prefix type: %v", prefixType)
+	}
+}
+
+func keyFormatFromParameters(params *Parameters) (*kmsepb.KmsEnvelopeAeadKeyFormat, error) {
+	dekTemplate, err := protoserialization.SerializeParameters(params.DEKParameters())
+	if err != nil {
+		return nil, err
+	}
+	return &kmsepb.KmsEnvelopeAeadKeyFormat{
+		KekUri:      params.KEKURI(),
+		DekTemplate: dekTemplate,
+	}, nil
+}
+
+func parametersFromKeyFormat(format *kmsepb.KmsEnvelopeAeadKeyFormat, prefixType tinkpb.OutputPrefixType) (*Parameters, error) {
+	variant, err := variantFromProto(prefixType)
+	if err != nil {
+		return nil, err
+	}
+	dekParameters, err := protoserialization.ParseParameters(format.GetDekTemplate())
+	if err != nil {
+		return nil, err
+	}
+	return NewParameters(ParametersOpts{
+		KEKURI:        format.GetKekUri(),
+		DEKParameters: dekParameters,
+		Variant:       variant,
+	})
+}
+
+type keySerializer struct{}
+
+var _ protoserialization.KeySerializer = (*keySerializer)(nil)
+
+func (s *keySerializer) SerializeKey(key key.Key) (*protoserialization.KeySerialization, error) {
+	actualKey, ok := key.(*Key)
+	if !ok || actualKey == nil {
+		return nil, fmt.Errorf("invalid key type: got %T, want *kmsenvelope.Key", key)
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromVariant(actualKey.parameters.Variant())
+	if err != nil {
+		return nil, err
+	}
+	format, err := keyFormatFromParameters(actualKey.parameters)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(&kmsepb.KmsEnvelopeAeadKey{
+		Version: protoVersion,
+		Params:  format,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// idRequirement is zero if the key doesn't have a key requirement.
+	idRequirement, _ := actualKey.IDRequirement()
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_REMOTE,
+	}
+	return protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+}
+
+type keyParser struct{}
+
+var _ protoserialization.KeyParser = (*keyParser)(nil)
+
+// ParseKey parses a KmsEnvelopeAeadKey.
+//
+// Keys that are valid for the KMS envelope AEAD key manager but cannot be
+// represented as a [Key], for example because their DEK template has no
+// registered parameters parser or because they use the CRUNCHY or LEGACY
+// output prefix, are returned as a [protoserialization.FallbackProtoKey] so
+// that existing keysets keep working.
+func (s *keyParser) ParseKey(keySerialization *protoserialization.KeySerialization) (key.Key, error) {
+	if keySerialization == nil {
+		return nil, fmt.Errorf("key serialization is nil")
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetTypeUrl() != typeURL {
+		return nil, fmt.Errorf("invalid type URL: got %v, want %v", keyData.GetTypeUrl(), typeURL)
+	}
+	if keyData.GetKeyMaterialType() != tinkpb.KeyData_REMOTE {
+		return nil, fmt.Errorf("invalid key material type: got %v, want %v", keyData.GetKeyMaterialType(), tinkpb.KeyData_REMOTE)
+	}
+	protoKey := new(kmsepb.KmsEnvelopeAeadKey)
+	if err := proto.Unmarshal(keyData.GetValue(), protoKey); err != nil {
+		return nil, err
+	}
+	if protoKey.GetVersion() != protoVersion {
+		return nil, fmt.Errorf("unsupported version: got %v, want %v", protoKey.GetVersion(), protoVersion)
+	}
+	params, err := parametersFromKeyFormat(protoKey.GetParams(), keySerialization.OutputPrefixType())
+	if err != nil {
+		return protoserialization.NewFallbackProtoKey(keySerialization), nil
+	}
+	// keySerialization.IDRequirement() returns zero if the key doesn't have a
+	// key requirement.
+	keyID, _ := keySerialization.IDRequirement()
+	return NewKey(keyID, params)
+}
+
+type parametersSerializer struct{}
+
+var _ protoserialization.ParametersSerializer = (*parametersSerializer)(nil)
+
+func (s *parametersSerializer) Serialize(parameters key.Parameters) (*tinkpb.KeyTemplate, error) {
+	actualParameters, ok := parameters.(*Parameters)
+	if !ok || actualParameters == nil {
+		return nil, fmt.Errorf("invalid parameters type: got %T, want *kmsenvelope.Parameters", parameters)
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromVariant(actualParameters.Variant())
+	if err != nil {
+		return nil, err
+	}
+	format, err := keyFormatFromParameters(actualParameters)
+	if err != nil {
+		return nil, err
+	}
+	serializedFormat, err := proto.Marshal(format)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          typeURL,
+		OutputPrefixType: outputPrefixType,
+		Value:            serializedFormat,
+	}, nil
+}
+
+type parametersParser struct{}
+
+var _ protoserialization.ParametersParser = (*parametersParser)(nil)
+
+func (s *parametersParser) Parse(keyTemplate *tinkpb.KeyTemplate) (key.Parameters, error) {
+	if keyTemplate.GetTypeUrl() != typeURL {
+		return nil, fmt.Errorf("invalid type URL: got %q, want %q", keyTemplate.GetTypeUrl(), typeURL)
+	}
+	format := new(kmsepb.KmsEnvelopeAeadKeyFormat)
+	if err := proto.Unmarshal(keyTemplate.GetValue(), format); err != nil {
+		return nil, err
+	}
+	return parametersFromKeyFormat(format, keyTemplate.GetOutputPrefixType())
+}
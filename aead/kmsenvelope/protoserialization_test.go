@@ -0,0 +1,329 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kmsenvelope
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"github.com/tink-crypto/tink-go/v2/aead/aesgcmsiv"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	aesgcmsivpb "github.com/tink-crypto/tink-go/v2/proto/aes_gcm_siv_go_proto"
+	kmsepb "github.com/tink-crypto/tink-go/v2/proto/kms_envelope_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const testKEKURI = "fake-kms://some-key"
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	t.Helper()
+	serialized, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	return serialized
+}
+
+func mustCreateKeySerialization(t *testing.T, keyData *tinkpb.KeyData, outputPrefixType tinkpb.OutputPrefixType, idRequirement uint32) *protoserialization.KeySerialization {
+	t.Helper()
+	ks, err := protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+	if err != nil {
+		t.Fatalf("protoserialization.NewKeySerialization() err = %v, want nil", err)
+	}
+	return ks
+}
+
+func aesGCMSIVDEKTemplate(t *testing.T) *tinkpb.KeyTemplate {
+	t.Helper()
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          "type.googleapis.com/google.crypto.tink.AesGcmSivKey",
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		Value:            mustMarshal(t, &aesgcmsivpb.AesGcmSivKeyFormat{KeySize: 16}),
+	}
+}
+
+func testParameters(t *testing.T, variant Variant) *Parameters {
+	t.Helper()
+	dekParams, err := aesgcmsiv.NewParameters(16, aesgcmsiv.VariantNoPrefix)
+	if err != nil {
+		t.Fatalf("aesgcmsiv.NewParameters() err = %v, want nil", err)
+	}
+	params, err := NewParameters(ParametersOpts{
+		KEKURI:        testKEKURI,
+		DEKParameters: dekParams,
+		Variant:       variant,
+	})
+	if err != nil {
+		t.Fatalf("NewParameters() err = %v, want nil", err)
+	}
+	return params
+}
+
+func TestSerializeParseKey(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		variant          Variant
+		idRequirement    uint32
+		outputPrefixType tinkpb.OutputPrefixType
+	}{
+		{
+			name:             "TINK",
+			variant:          VariantTink,
+			idRequirement:    0x11223344,
+			outputPrefixType: tinkpb.OutputPrefixType_TINK,
+		},
+		{
+			name:             "NO_PREFIX",
+			variant:          VariantNoPrefix,
+			outputPrefixType: tinkpb.OutputPrefixType_RAW,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := NewKey(tc.idRequirement, testParameters(t, tc.variant))
+			if err != nil {
+				t.Fatalf("NewKey() err = %v, want nil", err)
+			}
+			keySerialization, err := protoserialization.SerializeKey(key)
+			if err != nil {
+				t.Fatalf("protoserialization.SerializeKey() err = %v, want nil", err)
+			}
+			if got, want := keySerialization.OutputPrefixType(), tc.outputPrefixType; got != want {
+				t.Errorf("keySerialization.OutputPrefixType() = %v, want %v", got, want)
+			}
+			if got, want := keySerialization.KeyData().GetKeyMaterialType(), tinkpb.KeyData_REMOTE; got != want {
+				t.Errorf("keySerialization.KeyData().GetKeyMaterialType() = %v, want %v", got, want)
+			}
+			protoKey := new(kmsepb.KmsEnvelopeAeadKey)
+			if err := proto.Unmarshal(keySerialization.KeyData().GetValue(), protoKey); err != nil {
+				t.Fatalf("proto.Unmarshal() err = %v, want nil", err)
+			}
+			if got, want := protoKey.GetParams().GetKekUri(), testKEKURI; got != want {
+				t.Errorf("protoKey.GetParams().GetKekUri() = %q, want %q", got, want)
+			}
+			dekTemplate, err := protoserialization.SerializeParameters(key.parameters.DEKParameters())
+			if err != nil {
+				t.Fatalf("protoserialization.SerializeParameters() err = %v, want nil", err)
+			}
+			if diff := cmp.Diff(dekTemplate, protoKey.GetParams().GetDekTemplate(), protocmp.Transform()); diff != "" {
+				t.Errorf("DEK template diff (-want +got):\n%s", diff)
+			}
+
+			parsedKey, err := protoserialization.ParseKey(keySerialization)
+			if err != nil {
+				t.Fatalf("protoserialization.ParseKey() err = %v, want nil", err)
+			}
+			if !parsedKey.Equal(key) {
+				t.Errorf("parsedKey.Equal(key) = false, want true")
+			}
+		})
+	}
+}
+
+func TestParseKeyWithRawDEKTemplate(t *testing.T) {
+	keyData := &tinkpb.KeyData{
+		TypeUrl: typeURL,
+		Value: mustMarshal(t, &kmsepb.KmsEnvelopeAeadKey{
+			Version: protoVersion,
+			Params: &kmsepb.KmsEnvelopeAeadKeyFormat{
+				KekUri:      testKEKURI,
+				DekTemplate: aesGCMSIVDEKTemplate(t),
+			},
+		}),
+		KeyMaterialType: tinkpb.KeyData_REMOTE,
+	}
+	ks := mustCreateKeySerialization(t, keyData, tinkpb.OutputPrefixType_TINK, 123)
+	key, err := protoserialization.ParseKey(ks)
+	if err != nil {
+		t.Fatalf("protoserialization.ParseKey() err = %v, want nil", err)
+	}
+	want, err := NewKey(123, testParameters(t, VariantTink))
+	if err != nil {
+		t.Fatalf("NewKey() err = %v, want nil", err)
+	}
+	if !key.Equal(want) {
+		t.Errorf("protoserialization.ParseKey() = %v, want %v", key, want)
+	}
+}
+
+func TestParseKeyFails(t *testing.T) {
+	validKey := &kmsepb.KmsEnvelopeAeadKey{
+		Version: protoVersion,
+		Params: &kmsepb.KmsEnvelopeAeadKeyFormat{
+			KekUri:      testKEKURI,
+			DekTemplate: aesGCMSIVDEKTemplate(t),
+		},
+	}
+	for _, tc := range []struct {
+		name    string
+		keyData *tinkpb.KeyData
+	}{
+		{
+			name: "wrong type URL",
+			keyData: &tinkpb.KeyData{
+				TypeUrl:         "type.googleapis.com/google.crypto.tink.AesGcmSivKey",
+				Value:           mustMarshal(t, validKey),
+				KeyMaterialType: tinkpb.KeyData_REMOTE,
+			},
+		},
+		{
+			name: "wrong key material type",
+			keyData: &tinkpb.KeyData{
+				TypeUrl:         typeURL,
+				Value:           mustMarshal(t, validKey),
+				KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+			},
+		},
+		{
+			name: "wrong version",
+			keyData: &tinkpb.KeyData{
+				TypeUrl: typeURL,
+				Value: mustMarshal(t, &kmsepb.KmsEnvelopeAeadKey{
+					Version: protoVersion + 1,
+					Params:  validKey.GetParams(),
+				}),
+				KeyMaterialType: tinkpb.KeyData_REMOTE,
+			},
+		},
+		{
+			name: "invalid proto",
+			keyData: &tinkpb.KeyData{
+				TypeUrl:         typeURL,
+				Value:           []byte("invalid"),
+				KeyMaterialType: tinkpb.KeyData_REMOTE,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ks := mustCreateKeySerialization(t, tc.keyData, tinkpb.OutputPrefixType_TINK, 123)
+			p := &keyParser{}
+			if _, err := p.ParseKey(ks); err == nil {
+				t.Errorf("p.ParseKey() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseKeyFallsBackForUnrepresentableKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		dekTemplate      *tinkpb.KeyTemplate
+		outputPrefixType tinkpb.OutputPrefixType
+	}{
+		{
+			name: "DEK without parameters parser",
+			dekTemplate: &tinkpb.KeyTemplate{
+				TypeUrl:          "type.googleapis.com/google.crypto.tink.AesSivKey",
+				OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+			},
+			outputPrefixType: tinkpb.OutputPrefixType_TINK,
+		},
+		{
+			name:             "CRUNCHY output prefix",
+			dekTemplate:      aesGCMSIVDEKTemplate(t),
+			outputPrefixType: tinkpb.OutputPrefixType_CRUNCHY,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			keyData := &tinkpb.KeyData{
+				TypeUrl: typeURL,
+				Value: mustMarshal(t, &kmsepb.KmsEnvelopeAeadKey{
+					Version: protoVersion,
+					Params: &kmsepb.KmsEnvelopeAeadKeyFormat{
+						KekUri:      testKEKURI,
+						DekTemplate: tc.dekTemplate,
+					},
+				}),
+				KeyMaterialType: tinkpb.KeyData_REMOTE,
+			}
+			ks := mustCreateKeySerialization(t, keyData, tc.outputPrefixType, 123)
+			key, err := protoserialization.ParseKey(ks)
+			if err != nil {
+				t.Fatalf("protoserialization.ParseKey() err = %v, want nil", err)
+			}
+			if _, ok := key.(*protoserialization.FallbackProtoKey); !ok {
+				t.Errorf("protoserialization.ParseKey() = %T, want *protoserialization.FallbackProtoKey", key)
+			}
+		})
+	}
+}
+
+func TestSerializeParseParameters(t *testing.T) {
+	params := testParameters(t, VariantTink)
+	keyTemplate, err := protoserialization.SerializeParameters(params)
+	if err != nil {
+		t.Fatalf("protoserialization.SerializeParameters() err = %v, want nil", err)
+	}
+	if got, want := keyTemplate.GetTypeUrl(), typeURL; got != want {
+		t.Errorf("keyTemplate.GetTypeUrl() = %q, want %q", got, want)
+	}
+	if got, want := keyTemplate.GetOutputPrefixType(), tinkpb.OutputPrefixType_TINK; got != want {
+		t.Errorf("keyTemplate.GetOutputPrefixType() = %v, want %v", got, want)
+	}
+	parsedParams, err := protoserialization.ParseParameters(keyTemplate)
+	if err != nil {
+		t.Fatalf("protoserialization.ParseParameters() err = %v, want nil", err)
+	}
+	if !parsedParams.Equal(params) {
+		t.Errorf("parsedParams.Equal(params) = false, want true")
+	}
+}
+
+func TestParseParametersFails(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		keyTemplate *tinkpb.KeyTemplate
+	}{
+		{
+			name: "wrong type URL",
+			keyTemplate: &tinkpb.KeyTemplate{
+				TypeUrl:          "type.googleapis.com/google.crypto.tink.AesGcmSivKey",
+				OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+			},
+		},
+		{
+			name: "unsupported DEK",
+			keyTemplate: &tinkpb.KeyTemplate{
+				TypeUrl:          typeURL,
+				OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+				Value: mustMarshal(t, &kmsepb.KmsEnvelopeAeadKeyFormat{
+					KekUri: testKEKURI,
+					DekTemplate: &tinkpb.KeyTemplate{
+						TypeUrl: "type.googleapis.com/google.crypto.tink.HmacKey",
+					},
+				}),
+			},
+		},
+		{
+			name: "unsupported output prefix",
+			keyTemplate: &tinkpb.KeyTemplate{
+				TypeUrl:          typeURL,
+				OutputPrefixType: tinkpb.OutputPrefixType_LEGACY,
+				Value: mustMarshal(t, &kmsepb.KmsEnvelopeAeadKeyFormat{
+					KekUri:      testKEKURI,
+					DekTemplate: aesGCMSIVDEKTemplate(t),
+				}),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &parametersParser{}
+			if _, err := p.Parse(tc.keyTemplate); err == nil {
+				t.Errorf("p.Parse() err = nil, want error")
+			}
+		})
+	}
+}
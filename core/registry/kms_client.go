@@ -24,3 +24,13 @@ type KMSClient interface {
 	// GetAEAD  gets an AEAD backend by keyURI.
 	GetAEAD(keyURI string) (tink.AEAD, error)
 }
+
+// KMSClientWithContext is a KMSClient that can also produce
+// [tink.AEADWithContext] primitives, so that deadlines, cancellation and other
+// request-scoped values of the context propagate to the calls to the KMS.
+type KMSClientWithContext interface {
+	KMSClient
+
+	// GetAEADWithContext gets an AEADWithContext backend by keyURI.
+	GetAEADWithContext(keyURI string) (tink.AEADWithContext, error)
+}
@@ -29,10 +29,12 @@
 package registry
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/tink"
 	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
 )
 
@@ -145,6 +147,50 @@ func GetKMSClient(keyURI string) (KMSClient, error) {
 	return nil, fmt.Errorf("KMS client supporting %s not found", keyURI)
 }
 
+// GetKMSAEADWithContext returns an AEADWithContext backed by the key keyURI of
+// the first registered KMS client that supports it.
+//
+// Clients implementing [KMSClientWithContext] are asked for an AEADWithContext
+// directly. For other clients, the AEAD returned by GetAEAD is used if it
+// implements [tink.AEADWithContext]; otherwise it is adapted so that calls
+// fail once the context is done, but the context is not passed to the KMS.
+func GetKMSAEADWithContext(keyURI string) (tink.AEADWithContext, error) {
+	kmsClient, err := GetKMSClient(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := kmsClient.(KMSClientWithContext); ok {
+		return c.GetAEADWithContext(keyURI)
+	}
+	a, err := kmsClient.GetAEAD(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	if ac, ok := a.(tink.AEADWithContext); ok {
+		return ac, nil
+	}
+	return &aeadWithContextAdapter{aead: a}, nil
+}
+
+// aeadWithContextAdapter adapts a [tink.AEAD] to [tink.AEADWithContext].
+type aeadWithContextAdapter struct {
+	aead tink.AEAD
+}
+
+func (a *aeadWithContextAdapter) EncryptWithContext(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.aead.Encrypt(plaintext, associatedData)
+}
+
+func (a *aeadWithContextAdapter) DecryptWithContext(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.aead.Decrypt(ciphertext, associatedData)
+}
+
 // ClearKMSClients removes all registered KMS clients.
 //
 // Should only be used in tests.
@@ -15,6 +15,9 @@
 package registry_test
 
 import (
+	"bytes"
+	"context"
+	"strings"
 	"testing"
 
 	"google.golang.org/protobuf/proto"
@@ -24,6 +27,7 @@ import (
 	"github.com/tink-crypto/tink-go/v2/mac/subtle"
 	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
 	"github.com/tink-crypto/tink-go/v2/testutil"
+	"github.com/tink-crypto/tink-go/v2/tink"
 	gcmpb "github.com/tink-crypto/tink-go/v2/proto/aes_gcm_go_proto"
 	commonpb "github.com/tink-crypto/tink-go/v2/proto/common_go_proto"
 	hmacpb "github.com/tink-crypto/tink-go/v2/proto/hmac_go_proto"
@@ -252,3 +256,74 @@ func TestClearKMSClients(t *testing.T) {
 		t.Errorf("registry.GetKMSClient('fake-kms://xyz-123') succeeded, want fail")
 	}
 }
+
+// legacyKMSClient is a KMSClient that only returns plain AEADs.
+type legacyKMSClient struct {
+	prefix string
+}
+
+// plainAEAD hides the AEADWithContext methods of the wrapped AEAD.
+type plainAEAD struct {
+	tink.AEAD
+}
+
+func (c *legacyKMSClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, c.prefix)
+}
+
+func (c *legacyKMSClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	a, err := fakekms.NewAEAD(strings.Replace(keyURI, c.prefix, "fake-kms://", 1))
+	if err != nil {
+		return nil, err
+	}
+	return &plainAEAD{a}, nil
+}
+
+func TestGetKMSAEADWithContext(t *testing.T) {
+	defer registry.ClearKMSClients()
+	fakeKeyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	client, err := fakekms.NewClient("fake-kms://")
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() err = %v, want nil", err)
+	}
+	registry.RegisterKMSClient(client)
+	registry.RegisterKMSClient(&legacyKMSClient{prefix: "legacy-kms://"})
+
+	for _, keyURI := range []string{fakeKeyURI, strings.Replace(fakeKeyURI, "fake-kms://", "legacy-kms://", 1)} {
+		t.Run(keyURI, func(t *testing.T) {
+			a, err := registry.GetKMSAEADWithContext(keyURI)
+			if err != nil {
+				t.Fatalf("registry.GetKMSAEADWithContext() err = %v, want nil", err)
+			}
+			ctx := context.Background()
+			plaintext := []byte("plaintext")
+			associatedData := []byte("associatedData")
+			ciphertext, err := a.EncryptWithContext(ctx, plaintext, associatedData)
+			if err != nil {
+				t.Fatalf("a.EncryptWithContext() err = %v, want nil", err)
+			}
+			decrypted, err := a.DecryptWithContext(ctx, ciphertext, associatedData)
+			if err != nil {
+				t.Fatalf("a.DecryptWithContext() err = %v, want nil", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("a.DecryptWithContext() = %q, want %q", decrypted, plaintext)
+			}
+			canceledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			if _, err := a.EncryptWithContext(canceledCtx, plaintext, associatedData); err == nil {
+				t.Errorf("a.EncryptWithContext() with canceled context err = nil, want error")
+			}
+			if _, err := a.DecryptWithContext(canceledCtx, ciphertext, associatedData); err == nil {
+				t.Errorf("a.DecryptWithContext() with canceled context err = nil, want error")
+			}
+		})
+	}
+
+	if _, err := registry.GetKMSAEADWithContext("unknown-kms://key"); err == nil {
+		t.Errorf("registry.GetKMSAEADWithContext() with unknown URI err = nil, want error")
+	}
+}
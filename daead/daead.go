@@ -32,4 +32,7 @@ func init() {
 	if err := internalregistry.AllowKeyDerivation(aesSIVTypeURL); err != nil {
 		panic(fmt.Sprintf("daead.init() failed: %v", err))
 	}
+	if err := registry.RegisterKeyManager(new(kmsEnvelopeDAEADKeyManager)); err != nil {
+		panic(fmt.Sprintf("daead.init() failed: %v", err))
+	}
 }
@@ -20,6 +20,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"github.com/tink-crypto/tink-go/v2/internal/tinkerror"
 	aspb "github.com/tink-crypto/tink-go/v2/proto/aes_siv_go_proto"
+	kmsedpb "github.com/tink-crypto/tink-go/v2/proto/kms_envelope_deterministic_aead_go_proto"
 	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
 )
 
@@ -38,3 +39,34 @@ func AESSIVKeyTemplate() *tinkpb.KeyTemplate {
 		Value:            serializedFormat,
 	}
 }
+
+// CreateKMSEnvelopeDeterministicAEADKeyTemplate returns a key template that
+// generates a KMS envelope deterministic AEAD key for a given key encryption
+// key (KEK) in a remote key management service (KMS).
+//
+// Each generated key holds its own data encryption key (DEK), which is
+// generated with dekTemplate and stored in the keyset encrypted by the KEK.
+// Encryption with the key is therefore deterministic, and no call to the KMS
+// is made when encrypting or decrypting data. The KMS is called when a key is
+// generated and when a primitive is created from the keyset, so a KMS client
+// supporting uri must be registered with registry.RegisterKMSClient.
+//
+// dekTemplate must be a KeyTemplate for AesSivKey; any other key template is
+// rejected.
+func CreateKMSEnvelopeDeterministicAEADKeyTemplate(uri string, dekTemplate *tinkpb.KeyTemplate) (*tinkpb.KeyTemplate, error) {
+	if !isSupportedKMSEnvelopeDAEADDEK(dekTemplate.GetTypeUrl()) {
+		return nil, fmt.Errorf("unsupported DEK key type %s. Only AesSivKey is supported", dekTemplate.GetTypeUrl())
+	}
+	serializedFormat, err := proto.Marshal(&kmsedpb.KmsEnvelopeDeterministicAeadKeyFormat{
+		KekUri:      uri,
+		DekTemplate: dekTemplate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key format: %s", err)
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          kmsEnvelopeDAEADTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+		Value:            serializedFormat,
+	}, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daead
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	kmsedpb "github.com/tink-crypto/tink-go/v2/proto/kms_envelope_deterministic_aead_go_proto"
+	tpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const (
+	kmsEnvelopeDAEADKeyVersion = 0
+	kmsEnvelopeDAEADTypeURL    = "type.googleapis.com/google.crypto.tink.KmsEnvelopeDeterministicAeadKey"
+)
+
+var (
+	errInvalidKMSEnvelopeDAEADKey       = errors.New("kms_envelope_daead_key_manager: invalid key")
+	errInvalidKMSEnvelopeDAEADKeyFormat = errors.New("kms_envelope_daead_key_manager: invalid key format")
+)
+
+// kmsEnvelopeDAEADKeyManager is an implementation of KeyManager interface.
+//
+// Unlike KMS envelope AEAD keys, each key holds a single data encryption key
+// (DEK), encrypted with the remote key encryption key (KEK). The DEK is
+// generated and encrypted when the key is created, so creating keys requires
+// access to the KMS. The KEK is used again only when a primitive is created.
+type kmsEnvelopeDAEADKeyManager struct{}
+
+// getKEK returns the remote AEAD for the KEK in the given key format.
+func getKEK(keyFormat *kmsedpb.KmsEnvelopeDeterministicAeadKeyFormat) (tink.AEAD, error) {
+	uri := keyFormat.GetKekUri()
+	kmsClient, err := registry.GetKMSClient(uri)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := kmsClient.GetAEAD(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aead backend: %v", err)
+	}
+	return kek, nil
+}
+
+// Primitive decrypts the DEK of the given serialized
+// KmsEnvelopeDeterministicAeadKey and returns the DEK's DeterministicAEAD.
+func (km *kmsEnvelopeDAEADKeyManager) Primitive(serializedKey []byte) (any, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidKMSEnvelopeDAEADKey
+	}
+	key := new(kmsedpb.KmsEnvelopeDeterministicAeadKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidKMSEnvelopeDAEADKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: %v", err)
+	}
+	kek, err := getKEK(key.GetParams())
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: %v", err)
+	}
+	dek, err := kek.Decrypt(key.GetEncryptedDek(), []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: cannot decrypt DEK: %v", err)
+	}
+	p, err := registry.Primitive(key.GetParams().GetDekTemplate().GetTypeUrl(), dek)
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: %v", err)
+	}
+	d, ok := p.(tink.DeterministicAEAD)
+	if !ok {
+		return nil, errors.New("kms_envelope_daead_key_manager: failed to convert DeterministicAEAD primitive")
+	}
+	return d, nil
+}
+
+// NewKey generates a new DEK according to the given serialized
+// KmsEnvelopeDeterministicAeadKeyFormat and encrypts it with the KEK.
+func (km *kmsEnvelopeDAEADKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidKMSEnvelopeDAEADKeyFormat
+	}
+	keyFormat := new(kmsedpb.KmsEnvelopeDeterministicAeadKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidKMSEnvelopeDAEADKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: %v", err)
+	}
+	kek, err := getKEK(keyFormat)
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: %v", err)
+	}
+	dekKeyData, err := registry.NewKeyData(keyFormat.GetDekTemplate())
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: %v", err)
+	}
+	encryptedDEK, err := kek.Encrypt(dekKeyData.GetValue(), []byte{})
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_daead_key_manager: cannot encrypt DEK: %v", err)
+	}
+	return &kmsedpb.KmsEnvelopeDeterministicAeadKey{
+		Version:      kmsEnvelopeDAEADKeyVersion,
+		Params:       keyFormat,
+		EncryptedDek: encryptedDEK,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to the given serialized
+// KmsEnvelopeDeterministicAeadKeyFormat.
+// It should be used solely by the key management API.
+func (km *kmsEnvelopeDAEADKeyManager) NewKeyData(serializedKeyFormat []byte) (*tpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tpb.KeyData{
+		TypeUrl:         kmsEnvelopeDAEADTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *kmsEnvelopeDAEADKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == kmsEnvelopeDAEADTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *kmsEnvelopeDAEADKeyManager) TypeURL() string {
+	return kmsEnvelopeDAEADTypeURL
+}
+
+func (km *kmsEnvelopeDAEADKeyManager) validateKey(key *kmsedpb.KmsEnvelopeDeterministicAeadKey) error {
+	if err := keyset.ValidateKeyVersion(key.GetVersion(), kmsEnvelopeDAEADKeyVersion); err != nil {
+		return err
+	}
+	if len(key.GetEncryptedDek()) == 0 {
+		return errors.New("encrypted DEK is empty")
+	}
+	return km.validateKeyFormat(key.GetParams())
+}
+
+func (km *kmsEnvelopeDAEADKeyManager) validateKeyFormat(keyFormat *kmsedpb.KmsEnvelopeDeterministicAeadKeyFormat) error {
+	if !isSupportedKMSEnvelopeDAEADDEK(keyFormat.GetDekTemplate().GetTypeUrl()) {
+		return fmt.Errorf("unsupported DEK key type %s. Only AesSivKey is supported with KMS envelope deterministic AEAD", keyFormat.GetDekTemplate().GetTypeUrl())
+	}
+	return nil
+}
+
+func isSupportedKMSEnvelopeDAEADDEK(dekKeyTypeURL string) bool {
+	return dekKeyTypeURL == aesSIVTypeURL
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daead_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/daead"
+	"github.com/tink-crypto/tink-go/v2/insecurecleartextkeyset"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func newKMSEnvelopeDAEADHandle(t *testing.T) (*keyset.Handle, string) {
+	t.Helper()
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	client, err := fakekms.NewClient(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() err = %v, want nil", err)
+	}
+	registry.RegisterKMSClient(client)
+	template, err := daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate(keyURI, daead.AESSIVKeyTemplate())
+	if err != nil {
+		t.Fatalf("daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate() err = %v, want nil", err)
+	}
+	handle, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	return handle, keyURI
+}
+
+func newDAEAD(t *testing.T, handle *keyset.Handle) tink.DeterministicAEAD {
+	t.Helper()
+	d, err := daead.New(handle)
+	if err != nil {
+		t.Fatalf("daead.New() err = %v, want nil", err)
+	}
+	return d
+}
+
+func TestKMSEnvelopeDeterministicAEADEncryptDecrypt(t *testing.T) {
+	defer registry.ClearKMSClients()
+	handle, _ := newKMSEnvelopeDAEADHandle(t)
+	d := newDAEAD(t, handle)
+
+	plaintext := []byte("plaintext")
+	associatedData := []byte("associatedData")
+	ciphertext, err := d.EncryptDeterministically(plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("d.EncryptDeterministically() err = %v, want nil", err)
+	}
+	ciphertext2, err := d.EncryptDeterministically(plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("d.EncryptDeterministically() err = %v, want nil", err)
+	}
+	if !bytes.Equal(ciphertext, ciphertext2) {
+		t.Error("d.EncryptDeterministically() returned different ciphertexts for the same input")
+	}
+	decrypted, err := d.DecryptDeterministically(ciphertext, associatedData)
+	if err != nil {
+		t.Fatalf("d.DecryptDeterministically() err = %v, want nil", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("d.DecryptDeterministically() = %q, want %q", decrypted, plaintext)
+	}
+	if _, err := d.DecryptDeterministically(ciphertext, []byte("invalid")); err == nil {
+		t.Error("d.DecryptDeterministically() with invalid associated data err = nil, want error")
+	}
+}
+
+func TestKMSEnvelopeDeterministicAEADDEKIsFixedPerKey(t *testing.T) {
+	defer registry.ClearKMSClients()
+	handle, keyURI := newKMSEnvelopeDAEADHandle(t)
+	plaintext := []byte("plaintext")
+	ciphertext, err := newDAEAD(t, handle).EncryptDeterministically(plaintext, nil)
+	if err != nil {
+		t.Fatalf("EncryptDeterministically() err = %v, want nil", err)
+	}
+
+	// The serialized keyset contains the encrypted DEK only, so a keyset read
+	// back produces the same ciphertexts.
+	buf := new(bytes.Buffer)
+	if err := insecurecleartextkeyset.Write(handle, keyset.NewBinaryWriter(buf)); err != nil {
+		t.Fatalf("insecurecleartextkeyset.Write() err = %v, want nil", err)
+	}
+	readHandle, err := insecurecleartextkeyset.Read(keyset.NewBinaryReader(buf))
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() err = %v, want nil", err)
+	}
+	got, err := newDAEAD(t, readHandle).EncryptDeterministically(plaintext, nil)
+	if err != nil {
+		t.Fatalf("EncryptDeterministically() err = %v, want nil", err)
+	}
+	if !bytes.Equal(got, ciphertext) {
+		t.Error("EncryptDeterministically() with the read keyset returned a different ciphertext")
+	}
+
+	// A new key with the same KEK has a different DEK.
+	template, err := daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate(keyURI, daead.AESSIVKeyTemplate())
+	if err != nil {
+		t.Fatalf("daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate() err = %v, want nil", err)
+	}
+	otherHandle, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	if _, err := newDAEAD(t, otherHandle).DecryptDeterministically(ciphertext, nil); err == nil {
+		t.Error("DecryptDeterministically() with another key err = nil, want error")
+	}
+}
+
+func TestKMSEnvelopeDeterministicAEADKeyIsSecret(t *testing.T) {
+	defer registry.ClearKMSClients()
+	// The key contains an encrypted DEK, so it is not exported without secrets.
+	handle, _ := newKMSEnvelopeDAEADHandle(t)
+	buf := new(bytes.Buffer)
+	if err := handle.WriteWithNoSecrets(keyset.NewBinaryWriter(buf)); err == nil {
+		t.Error("handle.WriteWithNoSecrets() err = nil, want error")
+	}
+	keyData := insecurecleartextkeyset.KeysetMaterial(handle).GetKey()[0].GetKeyData()
+	if got, want := keyData.GetKeyMaterialType(), tinkpb.KeyData_SYMMETRIC; got != want {
+		t.Errorf("keyData.GetKeyMaterialType() = %v, want %v", got, want)
+	}
+}
+
+func TestKMSEnvelopeDeterministicAEADFailures(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	if _, err := daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate(keyURI, aead.AES128GCMKeyTemplate()); err == nil {
+		t.Error("daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate() with AEAD DEK template err = nil, want error")
+	}
+	template, err := daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate(keyURI, daead.AESSIVKeyTemplate())
+	if err != nil {
+		t.Fatalf("daead.CreateKMSEnvelopeDeterministicAEADKeyTemplate() err = %v, want nil", err)
+	}
+	// No KMS client is registered, so the DEK cannot be encrypted.
+	if _, err := keyset.NewHandle(template); err == nil {
+		t.Error("keyset.NewHandle() without KMS client err = nil, want error")
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hcvault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// maxResponseSize bounds the size of the responses read from Vault.
+const maxResponseSize = 1 << 26
+
+// transitKey identifies a key of a Transit secrets engine.
+type transitKey struct {
+	host      string
+	mountPath string
+	name      string
+}
+
+// parseKeyURI parses keyURI of the form
+// hcvault://<host>/<mount path>/keys/<key name>.
+func parseKeyURI(keyURI string) (*transitKey, error) {
+	u, err := url.Parse(keyURI)
+	if err != nil {
+		return nil, fmt.Errorf("hcvault: invalid key URI: %v", err)
+	}
+	if !strings.EqualFold(u.Scheme, "hcvault") || u.Host == "" {
+		return nil, fmt.Errorf("hcvault: invalid key URI %q", keyURI)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("hcvault: key URI %q must not have a query or fragment", keyURI)
+	}
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/keys/")
+	if i <= 0 {
+		return nil, fmt.Errorf("hcvault: key URI %q must have the form %s<host>/<mount path>/keys/<key name>", keyURI, vaultPrefix)
+	}
+	name := path[i+len("/keys/"):]
+	if name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("hcvault: invalid key name in key URI %q", keyURI)
+	}
+	return &transitKey{
+		host:      u.Host,
+		mountPath: path[:i],
+		name:      name,
+	}, nil
+}
+
+// endpoint returns the URL of the given Transit operation for k.
+func (k *transitKey) endpoint(operation string) string {
+	return (&url.URL{
+		Scheme: "https",
+		Host:   k.host,
+		Path:   fmt.Sprintf("/v1/%s/%s/%s", k.mountPath, operation, k.name),
+	}).String()
+}
+
+// vaultAEAD is an AEAD that encrypts and decrypts using the Transit
+// secrets engine of HashiCorp Vault.
+//
+// The associated data is authenticated by Vault, which requires a Transit key
+// of an AEAD type such as aes256-gcm96.
+type vaultAEAD struct {
+	client *vaultClient
+	key    *transitKey
+}
+
+var _ tink.AEAD = (*vaultAEAD)(nil)
+var _ tink.AEADWithContext = (*vaultAEAD)(nil)
+
+type encryptRequest struct {
+	Plaintext      string `json:"plaintext"`
+	AssociatedData string `json:"associated_data,omitempty"`
+}
+
+type decryptRequest struct {
+	Ciphertext     string `json:"ciphertext"`
+	AssociatedData string `json:"associated_data,omitempty"`
+}
+
+type transitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// Encrypt encrypts the plaintext with associatedData.
+func (a *vaultAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	return a.EncryptWithContext(context.Background(), plaintext, associatedData)
+}
+
+// Decrypt decrypts the ciphertext and verifies the associated data.
+func (a *vaultAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	return a.DecryptWithContext(context.Background(), ciphertext, associatedData)
+}
+
+// EncryptWithContext encrypts the plaintext with associatedData.
+//
+// The returned ciphertext is the Vault ciphertext string, such as
+// "vault:v1:...".
+func (a *vaultAEAD) EncryptWithContext(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	resp, err := a.do(ctx, "encrypt", &encryptRequest{
+		Plaintext:      base64.StdEncoding.EncodeToString(plaintext),
+		AssociatedData: base64.StdEncoding.EncodeToString(associatedData),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("hcvault: encryption failed: %v", err)
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("hcvault: encryption failed: empty ciphertext in response")
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// DecryptWithContext decrypts the ciphertext and verifies the associated data.
+func (a *vaultAEAD) DecryptWithContext(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error) {
+	resp, err := a.do(ctx, "decrypt", &decryptRequest{
+		Ciphertext:     string(ciphertext),
+		AssociatedData: base64.StdEncoding.EncodeToString(associatedData),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("hcvault: decryption failed: %v", err)
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("hcvault: decryption failed: invalid plaintext in response: %v", err)
+	}
+	return plaintext, nil
+}
+
+// do sends a Transit request for operation and returns the decoded response.
+func (a *vaultAEAD) do(ctx context.Context, operation string, body any) (*transitResponse, error) {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.key.endpoint(operation), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", a.client.token)
+	req.Header.Set("X-Vault-Request", "true")
+	if a.client.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", a.client.namespace)
+	}
+	httpResp, err := a.client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	resp := new(transitResponse)
+	// The body of a failed request may not be JSON, so only report the decoding
+	// error for successful requests.
+	decodeErr := json.Unmarshal(respBody, resp)
+	if httpResp.StatusCode != http.StatusOK {
+		if decodeErr == nil && len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("vault returned status %d: %s", httpResp.StatusCode, strings.Join(resp.Errors, "; "))
+		}
+		return nil, fmt.Errorf("vault returned status %d", httpResp.StatusCode)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("invalid response: %v", decodeErr)
+	}
+	return resp, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hcvault provides an implementation of registry.KMSClient backed by
+// the Transit secrets engine of HashiCorp Vault.
+//
+// Key URIs have the form
+//
+//	hcvault://<vault host>[:<port>]/<transit mount path>/keys/<key name>
+//
+// for example hcvault://vault.example.com:8200/transit/keys/my-key. All
+// requests are sent over HTTPS to the Vault HTTP API.
+package hcvault
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+const vaultPrefix = "hcvault://"
+
+// ClientOption is an option for [NewClient].
+type ClientOption func(*vaultClient) error
+
+// WithNamespace sets the Vault Enterprise namespace of all requests.
+func WithNamespace(namespace string) ClientOption {
+	return func(c *vaultClient) error {
+		if namespace == "" {
+			return fmt.Errorf("namespace must not be empty")
+		}
+		c.namespace = namespace
+		return nil
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests to Vault. If set,
+// the tlsConfig passed to [NewClient] is ignored.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *vaultClient) error {
+		if httpClient == nil {
+			return fmt.Errorf("httpClient must not be nil")
+		}
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// vaultClient represents a client that connects to a HashiCorp Vault server.
+type vaultClient struct {
+	uriPrefix  string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+var _ registry.KMSClientWithContext = (*vaultClient)(nil)
+
+// NewClient returns a new client for HashiCorp Vault that handles key URIs
+// starting with uriPrefix. uriPrefix must start with "hcvault://".
+//
+// Requests are authenticated with token. tlsConfig configures the HTTPS
+// connections to Vault; if it is nil, the default configuration is used.
+func NewClient(uriPrefix string, tlsConfig *tls.Config, token string, opts ...ClientOption) (registry.KMSClientWithContext, error) {
+	if !strings.HasPrefix(strings.ToLower(uriPrefix), vaultPrefix) {
+		return nil, fmt.Errorf("hcvault: uriPrefix must start with %s, but got %s", vaultPrefix, uriPrefix)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("hcvault: token must not be empty")
+	}
+	c := &vaultClient{
+		uriPrefix: uriPrefix,
+		token:     token,
+		httpClient: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, fmt.Errorf("hcvault: %v", err)
+		}
+	}
+	return c, nil
+}
+
+// Supported returns true if this client does support keyURI.
+func (c *vaultClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, c.uriPrefix)
+}
+
+// GetAEAD returns an AEAD backed by the Transit key identified by keyURI.
+//
+// The returned AEAD also implements [tink.AEADWithContext].
+func (c *vaultClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	return c.newAEAD(keyURI)
+}
+
+// GetAEADWithContext returns an AEADWithContext backed by the Transit key
+// identified by keyURI. The context is used for the requests to Vault.
+func (c *vaultClient) GetAEADWithContext(keyURI string) (tink.AEADWithContext, error) {
+	return c.newAEAD(keyURI)
+}
+
+func (c *vaultClient) newAEAD(keyURI string) (*vaultAEAD, error) {
+	if !c.Supported(keyURI) {
+		return nil, fmt.Errorf("hcvault: keyURI must start with prefix %s, but got %s", c.uriPrefix, keyURI)
+	}
+	key, err := parseKeyURI(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	return &vaultAEAD{
+		client: c,
+		key:    key,
+	}, nil
+}
@@ -0,0 +1,380 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hcvault_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/integration/hcvault"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+const (
+	testToken     = "test-token"
+	testNamespace = "team-a"
+)
+
+// fakeTransit is a minimal stand-in for the Transit secrets engine API.
+type fakeTransit struct {
+	namespace string
+
+	mu   sync.Mutex
+	keys map[string]cipher.AEAD
+}
+
+func (f *fakeTransit) key(name string) cipher.AEAD {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if k, ok := f.keys[name]; ok {
+		return k
+	}
+	keyValue := make([]byte, 32)
+	rand.Read(keyValue)
+	block, _ := aes.NewCipher(keyValue)
+	k, _ := cipher.NewGCM(block)
+	f.keys[name] = k
+	return k
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string][]string{"errors": {msg}})
+}
+
+func (f *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "unsupported method")
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != testToken {
+		writeError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+	if r.Header.Get("X-Vault-Namespace") != f.namespace {
+		writeError(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+	// Path is /v1/<mount path>/<operation>/<key name>.
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"), "/")
+	if len(parts) < 3 {
+		writeError(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+	operation, name := parts[len(parts)-2], strings.Join(parts[:len(parts)-2], "/")+"/"+parts[len(parts)-1]
+	var req struct {
+		Plaintext      string `json:"plaintext"`
+		Ciphertext     string `json:"ciphertext"`
+		AssociatedData string `json:"associated_data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ad, err := base64.StdEncoding.DecodeString(req.AssociatedData)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	k := f.key(name)
+	var data map[string]string
+	switch operation {
+	case "encrypt":
+		pt, err := base64.StdEncoding.DecodeString(req.Plaintext)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		nonce := make([]byte, k.NonceSize())
+		rand.Read(nonce)
+		ct := k.Seal(nonce, nonce, pt, ad)
+		data = map[string]string{"ciphertext": "vault:v1:" + base64.StdEncoding.EncodeToString(ct)}
+	case "decrypt":
+		ct, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(req.Ciphertext, "vault:v1:"))
+		if err != nil || len(ct) < k.NonceSize() {
+			writeError(w, http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		pt, err := k.Open(nil, ct[:k.NonceSize()], ct[k.NonceSize():], ad)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "cipher: message authentication failed")
+			return
+		}
+		data = map[string]string{"plaintext": base64.StdEncoding.EncodeToString(pt)}
+	default:
+		writeError(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+// newTestServer starts a fake Transit server and returns a key URI prefix
+// pointing to it and a TLS config that trusts it.
+func newTestServer(t *testing.T, namespace string) (string, *tls.Config) {
+	t.Helper()
+	server := httptest.NewTLSServer(&fakeTransit{namespace: namespace, keys: make(map[string]cipher.AEAD)})
+	t.Cleanup(server.Close)
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	return "hcvault://" + strings.TrimPrefix(server.URL, "https://"), &tls.Config{RootCAs: pool}
+}
+
+func mustNewClient(t *testing.T, uriPrefix string, tlsConfig *tls.Config, opts ...hcvault.ClientOption) registry.KMSClientWithContext {
+	t.Helper()
+	client, err := hcvault.NewClient(uriPrefix, tlsConfig, testToken, opts...)
+	if err != nil {
+		t.Fatalf("hcvault.NewClient() err = %v, want nil", err)
+	}
+	return client
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	uriPrefix, tlsConfig := newTestServer(t, "")
+	client := mustNewClient(t, uriPrefix, tlsConfig)
+	a, err := client.GetAEAD(uriPrefix + "/transit/keys/my-key")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	plaintext := []byte("plaintext")
+	associatedData := []byte("associatedData")
+	ciphertext, err := a.Encrypt(plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	if !strings.HasPrefix(string(ciphertext), "vault:v1:") {
+		t.Errorf("a.Encrypt() = %q, want prefix %q", ciphertext, "vault:v1:")
+	}
+	decrypted, err := a.Decrypt(ciphertext, associatedData)
+	if err != nil {
+		t.Fatalf("a.Decrypt() err = %v, want nil", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("a.Decrypt() = %q, want %q", decrypted, plaintext)
+	}
+	if _, err := a.Decrypt(ciphertext, []byte("invalid")); err == nil {
+		t.Errorf("a.Decrypt() with invalid associated data err = nil, want error")
+	}
+
+	// A different key can't decrypt the ciphertext.
+	other, err := client.GetAEAD(uriPrefix + "/transit/keys/other-key")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := other.Decrypt(ciphertext, associatedData); err == nil {
+		t.Errorf("other.Decrypt() err = nil, want error")
+	}
+}
+
+func TestEncryptDecryptWithContext(t *testing.T) {
+	uriPrefix, tlsConfig := newTestServer(t, "")
+	client := mustNewClient(t, uriPrefix, tlsConfig)
+	a, err := client.GetAEAD(uriPrefix + "/nested/transit/keys/my-key")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	ac, ok := a.(tink.AEADWithContext)
+	if !ok {
+		t.Fatalf("client.GetAEAD() doesn't implement tink.AEADWithContext")
+	}
+	ctx := context.Background()
+	plaintext := []byte("plaintext")
+	ciphertext, err := ac.EncryptWithContext(ctx, plaintext, nil)
+	if err != nil {
+		t.Fatalf("ac.EncryptWithContext() err = %v, want nil", err)
+	}
+	decrypted, err := ac.DecryptWithContext(ctx, ciphertext, nil)
+	if err != nil {
+		t.Fatalf("ac.DecryptWithContext() err = %v, want nil", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("ac.DecryptWithContext() = %q, want %q", decrypted, plaintext)
+	}
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := ac.EncryptWithContext(canceledCtx, plaintext, nil); err == nil {
+		t.Errorf("ac.EncryptWithContext() with canceled context err = nil, want error")
+	}
+	if _, err := ac.DecryptWithContext(canceledCtx, ciphertext, nil); err == nil {
+		t.Errorf("ac.DecryptWithContext() with canceled context err = nil, want error")
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	uriPrefix, tlsConfig := newTestServer(t, testNamespace)
+	keyURI := uriPrefix + "/transit/keys/my-key"
+
+	withNamespace := mustNewClient(t, uriPrefix, tlsConfig, hcvault.WithNamespace(testNamespace))
+	a, err := withNamespace.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("withNamespace.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err != nil {
+		t.Errorf("a.Encrypt() err = %v, want nil", err)
+	}
+
+	withoutNamespace := mustNewClient(t, uriPrefix, tlsConfig)
+	a, err = withoutNamespace.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("withoutNamespace.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err == nil {
+		t.Errorf("a.Encrypt() without namespace err = nil, want error")
+	}
+}
+
+func TestInvalidToken(t *testing.T) {
+	uriPrefix, tlsConfig := newTestServer(t, "")
+	client, err := hcvault.NewClient(uriPrefix, tlsConfig, "invalid-token")
+	if err != nil {
+		t.Fatalf("hcvault.NewClient() err = %v, want nil", err)
+	}
+	a, err := client.GetAEAD(uriPrefix + "/transit/keys/my-key")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	_, err = a.Encrypt([]byte("plaintext"), nil)
+	if err == nil {
+		t.Fatalf("a.Encrypt() err = nil, want error")
+	}
+	if !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("a.Encrypt() err = %v, want error containing %q", err, "permission denied")
+	}
+}
+
+func TestUntrustedServerFails(t *testing.T) {
+	uriPrefix, _ := newTestServer(t, "")
+	client := mustNewClient(t, uriPrefix, nil)
+	a, err := client.GetAEAD(uriPrefix + "/transit/keys/my-key")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err == nil {
+		t.Errorf("a.Encrypt() err = nil, want error")
+	}
+}
+
+func TestNewClientFails(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		uriPrefix string
+		token     string
+		opts      []hcvault.ClientOption
+	}{
+		{"wrong prefix", "gcp-kms://vault.example.com", testToken, nil},
+		{"empty token", "hcvault://vault.example.com", "", nil},
+		{"empty namespace", "hcvault://vault.example.com", testToken, []hcvault.ClientOption{hcvault.WithNamespace("")}},
+		{"nil http client", "hcvault://vault.example.com", testToken, []hcvault.ClientOption{hcvault.WithHTTPClient(nil)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := hcvault.NewClient(tc.uriPrefix, nil, tc.token, tc.opts...); err == nil {
+				t.Errorf("hcvault.NewClient() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestGetAEADFails(t *testing.T) {
+	client := mustNewClient(t, "hcvault://vault.example.com", nil)
+	for _, keyURI := range []string{
+		"hcvault://other.example.com/transit/keys/my-key",
+		"hcvault://vault.example.com/transit/my-key",
+		"hcvault://vault.example.com/keys/my-key",
+		"hcvault://vault.example.com/transit/keys/",
+		"hcvault://vault.example.com/transit/keys/my-key/rotate",
+		"hcvault://vault.example.com/transit/keys/my-key?version=1",
+	} {
+		t.Run(keyURI, func(t *testing.T) {
+			if _, err := client.GetAEAD(keyURI); err == nil {
+				t.Errorf("client.GetAEAD(%q) err = nil, want error", keyURI)
+			}
+		})
+	}
+}
+
+func TestSupported(t *testing.T) {
+	client := mustNewClient(t, "hcvault://vault.example.com/transit/", nil)
+	if !client.Supported("hcvault://vault.example.com/transit/keys/my-key") {
+		t.Errorf("client.Supported() = false, want true")
+	}
+	if client.Supported("hcvault://vault.example.com/other/keys/my-key") {
+		t.Errorf("client.Supported() = true, want false")
+	}
+}
+
+func TestKeyEncryptionKey(t *testing.T) {
+	uriPrefix, tlsConfig := newTestServer(t, "")
+	client := mustNewClient(t, uriPrefix, tlsConfig)
+	kek, err := client.GetAEAD(uriPrefix + "/transit/keys/kek")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	kekWithContext := kek.(tink.AEADWithContext)
+	ctx := context.Background()
+
+	t.Run("keyset encryption", func(t *testing.T) {
+		handle, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+		}
+		buf := new(bytes.Buffer)
+		associatedData := []byte("keyset associated data")
+		if err := handle.WriteWithContext(ctx, keyset.NewBinaryWriter(buf), kekWithContext, associatedData); err != nil {
+			t.Fatalf("handle.WriteWithContext() err = %v, want nil", err)
+		}
+		got, err := keyset.ReadWithContext(ctx, keyset.NewBinaryReader(buf), kekWithContext, associatedData)
+		if err != nil {
+			t.Fatalf("keyset.ReadWithContext() err = %v, want nil", err)
+		}
+		if got.KeysetInfo().GetPrimaryKeyId() != handle.KeysetInfo().GetPrimaryKeyId() {
+			t.Errorf("keyset.ReadWithContext() primary = %v, want %v", got.KeysetInfo().GetPrimaryKeyId(), handle.KeysetInfo().GetPrimaryKeyId())
+		}
+	})
+
+	t.Run("envelope encryption", func(t *testing.T) {
+		envelope, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kekWithContext)
+		if err != nil {
+			t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+		}
+		plaintext := []byte("plaintext")
+		associatedData := []byte("associatedData")
+		ciphertext, err := envelope.EncryptWithContext(ctx, plaintext, associatedData)
+		if err != nil {
+			t.Fatalf("envelope.EncryptWithContext() err = %v, want nil", err)
+		}
+		decrypted, err := envelope.DecryptWithContext(ctx, ciphertext, associatedData)
+		if err != nil {
+			t.Fatalf("envelope.DecryptWithContext() err = %v, want nil", err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("envelope.DecryptWithContext() = %q, want %q", decrypted, plaintext)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localkms provides an implementation of registry.KMSClient for
+// offline and air-gapped deployments, where the key encryption keys are
+// stored in local files.
+//
+// A master key is a Tink AES-256-GCM keyset stored in a directory, encrypted
+// under a key derived from a passphrase with Argon2id or scrypt. Each rotation
+// writes a new version of the master keyset to
+// <dir>/master-key.<version>.json, and the latest version is used. Rotated
+// keysets keep the previous keys, so ciphertexts encrypted under an older
+// version can still be decrypted.
+//
+// Key URIs have the form local-kms://<dir>, for example
+// local-kms:///var/lib/tink/master-key.
+package localkms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+const localPrefix = "local-kms://"
+
+// localClient is a client for master keys stored in local directories.
+type localClient struct {
+	uriPrefix  string
+	passphrase []byte
+
+	mu sync.Mutex
+	// aeads caches the AEAD of each key URI, so that the master key is only
+	// derived from the passphrase again after a rotation.
+	aeads map[string]*cachedAEAD
+}
+
+// cachedAEAD is the AEAD for a version of a master key.
+type cachedAEAD struct {
+	version int
+	aead    *localAEAD
+}
+
+var _ registry.KMSClientWithContext = (*localClient)(nil)
+
+// NewClient returns a new client that handles key URIs starting with
+// uriPrefix, which must start with "local-kms://". passphrase is used to
+// decrypt the master keys.
+func NewClient(uriPrefix string, passphrase []byte) (registry.KMSClientWithContext, error) {
+	if !strings.HasPrefix(strings.ToLower(uriPrefix), localPrefix) {
+		return nil, fmt.Errorf("localkms: uriPrefix must start with %s, but got %s", localPrefix, uriPrefix)
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("localkms: passphrase must not be empty")
+	}
+	return &localClient{
+		uriPrefix:  uriPrefix,
+		passphrase: append([]byte{}, passphrase...),
+		aeads:      make(map[string]*cachedAEAD),
+	}, nil
+}
+
+// Supported returns true if this client does support keyURI.
+func (c *localClient) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, c.uriPrefix)
+}
+
+// GetAEAD returns an AEAD backed by the latest version of the master key in
+// the directory identified by keyURI.
+//
+// The AEAD is cached, and the master key is only read and derived from the
+// passphrase again once a new version has been written. The returned AEAD
+// also implements [tink.AEADWithContext].
+func (c *localClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	return c.getAEAD(keyURI)
+}
+
+// GetAEADWithContext returns an AEADWithContext backed by the latest version
+// of the master key in the directory identified by keyURI. It is cached like
+// the AEAD returned by GetAEAD.
+func (c *localClient) GetAEADWithContext(keyURI string) (tink.AEADWithContext, error) {
+	return c.getAEAD(keyURI)
+}
+
+func (c *localClient) getAEAD(keyURI string) (*localAEAD, error) {
+	if !c.Supported(keyURI) {
+		return nil, fmt.Errorf("localkms: keyURI must start with prefix %s, but got %s", c.uriPrefix, keyURI)
+	}
+	dir := keyURI[len(localPrefix):]
+	if dir == "" {
+		return nil, fmt.Errorf("localkms: keyURI %s has no directory", keyURI)
+	}
+	versions, err := listVersions(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("localkms: no master key in %s", dir)
+	}
+	version := versions[len(versions)-1]
+
+	// Key derivation is deliberately slow, so it is done under the lock: it
+	// makes concurrent callers wait for one derivation instead of each doing
+	// their own.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.aeads[keyURI]; ok && cached.version == version {
+		return cached.aead, nil
+	}
+	handle, err := readMasterKey(dir, version, c.passphrase)
+	if err != nil {
+		return nil, err
+	}
+	primitive, err := aead.New(handle)
+	if err != nil {
+		return nil, fmt.Errorf("localkms: %v", err)
+	}
+	a := &localAEAD{aead: primitive}
+	c.aeads[keyURI] = &cachedAEAD{version: version, aead: a}
+	return a, nil
+}
+
+// localAEAD is an AEAD backed by a master keyset.
+type localAEAD struct {
+	aead tink.AEAD
+}
+
+var _ tink.AEAD = (*localAEAD)(nil)
+var _ tink.AEADWithContext = (*localAEAD)(nil)
+
+// Encrypt encrypts plaintext with associatedData.
+func (a *localAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	return a.aead.Encrypt(plaintext, associatedData)
+}
+
+// Decrypt decrypts ciphertext with associatedData.
+func (a *localAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	return a.aead.Decrypt(ciphertext, associatedData)
+}
+
+// EncryptWithContext encrypts plaintext with associatedData. It fails if ctx
+// is done.
+func (a *localAEAD) EncryptWithContext(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.aead.Encrypt(plaintext, associatedData)
+}
+
+// DecryptWithContext decrypts ciphertext with associatedData. It fails if ctx
+// is done.
+func (a *localAEAD) DecryptWithContext(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.aead.Decrypt(ciphertext, associatedData)
+}
@@ -0,0 +1,281 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localkms_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/integration/localkms"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+var (
+	passphrase = []byte("correct horse battery staple")
+	// fastKDF keeps the tests fast. Don't use such parameters in production.
+	fastKDF = localkms.WithArgon2id(1, 64, 1)
+)
+
+func mustCreateMasterKey(t *testing.T, opts ...localkms.CreateOption) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "master")
+	if err := localkms.CreateMasterKey(dir, passphrase, opts...); err != nil {
+		t.Fatalf("localkms.CreateMasterKey() err = %v, want nil", err)
+	}
+	return dir
+}
+
+func mustGetAEAD(t *testing.T, dir string) tink.AEAD {
+	t.Helper()
+	client, err := localkms.NewClient("local-kms://", passphrase)
+	if err != nil {
+		t.Fatalf("localkms.NewClient() err = %v, want nil", err)
+	}
+	a, err := client.GetAEAD("local-kms://" + dir)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	return a
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []localkms.CreateOption
+	}{
+		{"default", nil},
+		{"argon2id", []localkms.CreateOption{fastKDF}},
+		{"scrypt", []localkms.CreateOption{localkms.WithScrypt(1<<10, 8, 1)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := mustCreateMasterKey(t, tc.opts...)
+			a := mustGetAEAD(t, dir)
+			plaintext := []byte("plaintext")
+			associatedData := []byte("associatedData")
+			ciphertext, err := a.Encrypt(plaintext, associatedData)
+			if err != nil {
+				t.Fatalf("a.Encrypt() err = %v, want nil", err)
+			}
+			// A new AEAD reads the master key again.
+			decrypted, err := mustGetAEAD(t, dir).Decrypt(ciphertext, associatedData)
+			if err != nil {
+				t.Fatalf("a.Decrypt() err = %v, want nil", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("a.Decrypt() = %q, want %q", decrypted, plaintext)
+			}
+			if _, err := a.Decrypt(ciphertext, []byte("invalid")); err == nil {
+				t.Errorf("a.Decrypt() with invalid associated data err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestKeysetEncryptionWithContext(t *testing.T) {
+	dir := mustCreateMasterKey(t, fastKDF)
+	kek, ok := mustGetAEAD(t, dir).(tink.AEADWithContext)
+	if !ok {
+		t.Fatalf("client.GetAEAD() doesn't implement tink.AEADWithContext")
+	}
+	handle, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	ctx := context.Background()
+	buf := new(bytes.Buffer)
+	associatedData := []byte("keyset associated data")
+	if err := handle.WriteWithContext(ctx, keyset.NewBinaryWriter(buf), kek, associatedData); err != nil {
+		t.Fatalf("handle.WriteWithContext() err = %v, want nil", err)
+	}
+	encrypted := buf.Bytes()
+	got, err := keyset.ReadWithContext(ctx, keyset.NewBinaryReader(bytes.NewReader(encrypted)), kek, associatedData)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithContext() err = %v, want nil", err)
+	}
+	if got.KeysetInfo().GetPrimaryKeyId() != handle.KeysetInfo().GetPrimaryKeyId() {
+		t.Errorf("keyset.ReadWithContext() primary = %v, want %v", got.KeysetInfo().GetPrimaryKeyId(), handle.KeysetInfo().GetPrimaryKeyId())
+	}
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := keyset.ReadWithContext(canceledCtx, keyset.NewBinaryReader(bytes.NewReader(encrypted)), kek, associatedData); err == nil {
+		t.Errorf("keyset.ReadWithContext() with canceled context err = nil, want error")
+	}
+}
+
+func TestRotateMasterKey(t *testing.T) {
+	dir := mustCreateMasterKey(t, fastKDF)
+	oldAEAD := mustGetAEAD(t, dir)
+	plaintext := []byte("plaintext")
+	oldCiphertext, err := oldAEAD.Encrypt(plaintext, nil)
+	if err != nil {
+		t.Fatalf("oldAEAD.Encrypt() err = %v, want nil", err)
+	}
+
+	version, err := localkms.RotateMasterKey(dir, passphrase, localkms.WithScrypt(1<<10, 8, 1))
+	if err != nil {
+		t.Fatalf("localkms.RotateMasterKey() err = %v, want nil", err)
+	}
+	if version != 2 {
+		t.Errorf("localkms.RotateMasterKey() = %v, want 2", version)
+	}
+	versions, err := localkms.Versions(dir)
+	if err != nil {
+		t.Fatalf("localkms.Versions() err = %v, want nil", err)
+	}
+	if diff := cmp.Diff([]int{1, 2}, versions); diff != "" {
+		t.Errorf("localkms.Versions() diff (-want +got):\n%s", diff)
+	}
+
+	newAEAD := mustGetAEAD(t, dir)
+	decrypted, err := newAEAD.Decrypt(oldCiphertext, nil)
+	if err != nil {
+		t.Fatalf("newAEAD.Decrypt() of old ciphertext err = %v, want nil", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("newAEAD.Decrypt() = %q, want %q", decrypted, plaintext)
+	}
+	newCiphertext, err := newAEAD.Encrypt(plaintext, nil)
+	if err != nil {
+		t.Fatalf("newAEAD.Encrypt() err = %v, want nil", err)
+	}
+	// The new primary key is not in the old master keyset.
+	if _, err := oldAEAD.Decrypt(newCiphertext, nil); err == nil {
+		t.Errorf("oldAEAD.Decrypt() of new ciphertext err = nil, want error")
+	}
+}
+
+func TestClientCachesAEADUntilRotation(t *testing.T) {
+	dir := mustCreateMasterKey(t, fastKDF)
+	client, err := localkms.NewClient("local-kms://", passphrase)
+	if err != nil {
+		t.Fatalf("localkms.NewClient() err = %v, want nil", err)
+	}
+	keyURI := "local-kms://" + dir
+	first, err := client.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	second, err := client.GetAEADWithContext(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEADWithContext() err = %v, want nil", err)
+	}
+	if first != second.(tink.AEAD) {
+		t.Errorf("client.GetAEADWithContext() returned a new AEAD, want the cached one")
+	}
+
+	if _, err := localkms.RotateMasterKey(dir, passphrase, fastKDF); err != nil {
+		t.Fatalf("localkms.RotateMasterKey() err = %v, want nil", err)
+	}
+	rotated, err := client.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	if rotated == first {
+		t.Fatalf("client.GetAEAD() after rotation returned the cached AEAD, want a new one")
+	}
+	ciphertext, err := rotated.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("rotated.Encrypt() err = %v, want nil", err)
+	}
+	// The new primary key is not in the first master keyset.
+	if _, err := first.Decrypt(ciphertext, nil); err == nil {
+		t.Errorf("first.Decrypt() of ciphertext under the rotated key err = nil, want error")
+	}
+}
+
+func TestWrongPassphraseFails(t *testing.T) {
+	dir := mustCreateMasterKey(t, fastKDF)
+	client, err := localkms.NewClient("local-kms://", []byte("wrong passphrase"))
+	if err != nil {
+		t.Fatalf("localkms.NewClient() err = %v, want nil", err)
+	}
+	if _, err := client.GetAEAD("local-kms://" + dir); err == nil {
+		t.Errorf("client.GetAEAD() with wrong passphrase err = nil, want error")
+	}
+	if _, err := localkms.RotateMasterKey(dir, []byte("wrong passphrase"), fastKDF); err == nil {
+		t.Errorf("localkms.RotateMasterKey() with wrong passphrase err = nil, want error")
+	}
+}
+
+func TestSwappedVersionFails(t *testing.T) {
+	dir := mustCreateMasterKey(t, fastKDF)
+	content, err := os.ReadFile(filepath.Join(dir, "master-key.1.json"))
+	if err != nil {
+		t.Fatalf("os.ReadFile() err = %v, want nil", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "master-key.2.json"), content, 0600); err != nil {
+		t.Fatalf("os.WriteFile() err = %v, want nil", err)
+	}
+	client, err := localkms.NewClient("local-kms://", passphrase)
+	if err != nil {
+		t.Fatalf("localkms.NewClient() err = %v, want nil", err)
+	}
+	if _, err := client.GetAEAD("local-kms://" + dir); err == nil {
+		t.Errorf("client.GetAEAD() with swapped version err = nil, want error")
+	}
+}
+
+func TestCreateMasterKeyFails(t *testing.T) {
+	dir := mustCreateMasterKey(t, fastKDF)
+	if err := localkms.CreateMasterKey(dir, passphrase, fastKDF); err == nil {
+		t.Errorf("localkms.CreateMasterKey() with existing master key err = nil, want error")
+	}
+	for _, tc := range []struct {
+		name       string
+		passphrase []byte
+		opts       []localkms.CreateOption
+	}{
+		{"empty passphrase", nil, nil},
+		{"zero argon2id time", passphrase, []localkms.CreateOption{localkms.WithArgon2id(0, 64, 1)}},
+		{"small argon2id memory", passphrase, []localkms.CreateOption{localkms.WithArgon2id(1, 8, 2)}},
+		{"scrypt N not a power of 2", passphrase, []localkms.CreateOption{localkms.WithScrypt(1000, 8, 1)}},
+		{"zero scrypt r", passphrase, []localkms.CreateOption{localkms.WithScrypt(1<<10, 0, 1)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := localkms.CreateMasterKey(filepath.Join(t.TempDir(), "master"), tc.passphrase, tc.opts...); err == nil {
+				t.Errorf("localkms.CreateMasterKey() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	if _, err := localkms.NewClient("fake-kms://", passphrase); err == nil {
+		t.Errorf("localkms.NewClient() with wrong prefix err = nil, want error")
+	}
+	if _, err := localkms.NewClient("local-kms://", nil); err == nil {
+		t.Errorf("localkms.NewClient() with empty passphrase err = nil, want error")
+	}
+	client, err := localkms.NewClient("local-kms:///var/lib/tink/", passphrase)
+	if err != nil {
+		t.Fatalf("localkms.NewClient() err = %v, want nil", err)
+	}
+	if !client.Supported("local-kms:///var/lib/tink/master") {
+		t.Errorf("client.Supported() = false, want true")
+	}
+	if client.Supported("local-kms:///tmp/master") {
+		t.Errorf("client.Supported() = true, want false")
+	}
+	if _, err := client.GetAEAD("local-kms:///var/lib/tink/does-not-exist"); err == nil {
+		t.Errorf("client.GetAEAD() with missing directory err = nil, want error")
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localkms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/aead/subtle"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+)
+
+const (
+	kdfArgon2id = "argon2id"
+	kdfScrypt   = "scrypt"
+
+	saltSize       = 16
+	derivedKeySize = 32
+
+	// Upper bounds of the KDF parameters accepted when reading a master key
+	// file, so that a tampered file can't exhaust memory or CPU.
+	maxArgon2idMemoryKiB = 4 << 20
+	maxArgon2idTime      = 64
+	maxScryptN           = 1 << 22
+	maxScryptRP          = 1 << 10
+)
+
+// masterKeyFilePattern matches the file names of the master key versions.
+var masterKeyFilePattern = regexp.MustCompile(`^master-key\.([1-9][0-9]*)\.json$`)
+
+func masterKeyFileName(version int) string {
+	return fmt.Sprintf("master-key.%d.json", version)
+}
+
+// kdfParams are the parameters of the key derivation from the passphrase.
+type kdfParams struct {
+	Algorithm string `json:"algorithm"`
+	Salt      []byte `json:"salt"`
+	// Argon2id parameters.
+	Time      uint32 `json:"time,omitempty"`
+	MemoryKiB uint32 `json:"memoryKiB,omitempty"`
+	Threads   uint8  `json:"threads,omitempty"`
+	// scrypt parameters.
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+}
+
+func (p *kdfParams) validate() error {
+	if len(p.Salt) < saltSize {
+		return fmt.Errorf("salt must be at least %d bytes", saltSize)
+	}
+	switch p.Algorithm {
+	case kdfArgon2id:
+		if p.Time == 0 || p.Time > maxArgon2idTime {
+			return fmt.Errorf("invalid argon2id time %d", p.Time)
+		}
+		if p.Threads == 0 || p.MemoryKiB < 8*uint32(p.Threads) || p.MemoryKiB > maxArgon2idMemoryKiB {
+			return fmt.Errorf("invalid argon2id memory %d KiB with %d threads", p.MemoryKiB, p.Threads)
+		}
+	case kdfScrypt:
+		if p.N <= 1 || p.N&(p.N-1) != 0 || p.N > maxScryptN {
+			return fmt.Errorf("invalid scrypt N %d", p.N)
+		}
+		if p.R <= 0 || p.R > maxScryptRP || p.P <= 0 || p.P > maxScryptRP {
+			return fmt.Errorf("invalid scrypt r %d or p %d", p.R, p.P)
+		}
+	default:
+		return fmt.Errorf("unknown KDF %q", p.Algorithm)
+	}
+	return nil
+}
+
+func (p *kdfParams) deriveKey(passphrase []byte) ([]byte, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	switch p.Algorithm {
+	case kdfArgon2id:
+		return argon2.IDKey(passphrase, p.Salt, p.Time, p.MemoryKiB, p.Threads, derivedKeySize), nil
+	default:
+		return scrypt.Key(passphrase, p.Salt, p.N, p.R, p.P, derivedKeySize)
+	}
+}
+
+// masterKeyFile is the content of a master key file.
+type masterKeyFile struct {
+	Version int       `json:"version"`
+	KDF     kdfParams `json:"kdf"`
+	// Keyset is the binary serialization of the master keyset encrypted with
+	// AES-256-GCM under the key derived from the passphrase.
+	Keyset []byte `json:"keyset"`
+}
+
+// associatedData binds the encrypted keyset to its version, so that versions
+// can't be swapped by renaming files.
+func associatedData(version int) []byte {
+	return []byte("local-kms master key version " + strconv.Itoa(version))
+}
+
+// CreateOption is an option for [CreateMasterKey] and [RotateMasterKey].
+type CreateOption func(*kdfParams) error
+
+// WithArgon2id derives the key encrypting the master keyset with Argon2id
+// (RFC 9106). This is the default, with time = 3, memory = 64 MiB and
+// threads = 4.
+func WithArgon2id(time, memoryKiB uint32, threads uint8) CreateOption {
+	return func(p *kdfParams) error {
+		*p = kdfParams{Algorithm: kdfArgon2id, Salt: p.Salt, Time: time, MemoryKiB: memoryKiB, Threads: threads}
+		return p.validate()
+	}
+}
+
+// WithScrypt derives the key encrypting the master keyset with scrypt
+// (RFC 7914). n must be a power of two, for example 1<<15 with r = 8 and
+// p = 1.
+func WithScrypt(n, r, p int) CreateOption {
+	return func(params *kdfParams) error {
+		*params = kdfParams{Algorithm: kdfScrypt, Salt: params.Salt, N: n, R: r, P: p}
+		return params.validate()
+	}
+}
+
+func newKDFParams(opts []CreateOption) (*kdfParams, error) {
+	p := &kdfParams{
+		Algorithm: kdfArgon2id,
+		Salt:      random.GetRandomBytes(saltSize),
+		Time:      3,
+		MemoryKiB: 64 * 1024,
+		Threads:   4,
+	}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, fmt.Errorf("localkms: %v", err)
+		}
+	}
+	return p, nil
+}
+
+// CreateMasterKey creates version 1 of a new AES-256-GCM master keyset in dir,
+// encrypted under a key derived from passphrase. dir is created if it doesn't
+// exist, and must not already contain a master key.
+func CreateMasterKey(dir string, passphrase []byte, opts ...CreateOption) error {
+	if len(passphrase) == 0 {
+		return fmt.Errorf("localkms: passphrase must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	versions, err := listVersions(dir)
+	if err != nil {
+		return err
+	}
+	if len(versions) != 0 {
+		return fmt.Errorf("localkms: %s already contains a master key", dir)
+	}
+	params, err := newKDFParams(opts)
+	if err != nil {
+		return err
+	}
+	handle, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	return writeMasterKey(dir, 1, handle, passphrase, params)
+}
+
+// RotateMasterKey adds a new AES-256-GCM key to the latest master keyset in
+// dir, makes it primary and writes the result as a new version. The previous
+// keys are kept, so existing ciphertexts can still be decrypted. The new
+// version may use different KDF parameters. It returns the new version.
+func RotateMasterKey(dir string, passphrase []byte, opts ...CreateOption) (int, error) {
+	version, handle, err := readLatestMasterKey(dir, passphrase)
+	if err != nil {
+		return 0, err
+	}
+	manager := keyset.NewManagerFromHandle(handle)
+	keyID, err := manager.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		return 0, fmt.Errorf("localkms: %v", err)
+	}
+	if err := manager.SetPrimary(keyID); err != nil {
+		return 0, fmt.Errorf("localkms: %v", err)
+	}
+	rotated, err := manager.Handle()
+	if err != nil {
+		return 0, fmt.Errorf("localkms: %v", err)
+	}
+	params, err := newKDFParams(opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeMasterKey(dir, version+1, rotated, passphrase, params); err != nil {
+		return 0, err
+	}
+	return version + 1, nil
+}
+
+// Versions returns the versions of the master key in dir, in increasing
+// order.
+func Versions(dir string) ([]int, error) {
+	return listVersions(dir)
+}
+
+func listVersions(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("localkms: %v", err)
+	}
+	var versions []int
+	for _, e := range entries {
+		m := masterKeyFilePattern.FindStringSubmatch(e.Name())
+		if m == nil || !e.Type().IsRegular() {
+			continue
+		}
+		v, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+func writeMasterKey(dir string, version int, handle *keyset.Handle, passphrase []byte, params *kdfParams) error {
+	derivedKey, err := params.deriveKey(passphrase)
+	if err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	kek, err := subtle.NewAESGCM(derivedKey)
+	if err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := handle.WriteWithAssociatedData(keyset.NewBinaryWriter(buf), kek, associatedData(version)); err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	content, err := json.MarshalIndent(&masterKeyFile{
+		Version: version,
+		KDF:     *params,
+		Keyset:  buf.Bytes(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	// Write to a temporary file and hard link it to its final name, which fails
+	// if the version already exists, e.g. because of a concurrent rotation.
+	tmp, err := os.CreateTemp(dir, ".master-key-*.tmp")
+	if err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("localkms: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("localkms: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("localkms: %v", err)
+	}
+	if err := os.Link(tmp.Name(), filepath.Join(dir, masterKeyFileName(version))); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("localkms: master key version %d already exists", version)
+		}
+		return fmt.Errorf("localkms: %v", err)
+	}
+	return nil
+}
+
+func readLatestMasterKey(dir string, passphrase []byte) (int, *keyset.Handle, error) {
+	versions, err := listVersions(dir)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(versions) == 0 {
+		return 0, nil, fmt.Errorf("localkms: no master key in %s", dir)
+	}
+	version := versions[len(versions)-1]
+	handle, err := readMasterKey(dir, version, passphrase)
+	if err != nil {
+		return 0, nil, err
+	}
+	return version, handle, nil
+}
+
+func readMasterKey(dir string, version int, passphrase []byte) (*keyset.Handle, error) {
+	content, err := os.ReadFile(filepath.Join(dir, masterKeyFileName(version)))
+	if err != nil {
+		return nil, fmt.Errorf("localkms: %v", err)
+	}
+	file := new(masterKeyFile)
+	if err := json.Unmarshal(content, file); err != nil {
+		return nil, fmt.Errorf("localkms: invalid master key file: %v", err)
+	}
+	if file.Version != version {
+		return nil, fmt.Errorf("localkms: master key file of version %d contains version %d", version, file.Version)
+	}
+	derivedKey, err := file.KDF.deriveKey(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("localkms: invalid master key file: %v", err)
+	}
+	kek, err := subtle.NewAESGCM(derivedKey)
+	if err != nil {
+		return nil, fmt.Errorf("localkms: %v", err)
+	}
+	handle, err := keyset.ReadWithAssociatedData(keyset.NewBinaryReader(bytes.NewReader(file.Keyset)), kek, associatedData(version))
+	if err != nil {
+		return nil, fmt.Errorf("localkms: cannot decrypt master key version %d, wrong passphrase? %v", version, err)
+	}
+	return handle, nil
+}
@@ -0,0 +1,17 @@
+module github.com/tink-crypto/tink-go/v2/integration/pkcs11
+
+go 1.22
+
+require (
+	github.com/google/go-cmp v0.6.0
+	github.com/miekg/pkcs11 v1.1.1
+	github.com/tink-crypto/tink-go/v2 v2.0.0
+)
+
+require (
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	google.golang.org/protobuf v1.36.0 // indirect
+)
+
+replace github.com/tink-crypto/tink-go/v2 => ../..
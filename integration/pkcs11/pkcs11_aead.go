@@ -0,0 +1,206 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11
+
+import (
+	"fmt"
+	"slices"
+
+	p11 "github.com/miekg/pkcs11"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+const (
+	gcmIVSize  = 12
+	gcmTagSize = 16
+)
+
+// pkcs11AEAD is an AEAD whose operations are done by a PKCS #11 token.
+type pkcs11AEAD struct {
+	ctx       *p11.Ctx
+	slot      uint
+	pin       string
+	hasPIN    bool
+	object    string
+	id        []byte
+	mechanism Mechanism
+}
+
+var _ tink.AEAD = (*pkcs11AEAD)(nil)
+
+// withKey opens a session, logs in and calls f with the handle of the key.
+func (a *pkcs11AEAD) withKey(f func(session p11.SessionHandle, key p11.ObjectHandle) error) error {
+	session, err := a.ctx.OpenSession(a.slot, p11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("cannot open session: %v", err)
+	}
+	defer a.ctx.CloseSession(session)
+	if a.hasPIN {
+		if err := a.ctx.Login(session, p11.CKU_USER, a.pin); err != nil && !isError(err, p11.CKR_USER_ALREADY_LOGGED_IN) {
+			return fmt.Errorf("cannot log in: %v", err)
+		}
+	}
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_SECRET_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_AES),
+	}
+	if a.object != "" {
+		template = append(template, p11.NewAttribute(p11.CKA_LABEL, a.object))
+	}
+	if a.id != nil {
+		template = append(template, p11.NewAttribute(p11.CKA_ID, a.id))
+	}
+	if err := a.ctx.FindObjectsInit(session, template); err != nil {
+		return fmt.Errorf("cannot find key: %v", err)
+	}
+	keys, _, err := a.ctx.FindObjects(session, 2)
+	if finalErr := a.ctx.FindObjectsFinal(session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot find key: %v", err)
+	}
+	if len(keys) != 1 {
+		return fmt.Errorf("found %d AES keys matching the key URI, want exactly 1", len(keys))
+	}
+	return f(session, keys[0])
+}
+
+// Encrypt encrypts plaintext with associatedData inside the token.
+//
+// With AES-GCM, the ciphertext is IV || ciphertext || tag. With AES-KWP, the
+// ciphertext is the wrapped plaintext and associatedData must be empty.
+func (a *pkcs11AEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	var ciphertext []byte
+	err := a.withKey(func(session p11.SessionHandle, key p11.ObjectHandle) error {
+		var err error
+		switch a.mechanism {
+		case AESGCM:
+			ciphertext, err = a.encryptGCM(session, key, plaintext, associatedData)
+		case AESKWP:
+			ciphertext, err = a.wrap(session, key, plaintext, associatedData)
+		default:
+			err = fmt.Errorf("unsupported mechanism %v", a.mechanism)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: encryption failed: %v", err)
+	}
+	return ciphertext, nil
+}
+
+// Decrypt decrypts ciphertext with associatedData inside the token.
+func (a *pkcs11AEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	var plaintext []byte
+	err := a.withKey(func(session p11.SessionHandle, key p11.ObjectHandle) error {
+		var err error
+		switch a.mechanism {
+		case AESGCM:
+			plaintext, err = a.decryptGCM(session, key, ciphertext, associatedData)
+		case AESKWP:
+			plaintext, err = a.unwrap(session, key, ciphertext, associatedData)
+		default:
+			err = fmt.Errorf("unsupported mechanism %v", a.mechanism)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: decryption failed: %v", err)
+	}
+	return plaintext, nil
+}
+
+func (a *pkcs11AEAD) encryptGCM(session p11.SessionHandle, key p11.ObjectHandle, plaintext, associatedData []byte) ([]byte, error) {
+	iv := random.GetRandomBytes(gcmIVSize)
+	params := p11.NewGCMParams(iv, associatedData, 8*gcmTagSize)
+	defer params.Free()
+	if err := a.ctx.EncryptInit(session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_AES_GCM, params)}, key); err != nil {
+		return nil, err
+	}
+	ciphertext, err := a.ctx.Encrypt(session, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	// Some tokens ignore the given IV and generate their own.
+	if actualIV := params.IV(); len(actualIV) == gcmIVSize {
+		iv = actualIV
+	}
+	return slices.Concat(iv, ciphertext), nil
+}
+
+func (a *pkcs11AEAD) decryptGCM(session p11.SessionHandle, key p11.ObjectHandle, ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < gcmIVSize+gcmTagSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	params := p11.NewGCMParams(ciphertext[:gcmIVSize], associatedData, 8*gcmTagSize)
+	defer params.Free()
+	if err := a.ctx.DecryptInit(session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_AES_GCM, params)}, key); err != nil {
+		return nil, err
+	}
+	return a.ctx.Decrypt(session, ciphertext[gcmIVSize:])
+}
+
+// dataObjectTemplate is the template of the temporary session objects that
+// hold the data wrapped with AES-KWP.
+func dataObjectTemplate() []*p11.Attribute {
+	return []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_SECRET_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_GENERIC_SECRET),
+		p11.NewAttribute(p11.CKA_TOKEN, false),
+		p11.NewAttribute(p11.CKA_SENSITIVE, false),
+		p11.NewAttribute(p11.CKA_EXTRACTABLE, true),
+	}
+}
+
+// wrap wraps plaintext by importing it as a temporary generic secret and
+// wrapping it with key.
+func (a *pkcs11AEAD) wrap(session p11.SessionHandle, key p11.ObjectHandle, plaintext, associatedData []byte) ([]byte, error) {
+	if len(associatedData) != 0 {
+		return nil, fmt.Errorf("AES-KWP doesn't support associated data")
+	}
+	if len(plaintext) == 0 {
+		return nil, fmt.Errorf("AES-KWP doesn't support empty plaintexts")
+	}
+	template := append(dataObjectTemplate(), p11.NewAttribute(p11.CKA_VALUE, plaintext))
+	data, err := a.ctx.CreateObject(session, template)
+	if err != nil {
+		return nil, err
+	}
+	defer a.ctx.DestroyObject(session, data)
+	return a.ctx.WrapKey(session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_AES_KEY_WRAP_PAD, nil)}, key, data)
+}
+
+// unwrap unwraps ciphertext into a temporary generic secret and returns its
+// value.
+func (a *pkcs11AEAD) unwrap(session p11.SessionHandle, key p11.ObjectHandle, ciphertext, associatedData []byte) ([]byte, error) {
+	if len(associatedData) != 0 {
+		return nil, fmt.Errorf("AES-KWP doesn't support associated data")
+	}
+	data, err := a.ctx.UnwrapKey(session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_AES_KEY_WRAP_PAD, nil)}, key, ciphertext, dataObjectTemplate())
+	if err != nil {
+		return nil, err
+	}
+	defer a.ctx.DestroyObject(session, data)
+	attrs, err := a.ctx.GetAttributeValue(session, data, []*p11.Attribute{p11.NewAttribute(p11.CKA_VALUE, nil)})
+	if err != nil {
+		return nil, err
+	}
+	if len(attrs) != 1 {
+		return nil, fmt.Errorf("cannot read unwrapped value")
+	}
+	return attrs[0].Value, nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pkcs11 provides an implementation of registry.KMSClient for key
+// encryption keys stored in PKCS #11 tokens, such as HSMs.
+//
+// Keys are identified by PKCS #11 URIs (RFC 7512), for example
+//
+//	pkcs11:token=kek-token;object=my-kek?pin-value=1234
+//
+// The key must be an AES secret key. Encryption and decryption are done inside
+// the token with either AES-GCM (CKM_AES_GCM) or AES key wrap with padding
+// (CKM_AES_KEY_WRAP_PAD, RFC 5649), see [WithMechanism].
+package pkcs11
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	p11 "github.com/miekg/pkcs11"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// Mechanism is the PKCS #11 mechanism used to encrypt with the key.
+type Mechanism int
+
+const (
+	// UnknownMechanism is the default value of Mechanism.
+	UnknownMechanism Mechanism = iota
+	// AESGCM is AES-GCM with a random 96-bit IV and a 128-bit tag. It
+	// authenticates the associated data.
+	AESGCM
+	// AESKWP is AES key wrap with padding (RFC 5649). It does not support
+	// associated data.
+	AESKWP
+)
+
+func (m Mechanism) String() string {
+	switch m {
+	case AESGCM:
+		return "AES-GCM"
+	case AESKWP:
+		return "AES-KWP"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ClientOption is an option for [NewClient].
+type ClientOption func(*pkcs11Client) error
+
+// WithPIN sets the user PIN used to log in to the token. A pin-value in the
+// key URI takes precedence.
+func WithPIN(pin string) ClientOption {
+	return func(c *pkcs11Client) error {
+		c.pin = pin
+		c.hasPIN = true
+		return nil
+	}
+}
+
+// WithMechanism sets the mechanism used to encrypt with the key. The default
+// is [AESGCM].
+func WithMechanism(mechanism Mechanism) ClientOption {
+	return func(c *pkcs11Client) error {
+		if mechanism != AESGCM && mechanism != AESKWP {
+			return fmt.Errorf("unsupported mechanism %v", mechanism)
+		}
+		c.mechanism = mechanism
+		return nil
+	}
+}
+
+var (
+	modulesMu sync.Mutex
+	// modules holds the loaded modules by path. A module must only be
+	// initialized once per process, so modules are shared between clients.
+	modules = make(map[string]*p11.Ctx)
+)
+
+// loadModule loads and initializes the PKCS #11 module at path.
+func loadModule(path string) (*p11.Ctx, error) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	if ctx, ok := modules[path]; ok {
+		return ctx, nil
+	}
+	ctx := p11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("cannot load module %s", path)
+	}
+	if err := ctx.Initialize(); err != nil && !isError(err, p11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("cannot initialize module %s: %v", path, err)
+	}
+	modules[path] = ctx
+	return ctx, nil
+}
+
+func isError(err error, code uint) bool {
+	var p11Err p11.Error
+	return errors.As(err, &p11Err) && uint(p11Err) == code
+}
+
+// pkcs11Client is a client for keys stored in the tokens of a PKCS #11 module.
+type pkcs11Client struct {
+	uriPrefix  string
+	modulePath string
+	ctx        *p11.Ctx
+	pin        string
+	hasPIN     bool
+	mechanism  Mechanism
+}
+
+var _ registry.KMSClient = (*pkcs11Client)(nil)
+
+// NewClient returns a new client for the PKCS #11 module at modulePath, for
+// example /usr/lib/softhsm/libsofthsm2.so. It handles key URIs starting with
+// uriPrefix, which must start with "pkcs11:".
+func NewClient(uriPrefix, modulePath string, opts ...ClientOption) (registry.KMSClient, error) {
+	if !strings.HasPrefix(strings.ToLower(uriPrefix), pkcs11Prefix) {
+		return nil, fmt.Errorf("pkcs11: uriPrefix must start with %s, but got %s", pkcs11Prefix, uriPrefix)
+	}
+	if modulePath == "" {
+		return nil, fmt.Errorf("pkcs11: modulePath must not be empty")
+	}
+	c := &pkcs11Client{
+		uriPrefix:  uriPrefix,
+		modulePath: modulePath,
+		mechanism:  AESGCM,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, fmt.Errorf("pkcs11: %v", err)
+		}
+	}
+	ctx, err := loadModule(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: %v", err)
+	}
+	c.ctx = ctx
+	return c, nil
+}
+
+// Supported returns true if this client does support keyURI.
+func (c *pkcs11Client) Supported(keyURI string) bool {
+	return strings.HasPrefix(keyURI, c.uriPrefix)
+}
+
+// GetAEAD returns an AEAD backed by the key identified by keyURI.
+func (c *pkcs11Client) GetAEAD(keyURI string) (tink.AEAD, error) {
+	if !c.Supported(keyURI) {
+		return nil, fmt.Errorf("pkcs11: keyURI must start with prefix %s, but got %s", c.uriPrefix, keyURI)
+	}
+	uri, err := parseKeyURI(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	if uri.modulePath != "" && uri.modulePath != c.modulePath {
+		return nil, fmt.Errorf("pkcs11: key URI module-path %s doesn't match the client module %s", uri.modulePath, c.modulePath)
+	}
+	slot, err := c.findSlot(uri)
+	if err != nil {
+		return nil, err
+	}
+	pin, hasPIN := c.pin, c.hasPIN
+	if uri.hasPIN {
+		pin, hasPIN = uri.pinValue, true
+	}
+	return &pkcs11AEAD{
+		ctx:       c.ctx,
+		slot:      slot,
+		pin:       pin,
+		hasPIN:    hasPIN,
+		object:    uri.object,
+		id:        uri.id,
+		mechanism: c.mechanism,
+	}, nil
+}
+
+// findSlot returns the slot of the only token that matches uri.
+func (c *pkcs11Client) findSlot(uri *keyURI) (uint, error) {
+	slots, err := c.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: cannot list slots: %v", err)
+	}
+	var matches []uint
+	for _, slot := range slots {
+		if uri.slotID != nil && *uri.slotID != slot {
+			continue
+		}
+		info, err := c.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("pkcs11: cannot get token info of slot %d: %v", slot, err)
+		}
+		if (uri.token != "" && uri.token != info.Label) ||
+			(uri.manufacturer != "" && uri.manufacturer != info.ManufacturerID) ||
+			(uri.model != "" && uri.model != info.Model) ||
+			(uri.serial != "" && uri.serial != info.SerialNumber) {
+			continue
+		}
+		matches = append(matches, slot)
+	}
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("pkcs11: no token matches the key URI")
+	case 1:
+		return matches[0], nil
+	default:
+		return 0, fmt.Errorf("pkcs11: %d tokens match the key URI, want exactly 1", len(matches))
+	}
+}
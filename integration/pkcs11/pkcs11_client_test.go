@@ -0,0 +1,312 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	p11 "github.com/miekg/pkcs11"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/integration/pkcs11"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+)
+
+// The tests below run against SoftHSM2. The module is taken from the
+// SOFTHSM2_MODULE environment variable, or from the usual install locations.
+// Tests that need a token are skipped if the module is not found.
+const (
+	tokenLabel = "tink-test"
+	soPIN      = "5678"
+	userPIN    = "1234"
+	kekLabel   = "tink-test-kek"
+	kekID      = "\x01\x02"
+)
+
+var modulePath string
+
+func findSoftHSM() string {
+	candidates := []string{
+		os.Getenv("SOFTHSM2_MODULE"),
+		"/usr/lib/softhsm/libsofthsm2.so",
+		"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+		"/usr/local/lib/softhsm/libsofthsm2.so",
+		"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+	}
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	return ""
+}
+
+// setUpSoftHSM creates a SoftHSM2 token in a temporary directory, with an
+// AES-256 key that can be used for encryption and for key wrapping.
+func setUpSoftHSM(dir, module string) error {
+	tokenDir := filepath.Join(dir, "tokens")
+	if err := os.Mkdir(tokenDir, 0700); err != nil {
+		return err
+	}
+	conf := filepath.Join(dir, "softhsm2.conf")
+	if err := os.WriteFile(conf, []byte(fmt.Sprintf("directories.tokendir = %s\nobjectstore.backend = file\n", tokenDir)), 0600); err != nil {
+		return err
+	}
+	os.Setenv("SOFTHSM2_CONF", conf)
+
+	ctx := p11.New(module)
+	if ctx == nil {
+		return fmt.Errorf("cannot load %s", module)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return err
+	}
+	slots, err := ctx.GetSlotList(false)
+	if err != nil || len(slots) == 0 {
+		return fmt.Errorf("no slot available: %v", err)
+	}
+	if err := ctx.InitToken(slots[0], soPIN, tokenLabel); err != nil {
+		return err
+	}
+	// SoftHSM2 moves the initialized token to a new slot.
+	slots, err = ctx.GetSlotList(true)
+	if err != nil {
+		return err
+	}
+	var slot uint
+	found := false
+	for _, s := range slots {
+		info, err := ctx.GetTokenInfo(s)
+		if err == nil && info.Label == tokenLabel {
+			slot, found = s, true
+		}
+	}
+	if !found {
+		return fmt.Errorf("token %s not found", tokenLabel)
+	}
+	session, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	if err != nil {
+		return err
+	}
+	defer ctx.CloseSession(session)
+	if err := ctx.Login(session, p11.CKU_SO, soPIN); err != nil {
+		return err
+	}
+	if err := ctx.InitPIN(session, userPIN); err != nil {
+		return err
+	}
+	if err := ctx.Logout(session); err != nil {
+		return err
+	}
+	if err := ctx.Login(session, p11.CKU_USER, userPIN); err != nil {
+		return err
+	}
+	_, err = ctx.GenerateKey(session, []*p11.Mechanism{p11.NewMechanism(p11.CKM_AES_KEY_GEN, nil)}, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_SECRET_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_AES),
+		p11.NewAttribute(p11.CKA_VALUE_LEN, 32),
+		p11.NewAttribute(p11.CKA_TOKEN, true),
+		p11.NewAttribute(p11.CKA_PRIVATE, true),
+		p11.NewAttribute(p11.CKA_SENSITIVE, true),
+		p11.NewAttribute(p11.CKA_EXTRACTABLE, false),
+		p11.NewAttribute(p11.CKA_ENCRYPT, true),
+		p11.NewAttribute(p11.CKA_DECRYPT, true),
+		p11.NewAttribute(p11.CKA_WRAP, true),
+		p11.NewAttribute(p11.CKA_UNWRAP, true),
+		p11.NewAttribute(p11.CKA_LABEL, kekLabel),
+		p11.NewAttribute(p11.CKA_ID, []byte(kekID)),
+	})
+	return err
+}
+
+func TestMain(m *testing.M) {
+	if module := findSoftHSM(); module != "" {
+		dir, err := os.MkdirTemp("", "softhsm")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "os.MkdirTemp() err = %v\n", err)
+			os.Exit(1)
+		}
+		if err := setUpSoftHSM(dir, module); err != nil {
+			fmt.Fprintf(os.Stderr, "setting up SoftHSM2 failed: %v\n", err)
+			os.RemoveAll(dir)
+			os.Exit(1)
+		}
+		modulePath = module
+		code := m.Run()
+		os.RemoveAll(dir)
+		os.Exit(code)
+	}
+	os.Exit(m.Run())
+}
+
+func skipWithoutSoftHSM(t *testing.T) {
+	t.Helper()
+	if modulePath == "" {
+		t.Skip("SoftHSM2 is not installed, set SOFTHSM2_MODULE to run this test")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	skipWithoutSoftHSM(t)
+	for _, tc := range []struct {
+		name           string
+		mechanism      pkcs11.Mechanism
+		keyURI         string
+		associatedData []byte
+	}{
+		{"AES-GCM", pkcs11.AESGCM, "pkcs11:token=" + tokenLabel + ";object=" + kekLabel, []byte("associated data")},
+		{"AES-GCM by id", pkcs11.AESGCM, "pkcs11:token=" + tokenLabel + ";id=%01%02", nil},
+		{"AES-KWP", pkcs11.AESKWP, "pkcs11:token=" + tokenLabel + ";object=" + kekLabel, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := pkcs11.NewClient("pkcs11:", modulePath, pkcs11.WithPIN(userPIN), pkcs11.WithMechanism(tc.mechanism))
+			if err != nil {
+				t.Fatalf("pkcs11.NewClient() err = %v, want nil", err)
+			}
+			a, err := client.GetAEAD(tc.keyURI)
+			if err != nil {
+				t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+			}
+			plaintext := []byte("a data encryption key")
+			ciphertext, err := a.Encrypt(plaintext, tc.associatedData)
+			if err != nil {
+				t.Fatalf("a.Encrypt() err = %v, want nil", err)
+			}
+			decrypted, err := a.Decrypt(ciphertext, tc.associatedData)
+			if err != nil {
+				t.Fatalf("a.Decrypt() err = %v, want nil", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("a.Decrypt() = %q, want %q", decrypted, plaintext)
+			}
+			ciphertext[len(ciphertext)-1] ^= 1
+			if _, err := a.Decrypt(ciphertext, tc.associatedData); err == nil {
+				t.Errorf("a.Decrypt() with modified ciphertext err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestAESKWPRejectsAssociatedData(t *testing.T) {
+	skipWithoutSoftHSM(t)
+	client, err := pkcs11.NewClient("pkcs11:", modulePath, pkcs11.WithPIN(userPIN), pkcs11.WithMechanism(pkcs11.AESKWP))
+	if err != nil {
+		t.Fatalf("pkcs11.NewClient() err = %v, want nil", err)
+	}
+	a, err := client.GetAEAD("pkcs11:token=" + tokenLabel + ";object=" + kekLabel)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), []byte("associated data")); err == nil {
+		t.Errorf("a.Encrypt() with associated data err = nil, want error")
+	}
+}
+
+func TestWrongPINFails(t *testing.T) {
+	skipWithoutSoftHSM(t)
+	client, err := pkcs11.NewClient("pkcs11:", modulePath)
+	if err != nil {
+		t.Fatalf("pkcs11.NewClient() err = %v, want nil", err)
+	}
+	a, err := client.GetAEAD("pkcs11:token=" + tokenLabel + ";object=" + kekLabel + "?pin-value=0000")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err == nil {
+		t.Errorf("a.Encrypt() with wrong PIN err = nil, want error")
+	}
+}
+
+func TestGetAEADFailsForUnknownToken(t *testing.T) {
+	skipWithoutSoftHSM(t)
+	client, err := pkcs11.NewClient("pkcs11:", modulePath, pkcs11.WithPIN(userPIN))
+	if err != nil {
+		t.Fatalf("pkcs11.NewClient() err = %v, want nil", err)
+	}
+	if _, err := client.GetAEAD("pkcs11:token=unknown;object=" + kekLabel); err == nil {
+		t.Errorf("client.GetAEAD() err = nil, want error")
+	}
+	if _, err := client.GetAEAD("pkcs11:token=" + tokenLabel + ";object=" + kekLabel + "?module-path=/other/module.so"); err == nil {
+		t.Errorf("client.GetAEAD() with other module-path err = nil, want error")
+	}
+}
+
+func TestKeyEncryptionKey(t *testing.T) {
+	skipWithoutSoftHSM(t)
+	for _, mechanism := range []pkcs11.Mechanism{pkcs11.AESGCM, pkcs11.AESKWP} {
+		t.Run(mechanism.String(), func(t *testing.T) {
+			client, err := pkcs11.NewClient("pkcs11:", modulePath, pkcs11.WithPIN(userPIN), pkcs11.WithMechanism(mechanism))
+			if err != nil {
+				t.Fatalf("pkcs11.NewClient() err = %v, want nil", err)
+			}
+			kek, err := client.GetAEAD("pkcs11:token=" + tokenLabel + ";object=" + kekLabel)
+			if err != nil {
+				t.Fatalf("client.GetAEAD() err = %v, want nil", err)
+			}
+
+			handle, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+			}
+			buf := new(bytes.Buffer)
+			if err := handle.Write(keyset.NewBinaryWriter(buf), kek); err != nil {
+				t.Fatalf("handle.Write() err = %v, want nil", err)
+			}
+			got, err := keyset.Read(keyset.NewBinaryReader(buf), kek)
+			if err != nil {
+				t.Fatalf("keyset.Read() err = %v, want nil", err)
+			}
+			if got.KeysetInfo().GetPrimaryKeyId() != handle.KeysetInfo().GetPrimaryKeyId() {
+				t.Errorf("keyset.Read() primary = %v, want %v", got.KeysetInfo().GetPrimaryKeyId(), handle.KeysetInfo().GetPrimaryKeyId())
+			}
+
+			envelope := aead.NewKMSEnvelopeAEAD2(aead.AES256GCMKeyTemplate(), kek)
+			plaintext := []byte("plaintext")
+			associatedData := []byte("associatedData")
+			ciphertext, err := envelope.Encrypt(plaintext, associatedData)
+			if err != nil {
+				t.Fatalf("envelope.Encrypt() err = %v, want nil", err)
+			}
+			decrypted, err := envelope.Decrypt(ciphertext, associatedData)
+			if err != nil {
+				t.Fatalf("envelope.Decrypt() err = %v, want nil", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("envelope.Decrypt() = %q, want %q", decrypted, plaintext)
+			}
+		})
+	}
+}
+
+func TestNewClientFails(t *testing.T) {
+	if _, err := pkcs11.NewClient("hcvault://", "/usr/lib/softhsm/libsofthsm2.so"); err == nil {
+		t.Errorf("pkcs11.NewClient() with wrong prefix err = nil, want error")
+	}
+	if _, err := pkcs11.NewClient("pkcs11:", ""); err == nil {
+		t.Errorf("pkcs11.NewClient() with empty module path err = nil, want error")
+	}
+	if _, err := pkcs11.NewClient("pkcs11:", filepath.Join(t.TempDir(), "missing.so")); err == nil {
+		t.Errorf("pkcs11.NewClient() with missing module err = nil, want error")
+	}
+	if _, err := pkcs11.NewClient("pkcs11:", "/usr/lib/softhsm/libsofthsm2.so", pkcs11.WithMechanism(pkcs11.UnknownMechanism)); err == nil {
+		t.Errorf("pkcs11.NewClient() with unknown mechanism err = nil, want error")
+	}
+}
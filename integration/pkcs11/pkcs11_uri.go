@@ -0,0 +1,132 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const pkcs11Prefix = "pkcs11:"
+
+// keyURI is a parsed PKCS #11 URI (RFC 7512) identifying a secret key.
+type keyURI struct {
+	// Token attributes.
+	token        string
+	manufacturer string
+	model        string
+	serial       string
+	slotID       *uint
+	// Object attributes.
+	object string
+	id     []byte
+	// Query attributes.
+	pinValue   string
+	hasPIN     bool
+	modulePath string
+}
+
+// parseKeyURI parses a PKCS #11 URI as defined in RFC 7512. For compatibility
+// with other Tink KMS clients, "pkcs11://" is accepted as well as "pkcs11:".
+func parseKeyURI(uri string) (*keyURI, error) {
+	if !strings.HasPrefix(strings.ToLower(uri), pkcs11Prefix) {
+		return nil, fmt.Errorf("pkcs11: key URI must start with %s, but got %s", pkcs11Prefix, uri)
+	}
+	rest := strings.TrimPrefix(uri[len(pkcs11Prefix):], "//")
+	path, query, _ := strings.Cut(rest, "?")
+	k := &keyURI{}
+	if path != "" {
+		for _, attr := range strings.Split(path, ";") {
+			name, value, err := parseAttribute(attr)
+			if err != nil {
+				return nil, err
+			}
+			switch name {
+			case "token":
+				k.token = value
+			case "manufacturer":
+				k.manufacturer = value
+			case "model":
+				k.model = value
+			case "serial":
+				k.serial = value
+			case "slot-id":
+				slotID, err := strconv.ParseUint(value, 10, 0)
+				if err != nil {
+					return nil, fmt.Errorf("pkcs11: invalid slot-id %q", value)
+				}
+				id := uint(slotID)
+				k.slotID = &id
+			case "object":
+				k.object = value
+			case "id":
+				k.id = []byte(value)
+			case "type":
+				if value != "secret-key" {
+					return nil, fmt.Errorf("pkcs11: unsupported object type %q, want secret-key", value)
+				}
+			case "library-manufacturer", "library-description", "library-version", "slot-manufacturer", "slot-description":
+				// These don't select a token and are ignored.
+			default:
+				if !strings.HasPrefix(name, "x-") {
+					return nil, fmt.Errorf("pkcs11: unknown path attribute %q", name)
+				}
+			}
+		}
+	}
+	if query != "" {
+		for _, attr := range strings.Split(query, "&") {
+			name, value, err := parseAttribute(attr)
+			if err != nil {
+				return nil, err
+			}
+			switch name {
+			case "pin-value":
+				k.pinValue = value
+				k.hasPIN = true
+			case "module-path":
+				k.modulePath = value
+			case "module-name":
+				// The module is configured on the client.
+			case "pin-source":
+				return nil, fmt.Errorf("pkcs11: pin-source is not supported, use the client PIN instead")
+			default:
+				if !strings.HasPrefix(name, "x-") {
+					return nil, fmt.Errorf("pkcs11: unknown query attribute %q", name)
+				}
+			}
+		}
+	}
+	if k.object == "" && k.id == nil {
+		return nil, fmt.Errorf("pkcs11: key URI %q must specify object or id", uri)
+	}
+	return k, nil
+}
+
+// parseAttribute parses and percent-decodes an attribute of the form
+// name=value.
+func parseAttribute(attr string) (string, string, error) {
+	name, value, ok := strings.Cut(attr, "=")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("pkcs11: invalid attribute %q", attr)
+	}
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return "", "", fmt.Errorf("pkcs11: invalid value of attribute %q: %v", name, err)
+	}
+	return strings.ToLower(name), decoded, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestParseKeyURI(t *testing.T) {
+	for _, tc := range []struct {
+		uri  string
+		want *keyURI
+	}{
+		{
+			uri:  "pkcs11:token=kek-token;object=my-kek",
+			want: &keyURI{token: "kek-token", object: "my-kek"},
+		},
+		{
+			uri:  "pkcs11://token=kek-token;object=my-kek",
+			want: &keyURI{token: "kek-token", object: "my-kek"},
+		},
+		{
+			uri:  "pkcs11:token=The%20Token;id=%01%02;type=secret-key?pin-value=1234",
+			want: &keyURI{token: "The Token", id: []byte{1, 2}, pinValue: "1234", hasPIN: true},
+		},
+		{
+			uri:  "pkcs11:manufacturer=SoftHSM%20project;model=SoftHSM%20v2;serial=1234;slot-id=7;object=kek?module-path=/usr/lib/softhsm/libsofthsm2.so",
+			want: &keyURI{manufacturer: "SoftHSM project", model: "SoftHSM v2", serial: "1234", slotID: uintPtr(7), object: "kek", modulePath: "/usr/lib/softhsm/libsofthsm2.so"},
+		},
+		{
+			uri:  "pkcs11:object=kek;x-vendor=value;library-manufacturer=foo?module-name=softhsm2&x-other=1",
+			want: &keyURI{object: "kek"},
+		},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			got, err := parseKeyURI(tc.uri)
+			if err != nil {
+				t.Fatalf("parseKeyURI(%q) err = %v, want nil", tc.uri, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(keyURI{})); diff != "" {
+				t.Errorf("parseKeyURI(%q) diff (-want +got):\n%s", tc.uri, diff)
+			}
+		})
+	}
+}
+
+func TestParseKeyURIFails(t *testing.T) {
+	for _, uri := range []string{
+		"hcvault://vault/transit/keys/kek",
+		"pkcs11:token=kek-token",
+		"pkcs11:",
+		"pkcs11:object=kek;type=private",
+		"pkcs11:object=kek;slot-id=abc",
+		"pkcs11:object=kek;unknown=1",
+		"pkcs11:object=kek;token",
+		"pkcs11:object=%zz",
+		"pkcs11:object=kek?pin-source=file:/etc/pin",
+		"pkcs11:object=kek?unknown=1",
+	} {
+		t.Run(uri, func(t *testing.T) {
+			if _, err := parseKeyURI(uri); err == nil {
+				t.Errorf("parseKeyURI(%q) err = nil, want error", uri)
+			}
+		})
+	}
+}
@@ -15,7 +15,12 @@
 package aead
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/internal/fips140"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
 )
 
 const (
@@ -38,3 +43,52 @@ func CheckPlaintextSize(size uint64) error {
 	}
 	return nil
 }
+
+// AESGCM is AES-GCM with a random 12-byte IV, for packages that cannot use
+// aead/subtle.AESGCM without an import cycle. Ciphertexts are of the form
+// iv || ciphertext || tag.
+type AESGCM struct {
+	cipher cipher.AEAD
+}
+
+// NewAESGCM returns an AESGCM with the given 16 or 32-byte key.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	if err := ValidateAESKeySize(uint32(len(key))); err != nil {
+		return nil, err
+	}
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := fips140.NewGCM(c)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{cipher: gcm}, nil
+}
+
+// Encrypt encrypts plaintext with associatedData.
+func (a *AESGCM) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	if err := CheckPlaintextSize(uint64(len(plaintext))); err != nil {
+		return nil, err
+	}
+	if a.cipher.NonceSize() == 0 {
+		// The cipher generates the IV and prepends it to the ciphertext.
+		return a.cipher.Seal(nil, nil, plaintext, associatedData), nil
+	}
+	iv := random.GetRandomBytes(AESGCMIVSize)
+	dst := make([]byte, 0, AESGCMIVSize+len(plaintext)+AESGCMTagSize)
+	dst = append(dst, iv...)
+	return a.cipher.Seal(dst, iv, plaintext, associatedData), nil
+}
+
+// Decrypt decrypts ciphertext with associatedData.
+func (a *AESGCM) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < AESGCMIVSize+AESGCMTagSize {
+		return nil, fmt.Errorf("ciphertext with size %d is too short", len(ciphertext))
+	}
+	if a.cipher.NonceSize() == 0 {
+		return a.cipher.Open(nil, nil, ciphertext, associatedData)
+	}
+	return a.cipher.Open(nil, ciphertext[:AESGCMIVSize], ciphertext[AESGCMIVSize:], associatedData)
+}
@@ -15,6 +15,7 @@
 package aead_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/tink-crypto/tink-go/v2/internal/aead"
@@ -30,3 +31,37 @@ func TestCheckPlaintextSize(t *testing.T) {
 		t.Errorf("aead.CheckPlaintextSize(%v) err = nil, want error", 1<<60)
 	}
 }
+
+func TestAESGCMEncryptDecrypt(t *testing.T) {
+	for _, keySize := range []int{16, 32} {
+		a, err := aead.NewAESGCM(make([]byte, keySize))
+		if err != nil {
+			t.Fatalf("aead.NewAESGCM() err = %v, want nil", err)
+		}
+		plaintext := []byte("plaintext")
+		associatedData := []byte("associatedData")
+		ciphertext, err := a.Encrypt(plaintext, associatedData)
+		if err != nil {
+			t.Fatalf("a.Encrypt() err = %v, want nil", err)
+		}
+		if got, want := len(ciphertext), aead.AESGCMIVSize+len(plaintext)+aead.AESGCMTagSize; got != want {
+			t.Errorf("len(ciphertext) = %d, want %d", got, want)
+		}
+		got, err := a.Decrypt(ciphertext, associatedData)
+		if err != nil {
+			t.Fatalf("a.Decrypt() err = %v, want nil", err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("a.Decrypt() = %q, want %q", got, plaintext)
+		}
+		if _, err := a.Decrypt(ciphertext, []byte("invalid")); err == nil {
+			t.Error("a.Decrypt() with invalid associated data err = nil, want error")
+		}
+		if _, err := a.Decrypt(ciphertext[:aead.AESGCMIVSize+aead.AESGCMTagSize-1], associatedData); err == nil {
+			t.Error("a.Decrypt() with short ciphertext err = nil, want error")
+		}
+	}
+	if _, err := aead.NewAESGCM(make([]byte, 24)); err == nil {
+		t.Error("aead.NewAESGCM() with 24-byte key err = nil, want error")
+	}
+}
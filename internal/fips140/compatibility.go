@@ -0,0 +1,7 @@
+package fips140
+
+// CompatibleKeyManager is implemented by key managers whose primitives only
+// use FIPS 140-2 approved algorithms.
+type CompatibleKeyManager interface {
+	FIPSCompatible() bool
+}
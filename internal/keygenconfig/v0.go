@@ -25,6 +25,7 @@ import (
 	"github.com/tink-crypto/tink-go/v2/aead/xaesgcm"
 	"github.com/tink-crypto/tink-go/v2/aead/xchacha20poly1305"
 	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/kwp"
 )
 
 var configV0 = mustCreateConfigV0()
@@ -50,6 +51,9 @@ func mustCreateConfigV0() Config {
 	if err := config.RegisterKeyCreator(reflect.TypeFor[*xchacha20poly1305.Parameters](), xchacha20poly1305.KeyCreator(internalapi.Token{})); err != nil {
 		panic(fmt.Sprintf("keygenconfig: failed to register XChaCha20-Poly1305: %v", err))
 	}
+	if err := config.RegisterKeyCreator(reflect.TypeFor[*kwp.Parameters](), kwp.KeyCreator(internalapi.Token{})); err != nil {
+		panic(fmt.Sprintf("keygenconfig: failed to register AES-KWP: %v", err))
+	}
 
 	return *config
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/tink-crypto/tink-go/v2/aead/xchacha20poly1305"
 	"github.com/tink-crypto/tink-go/v2/internal/keygenconfig"
 	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/kwp"
 )
 
 func mustCreateAESGCMParams(t *testing.T, variant aesgcm.Variant) *aesgcm.Parameters {
@@ -94,6 +95,15 @@ func mustCreateXChaCha20Poly1305Params(t *testing.T, variant xchacha20poly1305.V
 	return params
 }
 
+func mustCreateKWPParams(t *testing.T) *kwp.Parameters {
+	t.Helper()
+	params, err := kwp.NewParameters(32)
+	if err != nil {
+		t.Fatalf("kwp.NewParameters() err = %v, want nil", err)
+	}
+	return params
+}
+
 func tryCast[T any](k key.Key) error {
 	if _, ok := k.(T); !ok {
 		return fmt.Errorf("key is of type %T; want %T", k, (*T)(nil))
@@ -181,6 +191,12 @@ func TestV0(t *testing.T) {
 			idRequirement: 0,
 			tryCast:       tryCast[*xchacha20poly1305.Key],
 		},
+		{
+			name:          "AES-KWP",
+			p:             mustCreateKWPParams(t),
+			idRequirement: 0,
+			tryCast:       tryCast[*kwp.Key],
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			key, err := config.CreateKey(tc.p, tc.idRequirement)
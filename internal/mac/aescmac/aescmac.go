@@ -164,3 +164,64 @@ func (c *CMAC) XOREndAndCompute(data, last []byte) ([]byte, error) {
 	c.bc.Encrypt(output, output)
 	return output, nil
 }
+
+// Computation computes an AES-CMAC incrementally.
+type Computation struct {
+	c     *CMAC
+	state [BlockSize]byte
+	// buf holds the last block of the data written so far. It is only processed
+	// once more data is written, as the last block is handled differently.
+	buf      [BlockSize]byte
+	bufLen   int
+	finished bool
+}
+
+// NewComputation returns a new Computation that computes an AES-CMAC with the
+// same key as c.
+func (c *CMAC) NewComputation() *Computation {
+	return &Computation{c: c}
+}
+
+// Write adds data to the computation.
+func (m *Computation) Write(data []byte) (int, error) {
+	if m.finished {
+		return 0, fmt.Errorf("aescmac: computation already finished")
+	}
+	n := len(data)
+	for len(data) > 0 {
+		if m.bufLen == BlockSize {
+			// There is more data, so the buffered block isn't the last one.
+			subtle.XORBytes(m.state[:], m.state[:], m.buf[:])
+			m.c.bc.Encrypt(m.state[:], m.state[:])
+			m.bufLen = 0
+		}
+		copied := copy(m.buf[m.bufLen:], data)
+		m.bufLen += copied
+		data = data[copied:]
+	}
+	return n, nil
+}
+
+// Finish returns the AES-CMAC over all data written. Data can't be written
+// after calling Finish.
+func (m *Computation) Finish() ([]byte, error) {
+	if m.finished {
+		return nil, fmt.Errorf("aescmac: computation already finished")
+	}
+	m.finished = true
+	var lastBlock [BlockSize]byte
+	// The following "if" only depends on the length of the data.
+	if m.bufLen == BlockSize {
+		// Full last block.
+		subtle.XORBytes(lastBlock[:], m.buf[:], m.c.k1[:])
+	} else {
+		// Either empty or partial last block.
+		copy(lastBlock[:], m.buf[:m.bufLen])
+		lastBlock[m.bufLen] = pad
+		subtle.XORBytes(lastBlock[:], lastBlock[:], m.c.k2[:])
+	}
+	output := make([]byte, BlockSize)
+	subtle.XORBytes(output, m.state[:], lastBlock[:])
+	m.c.bc.Encrypt(output, output)
+	return output, nil
+}
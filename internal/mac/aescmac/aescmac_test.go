@@ -204,3 +204,48 @@ func TestAESCMACPRFWycheproofCases(t *testing.T) {
 		}
 	}
 }
+
+func TestComputationMatchesCompute(t *testing.T) {
+	key := random.GetRandomBytes(32)
+	cmac, err := aescmac.New(key)
+	if err != nil {
+		t.Fatalf("aescmac.New() err = %v, want nil", err)
+	}
+	for _, dataSize := range []int{0, 1, 15, 16, 17, 31, 32, 33, 100} {
+		for _, chunkSize := range []int{1, 7, 16, 17, 200} {
+			t.Run(fmt.Sprintf("data_%d_chunk_%d", dataSize, chunkSize), func(t *testing.T) {
+				data := random.GetRandomBytes(uint32(dataSize))
+				computation := cmac.NewComputation()
+				for i := 0; i < len(data); i += chunkSize {
+					if _, err := computation.Write(data[i:min(i+chunkSize, len(data))]); err != nil {
+						t.Fatalf("computation.Write() err = %v, want nil", err)
+					}
+				}
+				got, err := computation.Finish()
+				if err != nil {
+					t.Fatalf("computation.Finish() err = %v, want nil", err)
+				}
+				if want := cmac.Compute(data); !bytes.Equal(got, want) {
+					t.Errorf("computation.Finish() = %x, want %x", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestComputationFailsAfterFinish(t *testing.T) {
+	cmac, err := aescmac.New(random.GetRandomBytes(32))
+	if err != nil {
+		t.Fatalf("aescmac.New() err = %v, want nil", err)
+	}
+	computation := cmac.NewComputation()
+	if _, err := computation.Finish(); err != nil {
+		t.Fatalf("computation.Finish() err = %v, want nil", err)
+	}
+	if _, err := computation.Write([]byte("data")); err == nil {
+		t.Errorf("computation.Write() err = nil, want error")
+	}
+	if _, err := computation.Finish(); err == nil {
+		t.Errorf("computation.Finish() err = nil, want error")
+	}
+}
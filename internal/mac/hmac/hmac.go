@@ -102,3 +102,40 @@ func (h *HMAC) VerifyMAC(mac []byte, data ...[]byte) error {
 	}
 	return errors.New("HMAC: invalid MAC")
 }
+
+// Computation computes an HMAC incrementally.
+type Computation struct {
+	mac      hash.Hash
+	tagSize  uint32
+	finished bool
+}
+
+// NewComputation returns a new Computation that computes an HMAC with the
+// same key and parameters as h.
+func (h *HMAC) NewComputation() (*Computation, error) {
+	if h.HashFunc == nil {
+		return nil, fmt.Errorf("hmac: invalid hash algorithm")
+	}
+	return &Computation{
+		mac:     hmac.New(h.HashFunc, h.key),
+		tagSize: h.tagSize,
+	}, nil
+}
+
+// Write adds data to the computation.
+func (c *Computation) Write(data []byte) (int, error) {
+	if c.finished {
+		return 0, fmt.Errorf("hmac: computation already finished")
+	}
+	return c.mac.Write(data)
+}
+
+// Finish returns the HMAC over all data written. Data can't be written after
+// calling Finish.
+func (c *Computation) Finish() ([]byte, error) {
+	if c.finished {
+		return nil, fmt.Errorf("hmac: computation already finished")
+	}
+	c.finished = true
+	return c.mac.Sum(nil)[:c.tagSize], nil
+}
@@ -206,3 +206,36 @@ func TestHMACTruncation(t *testing.T) {
 		})
 	}
 }
+
+func TestComputationMatchesComputeMAC(t *testing.T) {
+	for _, test := range hmacTests {
+		t.Run(test.desc, func(t *testing.T) {
+			h, err := hmac.New(test.hashAlg, test.key, test.tagSize)
+			if err != nil {
+				t.Fatalf("hmac.New() err = %v, want nil", err)
+			}
+			computation, err := h.NewComputation()
+			if err != nil {
+				t.Fatalf("h.NewComputation() err = %v, want nil", err)
+			}
+			for _, b := range test.data {
+				if _, err := computation.Write([]byte{b}); err != nil {
+					t.Fatalf("computation.Write() err = %v, want nil", err)
+				}
+			}
+			got, err := computation.Finish()
+			if err != nil {
+				t.Fatalf("computation.Finish() err = %v, want nil", err)
+			}
+			if hex.EncodeToString(got) != test.expectedMac {
+				t.Errorf("computation.Finish() = %x, want %s", got, test.expectedMac)
+			}
+			if _, err := computation.Write(test.data); err == nil {
+				t.Errorf("computation.Write() after Finish() err = nil, want error")
+			}
+			if _, err := computation.Finish(); err == nil {
+				t.Errorf("computation.Finish() after Finish() err = nil, want error")
+			}
+		})
+	}
+}
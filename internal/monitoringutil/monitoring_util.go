@@ -91,3 +91,32 @@ func KeysetInfoFromPrimitiveSet[T any](ps *primitiveset.PrimitiveSet[T]) (*monit
 		Entries:      entries,
 	}, nil
 }
+
+// KeysetInfoFromProto creates a `KeysetInfo` from a proto `KeysetInfo` and
+// the monitoring annotations of the keyset.
+func KeysetInfoFromProto(annotations map[string]string, info *tpb.KeysetInfo) (*monitoring.KeysetInfo, error) {
+	if info == nil {
+		return nil, fmt.Errorf("keyset info is nil")
+	}
+	if len(info.GetKeyInfo()) == 0 {
+		return nil, fmt.Errorf("keyset info is empty")
+	}
+	entries := []*monitoring.Entry{}
+	for _, ki := range info.GetKeyInfo() {
+		keyStatus, err := keyStatusFromProto(ki.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &monitoring.Entry{
+			KeyID:     ki.GetKeyId(),
+			Status:    keyStatus,
+			KeyType:   parseKeyTypeURL(ki.GetTypeUrl()),
+			KeyPrefix: ki.GetOutputPrefixType().String(),
+		})
+	}
+	return &monitoring.KeysetInfo{
+		Annotations:  annotations,
+		PrimaryKeyID: info.GetPrimaryKeyId(),
+		Entries:      entries,
+	}, nil
+}
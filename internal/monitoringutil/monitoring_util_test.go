@@ -148,3 +148,63 @@ func TestKeysetInfoFromPrimitiveSet(t *testing.T) {
 		t.Errorf("got = %v, want = %v, with diff: %v", got, want, cmp.Diff(got, want))
 	}
 }
+
+func TestKeysetInfoFromProto(t *testing.T) {
+	info := &tpb.KeysetInfo{
+		PrimaryKeyId: 1,
+		KeyInfo: []*tpb.KeysetInfo_KeyInfo{
+			{
+				KeyId:            1,
+				Status:           tpb.KeyStatusType_ENABLED,
+				TypeUrl:          "type.googleapis.com/google.crypto.tink.AesGcmKey",
+				OutputPrefixType: tpb.OutputPrefixType_TINK,
+			},
+			{
+				KeyId:            2,
+				Status:           tpb.KeyStatusType_DISABLED,
+				TypeUrl:          "type.googleapis.com/google.crypto.tink.AesSivKey",
+				OutputPrefixType: tpb.OutputPrefixType_RAW,
+			},
+		},
+	}
+	annotations := map[string]string{"foo": "bar"}
+	want := &monitoring.KeysetInfo{
+		PrimaryKeyID: 1,
+		Annotations:  annotations,
+		Entries: []*monitoring.Entry{
+			{
+				KeyID:     1,
+				Status:    monitoring.Enabled,
+				KeyType:   "tink.AesGcmKey",
+				KeyPrefix: "TINK",
+			},
+			{
+				KeyID:     2,
+				Status:    monitoring.Disabled,
+				KeyType:   "tink.AesSivKey",
+				KeyPrefix: "RAW",
+			},
+		},
+	}
+	got, err := monitoringutil.KeysetInfoFromProto(annotations, info)
+	if err != nil {
+		t.Fatalf("monitoringutil.KeysetInfoFromProto() err = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("monitoringutil.KeysetInfoFromProto() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestKeysetInfoFromProtoFails(t *testing.T) {
+	for _, info := range []*tpb.KeysetInfo{
+		nil,
+		&tpb.KeysetInfo{},
+		&tpb.KeysetInfo{
+			KeyInfo: []*tpb.KeysetInfo_KeyInfo{{Status: tpb.KeyStatusType_UNKNOWN_STATUS}},
+		},
+	} {
+		if _, err := monitoringutil.KeysetInfoFromProto(nil, info); err == nil {
+			t.Errorf("monitoringutil.KeysetInfoFromProto(nil, %v) err = nil, want error", info)
+		}
+	}
+}
@@ -372,6 +372,17 @@ func UnregisterKeySerializer[K key.Key]() {
 	delete(keySerializers, keyType)
 }
 
+// UnregisterParametersSerializer removes the serializer for the given
+// parameters type from the global registry. If no serializer is registered for
+// the given type, this function does nothing.
+//
+// This function is intended to be used in tests only.
+func UnregisterParametersSerializer[P key.Parameters]() {
+	parametersSerializersMu.Lock()
+	defer parametersSerializersMu.Unlock()
+	delete(parameterSerializers, reflect.TypeOf((*P)(nil)).Elem())
+}
+
 // ClearParametersSerializers clears the global parameters serializers registry.
 //
 // This function is intended to be used in tests only.
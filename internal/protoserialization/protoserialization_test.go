@@ -911,6 +911,25 @@ func TestRegisterParametersSerializerFailsIfAlreadyRegistered(t *testing.T) {
 	}
 }
 
+func TestUnregisterParametersSerializer(t *testing.T) {
+	defer protoserialization.ClearParametersSerializers()
+	err := protoserialization.RegisterParametersSerializer[*testParams](&testParamsSerializer{})
+	if err != nil {
+		t.Fatalf("protoserialization.RegisterParametersSerializer[*testParams](&testParamsSerializer{}) err = %v, want nil", err)
+	}
+	protoserialization.UnregisterParametersSerializer[*testParams]()
+	params := &testParams{
+		hasIDRequirement: true,
+	}
+	if _, err := protoserialization.SerializeParameters(params); err == nil {
+		t.Errorf("protoserialization.SerializeParameters(params) err = nil, want error")
+	}
+	// The serializer can be registered again.
+	if err := protoserialization.RegisterParametersSerializer[*testParams](&testParamsSerializer{}); err != nil {
+		t.Errorf("protoserialization.RegisterParametersSerializer[*testParams](&testParamsSerializer{}) err = %v, want nil", err)
+	}
+}
+
 func TestSerializeParametersFailsIfNoSerializersRegistered(t *testing.T) {
 	defer protoserialization.ClearParametersSerializers()
 	params := &testParams{
@@ -0,0 +1,128 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shamir implements Shamir's secret sharing over GF(2^8).
+//
+// Each byte of the secret is shared independently with a random polynomial of
+// degree threshold-1. A share is the x coordinate, a non-zero byte, followed by
+// the evaluations of the polynomials at x.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// MaxShares is the maximum number of shares of a secret.
+const MaxShares = 255
+
+// mul multiplies a and b in GF(2^8) with the AES reduction polynomial
+// x^8 + x^4 + x^3 + x + 1, without data-dependent branches.
+func mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		a = (a << 1) ^ (-(a >> 7) & 0x1b)
+		b >>= 1
+	}
+	return p
+}
+
+// inv returns the multiplicative inverse of a in GF(2^8), which is a^254.
+// inv(0) is 0.
+func inv(a byte) byte {
+	r := a
+	for i := 0; i < 6; i++ {
+		r = mul(mul(r, r), a)
+	}
+	return mul(r, r)
+}
+
+// Split splits secret into n shares, any threshold of which are enough to
+// recover it with [Combine].
+func Split(secret []byte, n, threshold int) ([][]byte, error) {
+	if threshold < 1 || threshold > n {
+		return nil, fmt.Errorf("shamir: invalid threshold %d for %d shares", threshold, n)
+	}
+	if n > MaxShares {
+		return nil, fmt.Errorf("shamir: at most %d shares are supported, got %d", MaxShares, n)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("shamir: empty secret")
+	}
+	// coefficients[i] holds the coefficients of degree 1 to threshold-1 of the
+	// polynomial sharing secret[i].
+	coefficients := make([]byte, len(secret)*(threshold-1))
+	if _, err := rand.Read(coefficients); err != nil {
+		return nil, fmt.Errorf("shamir: %v", err)
+	}
+	shares := make([][]byte, n)
+	for s := range shares {
+		x := byte(s + 1)
+		share := make([]byte, 1+len(secret))
+		share[0] = x
+		for i, b := range secret {
+			coeffs := coefficients[i*(threshold-1) : (i+1)*(threshold-1)]
+			// Horner's method, from the highest degree coefficient down.
+			var y byte
+			for j := len(coeffs) - 1; j >= 0; j-- {
+				y = mul(y, x) ^ coeffs[j]
+			}
+			share[1+i] = mul(y, x) ^ b
+		}
+		shares[s] = share
+	}
+	return shares, nil
+}
+
+// Combine recovers the secret from shares created by [Split].
+//
+// At least threshold distinct shares must be given. With fewer shares, the
+// result is unrelated to the secret; callers must authenticate it.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("shamir: no shares")
+	}
+	size := len(shares[0])
+	if size < 2 {
+		return nil, errors.New("shamir: invalid share")
+	}
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("shamir: shares have different lengths")
+		}
+		if share[0] == 0 || seen[share[0]] {
+			return nil, errors.New("shamir: invalid or duplicate share index")
+		}
+		seen[share[0]] = true
+	}
+	secret := make([]byte, size-1)
+	for i, share := range shares {
+		// Lagrange basis polynomial for share i, evaluated at 0. Subtraction is
+		// addition in GF(2^8).
+		basis := byte(1)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			basis = mul(basis, mul(other[0], inv(other[0]^share[0])))
+		}
+		for k := range secret {
+			secret[k] ^= mul(basis, share[1+k])
+		}
+	}
+	return secret, nil
+}
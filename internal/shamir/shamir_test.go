@@ -0,0 +1,109 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shamir_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/internal/shamir"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := random.GetRandomBytes(32)
+	for _, tc := range []struct {
+		n, threshold int
+	}{
+		{1, 1}, {3, 1}, {3, 2}, {3, 3}, {5, 3}, {255, 4},
+	} {
+		shares, err := shamir.Split(secret, tc.n, tc.threshold)
+		if err != nil {
+			t.Fatalf("shamir.Split(n=%d, threshold=%d) err = %v, want nil", tc.n, tc.threshold, err)
+		}
+		if len(shares) != tc.n {
+			t.Fatalf("len(shares) = %d, want %d", len(shares), tc.n)
+		}
+		// Every window of threshold consecutive shares recovers the secret.
+		for start := 0; start+tc.threshold <= tc.n; start++ {
+			got, err := shamir.Combine(shares[start : start+tc.threshold])
+			if err != nil {
+				t.Fatalf("shamir.Combine() err = %v, want nil", err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Errorf("shamir.Combine(shares[%d:%d]) (n=%d, threshold=%d) != secret", start, start+tc.threshold, tc.n, tc.threshold)
+			}
+		}
+		// All shares together also recover the secret.
+		got, err := shamir.Combine(shares)
+		if err != nil {
+			t.Fatalf("shamir.Combine() err = %v, want nil", err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("shamir.Combine(all shares) (n=%d, threshold=%d) != secret", tc.n, tc.threshold)
+		}
+		if tc.threshold > 1 {
+			got, err := shamir.Combine(shares[:tc.threshold-1])
+			if err != nil {
+				t.Fatalf("shamir.Combine() err = %v, want nil", err)
+			}
+			if bytes.Equal(got, secret) {
+				t.Errorf("shamir.Combine() with %d shares (threshold=%d) recovered the secret", tc.threshold-1, tc.threshold)
+			}
+		}
+	}
+}
+
+func TestSplitInvalidArguments(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		secret       []byte
+		n, threshold int
+	}{
+		{"zero threshold", []byte("secret"), 3, 0},
+		{"threshold above n", []byte("secret"), 3, 4},
+		{"too many shares", []byte("secret"), 256, 2},
+		{"empty secret", nil, 3, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := shamir.Split(tc.secret, tc.n, tc.threshold); err == nil {
+				t.Error("shamir.Split() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestCombineInvalidShares(t *testing.T) {
+	shares, err := shamir.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("shamir.Split() err = %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		name   string
+		shares [][]byte
+	}{
+		{"no shares", nil},
+		{"duplicate shares", [][]byte{shares[0], shares[0]}},
+		{"zero index", [][]byte{append([]byte{0}, shares[0][1:]...), shares[1]}},
+		{"different lengths", [][]byte{shares[0], shares[1][:3]}},
+		{"too short", [][]byte{{1}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := shamir.Combine(tc.shares); err == nil {
+				t.Error("shamir.Combine() err = nil, want error")
+			}
+		})
+	}
+}
@@ -55,5 +55,16 @@ func (s *RSA_SSA_PKCS1_Signer) Sign(data []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	return s.SignDigest(digest)
+}
+
+// NewHash returns a new instance of the hash function used by the signer.
+func (s *RSA_SSA_PKCS1_Signer) NewHash() hash.Hash {
+	return s.hashFunc()
+}
+
+// SignDigest computes a signature for the given digest, which must have been
+// computed with the hash returned by NewHash.
+func (s *RSA_SSA_PKCS1_Signer) SignDigest(digest []byte) ([]byte, error) {
 	return rsa.SignPKCS1v15(rand.Reader, s.privateKey, s.hashID, digest)
 }
@@ -55,5 +55,16 @@ func (v *RSA_SSA_PKCS1_Verifier) Verify(signature, data []byte) error {
 	if err != nil {
 		return err
 	}
-	return rsa.VerifyPKCS1v15(v.publicKey, v.hashID, hashed, signature)
+	return v.VerifyDigest(signature, hashed)
+}
+
+// NewHash returns a new instance of the hash function used by the verifier.
+func (v *RSA_SSA_PKCS1_Verifier) NewHash() hash.Hash {
+	return v.hashFunc()
+}
+
+// VerifyDigest verifies whether the given signature is valid for the given
+// digest, which must have been computed with the hash returned by NewHash.
+func (v *RSA_SSA_PKCS1_Verifier) VerifyDigest(signature, digest []byte) error {
+	return rsa.VerifyPKCS1v15(v.publicKey, v.hashID, digest, signature)
 }
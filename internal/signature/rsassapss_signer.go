@@ -61,6 +61,16 @@ func (s *RSA_SSA_PSS_Signer) Sign(data []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return rsa.SignPSS(rand.Reader, s.privateKey, s.hashID, digest, &rsa.PSSOptions{SaltLength: s.saltLength})
+	return s.SignDigest(digest)
+}
 
+// NewHash returns a new instance of the hash function used by the signer.
+func (s *RSA_SSA_PSS_Signer) NewHash() hash.Hash {
+	return s.hashFunc()
+}
+
+// SignDigest computes a signature for the given digest, which must have been
+// computed with the hash returned by NewHash.
+func (s *RSA_SSA_PSS_Signer) SignDigest(digest []byte) ([]byte, error) {
+	return rsa.SignPSS(rand.Reader, s.privateKey, s.hashID, digest, &rsa.PSSOptions{SaltLength: s.saltLength})
 }
@@ -61,5 +61,16 @@ func (v *RSA_SSA_PSS_Verifier) Verify(signature, data []byte) error {
 	if err != nil {
 		return err
 	}
+	return v.VerifyDigest(signature, digest)
+}
+
+// NewHash returns a new instance of the hash function used by the verifier.
+func (v *RSA_SSA_PSS_Verifier) NewHash() hash.Hash {
+	return v.hashFunc()
+}
+
+// VerifyDigest verifies whether the given signature is valid for the given
+// digest, which must have been computed with the hash returned by NewHash.
+func (v *RSA_SSA_PSS_Verifier) VerifyDigest(signature, digest []byte) error {
 	return rsa.VerifyPSS(v.publicKey, v.hashID, digest, signature, &rsa.PSSOptions{SaltLength: v.saltLength})
 }
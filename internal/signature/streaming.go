@@ -0,0 +1,104 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"errors"
+	"hash"
+	"slices"
+
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+var errWriterFinished = errors.New("signature: writer already finished")
+
+// digestSigningWriter is a [tink.SigningWriter] for hash-then-sign schemes.
+type digestSigningWriter struct {
+	h        hash.Hash
+	prefix   []byte
+	suffix   []byte
+	sign     func(digest []byte) ([]byte, error)
+	finished bool
+}
+
+var _ tink.SigningWriter = (*digestSigningWriter)(nil)
+
+// NewDigestSigningWriter returns a [tink.SigningWriter] that hashes the data
+// written to it followed by suffix, signs the digest with sign and prepends
+// prefix to the signature.
+func NewDigestSigningWriter(h hash.Hash, prefix, suffix []byte, sign func(digest []byte) ([]byte, error)) tink.SigningWriter {
+	return &digestSigningWriter{
+		h:      h,
+		prefix: prefix,
+		suffix: suffix,
+		sign:   sign,
+	}
+}
+
+func (w *digestSigningWriter) Write(p []byte) (int, error) {
+	if w.finished {
+		return 0, errWriterFinished
+	}
+	return w.h.Write(p)
+}
+
+func (w *digestSigningWriter) Sign() ([]byte, error) {
+	if w.finished {
+		return nil, errWriterFinished
+	}
+	w.finished = true
+	w.h.Write(w.suffix)
+	sig, err := w.sign(w.h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(w.prefix, sig), nil
+}
+
+// digestVerifyingWriter is a [tink.VerifyingWriter] for hash-then-sign schemes.
+type digestVerifyingWriter struct {
+	h        hash.Hash
+	suffix   []byte
+	verify   func(digest []byte) error
+	finished bool
+}
+
+var _ tink.VerifyingWriter = (*digestVerifyingWriter)(nil)
+
+// NewDigestVerifyingWriter returns a [tink.VerifyingWriter] that hashes the
+// data written to it followed by suffix, and checks the digest with verify.
+func NewDigestVerifyingWriter(h hash.Hash, suffix []byte, verify func(digest []byte) error) tink.VerifyingWriter {
+	return &digestVerifyingWriter{
+		h:      h,
+		suffix: suffix,
+		verify: verify,
+	}
+}
+
+func (w *digestVerifyingWriter) Write(p []byte) (int, error) {
+	if w.finished {
+		return 0, errWriterFinished
+	}
+	return w.h.Write(p)
+}
+
+func (w *digestVerifyingWriter) Verify() error {
+	if w.finished {
+		return errWriterFinished
+	}
+	w.finished = true
+	w.h.Write(w.suffix)
+	return w.verify(w.h.Sum(nil))
+}
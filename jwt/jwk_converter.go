@@ -386,6 +386,8 @@ func keysetKeyFromStruct(val *spb.Value, keyID uint32) (*tinkpb.Keyset_Key, erro
 // It requires that all keys in the set have the "alg" field set. Currently, only
 // public keys for algorithms ES256, ES384, ES512, RS256, RS384, and RS512 are supported.
 // JWK is defined in https://www.rfc-editor.org/rfc/rfc7517.txt.
+// Invalid keys, such as RSA moduli that are too small or EC points that are not
+// on the curve, are rejected.
 func JWKSetToPublicKeysetHandle(jwkSet []byte) (*keyset.Handle, error) {
 	jwk := &spb.Struct{}
 	if err := jwk.UnmarshalJSON(jwkSet); err != nil {
@@ -559,6 +561,8 @@ func setKeyID(outKey *spb.Struct, key *tinkpb.Keyset_Key, customKID *string) err
 // JWKSetFromPublicKeysetHandle converts a Tink KeysetHandle with JWT keys into a Json Web Key (JWK) set.
 // Currently only public keys for algorithms ES256, ES384, ES512, RS256, RS384, and RS512 are supported.
 // JWK is defined in https://www.rfc-editor.org/rfc/rfc7517.html.
+// RSA moduli are encoded as unsigned big-endian integers without leading zero
+// bytes, as required by RFC 7518.
 func JWKSetFromPublicKeysetHandle(kh *keyset.Handle) ([]byte, error) {
 	b := &bytes.Buffer{}
 	if err := kh.WriteWithNoSecrets(keyset.NewBinaryWriter(b)); err != nil {
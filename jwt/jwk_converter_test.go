@@ -225,7 +225,7 @@ var jwkSetTestCases = []jwkSetTestCase{
 		jwkSet: `{
 			"keys":[{
 				"kty":"RSA",
-				"n":"AI83_8Uy0v4xS6kDZKqcqzSbeyksy2C67ajtI41J2KMDtO9jUaEAQ9uDhMubjZzPYh1wf_gtJgAC5PSiI3fOLUG0AHCbi_yXVfH3_1U_Yl4b_e8yx_NPyuIvwHwXwE5a32hiss9PuY2-qEivH5LK4AXxPiTiUc9x4gh1OwZaSTYWT7SRO-0ROwYwCwpg4Uf0IMLtmHou_NmNw0uOlOgKfx-EFmMzV-5pspEnwsHq_ijFSxmHNAdy5S0n4u1LIKKmgXJIyUu3AKfAJMydn6nTKzrOcpX0yMnxPq9yP8xKuK_mXysFyNvmS0Sq5c-grOETFeMFScweoUpWVnYOCCSyZ93yAhsTUWnDjZd7iuji9Y7zUo4PWlKXyRRz_aSpxrsn70LOZNLLUjILVeyfCRs2JXptfxCNg3wg6FVAH0xTORmPGICgWDmwOFgP1Y6tW-p0cnK8LwVkuRclyKAMvTtYm9xZZHUSjw86rHEnB2VfsPTIn0_WAVnJ2OAKhuVMtwjB7Q",
+				"n":"jzf_xTLS_jFLqQNkqpyrNJt7KSzLYLrtqO0jjUnYowO072NRoQBD24OEy5uNnM9iHXB_-C0mAALk9KIjd84tQbQAcJuL_JdV8ff_VT9iXhv97zLH80_K4i_AfBfATlrfaGKyz0-5jb6oSK8fksrgBfE-JOJRz3HiCHU7BlpJNhZPtJE77RE7BjALCmDhR_Qgwu2Yei782Y3DS46U6Ap_H4QWYzNX7mmykSfCwer-KMVLGYc0B3LlLSfi7UsgoqaBckjJS7cAp8AkzJ2fqdMrOs5ylfTIyfE-r3I_zEq4r-ZfKwXI2-ZLRKrlz6Cs4RMV4wVJzB6hSlZWdg4IJLJn3fICGxNRacONl3uK6OL1jvNSjg9aUpfJFHP9pKnGuyfvQs5k0stSMgtV7J8JGzYlem1_EI2DfCDoVUAfTFM5GY8YgKBYObA4WA_Vjq1b6nRycrwvBWS5FyXIoAy9O1ib3FlkdRKPDzqscScHZV-w9MifT9YBWcnY4AqG5Uy3CMHt",
 				"e":"AQAB",
 				"use":"sig",
 				"alg":"RS384",
@@ -253,7 +253,7 @@ var jwkSetTestCases = []jwkSetTestCase{
 		jwkSet: `{
 			"keys":[{
 				"kty":"RSA",
-				"n":"AKZtuHAGYy-1Mc78sdp1gOV3jMCJtO7NmhyLSproWcBnqSN1g9mB2EdB22-WLWhB_U_JlZRCdHT6CxPHSid0c9JJc-2CmiV9zU2sVTJUkCytOVS0hrcPEz5JK6a6VVy-Skc_1-I0D2YurXd0aRByDALC8heHMok6VQXW8qwHgRyc0Jr1RcbY-CF_SMlRXn88g4e3bnk1AJiPcmHsJOcwkanwlWxq46DxPv5ff0ruXN4gPDYU-6_J6yZJreYjwrl-LhkqzOkz6e-LE4sdI5WFJQR9cGGRMf4ktgF3kqFtcFNFkGtdOvw5MdLe0eaENDzZ8TZyQDgiHYl878x8uPPpmoeif5af_ZUAsrv_bV-h3RpSoTdTP4SlQMmP-3y2R2LxvUs_CiUahoVFwTt_bRHO0Qy-QwpTvAdJX8CzrK2auqycFawYm8xYjj_epTFSwBCJuZjamxpZSa29zTDqP4AXwt2-9LO-70j5muzDQL35czpBgaXSAEJkrM9du91OjkJ2vtYFVLjWougN5uVpEBx1Isk_KgreOgl3lF1vs2EjTuihaxJhM-17alJLmDL06ZEDsht2Uhu_ZExEfPwTKaR_-kfjlamuoLUvTtVhzNZuOHD_XAOrGafMjM9WVq_D5XjqF7WFnb_t4YIOQNmGeOeIFLb4LlR5nHB1HIHUpAWazrvl",
+				"n":"pm24cAZjL7Uxzvyx2nWA5XeMwIm07s2aHItKmuhZwGepI3WD2YHYR0Hbb5YtaEH9T8mVlEJ0dPoLE8dKJ3Rz0klz7YKaJX3NTaxVMlSQLK05VLSGtw8TPkkrprpVXL5KRz_X4jQPZi6td3RpEHIMAsLyF4cyiTpVBdbyrAeBHJzQmvVFxtj4IX9IyVFefzyDh7dueTUAmI9yYewk5zCRqfCVbGrjoPE-_l9_Su5c3iA8NhT7r8nrJkmt5iPCuX4uGSrM6TPp74sTix0jlYUlBH1wYZEx_iS2AXeSoW1wU0WQa106_Dkx0t7R5oQ0PNnxNnJAOCIdiXzvzHy48-mah6J_lp_9lQCyu_9tX6HdGlKhN1M_hKVAyY_7fLZHYvG9Sz8KJRqGhUXBO39tEc7RDL5DClO8B0lfwLOsrZq6rJwVrBibzFiOP96lMVLAEIm5mNqbGllJrb3NMOo_gBfC3b70s77vSPma7MNAvflzOkGBpdIAQmSsz1273U6OQna-1gVUuNai6A3m5WkQHHUiyT8qCt46CXeUXW-zYSNO6KFrEmEz7XtqUkuYMvTpkQOyG3ZSG79kTER8_BMppH_6R-OVqa6gtS9O1WHM1m44cP9cA6sZp8yMz1ZWr8PleOoXtYWdv-3hgg5A2YZ454gUtvguVHmccHUcgdSkBZrOu-U",
 				"e":"AQAB",
 				"use":"sig",
 				"alg":"RS512",
@@ -281,7 +281,7 @@ var jwkSetTestCases = []jwkSetTestCase{
 		jwkSet: `{
 			"keys":[{
 				"kty":"RSA",
-				"n":"AImrUP3PDttint7alBxKexY-Oe4nCj0TOZ06yuKgq7UQu-3Gc8KJyQHO5SzPlMBy6FjcWqOzz-kkNm9sej3AsdGhTJCcOCYDoLgArYCaMQoMLOOjMQJTVbHeiPpyVgHzvpG9Xw_IVNPbRJhsT4mzqHuyopUEEexVQcFo6F3U8zE1kppxzoMvIiz5-Zm6dFX8EozolMD2TLDh4NZFAb-6uJs8TYzS8Od6V0BVh1CfHL1CuIpvIirkgki2RGXNE1r57bhJfMZUWtqAUXb5SM2IFhLUcgGLV-PfxP2cxcJ7HHhk5-lFf5794CmqcFa4mliR2tJRnhUR2vmlgxqUjzwK3HE",
+				"n":"iatQ_c8O22Ke3tqUHEp7Fj457icKPRM5nTrK4qCrtRC77cZzwonJAc7lLM-UwHLoWNxao7PP6SQ2b2x6PcCx0aFMkJw4JgOguACtgJoxCgws46MxAlNVsd6I-nJWAfO-kb1fD8hU09tEmGxPibOoe7KilQQR7FVBwWjoXdTzMTWSmnHOgy8iLPn5mbp0VfwSjOiUwPZMsOHg1kUBv7q4mzxNjNLw53pXQFWHUJ8cvUK4im8iKuSCSLZEZc0TWvntuEl8xlRa2oBRdvlIzYgWEtRyAYtX49_E_ZzFwnsceGTn6UV_nv3gKapwVriaWJHa0lGeFRHa-aWDGpSPPArccQ",
 				"e":"AQAB",
 				"use":"sig",
 				"alg":"RS256",
@@ -509,21 +509,15 @@ func TestJWKSetToPublicKeysetPrimitivePS256SmallModulusFails(t *testing.T) {
 		 "kid":"DfpE4Q"
 		}]
 	}`
-	// Keys in the keyset are validated when the primitive is generated.
-	// JWKSetToPublicKeysetHandle doesn't fail, but NewVerifier will fail.
-	pubHandle, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk))
-	if err != nil {
-		t.Fatalf("jwt.JWKSetToPublicKeysetHandle() err = %v, want nil", err)
-	}
-	if _, err := jwt.NewVerifier(pubHandle); err == nil {
-		t.Errorf("jwt.NewVerifier() err = nil, want error")
+	if _, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk)); err == nil {
+		t.Errorf("jwt.JWKSetToPublicKeysetHandle() err = nil, want error")
 	}
 }
 
 func TestJWKSetToPublicKeysetPS256CorrectlySetsKID(t *testing.T) {
 	jwkSet := `{"keys":[
       {"kty":"RSA",
-       "n":"AQAB",
+       "n":"vmUOa62TYrxj7N8rZVAzoEdSnmsRQaNWBMAdB8adGa8n4ycGiYWoGv0uZWc8vH2jn6l3Pa_72bb2IHf3-KD2UaTwLk1x3yShXybEoS5ZF9bemzrn2ohNixGoN7Ofj7wPb61Z-F1Nv53nq308z-RI1WeyIH-9HjuIcuUxaWY0VevsXzCehMJP5g7kVzyl55bYcRi28didkVazrzVgNG35yNNMEL32oW1Vfvvp7hfQHtxSwkFOPzJgzIPHbJFbxALGrrgXHsoq7UtDQdS9vvoEp4_JzQhCtnCEKahgkTwOWyT96OlRGYiPJSFHWTujy1Qnd6OKc8LGEspAX4oD6Zl-YQ",
        "e":"AQAB",
        "use":"sig",
        "alg":"PS256",
@@ -554,7 +548,7 @@ func TestJWKSetToPublicKeysetPS256CorrectlySetsKID(t *testing.T) {
 func TestJWKSetToPublicKeysetPS256WithoutOptionalFieldsSucceeds(t *testing.T) {
 	jwkSet := `{"keys":[
       {"kty":"RSA",
-       "n":"AQAB",
+       "n":"vmUOa62TYrxj7N8rZVAzoEdSnmsRQaNWBMAdB8adGa8n4ycGiYWoGv0uZWc8vH2jn6l3Pa_72bb2IHf3-KD2UaTwLk1x3yShXybEoS5ZF9bemzrn2ohNixGoN7Ofj7wPb61Z-F1Nv53nq308z-RI1WeyIH-9HjuIcuUxaWY0VevsXzCehMJP5g7kVzyl55bYcRi28didkVazrzVgNG35yNNMEL32oW1Vfvvp7hfQHtxSwkFOPzJgzIPHbJFbxALGrrgXHsoq7UtDQdS9vvoEp4_JzQhCtnCEKahgkTwOWyT96OlRGYiPJSFHWTujy1Qnd6OKc8LGEspAX4oD6Zl-YQ",
        "e":"AQAB",
        "alg":"PS256"
       }]}`
@@ -698,21 +692,15 @@ func TestJWKSetToPublicKeysetPrimitiveRS256SmallModulusFails(t *testing.T) {
 		 "kid":"DfpE4Q"
 		}]
 	}`
-	// Keys in the keyset are validated when the primitive is generated.
-	// JWKSetToPublicKeysetHandle but NewVerifier will fail.
-	pubHandle, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk))
-	if err != nil {
-		t.Fatalf("jwt.JWKSetToPublicKeysetHandle() err = %v, want nil", err)
-	}
-	if _, err := jwt.NewVerifier(pubHandle); err == nil {
-		t.Errorf("jwt.NewVerifier() err = nil, want error")
+	if _, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk)); err == nil {
+		t.Errorf("jwt.JWKSetToPublicKeysetHandle() err = nil, want error")
 	}
 }
 
 func TestJWKSetToPublicKeysetRS256CorrectlySetsKID(t *testing.T) {
 	jwkSet := `{"keys":[
       {"kty":"RSA",
-       "n":"AQAB",
+       "n":"vmUOa62TYrxj7N8rZVAzoEdSnmsRQaNWBMAdB8adGa8n4ycGiYWoGv0uZWc8vH2jn6l3Pa_72bb2IHf3-KD2UaTwLk1x3yShXybEoS5ZF9bemzrn2ohNixGoN7Ofj7wPb61Z-F1Nv53nq308z-RI1WeyIH-9HjuIcuUxaWY0VevsXzCehMJP5g7kVzyl55bYcRi28didkVazrzVgNG35yNNMEL32oW1Vfvvp7hfQHtxSwkFOPzJgzIPHbJFbxALGrrgXHsoq7UtDQdS9vvoEp4_JzQhCtnCEKahgkTwOWyT96OlRGYiPJSFHWTujy1Qnd6OKc8LGEspAX4oD6Zl-YQ",
        "e":"AQAB",
        "use":"sig",
        "alg":"RS256",
@@ -743,7 +731,7 @@ func TestJWKSetToPublicKeysetRS256CorrectlySetsKID(t *testing.T) {
 func TestJWKSetToPublicKeysetRS256WithoutOptionalFieldsSucceeds(t *testing.T) {
 	jwkSet := `{"keys":[
       {"kty":"RSA",
-       "n":"AQAB",
+       "n":"vmUOa62TYrxj7N8rZVAzoEdSnmsRQaNWBMAdB8adGa8n4ycGiYWoGv0uZWc8vH2jn6l3Pa_72bb2IHf3-KD2UaTwLk1x3yShXybEoS5ZF9bemzrn2ohNixGoN7Ofj7wPb61Z-F1Nv53nq308z-RI1WeyIH-9HjuIcuUxaWY0VevsXzCehMJP5g7kVzyl55bYcRi28didkVazrzVgNG35yNNMEL32oW1Vfvvp7hfQHtxSwkFOPzJgzIPHbJFbxALGrrgXHsoq7UtDQdS9vvoEp4_JzQhCtnCEKahgkTwOWyT96OlRGYiPJSFHWTujy1Qnd6OKc8LGEspAX4oD6Zl-YQ",
        "e":"AQAB",
        "alg":"RS256"
       }]}`
@@ -886,14 +874,8 @@ func TestJWKSetToPublicKeysetES256WithSmallXPrimitiveFails(t *testing.T) {
     "use":"sig","alg":"ES256","key_ops":["verify"]}],
     "kid":"EhuduQ"
   }`
-	// Keys in the keyset are validated when the primitive is generated.
-	// JWKSetToPublicKeysetHandle but NewVerifier will fail.
-	pubHandle, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk))
-	if err != nil {
-		t.Fatalf("jwt.JWKSetToPublicKeysetHandle() err = %v, want nil", err)
-	}
-	if _, err := jwt.NewVerifier(pubHandle); err == nil {
-		t.Errorf("jwt.NewVerifier() err = nil, want error")
+	if _, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk)); err == nil {
+		t.Errorf("jwt.JWKSetToPublicKeysetHandle() err = nil, want error")
 	}
 }
 
@@ -907,14 +889,8 @@ func TestJWKSetToPublicKeysetES256WithSmallYFails(t *testing.T) {
     "use":"sig","alg":"ES256","key_ops":["verify"]}],
     "kid":"EhuduQ"
   }`
-	// Keys in the keyset are validated when the primitive is generated.
-	// JWKSetToPublicKeysetHandle but NewVerifier will fail.
-	pubHandle, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk))
-	if err != nil {
-		t.Fatalf("jwt.JWKSetToPublicKeysetHandle() err = %v, want nil", err)
-	}
-	if _, err := jwt.NewVerifier(pubHandle); err == nil {
-		t.Errorf("jwt.NewVerifier() err = nil, want error")
+	if _, err := jwt.JWKSetToPublicKeysetHandle([]byte(jwk)); err == nil {
+		t.Errorf("jwt.JWKSetToPublicKeysetHandle() err = nil, want error")
 	}
 }
 
@@ -1343,6 +1319,77 @@ func TestJWKSetFromPublicKeysetHandleTinkOutputPrefixHasKID(t *testing.T) {
 }
 
 func TestJWKSetFromPublicKeysetHandleInvalidKeysetsFails(t *testing.T) {
+	for _, tc := range []jwkSetTestCase{
+		{
+			tag: "unknown key type",
+			publicKeyset: `{
+      "primaryKeyId": 303799737,
+      "key": [
+          {
+              "keyId": 303799737,
+              "status": "ENABLED",
+              "outputPrefixType": "TINK",
+              "keyData": {
+                  "typeUrl": "type.googleapis.com/google.crypto.tink.Unknown",
+                  "keyMaterialType": "ASYMMETRIC_PUBLIC",
+                  "value": "IiDuhGJiGeaQ/qeqt1daC2xZRarm4VEsmSHJUWJY9EHbvxogwO6uIxh8SkKOO8VjZXNRTteRcwCPE4/4JElKyaa0fcQQAQ=="
+              }
+          }
+      ]
+  }`,
+		},
+		{
+			tag: "private ecdsa keyset",
+			publicKeyset: `{
+				"primaryKeyId": 134562784,
+				"key": [
+					{
+						"keyId": 134562784,
+						"status": "ENABLED",
+						"outputPrefixType": "TINK",
+						"keyData": {
+							"typeUrl": "type.googleapis.com/google.crypto.tink.JwtEcdsaPrivateKey",
+							"keyMaterialType": "ASYMMETRIC_PRIVATE",
+							"value": "EkgQARohALWntTjGJFD3KRuXFc5rw8fdfnY8ubqS5OKt4NqWeVaKIiEAWG0Wab1E7vYO0/Obradas8qjdsHgU001R8Bm6LF9OokaIQCIrpGJseO562pHyPeFFXCvidplCKXt6eTaD2jp/65h+A=="
+						}
+					}
+				]
+			}`,
+		},
+		{
+			tag: "hmac keyset",
+			publicKeyset: `{
+				"primaryKeyId": 3463846608,
+				"key": [
+					{
+						"keyId": 3463846608,
+						"status": "ENABLED",
+						"outputPrefixType": "TINK",
+						"keyData": {
+							"typeUrl": "type.googleapis.com/google.crypto.tink.JwtHmacKey",
+							"keyMaterialType": "SYMMETRIC",
+							"value": "EAEaIGGy1Ln34kXRnKbTTzW07w2QHBRZdkIaS6fgMmsMme6H"
+						}
+					}
+				]
+			}`,
+		},
+	} {
+		t.Run(tc.tag, func(t *testing.T) {
+			handle, err := createKeysetHandle(tc.publicKeyset)
+			if err != nil {
+				t.Fatalf("createKeysetHandle() err = %v, want nil", err)
+			}
+			if _, err := jwt.JWKSetFromPublicKeysetHandle(handle); err == nil {
+				t.Errorf("jwt.JWKSetFromPublicKeysetHandle() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestCreateKeysetHandleWithInvalidJWTPublicKeysFails(t *testing.T) {
+	// JWT keys are parsed when the keyset handle is created, so keysets with
+	// invalid JWT keys are rejected before they can be converted.
 	for _, tc := range []jwkSetTestCase{
 		{
 			tag: "invalid output prefix",
@@ -1381,7 +1428,7 @@ func TestJWKSetFromPublicKeysetHandleInvalidKeysetsFails(t *testing.T) {
 		}`,
 		},
 		{
-			tag: "private ecdsa keyset",
+			tag: "public key with private key material type",
 			publicKeyset: `{
       "primaryKeyId": 303799737,
       "key": [
@@ -1396,24 +1443,6 @@ func TestJWKSetFromPublicKeysetHandleInvalidKeysetsFails(t *testing.T) {
               }
           }
       ]
-  }`,
-		},
-		{
-			tag: "unknown key type",
-			publicKeyset: `{
-      "primaryKeyId": 303799737,
-      "key": [
-          {
-              "keyId": 303799737,
-              "status": "ENABLED",
-              "outputPrefixType": "TINK",
-              "keyData": {
-                  "typeUrl": "type.googleapis.com/google.crypto.tink.Unknown",
-                  "keyMaterialType": "ASYMMETRIC_PUBLIC",
-                  "value": "IiDuhGJiGeaQ/qeqt1daC2xZRarm4VEsmSHJUWJY9EHbvxogwO6uIxh8SkKOO8VjZXNRTteRcwCPE4/4JElKyaa0fcQQAQ=="
-              }
-          }
-      ]
   }`,
 		},
 		{
@@ -1436,12 +1465,8 @@ func TestJWKSetFromPublicKeysetHandleInvalidKeysetsFails(t *testing.T) {
 		},
 	} {
 		t.Run(tc.tag, func(t *testing.T) {
-			handle, err := createKeysetHandle(tc.publicKeyset)
-			if err != nil {
-				t.Fatalf("createKeysetHandle() err = %v, want nil", err)
-			}
-			if _, err := jwt.JWKSetFromPublicKeysetHandle(handle); err == nil {
-				t.Errorf("jwt.JWKSetFromPublicKeysetHandle() err = nil, want error")
+			if _, err := createKeysetHandle(tc.publicKeyset); err == nil {
+				t.Errorf("createKeysetHandle() err = nil, want error")
 			}
 		})
 	}
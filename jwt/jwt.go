@@ -13,6 +13,12 @@
 // limitations under the License.
 
 // Package jwt implements a subset of JSON Web Token (JWT) as defined by RFC 7519 (https://tools.ietf.org/html/rfc7519) that is considered safe and most often used.
+//
+// Importing this package registers parsers for the JWT keys, so JWT keys are
+// validated when a keyset handle is created. Keysets that contain an invalid
+// JWT key, e.g. an RSA modulus that is too small, an EC point that is not on
+// the curve, an unknown algorithm, a LEGACY output prefix or a TINK key with a
+// custom kid, fail to load.
 package jwt
 
 import (
@@ -20,6 +26,10 @@ import (
 	"fmt"
 
 	"github.com/tink-crypto/tink-go/v2/core/registry"
+	_ "github.com/tink-crypto/tink-go/v2/jwt/jwtecdsa"       // To register the JWT ECDSA parsers and serializers.
+	_ "github.com/tink-crypto/tink-go/v2/jwt/jwthmac"        // To register the JWT HMAC parsers and serializers.
+	_ "github.com/tink-crypto/tink-go/v2/jwt/jwtrsassapkcs1" // To register the JWT RSA-SSA-PKCS1 parsers and serializers.
+	_ "github.com/tink-crypto/tink-go/v2/jwt/jwtrsassapss"   // To register the JWT RSA-SSA-PSS parsers and serializers.
 )
 
 // A generic error returned when something went wrong before validation
@@ -0,0 +1,60 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"github.com/tink-crypto/tink-go/v2/keyset"
+)
+
+// DetachedSigner is the interface for signing arbitrary payloads with a JWT
+// signature key, without including the payload in the output.
+//
+// The payload is signed unencoded, with the "b64" header set to false, as
+// defined in RFC 7797. The output is in the JWS compact serialization with a
+// detached payload (RFC 7515, appendix F), that is "<header>..<signature>".
+type DetachedSigner interface {
+	// Signs payload and returns the JWS with the payload detached.
+	SignDetached(payload []byte) (string, error)
+}
+
+// DetachedVerifier is the interface for verifying JWS with detached,
+// unencoded payloads created by a DetachedSigner.
+type DetachedVerifier interface {
+	// Verifies that detached is a valid JWS for payload.
+	//
+	// The header must contain "b64": false and "crit": ["b64"]. Apart from that,
+	// the same header rules as for VerifyAndDecode apply.
+	VerifyDetached(detached string, payload []byte) error
+}
+
+// NewDetachedSigner generates a new instance of the DetachedSigner primitive.
+//
+// The primary key of handle is used for signing, in the same way as by NewSigner.
+func NewDetachedSigner(handle *keyset.Handle) (DetachedSigner, error) {
+	s, err := newWrappedSignerFromHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewDetachedVerifier generates a new instance of the DetachedVerifier primitive.
+func NewDetachedVerifier(handle *keyset.Handle) (DetachedVerifier, error) {
+	v, err := newWrappedVerifierFromHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/jwt"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func TestSignAndVerifyDetached(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"ES256", jwt.ES256Template()},
+		{"RawES256", jwt.RawES256Template()},
+		{"PS256", jwt.PS256_2048_F4_Key_Template()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			privateHandle, publicHandle := mustCreatePrivateAndPublicHandles(t, tc.template)
+			signer, err := jwt.NewDetachedSigner(privateHandle)
+			if err != nil {
+				t.Fatalf("jwt.NewDetachedSigner() err = %v, want nil", err)
+			}
+			verifier, err := jwt.NewDetachedVerifier(publicHandle)
+			if err != nil {
+				t.Fatalf("jwt.NewDetachedVerifier() err = %v, want nil", err)
+			}
+			payload := []byte("a large request body, with . and non-base64 characters")
+			detached, err := signer.SignDetached(payload)
+			if err != nil {
+				t.Fatalf("signer.SignDetached() err = %v, want nil", err)
+			}
+			parts := strings.Split(detached, ".")
+			if len(parts) != 3 || parts[1] != "" {
+				t.Fatalf("signer.SignDetached() = %q, want <header>..<signature>", detached)
+			}
+			jsonHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+			if err != nil {
+				t.Fatalf("base64.RawURLEncoding.DecodeString() err = %v, want nil", err)
+			}
+			var header map[string]any
+			if err := json.Unmarshal(jsonHeader, &header); err != nil {
+				t.Fatalf("json.Unmarshal() err = %v, want nil", err)
+			}
+			if header["b64"] != false {
+				t.Errorf("header[\"b64\"] = %v, want false", header["b64"])
+			}
+			if crit, ok := header["crit"].([]any); !ok || len(crit) != 1 || crit[0] != "b64" {
+				t.Errorf("header[\"crit\"] = %v, want [b64]", header["crit"])
+			}
+
+			if err := verifier.VerifyDetached(detached, payload); err != nil {
+				t.Errorf("verifier.VerifyDetached() err = %v, want nil", err)
+			}
+			if err := verifier.VerifyDetached(detached, []byte("another payload")); err == nil {
+				t.Errorf("verifier.VerifyDetached() with modified payload err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestVerifyDetachedRejectsCompactTokens(t *testing.T) {
+	privateHandle, publicHandle := mustCreatePrivateAndPublicHandles(t, jwt.ES256Template())
+	signer, err := jwt.NewSigner(privateHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewSigner() err = %v, want nil", err)
+	}
+	compact, err := signer.SignAndEncode(mustCreateRawJWT(t))
+	if err != nil {
+		t.Fatalf("signer.SignAndEncode() err = %v, want nil", err)
+	}
+	verifier, err := jwt.NewDetachedVerifier(publicHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewDetachedVerifier() err = %v, want nil", err)
+	}
+	parts := strings.Split(compact, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("base64.RawURLEncoding.DecodeString() err = %v, want nil", err)
+	}
+	for _, detached := range []string{compact, parts[0] + ".." + parts[2]} {
+		if err := verifier.VerifyDetached(detached, payload); err == nil {
+			t.Errorf("verifier.VerifyDetached(%q) err = nil, want error", detached)
+		}
+	}
+}
+
+func TestVerifyDetachedWithDifferentKeyFails(t *testing.T) {
+	privateHandle, _ := mustCreatePrivateAndPublicHandles(t, jwt.ES256Template())
+	_, otherPublicHandle := mustCreatePrivateAndPublicHandles(t, jwt.ES256Template())
+	signer, err := jwt.NewDetachedSigner(privateHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewDetachedSigner() err = %v, want nil", err)
+	}
+	detached, err := signer.SignDetached([]byte("payload"))
+	if err != nil {
+		t.Fatalf("signer.SignDetached() err = %v, want nil", err)
+	}
+	verifier, err := jwt.NewDetachedVerifier(otherPublicHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewDetachedVerifier() err = %v, want nil", err)
+	}
+	if err := verifier.VerifyDetached(detached, []byte("payload")); err == nil {
+		t.Errorf("verifier.VerifyDetached() err = nil, want error")
+	}
+}
@@ -144,6 +144,12 @@ func (km *jwtECDSASignerKeyManager) TypeURL() string {
 	return jwtECDSASignerTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *jwtECDSASignerKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 func (km *jwtECDSASignerKeyManager) validateKey(key *jepb.JwtEcdsaPrivateKey) (ecdsaParams, error) {
 	if err := keyset.ValidateKeyVersion(key.Version, jwtECDSASignerKeyVersion); err != nil {
 		return ecdsaParams{}, fmt.Errorf("invalid key version: %v", err)
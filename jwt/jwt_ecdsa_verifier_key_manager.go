@@ -92,6 +92,12 @@ func (km *jwtECDSAVerifierKeyManager) TypeURL() string {
 	return jwtECDSAVerifierTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *jwtECDSAVerifierKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 func ecdsaCustomKID(pk *jepb.JwtEcdsaPublicKey) *string {
 	if pk.GetCustomKid() == nil {
 		return nil
@@ -17,6 +17,7 @@ package jwt
 import (
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -37,6 +38,19 @@ func keyID(keyID uint32, outPrefixType tpb.OutputPrefixType) *string {
 
 // createUnsigned creates an unsigned JWT by created the header/payload, encoding them to a websafe base64 encoded string and concatenating.
 func createUnsigned(rawJWT *RawJWT, algo string, tinkKID *string, customKID *string) (string, error) {
+	encodedHeader, err := createHeaderForRawJWT(rawJWT, algo, tinkKID, customKID)
+	if err != nil {
+		return "", err
+	}
+	encodedPayload, err := encodePayload(rawJWT)
+	if err != nil {
+		return "", err
+	}
+	return dotConcat(encodedHeader, encodedPayload), nil
+}
+
+// createHeaderForRawJWT creates the websafe base64 encoded header of a JWT.
+func createHeaderForRawJWT(rawJWT *RawJWT, algo string, tinkKID *string, customKID *string) (string, error) {
 	if rawJWT == nil {
 		return "", fmt.Errorf("rawJWT is nil")
 	}
@@ -48,21 +62,41 @@ func createUnsigned(rawJWT *RawJWT, algo string, tinkKID *string, customKID *str
 		}
 		typeHeader = &th
 	}
-	if customKID != nil && tinkKID != nil {
-		return "", fmt.Errorf("TINK Keys are not allowed to have a kid value set")
-	}
-	if tinkKID != nil {
-		customKID = tinkKID
-	}
-	encodedHeader, err := createHeader(algo, typeHeader, customKID)
+	kid, err := selectKID(tinkKID, customKID)
 	if err != nil {
 		return "", err
 	}
+	header := newHeader(algo, typeHeader, kid)
+	for name, value := range rawJWT.customHeaders {
+		if isReservedHeader(name) {
+			return "", fmt.Errorf("header %q is reserved", name)
+		}
+		header.Fields[name] = value
+	}
+	return encodeHeader(header)
+}
+
+// encodePayload encodes the payload of rawJWT to a websafe base64 encoded string.
+func encodePayload(rawJWT *RawJWT) (string, error) {
+	if rawJWT == nil {
+		return "", fmt.Errorf("rawJWT is nil")
+	}
 	payload, err := rawJWT.JSONPayload()
 	if err != nil {
 		return "", err
 	}
-	return dotConcat(encodedHeader, base64Encode(payload)), nil
+	return base64Encode(payload), nil
+}
+
+// selectKID returns the kid that goes into the header.
+func selectKID(tinkKID, customKID *string) (*string, error) {
+	if customKID != nil && tinkKID != nil {
+		return nil, fmt.Errorf("TINK Keys are not allowed to have a kid value set")
+	}
+	if tinkKID != nil {
+		return tinkKID, nil
+	}
+	return customKID, nil
 }
 
 // combineUnsignedAndSignature combines the token with the raw signature to provide a signed token.
@@ -93,6 +127,172 @@ func splitSignedCompact(compact string) ([]byte, string, error) {
 	return witness, unsigned, nil
 }
 
+// createUnencodedPayloadHeader creates the websafe base64 encoded header of a
+// JWS with an unencoded payload, as defined in RFC 7797.
+func createUnencodedPayloadHeader(algo string, tinkKID, customKID *string) (string, error) {
+	kid, err := selectKID(tinkKID, customKID)
+	if err != nil {
+		return "", err
+	}
+	header := newHeader(algo, nil, kid)
+	header.Fields["b64"] = spb.NewBoolValue(false)
+	header.Fields["crit"] = spb.NewListValue(&spb.ListValue{Values: []*spb.Value{spb.NewStringValue("b64")}})
+	return encodeHeader(header)
+}
+
+// unencodedPayloadSigningInput returns the JWS signing input for an unencoded payload, see RFC 7797, section 3.
+func unencodedPayloadSigningInput(encodedHeader string, payload []byte) []byte {
+	input := make([]byte, 0, len(encodedHeader)+1+len(payload))
+	input = append(input, encodedHeader...)
+	input = append(input, '.')
+	return append(input, payload...)
+}
+
+// combineDetached combines the header with the raw signature to provide a
+// token in JWS compact serialization with a detached payload, see RFC 7515, appendix F.
+func combineDetached(encodedHeader string, signature []byte) string {
+	return encodedHeader + ".." + base64Encode(signature)
+}
+
+// splitDetached extracts the witness and the encoded header of a token in JWS
+// compact serialization with a detached payload.
+func splitDetached(detached string) ([]byte, string, error) {
+	parts := strings.Split(detached, ".")
+	if len(parts) != 3 {
+		return nil, "", fmt.Errorf("invalid token")
+	}
+	if len(parts[1]) != 0 {
+		return nil, "", fmt.Errorf("payload is not detached")
+	}
+	if len(parts[0]) == 0 {
+		return nil, "", fmt.Errorf("empty header")
+	}
+	witness, err := base64Decode(parts[2])
+	if err != nil {
+		return nil, "", fmt.Errorf("%q: %v", parts[2], err)
+	}
+	if len(witness) == 0 {
+		return nil, "", fmt.Errorf("empty signature")
+	}
+	return witness, parts[0], nil
+}
+
+// validateUnencodedPayloadHeader decodes and verifies the header of a JWS with an unencoded payload.
+func validateUnencodedPayloadHeader(encodedHeader, algorithm string, tinkKID, customKID *string) error {
+	jsonHeader, err := base64Decode(encodedHeader)
+	if err != nil {
+		return err
+	}
+	header, err := jsonToStruct(jsonHeader)
+	if err != nil {
+		return err
+	}
+	fields := header.GetFields()
+	b64, ok := fields["b64"]
+	if !ok {
+		return fmt.Errorf("header is missing \"b64\"")
+	}
+	if v, ok := b64.Kind.(*spb.Value_BoolValue); !ok || v.BoolValue {
+		return fmt.Errorf("\"b64\" header must be false")
+	}
+	crit, ok := fields["crit"]
+	if !ok {
+		return fmt.Errorf("header is missing \"crit\"")
+	}
+	critValues := crit.GetListValue().GetValues()
+	if len(critValues) != 1 || critValues[0].GetStringValue() != "b64" {
+		return fmt.Errorf("\"crit\" header must only contain \"b64\"")
+	}
+	// validateHeader rejects all tokens with crit headers, so only the remaining
+	// fields are passed on.
+	remaining := &spb.Struct{Fields: make(map[string]*spb.Value, len(fields))}
+	for name, value := range fields {
+		if name != "b64" && name != "crit" {
+			remaining.Fields[name] = value
+		}
+	}
+	return validateHeader(remaining, algorithm, tinkKID, customKID)
+}
+
+// jwsJSONSignature is a signature in the JWS JSON serialization, see RFC 7515, section 7.2.1.
+type jwsJSONSignature struct {
+	Protected string          `json:"protected"`
+	Header    json.RawMessage `json:"header,omitempty"`
+	Signature string          `json:"signature"`
+}
+
+// jwsJSON is a JWS in JSON serialization. It contains either the signatures
+// member of the general syntax, or the members of the flattened syntax.
+type jwsJSON struct {
+	Payload    string             `json:"payload"`
+	Signatures []jwsJSONSignature `json:"signatures,omitempty"`
+
+	Protected string          `json:"protected,omitempty"`
+	Header    json.RawMessage `json:"header,omitempty"`
+	Signature string          `json:"signature,omitempty"`
+}
+
+// encodeJSON encodes a payload and its signatures using the general JWS JSON serialization syntax.
+func encodeJSON(encodedPayload string, signatures []jwsJSONSignature) (string, error) {
+	if len(signatures) == 0 {
+		return "", fmt.Errorf("no signatures")
+	}
+	serialized, err := json.Marshal(&jwsJSON{
+		Payload:    encodedPayload,
+		Signatures: signatures,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(serialized), nil
+}
+
+// splitSignedJSON parses a JWS in either the general or the flattened JSON
+// serialization syntax and returns each signature as an unsigned token and
+// its witness, in the same format as splitSignedCompact.
+//
+// Unprotected headers are not integrity protected and are therefore rejected.
+func splitSignedJSON(serialized string) ([][]byte, []string, error) {
+	var j jwsJSON
+	if err := json.Unmarshal([]byte(serialized), &j); err != nil {
+		return nil, nil, err
+	}
+	if len(j.Payload) == 0 {
+		return nil, nil, fmt.Errorf("empty payload")
+	}
+	flattened := len(j.Protected) != 0 || len(j.Signature) != 0 || j.Header != nil
+	if flattened && j.Signatures != nil {
+		return nil, nil, fmt.Errorf("general and flattened syntax can't be mixed")
+	}
+	signatures := j.Signatures
+	if flattened {
+		signatures = []jwsJSONSignature{{Protected: j.Protected, Header: j.Header, Signature: j.Signature}}
+	}
+	if len(signatures) == 0 {
+		return nil, nil, fmt.Errorf("no signatures")
+	}
+	var witnesses [][]byte
+	var unsigned []string
+	for _, sig := range signatures {
+		if sig.Header != nil {
+			return nil, nil, fmt.Errorf("unprotected headers are not supported")
+		}
+		if len(sig.Protected) == 0 {
+			return nil, nil, fmt.Errorf("missing protected header")
+		}
+		witness, err := base64Decode(sig.Signature)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%q: %v", sig.Signature, err)
+		}
+		if len(witness) == 0 {
+			return nil, nil, fmt.Errorf("empty signature")
+		}
+		witnesses = append(witnesses, witness)
+		unsigned = append(unsigned, dotConcat(sig.Protected, j.Payload))
+	}
+	return witnesses, unsigned, nil
+}
+
 // decodeUnsignedTokenAndValidateHeader verifies the header on an unsigned JWT and decodes the payload into a RawJWT.
 // Expects the token to be in compact serialization format. The signature should be verified before calling this function.
 func decodeUnsignedTokenAndValidateHeader(unsigned, algorithm string, tinkKID, customKID *string) (*RawJWT, error) {
@@ -119,7 +319,7 @@ func decodeUnsignedTokenAndValidateHeader(unsigned, algorithm string, tinkKID, c
 	if err != nil {
 		return nil, err
 	}
-	return NewRawJWTFromJSON(typeHeader, jsonPayload)
+	return newRawJWTFromJSON(typeHeader, extractCustomHeaders(header), jsonPayload)
 }
 
 // base64Encode encodes a byte array into a base64 URL safe string with no padding.
@@ -170,7 +370,22 @@ func extractTypeHeader(header *spb.Struct) (*string, error) {
 	return &str.StringValue, nil
 }
 
-func createHeader(algorithm string, typeHeader, kid *string) (string, error) {
+// extractCustomHeaders returns all header parameters that aren't reserved.
+func extractCustomHeaders(header *spb.Struct) map[string]*spb.Value {
+	var customHeaders map[string]*spb.Value
+	for name, value := range header.GetFields() {
+		if isReservedHeader(name) {
+			continue
+		}
+		if customHeaders == nil {
+			customHeaders = make(map[string]*spb.Value)
+		}
+		customHeaders[name] = value
+	}
+	return customHeaders
+}
+
+func newHeader(algorithm string, typeHeader, kid *string) *spb.Struct {
 	header := &spb.Struct{
 		Fields: map[string]*spb.Value{
 			"alg": spb.NewStringValue(algorithm),
@@ -182,6 +397,10 @@ func createHeader(algorithm string, typeHeader, kid *string) (string, error) {
 	if kid != nil {
 		header.Fields["kid"] = spb.NewStringValue(*kid)
 	}
+	return header
+}
+
+func encodeHeader(header *spb.Struct) (string, error) {
 	jsonHeader, err := header.MarshalJSON()
 	if err != nil {
 		return "", err
@@ -122,6 +122,12 @@ func (km *jwtHMACKeyManager) TypeURL() string {
 	return jwtHMACTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *jwtHMACKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 func (km *jwtHMACKeyManager) validateKey(key *jwtmacpb.JwtHmacKey) error {
 	if key == nil {
 		return fmt.Errorf("key can't be nil")
@@ -0,0 +1,92 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/keyset"
+)
+
+// JSONSigner is the interface for signing JWTs using the JWS JSON
+// serialization, as defined in RFC 7515, section 7.2.
+type JSONSigner interface {
+	// Computes one signature per keyset, and encodes the JWT and the signatures
+	// in the general JWS JSON serialization syntax.
+	SignAndEncodeJSON(rawJWT *RawJWT) (string, error)
+}
+
+// JSONVerifier is the interface for verifying JWTs in the JWS JSON
+// serialization, as defined in RFC 7515, section 7.2.
+type JSONVerifier interface {
+	// Verifies and decodes a JWT in either the general or the flattened JWS JSON
+	// serialization syntax.
+	//
+	// Verification succeeds if at least one of the signatures is valid under a
+	// key in the keyset. The JWT is then validated in the same way as by
+	// VerifyAndDecode. Signatures with unprotected headers are rejected.
+	VerifyAndDecodeJSON(serialized string, validator *Validator) (*VerifiedJWT, error)
+}
+
+// wrappedJSONSigner is a JSONSigner that signs with the primary key of each of the keysets.
+type wrappedJSONSigner struct {
+	signers []*wrappedSigner
+}
+
+var _ JSONSigner = (*wrappedJSONSigner)(nil)
+
+// NewJSONSigner generates a new instance of the JSONSigner primitive.
+//
+// The output contains one signature for each of handles, created with its
+// primary key in the same way as by NewSigner.
+func NewJSONSigner(handles ...*keyset.Handle) (JSONSigner, error) {
+	if len(handles) == 0 {
+		return nil, fmt.Errorf("at least one keyset handle is required")
+	}
+	signers := make([]*wrappedSigner, 0, len(handles))
+	for _, handle := range handles {
+		s, err := newWrappedSignerFromHandle(handle)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, s)
+	}
+	return &wrappedJSONSigner{signers: signers}, nil
+}
+
+// NewJSONVerifier generates a new instance of the JSONVerifier primitive.
+func NewJSONVerifier(handle *keyset.Handle) (JSONVerifier, error) {
+	v, err := newWrappedVerifierFromHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (w *wrappedJSONSigner) SignAndEncodeJSON(rawJWT *RawJWT) (string, error) {
+	encodedPayload, err := encodePayload(rawJWT)
+	if err != nil {
+		return "", err
+	}
+	signatures := make([]jwsJSONSignature, 0, len(w.signers))
+	for _, s := range w.signers {
+		sig, err := s.signJSON(rawJWT, encodedPayload)
+		if err != nil {
+			return "", err
+		}
+		signatures = append(signatures, sig)
+	}
+	return encodeJSON(encodedPayload, signatures)
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/jwt"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func mustCreatePrivateAndPublicHandles(t *testing.T, template *tinkpb.KeyTemplate) (*keyset.Handle, *keyset.Handle) {
+	t.Helper()
+	privateHandle, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v, want nil", err)
+	}
+	return privateHandle, publicHandle
+}
+
+func mustCreateRawJWT(t *testing.T) *jwt.RawJWT {
+	t.Helper()
+	issuer := "issuer"
+	rawJWT, err := jwt.NewRawJWT(&jwt.RawJWTOptions{Issuer: &issuer, WithoutExpiration: true})
+	if err != nil {
+		t.Fatalf("jwt.NewRawJWT() err = %v, want nil", err)
+	}
+	return rawJWT
+}
+
+func mustCreateValidator(t *testing.T) *jwt.Validator {
+	t.Helper()
+	issuer := "issuer"
+	validator, err := jwt.NewValidator(&jwt.ValidatorOpts{ExpectedIssuer: &issuer, AllowMissingExpiration: true})
+	if err != nil {
+		t.Fatalf("jwt.NewValidator() err = %v, want nil", err)
+	}
+	return validator
+}
+
+func TestSignAndVerifyJSONWithMultipleSignatures(t *testing.T) {
+	ecdsaPrivate, ecdsaPublic := mustCreatePrivateAndPublicHandles(t, jwt.ES256Template())
+	rsaPrivate, rsaPublic := mustCreatePrivateAndPublicHandles(t, jwt.RawRS256_2048_F4_Key_Template())
+	signer, err := jwt.NewJSONSigner(ecdsaPrivate, rsaPrivate)
+	if err != nil {
+		t.Fatalf("jwt.NewJSONSigner() err = %v, want nil", err)
+	}
+	serialized, err := signer.SignAndEncodeJSON(mustCreateRawJWT(t))
+	if err != nil {
+		t.Fatalf("signer.SignAndEncodeJSON() err = %v, want nil", err)
+	}
+
+	var parsed struct {
+		Payload    string `json:"payload"`
+		Signatures []struct {
+			Protected string `json:"protected"`
+			Signature string `json:"signature"`
+		} `json:"signatures"`
+	}
+	if err := json.Unmarshal([]byte(serialized), &parsed); err != nil {
+		t.Fatalf("json.Unmarshal() err = %v, want nil", err)
+	}
+	if len(parsed.Signatures) != 2 {
+		t.Fatalf("len(signatures) = %d, want 2", len(parsed.Signatures))
+	}
+
+	for _, publicHandle := range []*keyset.Handle{ecdsaPublic, rsaPublic} {
+		verifier, err := jwt.NewJSONVerifier(publicHandle)
+		if err != nil {
+			t.Fatalf("jwt.NewJSONVerifier() err = %v, want nil", err)
+		}
+		verified, err := verifier.VerifyAndDecodeJSON(serialized, mustCreateValidator(t))
+		if err != nil {
+			t.Fatalf("verifier.VerifyAndDecodeJSON() err = %v, want nil", err)
+		}
+		if issuer, err := verified.Issuer(); err != nil || issuer != "issuer" {
+			t.Errorf("verified.Issuer() = %q, %v, want %q, nil", issuer, err, "issuer")
+		}
+
+		// Each signature is also a valid token in compact serialization.
+		compactVerifier, err := jwt.NewVerifier(publicHandle)
+		if err != nil {
+			t.Fatalf("jwt.NewVerifier() err = %v, want nil", err)
+		}
+		verifiedCount := 0
+		for _, sig := range parsed.Signatures {
+			compact := sig.Protected + "." + parsed.Payload + "." + sig.Signature
+			if _, err := compactVerifier.VerifyAndDecode(compact, mustCreateValidator(t)); err == nil {
+				verifiedCount++
+			}
+		}
+		if verifiedCount != 1 {
+			t.Errorf("number of signatures valid in compact serialization = %d, want 1", verifiedCount)
+		}
+	}
+}
+
+func TestVerifyJSONFlattenedSyntax(t *testing.T) {
+	privateHandle, publicHandle := mustCreatePrivateAndPublicHandles(t, jwt.ES256Template())
+	signer, err := jwt.NewSigner(privateHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewSigner() err = %v, want nil", err)
+	}
+	compact, err := signer.SignAndEncode(mustCreateRawJWT(t))
+	if err != nil {
+		t.Fatalf("signer.SignAndEncode() err = %v, want nil", err)
+	}
+	parts := strings.Split(compact, ".")
+	flattened := `{"payload":"` + parts[1] + `","protected":"` + parts[0] + `","signature":"` + parts[2] + `"}`
+	verifier, err := jwt.NewJSONVerifier(publicHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewJSONVerifier() err = %v, want nil", err)
+	}
+	if _, err := verifier.VerifyAndDecodeJSON(flattened, mustCreateValidator(t)); err != nil {
+		t.Errorf("verifier.VerifyAndDecodeJSON() err = %v, want nil", err)
+	}
+
+	withUnprotectedHeader := `{"payload":"` + parts[1] + `","protected":"` + parts[0] + `","header":{"kid":"x"},"signature":"` + parts[2] + `"}`
+	if _, err := verifier.VerifyAndDecodeJSON(withUnprotectedHeader, mustCreateValidator(t)); err == nil {
+		t.Errorf("verifier.VerifyAndDecodeJSON() with unprotected header err = nil, want error")
+	}
+}
+
+func TestVerifyJSONFails(t *testing.T) {
+	privateHandle, _ := mustCreatePrivateAndPublicHandles(t, jwt.ES256Template())
+	_, otherPublicHandle := mustCreatePrivateAndPublicHandles(t, jwt.ES256Template())
+	signer, err := jwt.NewJSONSigner(privateHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewJSONSigner() err = %v, want nil", err)
+	}
+	serialized, err := signer.SignAndEncodeJSON(mustCreateRawJWT(t))
+	if err != nil {
+		t.Fatalf("signer.SignAndEncodeJSON() err = %v, want nil", err)
+	}
+	verifier, err := jwt.NewJSONVerifier(otherPublicHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewJSONVerifier() err = %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		name       string
+		serialized string
+	}{
+		{"wrong key", serialized},
+		{"not json", "a.b.c"},
+		{"no signatures", `{"payload":"e30","signatures":[]}`},
+		{"empty payload", `{"payload":"","signatures":[{"protected":"e30","signature":"AA"}]}`},
+		{"mixed syntax", `{"payload":"e30","protected":"e30","signature":"AA","signatures":[{"protected":"e30","signature":"AA"}]}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := verifier.VerifyAndDecodeJSON(tc.serialized, mustCreateValidator(t)); err == nil {
+				t.Errorf("verifier.VerifyAndDecodeJSON() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestNewJSONSignerWithoutHandlesFails(t *testing.T) {
+	if _, err := jwt.NewJSONSigner(); err == nil {
+		t.Errorf("jwt.NewJSONSigner() err = nil, want error")
+	}
+}
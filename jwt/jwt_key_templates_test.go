@@ -15,6 +15,7 @@
 package jwt_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -160,3 +161,47 @@ func TestJWTSignVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleEntriesHaveTypedKeys(t *testing.T) {
+	// The typed key packages are not imported by this test, so that it checks
+	// that importing jwt registers them.
+	for _, tc := range []struct {
+		tag            string
+		template       *tinkpb.KeyTemplate
+		wantKeyType    string
+		wantPublicType string
+	}{
+		{tag: "JWT_HS256", template: jwt.HS256Template(), wantKeyType: "*jwthmac.Key"},
+		{tag: "JWT_ES256", template: jwt.ES256Template(), wantKeyType: "*jwtecdsa.PrivateKey", wantPublicType: "*jwtecdsa.PublicKey"},
+		{tag: "JWT_RS256_2048_F4", template: jwt.RS256_2048_F4_Key_Template(), wantKeyType: "*jwtrsassapkcs1.PrivateKey", wantPublicType: "*jwtrsassapkcs1.PublicKey"},
+		{tag: "JWT_PS256_2048_F4", template: jwt.PS256_2048_F4_Key_Template(), wantKeyType: "*jwtrsassapss.PrivateKey", wantPublicType: "*jwtrsassapss.PublicKey"},
+	} {
+		t.Run(tc.tag, func(t *testing.T) {
+			handle, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+			}
+			entry, err := handle.Primary()
+			if err != nil {
+				t.Fatalf("handle.Primary() err = %v, want nil", err)
+			}
+			if got := fmt.Sprintf("%T", entry.Key()); got != tc.wantKeyType {
+				t.Errorf("entry.Key() is of type %s, want %s", got, tc.wantKeyType)
+			}
+			if tc.wantPublicType == "" {
+				return
+			}
+			publicHandle, err := handle.Public()
+			if err != nil {
+				t.Fatalf("handle.Public() err = %v, want nil", err)
+			}
+			publicEntry, err := publicHandle.Primary()
+			if err != nil {
+				t.Fatalf("publicHandle.Primary() err = %v, want nil", err)
+			}
+			if got := fmt.Sprintf("%T", publicEntry.Key()); got != tc.wantPublicType {
+				t.Errorf("publicEntry.Key() is of type %s, want %s", got, tc.wantPublicType)
+			}
+		})
+	}
+}
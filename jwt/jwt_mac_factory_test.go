@@ -17,6 +17,7 @@ package jwt_test
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -404,7 +405,7 @@ func TestComputeFailureEmitsMonitoring(t *testing.T) {
 	if err := internalregistry.RegisterMonitoringClient(client); err != nil {
 		t.Fatalf("internalregistry.RegisterMonitoringClient() err = %v, want nil", err)
 	}
-	keyData, err := newKeyData(newJWTHMACKey(jwtmacpb.JwtHmacAlgorithm_HS256, &jwtmacpb.JwtHmacKey_CustomKid{Value: "custom-kid"}))
+	keyData, err := newKeyData(newJWTHMACKey(jwtmacpb.JwtHmacAlgorithm_HS256, nil))
 	if err != nil {
 		t.Fatalf("creating NewKeyData: %v", err)
 	}
@@ -427,7 +428,11 @@ func TestComputeFailureEmitsMonitoring(t *testing.T) {
 	if err != nil {
 		t.Fatalf("jwt.NewMAC() err = %v, want nil", err)
 	}
-	rawJWT, err := jwt.NewRawJWT(&jwt.RawJWTOptions{WithoutExpiration: true})
+	// NaN can't be encoded as JSON, so computing the token fails.
+	rawJWT, err := jwt.NewRawJWT(&jwt.RawJWTOptions{
+		WithoutExpiration: true,
+		CustomClaims:      map[string]any{"n": math.NaN()},
+	})
 	if err != nil {
 		t.Fatalf("jwt.NewRawJWT() err = %v, want nil", err)
 	}
@@ -217,3 +217,9 @@ func (km *jwtRSSignerKeyManager) DoesSupport(typeURL string) bool {
 func (km *jwtRSSignerKeyManager) TypeURL() string {
 	return jwtRSSignerTypeURL
 }
+
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *jwtRSSignerKeyManager) FIPSCompatible() bool {
+	return true
+}
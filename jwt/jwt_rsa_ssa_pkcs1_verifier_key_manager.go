@@ -93,6 +93,12 @@ func (km *jwtRSVerifierKeyManager) TypeURL() string {
 	return jwtRSVerifierTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *jwtRSVerifierKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 func validateRSPublicKey(pubKey *jrsppb.JwtRsaSsaPkcs1PublicKey) error {
 	if pubKey == nil {
 		return fmt.Errorf("nil public key")
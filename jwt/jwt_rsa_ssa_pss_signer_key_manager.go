@@ -216,3 +216,9 @@ func (km *jwtPSSignerKeyManager) DoesSupport(typeURL string) bool {
 func (km *jwtPSSignerKeyManager) TypeURL() string {
 	return jwtPSSignerTypeURL
 }
+
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *jwtPSSignerKeyManager) FIPSCompatible() bool {
+	return true
+}
@@ -98,6 +98,12 @@ func (km *jwtPSVerifierKeyManager) TypeURL() string {
 	return jwtPSVerifierTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *jwtPSVerifierKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 func validatePSPublicKey(pubKey *jrsppb.JwtRsaSsaPssPublicKey) error {
 	if pubKey == nil {
 		return fmt.Errorf("nil public key")
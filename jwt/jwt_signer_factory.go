@@ -28,6 +28,14 @@ import (
 
 // NewSigner generates a new instance of the JWT Signer primitive.
 func NewSigner(handle *keyset.Handle) (Signer, error) {
+	s, err := newWrappedSignerFromHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func newWrappedSignerFromHandle(handle *keyset.Handle) (*wrappedSigner, error) {
 	if handle == nil {
 		return nil, fmt.Errorf("keyset handle can't be nil")
 	}
@@ -45,6 +53,7 @@ type wrappedSigner struct {
 }
 
 var _ Signer = (*wrappedSigner)(nil)
+var _ DetachedSigner = (*wrappedSigner)(nil)
 
 func createSignerLogger(ps *primitiveset.PrimitiveSet[*signerWithKID]) (monitoring.Logger, error) {
 	// only keysets which contain annotations are monitored.
@@ -90,3 +99,26 @@ func (w *wrappedSigner) SignAndEncode(rawJWT *RawJWT) (string, error) {
 	w.logger.Log(primary.KeyID, 1)
 	return token, nil
 }
+
+func (w *wrappedSigner) SignDetached(payload []byte) (string, error) {
+	primary := w.ps.Primary
+	token, err := primary.Primitive.signDetachedWithKID(payload, keyID(primary.KeyID, primary.PrefixType))
+	if err != nil {
+		w.logger.LogFailure()
+		return "", err
+	}
+	w.logger.Log(primary.KeyID, len(payload))
+	return token, nil
+}
+
+// signJSON signs rawJWT with the primary key, using encodedPayload as the payload.
+func (w *wrappedSigner) signJSON(rawJWT *RawJWT, encodedPayload string) (jwsJSONSignature, error) {
+	primary := w.ps.Primary
+	sig, err := primary.Primitive.signJSONWithKID(rawJWT, encodedPayload, keyID(primary.KeyID, primary.PrefixType))
+	if err != nil {
+		w.logger.LogFailure()
+		return jwsJSONSignature{}, err
+	}
+	w.logger.Log(primary.KeyID, 1)
+	return sig, nil
+}
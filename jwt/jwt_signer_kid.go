@@ -50,3 +50,34 @@ func (s *signerWithKID) SignAndEncodeWithKID(rawJWT *RawJWT, kid *string) (strin
 	}
 	return combineUnsignedAndSignature(unsigned, signature), nil
 }
+
+// signJSONWithKID creates the header for rawJWT, and signs it together with
+// encodedPayload. It returns the signature in JWS JSON serialization.
+func (s *signerWithKID) signJSONWithKID(rawJWT *RawJWT, encodedPayload string, kid *string) (jwsJSONSignature, error) {
+	encodedHeader, err := createHeaderForRawJWT(rawJWT, s.algorithm, kid, s.customKID)
+	if err != nil {
+		return jwsJSONSignature{}, err
+	}
+	signature, err := s.ts.Sign([]byte(dotConcat(encodedHeader, encodedPayload)))
+	if err != nil {
+		return jwsJSONSignature{}, err
+	}
+	return jwsJSONSignature{
+		Protected: encodedHeader,
+		Signature: base64Encode(signature),
+	}, nil
+}
+
+// signDetachedWithKID signs payload without encoding it, as defined in RFC 7797.
+// The output is in compact serialization with a detached payload.
+func (s *signerWithKID) signDetachedWithKID(payload []byte, kid *string) (string, error) {
+	encodedHeader, err := createUnencodedPayloadHeader(s.algorithm, kid, s.customKID)
+	if err != nil {
+		return "", err
+	}
+	signature, err := s.ts.Sign(unencodedPayloadSigningInput(encodedHeader, payload))
+	if err != nil {
+		return "", err
+	}
+	return combineDetached(encodedHeader, signature), nil
+}
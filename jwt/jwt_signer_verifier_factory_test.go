@@ -20,6 +20,7 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -167,17 +168,17 @@ func TestFactoryVerifyWithDifferentKeyFails(t *testing.T) {
 }
 
 func TestFactorySignWithTinkAndCustomKIDFails(t *testing.T) {
-	_, privKeyHandle, _ := createKeyAndKeyHandles(t, refString("customKID"), tinkpb.OutputPrefixType_TINK)
-	signer, err := jwt.NewSigner(privKeyHandle)
+	privKey, err := createJWTECDSAKey(refString("customKID"))
 	if err != nil {
-		t.Fatalf("jwt.NewSigner() err = %v, want nil", err)
+		t.Fatal(err)
 	}
-	rawJWT, err := jwt.NewRawJWT(&jwt.RawJWTOptions{WithoutExpiration: true})
+	privKeyData, err := createKeyData(privKey)
 	if err != nil {
-		t.Fatalf("jwt.NewRawJWT() err = %v, want nil", err)
+		t.Fatal(err)
 	}
-	if _, err := signer.SignAndEncode(rawJWT); err == nil {
-		t.Errorf("signer.SignAndEncode() err = nil, want error")
+	// TINK keys with a custom kid are rejected when the keyset handle is created.
+	if _, _, err := createKeysetHandles(privKeyData, tinkpb.OutputPrefixType_TINK); err == nil {
+		t.Errorf("createKeysetHandles() err = nil, want error")
 	}
 }
 
@@ -187,6 +188,9 @@ type signerVerifierFactoryKIDTestCase struct {
 	signerKID            *string
 	verifierOutputPrefix tinkpb.OutputPrefixType
 	verifierKID          *string
+	// verifierKeyInvalid is set if the verifier key is rejected when its
+	// keyset handle is created.
+	verifierKeyInvalid bool
 }
 
 func TestFactorySignVerifyWithKIDFailure(t *testing.T) {
@@ -212,6 +216,7 @@ func TestFactorySignVerifyWithKIDFailure(t *testing.T) {
 			signerKID:            nil,
 			verifierOutputPrefix: tinkpb.OutputPrefixType_TINK,
 			verifierKID:          refString("customKID"),
+			verifierKeyInvalid:   true,
 		},
 		{
 			tag:                  "verifier with tink output prefix and custom kid when token has kid",
@@ -219,6 +224,7 @@ func TestFactorySignVerifyWithKIDFailure(t *testing.T) {
 			signerKID:            refString("customKID"),
 			verifierOutputPrefix: tinkpb.OutputPrefixType_TINK,
 			verifierKID:          refString("customKid"),
+			verifierKeyInvalid:   true,
 		},
 		{
 			tag:                  "token with fixed kid and verifier with tink output prefix",
@@ -250,6 +256,16 @@ func TestFactorySignVerifyWithKIDFailure(t *testing.T) {
 			if tc.verifierKID != nil {
 				key.PublicKey.CustomKid = &jepb.JwtEcdsaPublicKey_CustomKid{Value: *tc.verifierKID}
 			}
+			if tc.verifierKeyInvalid {
+				keyData, err := createKeyData(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, _, err := createKeysetHandles(keyData, tc.verifierOutputPrefix); err == nil {
+					t.Errorf("createKeysetHandles() err = nil, want error")
+				}
+				return
+			}
 			_, pubKeyHandle := createKeyHandlesFromKey(t, key, tc.verifierOutputPrefix)
 			verifier, err := jwt.NewVerifier(pubKeyHandle)
 			if err != nil {
@@ -592,8 +608,7 @@ func TestFactorySignAndVerifyWithAnnotationsEmitsMonitoringOnError(t *testing.T)
 	if err := internalregistry.RegisterMonitoringClient(client); err != nil {
 		t.Fatalf("internalregistry.RegisterMonitoringClient() err = %v, want nil", err)
 	}
-	kid := "intrusive_kid"
-	_, privHandle, pubHandle := createKeyAndKeyHandles(t, &kid, tinkpb.OutputPrefixType_TINK)
+	_, privHandle, pubHandle := createKeyAndKeyHandles(t, nil /*=kid*/, tinkpb.OutputPrefixType_TINK)
 	buff := &bytes.Buffer{}
 	if err := insecurecleartextkeyset.Write(privHandle, keyset.NewBinaryWriter(buff)); err != nil {
 		t.Fatalf("insecurecleartextkeyset.Write() err = %v, want nil", err)
@@ -619,14 +634,18 @@ func TestFactorySignAndVerifyWithAnnotationsEmitsMonitoringOnError(t *testing.T)
 	if err != nil {
 		t.Fatalf("jwt.NewVerifier() err = %v, want nil", err)
 	}
-	rawJWT, err := jwt.NewRawJWT(&jwt.RawJWTOptions{WithoutExpiration: true})
-	if err != nil {
-		t.Fatalf("jwt.NewRawJWT() err = %v, want nil", err)
-	}
 	validator, err := jwt.NewValidator(&jwt.ValidatorOpts{AllowMissingExpiration: true})
 	if err != nil {
 		t.Fatalf("jwt.NewValidator() err = %v, want nil", err)
 	}
+	// NaN can't be encoded as JSON, so computing the token fails.
+	rawJWT, err := jwt.NewRawJWT(&jwt.RawJWTOptions{
+		WithoutExpiration: true,
+		CustomClaims:      map[string]any{"n": math.NaN()},
+	})
+	if err != nil {
+		t.Fatalf("jwt.NewRawJWT() err = %v, want nil", err)
+	}
 	if _, err := signer.SignAndEncode(rawJWT); err == nil {
 		t.Fatalf("signer.SignAndEncode() err = nil, want error")
 	}
@@ -637,3 +656,46 @@ func TestFactorySignAndVerifyWithAnnotationsEmitsMonitoringOnError(t *testing.T)
 		t.Errorf("len(client.Failures()) = %d, want 2", len(client.Failures()))
 	}
 }
+
+func TestFactorySignVerifyWithCustomHeaders(t *testing.T) {
+	_, privateHandle, publicHandle := createKeyAndKeyHandles(t, nil /*=kid*/, tinkpb.OutputPrefixType_TINK)
+	signer, err := jwt.NewSigner(privateHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewSigner() err = %v, want nil", err)
+	}
+	verifier, err := jwt.NewVerifier(publicHandle)
+	if err != nil {
+		t.Fatalf("jwt.NewVerifier() err = %v, want nil", err)
+	}
+	rawJWT, err := jwt.NewRawJWT(&jwt.RawJWTOptions{
+		WithoutExpiration: true,
+		CustomHeaders:     map[string]any{"cty": "example", "x5t#S256": "thumbprint"},
+	})
+	if err != nil {
+		t.Fatalf("jwt.NewRawJWT() err = %v, want nil", err)
+	}
+	compact, err := signer.SignAndEncode(rawJWT)
+	if err != nil {
+		t.Fatalf("signer.SignAndEncode() err = %v, want nil", err)
+	}
+	validator, err := jwt.NewValidator(&jwt.ValidatorOpts{AllowMissingExpiration: true})
+	if err != nil {
+		t.Fatalf("jwt.NewValidator() err = %v, want nil", err)
+	}
+	verifiedJWT, err := verifier.VerifyAndDecode(compact, validator)
+	if err != nil {
+		t.Fatalf("verifier.VerifyAndDecode() err = %v, want nil", err)
+	}
+	for name, want := range map[string]string{"cty": "example", "x5t#S256": "thumbprint"} {
+		got, err := verifiedJWT.StringHeader(name)
+		if err != nil {
+			t.Fatalf("verifiedJWT.StringHeader(%q) err = %v, want nil", name, err)
+		}
+		if got != want {
+			t.Errorf("verifiedJWT.StringHeader(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if verifiedJWT.HasCustomHeader("kid") || verifiedJWT.HasCustomHeader("alg") {
+		t.Errorf("reserved headers must not be returned as custom headers")
+	}
+}
@@ -28,6 +28,14 @@ import (
 
 // NewVerifier generates a new instance of the JWT Verifier primitive.
 func NewVerifier(handle *keyset.Handle) (Verifier, error) {
+	v, err := newWrappedVerifierFromHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func newWrappedVerifierFromHandle(handle *keyset.Handle) (*wrappedVerifier, error) {
 	if handle == nil {
 		return nil, fmt.Errorf("keyset handle can't be nil")
 	}
@@ -45,6 +53,8 @@ type wrappedVerifier struct {
 }
 
 var _ Verifier = (*wrappedVerifier)(nil)
+var _ DetachedVerifier = (*wrappedVerifier)(nil)
+var _ JSONVerifier = (*wrappedVerifier)(nil)
 
 func createVerifierLogger(ps *primitiveset.PrimitiveSet[*verifierWithKID]) (monitoring.Logger, error) {
 	// only keysets which contain annotations are monitored.
@@ -81,17 +91,39 @@ func newWrappedVerifier(ps *primitiveset.PrimitiveSet[*verifierWithKID]) (*wrapp
 }
 
 func (w *wrappedVerifier) VerifyAndDecode(compact string, validator *Validator) (*VerifiedJWT, error) {
+	sig, content, err := splitSignedCompact(compact)
+	if err != nil {
+		w.logger.LogFailure()
+		return nil, errJwtVerification
+	}
+	return w.verifyAndDecode([][]byte{sig}, []string{content}, validator)
+}
+
+func (w *wrappedVerifier) VerifyAndDecodeJSON(serialized string, validator *Validator) (*VerifiedJWT, error) {
+	sigs, contents, err := splitSignedJSON(serialized)
+	if err != nil {
+		w.logger.LogFailure()
+		return nil, errJwtVerification
+	}
+	return w.verifyAndDecode(sigs, contents, validator)
+}
+
+// verifyAndDecode returns the first token in contents that has a valid
+// signature in sigs under any of the keys in the primitive set.
+func (w *wrappedVerifier) verifyAndDecode(sigs [][]byte, contents []string, validator *Validator) (*VerifiedJWT, error) {
 	var interestingErr error
-	for _, s := range w.ps.Entries {
-		for _, e := range s {
-			verifiedJWT, err := e.Primitive.VerifyAndDecodeWithKID(compact, validator, keyID(e.KeyID, e.PrefixType))
-			if err == nil {
-				w.logger.Log(e.KeyID, 1)
-				return verifiedJWT, nil
-			}
-			if err != errJwtVerification {
-				// any error that is not the generic errJwtVerification is considered interesting
-				interestingErr = err
+	for i, content := range contents {
+		for _, s := range w.ps.Entries {
+			for _, e := range s {
+				verifiedJWT, err := e.Primitive.verifyAndDecodeUnsignedWithKID(sigs[i], content, validator, keyID(e.KeyID, e.PrefixType))
+				if err == nil {
+					w.logger.Log(e.KeyID, 1)
+					return verifiedJWT, nil
+				}
+				if err != errJwtVerification {
+					// any error that is not the generic errJwtVerification is considered interesting
+					interestingErr = err
+				}
 			}
 		}
 	}
@@ -101,3 +133,16 @@ func (w *wrappedVerifier) VerifyAndDecode(compact string, validator *Validator)
 	}
 	return nil, errJwtVerification
 }
+
+func (w *wrappedVerifier) VerifyDetached(detached string, payload []byte) error {
+	for _, s := range w.ps.Entries {
+		for _, e := range s {
+			if err := e.Primitive.verifyDetachedWithKID(detached, payload, keyID(e.KeyID, e.PrefixType)); err == nil {
+				w.logger.Log(e.KeyID, len(payload))
+				return nil
+			}
+		}
+	}
+	w.logger.LogFailure()
+	return errJwtVerification
+}
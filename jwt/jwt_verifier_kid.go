@@ -44,6 +44,12 @@ func (v *verifierWithKID) VerifyAndDecodeWithKID(compact string, validator *Vali
 	if err != nil {
 		return nil, errJwtVerification
 	}
+	return v.verifyAndDecodeUnsignedWithKID(sig, content, validator, kid)
+}
+
+// verifyAndDecodeUnsignedWithKID verifies sig over an unsigned token in compact
+// serialization. It then validates the token, and returns a VerifiedJWT or an error.
+func (v *verifierWithKID) verifyAndDecodeUnsignedWithKID(sig []byte, content string, validator *Validator, kid *string) (*VerifiedJWT, error) {
 	if err := v.tv.Verify(sig, []byte(content)); err != nil {
 		return nil, errJwtVerification
 	}
@@ -56,3 +62,19 @@ func (v *verifierWithKID) VerifyAndDecodeWithKID(compact string, validator *Vali
 	}
 	return newVerifiedJWT(rawJWT)
 }
+
+// verifyDetachedWithKID verifies a token in compact serialization with a
+// detached, unencoded payload, as defined in RFC 7797.
+func (v *verifierWithKID) verifyDetachedWithKID(detached string, payload []byte, kid *string) error {
+	sig, encodedHeader, err := splitDetached(detached)
+	if err != nil {
+		return errJwtVerification
+	}
+	if err := v.tv.Verify(sig, unencodedPayloadSigningInput(encodedHeader, payload)); err != nil {
+		return errJwtVerification
+	}
+	if err := validateUnencodedPayloadHeader(encodedHeader, v.algorithm, kid, v.customKID); err != nil {
+		return errJwtVerification
+	}
+	return nil
+}
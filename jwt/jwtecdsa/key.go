@@ -0,0 +1,329 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtecdsa
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+// KIDStrategy defines how the "kid" header of a JWT is handled.
+//
+// There are three options:
+//
+//   - IgnoredKID: the "kid" header is not set when signing and is ignored
+//     when verifying.
+//   - Base64EncodedKeyIDAsKID: the "kid" header is set to the base64url
+//     encoding of the big endian key ID when signing, and is required to match
+//     when verifying.
+//   - CustomKID: the "kid" header is set to a fixed value chosen when the key
+//     is created. When verifying, the header is optional but must match if
+//     present.
+type KIDStrategy int
+
+const (
+	// UnknownKIDStrategy is the default value of KIDStrategy.
+	UnknownKIDStrategy KIDStrategy = iota
+	// Base64EncodedKeyIDAsKID sets the "kid" header to the base64url encoding
+	// of the big endian key ID.
+	Base64EncodedKeyIDAsKID
+	// IgnoredKID does not set the "kid" header and ignores it on verification.
+	IgnoredKID
+	// CustomKID sets the "kid" header to a value fixed at key creation time.
+	CustomKID
+)
+
+func (ks KIDStrategy) String() string {
+	switch ks {
+	case Base64EncodedKeyIDAsKID:
+		return "BASE64_ENCODED_KEY_ID_AS_KID"
+	case IgnoredKID:
+		return "IGNORED_KID"
+	case CustomKID:
+		return "CUSTOM_KID"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Algorithm is the JWA algorithm of a JWT ECDSA key.
+type Algorithm int
+
+const (
+	// UnknownAlgorithm is the default value of Algorithm.
+	UnknownAlgorithm Algorithm = iota
+	// ES256 is ECDSA using NIST P-256 and SHA-256.
+	ES256
+	// ES384 is ECDSA using NIST P-384 and SHA-384.
+	ES384
+	// ES512 is ECDSA using NIST P-521 and SHA-512.
+	ES512
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case ES256:
+		return "ES256"
+	case ES384:
+		return "ES384"
+	case ES512:
+		return "ES512"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Parameters represents the parameters of a JWT ECDSA key.
+type Parameters struct {
+	kidStrategy KIDStrategy
+	algorithm   Algorithm
+}
+
+var _ key.Parameters = (*Parameters)(nil)
+
+// KIDStrategy returns the "kid" header strategy.
+func (p *Parameters) KIDStrategy() KIDStrategy { return p.kidStrategy }
+
+// Algorithm returns the JWA algorithm.
+func (p *Parameters) Algorithm() Algorithm { return p.algorithm }
+
+func validateParameters(p *Parameters) error {
+	if p == nil {
+		return fmt.Errorf("parameters is nil")
+	}
+	switch p.kidStrategy {
+	case Base64EncodedKeyIDAsKID, IgnoredKID, CustomKID:
+	default:
+		return fmt.Errorf("unsupported kid strategy: %v", p.kidStrategy)
+	}
+	switch p.algorithm {
+	case ES256, ES384, ES512:
+	default:
+		return fmt.Errorf("unsupported algorithm: %v", p.algorithm)
+	}
+	return nil
+}
+
+// NewParameters creates a new JWT ECDSA Parameters value.
+func NewParameters(kidStrategy KIDStrategy, algorithm Algorithm) (*Parameters, error) {
+	p := &Parameters{
+		kidStrategy: kidStrategy,
+		algorithm:   algorithm,
+	}
+	if err := validateParameters(p); err != nil {
+		return nil, fmt.Errorf("jwtecdsa.NewParameters: %v", err)
+	}
+	return p, nil
+}
+
+// HasIDRequirement tells whether the key has an ID requirement.
+//
+// Only keys with the Base64EncodedKeyIDAsKID strategy have an ID requirement.
+func (p *Parameters) HasIDRequirement() bool { return p.kidStrategy == Base64EncodedKeyIDAsKID }
+
+// Equal tells whether this parameters value is equal to other.
+func (p *Parameters) Equal(other key.Parameters) bool {
+	that, ok := other.(*Parameters)
+	return ok && p.kidStrategy == that.kidStrategy && p.algorithm == that.algorithm
+}
+
+func ecdhCurveFromAlgorithm(algorithm Algorithm) (ecdh.Curve, error) {
+	switch algorithm {
+	case ES256:
+		return ecdh.P256(), nil
+	case ES384:
+		return ecdh.P384(), nil
+	case ES512:
+		return ecdh.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %v", algorithm)
+	}
+}
+
+// computeKID returns the "kid" header value for a key with the given strategy.
+func computeKID(kidStrategy KIDStrategy, idRequirement uint32, customKID string, hasCustomKID bool) (string, bool, error) {
+	switch kidStrategy {
+	case Base64EncodedKeyIDAsKID:
+		if hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must not be set for %v", kidStrategy)
+		}
+		buf := binary.BigEndian.AppendUint32(nil, idRequirement)
+		return base64.RawURLEncoding.EncodeToString(buf), true, nil
+	case IgnoredKID:
+		if hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must not be set for %v", kidStrategy)
+		}
+		return "", false, nil
+	case CustomKID:
+		if !hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must be set for %v", kidStrategy)
+		}
+		return customKID, true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported kid strategy: %v", kidStrategy)
+	}
+}
+
+// PublicKey represents a JWT ECDSA public key.
+type PublicKey struct {
+	publicPoint   []byte
+	idRequirement uint32
+	kid           string
+	hasKID        bool
+	parameters    *Parameters
+}
+
+var _ key.Key = (*PublicKey)(nil)
+
+// PublicKeyOpts contains the options for creating a new [PublicKey].
+type PublicKeyOpts struct {
+	// PublicPoint is the public point encoded uncompressed as per
+	// [SEC 1 v2.0, Section 2.3.3].
+	//
+	// [SEC 1 v2.0, Section 2.3.3]: https://www.secg.org/sec1-v2.pdf#page=17.08
+	PublicPoint []byte
+	// IDRequirement is the key ID. It must be zero unless the KID strategy is
+	// Base64EncodedKeyIDAsKID.
+	IDRequirement uint32
+	// CustomKID is the "kid" header value; only used if HasCustomKID is true.
+	CustomKID string
+	// HasCustomKID must be true if and only if the KID strategy is CustomKID.
+	HasCustomKID bool
+	// Parameters are the key parameters. They must be non-nil.
+	Parameters *Parameters
+}
+
+// NewPublicKey creates a new JWT ECDSA PublicKey value.
+func NewPublicKey(opts PublicKeyOpts) (*PublicKey, error) {
+	if err := validateParameters(opts.Parameters); err != nil {
+		return nil, fmt.Errorf("jwtecdsa.NewPublicKey: %v", err)
+	}
+	if !opts.Parameters.HasIDRequirement() && opts.IDRequirement != 0 {
+		return nil, fmt.Errorf("jwtecdsa.NewPublicKey: key ID must be zero for %v", opts.Parameters.KIDStrategy())
+	}
+	kid, hasKID, err := computeKID(opts.Parameters.KIDStrategy(), opts.IDRequirement, opts.CustomKID, opts.HasCustomKID)
+	if err != nil {
+		return nil, fmt.Errorf("jwtecdsa.NewPublicKey: %v", err)
+	}
+	curve, err := ecdhCurveFromAlgorithm(opts.Parameters.Algorithm())
+	if err != nil {
+		return nil, fmt.Errorf("jwtecdsa.NewPublicKey: %v", err)
+	}
+	if _, err := curve.NewPublicKey(opts.PublicPoint); err != nil {
+		return nil, fmt.Errorf("jwtecdsa.NewPublicKey: point validation failed: %v", err)
+	}
+	return &PublicKey{
+		publicPoint:   bytes.Clone(opts.PublicPoint),
+		idRequirement: opts.IDRequirement,
+		kid:           kid,
+		hasKID:        hasKID,
+		parameters:    opts.Parameters,
+	}, nil
+}
+
+// PublicPoint returns the public key uncompressed point.
+//
+// Point format as per [SEC 1 v2.0, Section 2.3.3].
+//
+// [SEC 1 v2.0, Section 2.3.3]: https://www.secg.org/sec1-v2.pdf#page=17.08
+func (k *PublicKey) PublicPoint() []byte { return bytes.Clone(k.publicPoint) }
+
+// KID returns the "kid" header value set by tokens signed with this key and
+// whether it is set.
+//
+// The second return value is false for keys with the IgnoredKID strategy.
+func (k *PublicKey) KID() (string, bool) { return k.kid, k.hasKID }
+
+// Parameters returns the parameters of this key.
+func (k *PublicKey) Parameters() key.Parameters { return k.parameters }
+
+// IDRequirement returns the key ID and whether it is required.
+func (k *PublicKey) IDRequirement() (uint32, bool) {
+	return k.idRequirement, k.Parameters().HasIDRequirement()
+}
+
+// Equal tells whether this key value is equal to other.
+func (k *PublicKey) Equal(other key.Key) bool {
+	that, ok := other.(*PublicKey)
+	return ok && k.Parameters().Equal(that.Parameters()) &&
+		k.idRequirement == that.idRequirement &&
+		k.kid == that.kid && k.hasKID == that.hasKID &&
+		bytes.Equal(k.publicPoint, that.publicPoint)
+}
+
+// PrivateKey represents a JWT ECDSA private key.
+type PrivateKey struct {
+	publicKey       *PublicKey
+	privateKeyValue secretdata.Bytes
+}
+
+var _ key.Key = (*PrivateKey)(nil)
+
+// NewPrivateKeyFromPublicKey creates a new JWT ECDSA PrivateKey value from a
+// public key and a private key value.
+//
+// The private key value must be octet encoded as per [SEC 1 v2.0, Section
+// 2.3.5].
+//
+// [SEC 1 v2.0, Section 2.3.5]: https://www.secg.org/sec1-v2.pdf#page=17.08
+func NewPrivateKeyFromPublicKey(publicKey *PublicKey, privateKeyValue secretdata.Bytes) (*PrivateKey, error) {
+	if publicKey == nil || publicKey.parameters == nil {
+		return nil, fmt.Errorf("jwtecdsa.NewPrivateKeyFromPublicKey: invalid public key")
+	}
+	curve, err := ecdhCurveFromAlgorithm(publicKey.parameters.Algorithm())
+	if err != nil {
+		return nil, fmt.Errorf("jwtecdsa.NewPrivateKeyFromPublicKey: %v", err)
+	}
+	ecdhPrivateKey, err := curve.NewPrivateKey(privateKeyValue.Data(insecuresecretdataaccess.Token{}))
+	if err != nil {
+		return nil, fmt.Errorf("jwtecdsa.NewPrivateKeyFromPublicKey: invalid private key value: %v", err)
+	}
+	if !bytes.Equal(ecdhPrivateKey.PublicKey().Bytes(), publicKey.publicPoint) {
+		return nil, fmt.Errorf("jwtecdsa.NewPrivateKeyFromPublicKey: private key value does not match public key")
+	}
+	return &PrivateKey{
+		publicKey:       publicKey,
+		privateKeyValue: privateKeyValue,
+	}, nil
+}
+
+// PrivateKeyValue returns the private key value as [secretdata.Bytes].
+func (k *PrivateKey) PrivateKeyValue() secretdata.Bytes { return k.privateKeyValue }
+
+// PublicKey returns the corresponding public key as [key.Key].
+func (k *PrivateKey) PublicKey() (key.Key, error) { return k.publicKey, nil }
+
+// Parameters returns the parameters of this key.
+func (k *PrivateKey) Parameters() key.Parameters { return k.publicKey.Parameters() }
+
+// IDRequirement returns the key ID and whether it is required.
+func (k *PrivateKey) IDRequirement() (uint32, bool) { return k.publicKey.IDRequirement() }
+
+// KID returns the "kid" header value and whether it is set.
+func (k *PrivateKey) KID() (string, bool) { return k.publicKey.KID() }
+
+// Equal tells whether this key value is equal to other.
+func (k *PrivateKey) Equal(other key.Key) bool {
+	that, ok := other.(*PrivateKey)
+	return ok && k.publicKey.Equal(that.publicKey) &&
+		k.privateKeyValue.Equal(that.privateKeyValue)
+}
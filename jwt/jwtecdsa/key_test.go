@@ -0,0 +1,309 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtecdsa_test
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/jwt/jwtecdsa"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+func mustCreateParameters(t *testing.T, kidStrategy jwtecdsa.KIDStrategy, algorithm jwtecdsa.Algorithm) *jwtecdsa.Parameters {
+	t.Helper()
+	params, err := jwtecdsa.NewParameters(kidStrategy, algorithm)
+	if err != nil {
+		t.Fatalf("jwtecdsa.NewParameters(%v, %v) err = %v, want nil", kidStrategy, algorithm, err)
+	}
+	return params
+}
+
+func curveForAlgorithm(t *testing.T, algorithm jwtecdsa.Algorithm) ecdh.Curve {
+	t.Helper()
+	switch algorithm {
+	case jwtecdsa.ES256:
+		return ecdh.P256()
+	case jwtecdsa.ES384:
+		return ecdh.P384()
+	case jwtecdsa.ES512:
+		return ecdh.P521()
+	default:
+		t.Fatalf("unsupported algorithm: %v", algorithm)
+		return nil
+	}
+}
+
+func TestNewParametersFails(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		kidStrategy jwtecdsa.KIDStrategy
+		algorithm   jwtecdsa.Algorithm
+	}{
+		{"unknown kid strategy", jwtecdsa.UnknownKIDStrategy, jwtecdsa.ES256},
+		{"unknown algorithm", jwtecdsa.IgnoredKID, jwtecdsa.UnknownAlgorithm},
+		{"invalid kid strategy", 100, jwtecdsa.ES256},
+		{"invalid algorithm", jwtecdsa.IgnoredKID, 100},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jwtecdsa.NewParameters(tc.kidStrategy, tc.algorithm); err == nil {
+				t.Errorf("jwtecdsa.NewParameters(%v, %v) err = nil, want error", tc.kidStrategy, tc.algorithm)
+			}
+		})
+	}
+}
+
+func TestNewParameters(t *testing.T) {
+	for _, kidStrategy := range []jwtecdsa.KIDStrategy{jwtecdsa.Base64EncodedKeyIDAsKID, jwtecdsa.IgnoredKID, jwtecdsa.CustomKID} {
+		for _, algorithm := range []jwtecdsa.Algorithm{jwtecdsa.ES256, jwtecdsa.ES384, jwtecdsa.ES512} {
+			t.Run(kidStrategy.String()+"_"+algorithm.String(), func(t *testing.T) {
+				params := mustCreateParameters(t, kidStrategy, algorithm)
+				if got, want := params.KIDStrategy(), kidStrategy; got != want {
+					t.Errorf("params.KIDStrategy() = %v, want %v", got, want)
+				}
+				if got, want := params.Algorithm(), algorithm; got != want {
+					t.Errorf("params.Algorithm() = %v, want %v", got, want)
+				}
+				if got, want := params.HasIDRequirement(), kidStrategy == jwtecdsa.Base64EncodedKeyIDAsKID; got != want {
+					t.Errorf("params.HasIDRequirement() = %v, want %v", got, want)
+				}
+				other := mustCreateParameters(t, kidStrategy, algorithm)
+				if !params.Equal(other) {
+					t.Errorf("params.Equal(other) = false, want true")
+				}
+			})
+		}
+	}
+}
+
+func TestParametersNotEqual(t *testing.T) {
+	p1 := mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES256)
+	p2 := mustCreateParameters(t, jwtecdsa.CustomKID, jwtecdsa.ES256)
+	p3 := mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES384)
+	if p1.Equal(p2) {
+		t.Errorf("p1.Equal(p2) = true, want false")
+	}
+	if p1.Equal(p3) {
+		t.Errorf("p1.Equal(p3) = true, want false")
+	}
+}
+
+func TestNewPublicKeyKID(t *testing.T) {
+	privKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdh.P256().GenerateKey() err = %v, want nil", err)
+	}
+	point := privKey.PublicKey().Bytes()
+	for _, tc := range []struct {
+		name       string
+		opts       jwtecdsa.PublicKeyOpts
+		wantKID    string
+		wantHasKID bool
+	}{
+		{
+			name: "Base64EncodedKeyIDAsKID",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint:   point,
+				IDRequirement: 0x01020304,
+				Parameters:    mustCreateParameters(t, jwtecdsa.Base64EncodedKeyIDAsKID, jwtecdsa.ES256),
+			},
+			wantKID:    "AQIDBA",
+			wantHasKID: true,
+		},
+		{
+			name: "IgnoredKID",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint: point,
+				Parameters:  mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES256),
+			},
+			wantKID:    "",
+			wantHasKID: false,
+		},
+		{
+			name: "CustomKID",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint:  point,
+				CustomKID:    "custom-kid",
+				HasCustomKID: true,
+				Parameters:   mustCreateParameters(t, jwtecdsa.CustomKID, jwtecdsa.ES256),
+			},
+			wantKID:    "custom-kid",
+			wantHasKID: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pubKey, err := jwtecdsa.NewPublicKey(tc.opts)
+			if err != nil {
+				t.Fatalf("jwtecdsa.NewPublicKey(%v) err = %v, want nil", tc.opts, err)
+			}
+			kid, hasKID := pubKey.KID()
+			if kid != tc.wantKID || hasKID != tc.wantHasKID {
+				t.Errorf("pubKey.KID() = %q, %v, want %q, %v", kid, hasKID, tc.wantKID, tc.wantHasKID)
+			}
+			idRequirement, required := pubKey.IDRequirement()
+			if idRequirement != tc.opts.IDRequirement || required != tc.opts.Parameters.HasIDRequirement() {
+				t.Errorf("pubKey.IDRequirement() = %v, %v, want %v, %v", idRequirement, required, tc.opts.IDRequirement, tc.opts.Parameters.HasIDRequirement())
+			}
+			other, err := jwtecdsa.NewPublicKey(tc.opts)
+			if err != nil {
+				t.Fatalf("jwtecdsa.NewPublicKey(%v) err = %v, want nil", tc.opts, err)
+			}
+			if !pubKey.Equal(other) {
+				t.Errorf("pubKey.Equal(other) = false, want true")
+			}
+		})
+	}
+}
+
+func TestNewPublicKeyFails(t *testing.T) {
+	privKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdh.P256().GenerateKey() err = %v, want nil", err)
+	}
+	point := privKey.PublicKey().Bytes()
+	for _, tc := range []struct {
+		name string
+		opts jwtecdsa.PublicKeyOpts
+	}{
+		{
+			name: "nil parameters",
+			opts: jwtecdsa.PublicKeyOpts{PublicPoint: point},
+		},
+		{
+			name: "ID requirement with IgnoredKID",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint:   point,
+				IDRequirement: 123,
+				Parameters:    mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES256),
+			},
+		},
+		{
+			name: "custom kid with IgnoredKID",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint:  point,
+				CustomKID:    "kid",
+				HasCustomKID: true,
+				Parameters:   mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES256),
+			},
+		},
+		{
+			name: "custom kid with Base64EncodedKeyIDAsKID",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint:   point,
+				IDRequirement: 123,
+				CustomKID:     "kid",
+				HasCustomKID:  true,
+				Parameters:    mustCreateParameters(t, jwtecdsa.Base64EncodedKeyIDAsKID, jwtecdsa.ES256),
+			},
+		},
+		{
+			name: "missing custom kid with CustomKID",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint: point,
+				Parameters:  mustCreateParameters(t, jwtecdsa.CustomKID, jwtecdsa.ES256),
+			},
+		},
+		{
+			name: "point on wrong curve",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint: point,
+				Parameters:  mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES384),
+			},
+		},
+		{
+			name: "invalid point",
+			opts: jwtecdsa.PublicKeyOpts{
+				PublicPoint: append([]byte{0x04}, make([]byte, 64)...),
+				Parameters:  mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES256),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jwtecdsa.NewPublicKey(tc.opts); err == nil {
+				t.Errorf("jwtecdsa.NewPublicKey(%v) err = nil, want error", tc.opts)
+			}
+		})
+	}
+}
+
+func TestNewPrivateKeyFromPublicKey(t *testing.T) {
+	for _, algorithm := range []jwtecdsa.Algorithm{jwtecdsa.ES256, jwtecdsa.ES384, jwtecdsa.ES512} {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			ecdhPrivKey, err := curveForAlgorithm(t, algorithm).GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatalf("GenerateKey() err = %v, want nil", err)
+			}
+			pubKey, err := jwtecdsa.NewPublicKey(jwtecdsa.PublicKeyOpts{
+				PublicPoint:   ecdhPrivKey.PublicKey().Bytes(),
+				IDRequirement: 123,
+				Parameters:    mustCreateParameters(t, jwtecdsa.Base64EncodedKeyIDAsKID, algorithm),
+			})
+			if err != nil {
+				t.Fatalf("jwtecdsa.NewPublicKey() err = %v, want nil", err)
+			}
+			keyValue := secretdata.NewBytesFromData(ecdhPrivKey.Bytes(), insecuresecretdataaccess.Token{})
+			privKey, err := jwtecdsa.NewPrivateKeyFromPublicKey(pubKey, keyValue)
+			if err != nil {
+				t.Fatalf("jwtecdsa.NewPrivateKeyFromPublicKey() err = %v, want nil", err)
+			}
+			if !privKey.PrivateKeyValue().Equal(keyValue) {
+				t.Errorf("privKey.PrivateKeyValue() = %v, want %v", privKey.PrivateKeyValue(), keyValue)
+			}
+			gotPubKey, err := privKey.PublicKey()
+			if err != nil {
+				t.Fatalf("privKey.PublicKey() err = %v, want nil", err)
+			}
+			if !gotPubKey.Equal(pubKey) {
+				t.Errorf("privKey.PublicKey() = %v, want %v", gotPubKey, pubKey)
+			}
+			if kid, hasKID := privKey.KID(); !hasKID || kid != "AAAAew" {
+				t.Errorf("privKey.KID() = %q, %v, want %q, true", kid, hasKID, "AAAAew")
+			}
+			otherPrivKey, err := jwtecdsa.NewPrivateKeyFromPublicKey(pubKey, keyValue)
+			if err != nil {
+				t.Fatalf("jwtecdsa.NewPrivateKeyFromPublicKey() err = %v, want nil", err)
+			}
+			if !privKey.Equal(otherPrivKey) {
+				t.Errorf("privKey.Equal(otherPrivKey) = false, want true")
+			}
+		})
+	}
+}
+
+func TestNewPrivateKeyFromPublicKeyFails(t *testing.T) {
+	ecdhPrivKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdh.P256().GenerateKey() err = %v, want nil", err)
+	}
+	otherECDHPrivKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdh.P256().GenerateKey() err = %v, want nil", err)
+	}
+	pubKey, err := jwtecdsa.NewPublicKey(jwtecdsa.PublicKeyOpts{
+		PublicPoint: ecdhPrivKey.PublicKey().Bytes(),
+		Parameters:  mustCreateParameters(t, jwtecdsa.IgnoredKID, jwtecdsa.ES256),
+	})
+	if err != nil {
+		t.Fatalf("jwtecdsa.NewPublicKey() err = %v, want nil", err)
+	}
+	if _, err := jwtecdsa.NewPrivateKeyFromPublicKey(nil, secretdata.NewBytesFromData(ecdhPrivKey.Bytes(), insecuresecretdataaccess.Token{})); err == nil {
+		t.Errorf("jwtecdsa.NewPrivateKeyFromPublicKey(nil, ...) err = nil, want error")
+	}
+	if _, err := jwtecdsa.NewPrivateKeyFromPublicKey(pubKey, secretdata.NewBytesFromData(otherECDHPrivKey.Bytes(), insecuresecretdataaccess.Token{})); err == nil {
+		t.Errorf("jwtecdsa.NewPrivateKeyFromPublicKey() with mismatched private key err = nil, want error")
+	}
+}
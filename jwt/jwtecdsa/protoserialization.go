@@ -0,0 +1,403 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtecdsa
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	jwtecdsapb "github.com/tink-crypto/tink-go/v2/proto/jwt_ecdsa_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const (
+	signerTypeURL   = "type.googleapis.com/google.crypto.tink.JwtEcdsaPrivateKey"
+	verifierTypeURL = "type.googleapis.com/google.crypto.tink.JwtEcdsaPublicKey"
+
+	// publicKeyProtoVersion is the accepted [jwtecdsapb.JwtEcdsaPublicKey]
+	// proto version.
+	publicKeyProtoVersion = 0
+	// privateKeyProtoVersion is the accepted [jwtecdsapb.JwtEcdsaPrivateKey]
+	// proto version.
+	privateKeyProtoVersion = 0
+)
+
+func protoAlgorithmFromAlgorithm(algorithm Algorithm) (jwtecdsapb.JwtEcdsaAlgorithm, error) {
+	switch algorithm {
+	case ES256:
+		return jwtecdsapb.JwtEcdsaAlgorithm_ES256, nil
+	case ES384:
+		return jwtecdsapb.JwtEcdsaAlgorithm_ES384, nil
+	case ES512:
+		return jwtecdsapb.JwtEcdsaAlgorithm_ES512, nil
+	default:
+		return jwtecdsapb.JwtEcdsaAlgorithm_ES_UNKNOWN, fmt.Errorf("unknown algorithm: %v", algorithm)
+	}
+}
+
+func algorithmFromProto(algorithm jwtecdsapb.JwtEcdsaAlgorithm) (Algorithm, error) {
+	switch algorithm {
+	case jwtecdsapb.JwtEcdsaAlgorithm_ES256:
+		return ES256, nil
+	case jwtecdsapb.JwtEcdsaAlgorithm_ES384:
+		return ES384, nil
+	case jwtecdsapb.JwtEcdsaAlgorithm_ES512:
+		return ES512, nil
+	default:
+		return UnknownAlgorithm, fmt.Errorf("unknown algorithm: %v", algorithm)
+	}
+}
+
+func protoOutputPrefixTypeFromKIDStrategy(kidStrategy KIDStrategy) (tinkpb.OutputPrefixType, error) {
+	switch kidStrategy {
+	case Base64EncodedKeyIDAsKID:
+		return tinkpb.OutputPrefixType_TINK, nil
+	case IgnoredKID, CustomKID:
+		return tinkpb.OutputPrefixType_RAW, nil
+	default:
+		return tinkpb.OutputPrefixType_UNKNOWN_PREFIX, fmt.Errorf("unknown kid strategy: %v", kidStrategy)
+	}
+}
+
+func kidStrategyFromProto(outputPrefixType tinkpb.OutputPrefixType, hasCustomKID bool) (KIDStrategy, error) {
+	switch outputPrefixType {
+	case tinkpb.OutputPrefixType_TINK:
+		if hasCustomKID {
+			return UnknownKIDStrategy, fmt.Errorf("custom kid is not allowed for TINK keys")
+		}
+		return Base64EncodedKeyIDAsKID, nil
+	case tinkpb.OutputPrefixType_RAW:
+		if hasCustomKID {
+			return CustomKID, nil
+		}
+		return IgnoredKID, nil
+	default:
+		return UnknownKIDStrategy, fmt.Errorf("unsupported output prefix type: %v", outputPrefixType)
+	}
+}
+
+func coordinateSizeForAlgorithm(algorithm Algorithm) (int, error) {
+	switch algorithm {
+	case ES256:
+		return 32, nil
+	case ES384:
+		return 48, nil
+	case ES512:
+		return 66, nil
+	default:
+		return 0, fmt.Errorf("unsupported algorithm: %v", algorithm)
+	}
+}
+
+// bigIntBytesToFixedSize pads with zeros or strips leading zeros from a big
+// endian integer so that it has exactly size bytes.
+func bigIntBytesToFixedSize(b []byte, size int) ([]byte, error) {
+	if len(b) <= size {
+		buf := make([]byte, size-len(b), size)
+		return append(buf, b...), nil
+	}
+	for _, c := range b[:len(b)-size] {
+		if c != 0 {
+			return nil, fmt.Errorf("big int has invalid size: %v, want at most %v", len(b), size)
+		}
+	}
+	return b[len(b)-size:], nil
+}
+
+func createProtoPublicKey(k *PublicKey) (*jwtecdsapb.JwtEcdsaPublicKey, error) {
+	algorithm, err := protoAlgorithmFromAlgorithm(k.parameters.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	coordinateSize, err := coordinateSizeForAlgorithm(k.parameters.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	if len(k.publicPoint) != 2*coordinateSize+1 {
+		return nil, fmt.Errorf("public point has invalid size: %v", len(k.publicPoint))
+	}
+	// Coordinates are serialized with an extra leading zero byte for
+	// compatibility with other Tink implementations.
+	x, err := bigIntBytesToFixedSize(k.publicPoint[1:1+coordinateSize], coordinateSize+1)
+	if err != nil {
+		return nil, err
+	}
+	y, err := bigIntBytesToFixedSize(k.publicPoint[1+coordinateSize:], coordinateSize+1)
+	if err != nil {
+		return nil, err
+	}
+	protoKey := &jwtecdsapb.JwtEcdsaPublicKey{
+		Version:   publicKeyProtoVersion,
+		Algorithm: algorithm,
+		X:         x,
+		Y:         y,
+	}
+	if k.parameters.KIDStrategy() == CustomKID {
+		protoKey.CustomKid = &jwtecdsapb.JwtEcdsaPublicKey_CustomKid{Value: k.kid}
+	}
+	return protoKey, nil
+}
+
+type publicKeySerializer struct{}
+
+var _ protoserialization.KeySerializer = (*publicKeySerializer)(nil)
+
+func (s *publicKeySerializer) SerializeKey(key key.Key) (*protoserialization.KeySerialization, error) {
+	publicKey, ok := key.(*PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid key type: %T, want *jwtecdsa.PublicKey", key)
+	}
+	// This is nil if PublicKey was created as a struct literal.
+	if publicKey.parameters == nil {
+		return nil, fmt.Errorf("invalid key: parameters is nil")
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(publicKey.parameters.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	protoKey, err := createProtoPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(protoKey)
+	if err != nil {
+		return nil, err
+	}
+	// idRequirement is zero if the key doesn't have a key requirement.
+	idRequirement, _ := publicKey.IDRequirement()
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         verifierTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}
+	return protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+}
+
+func newPublicKeyFromProto(protoKey *jwtecdsapb.JwtEcdsaPublicKey, outputPrefixType tinkpb.OutputPrefixType, keyID uint32) (*PublicKey, error) {
+	if protoKey.GetVersion() != publicKeyProtoVersion {
+		return nil, fmt.Errorf("public key has unsupported version: %v", protoKey.GetVersion())
+	}
+	kidStrategy, err := kidStrategyFromProto(outputPrefixType, protoKey.GetCustomKid() != nil)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := algorithmFromProto(protoKey.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	params, err := NewParameters(kidStrategy, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	coordinateSize, err := coordinateSizeForAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	x, err := bigIntBytesToFixedSize(protoKey.GetX(), coordinateSize)
+	if err != nil {
+		return nil, err
+	}
+	y, err := bigIntBytesToFixedSize(protoKey.GetY(), coordinateSize)
+	if err != nil {
+		return nil, err
+	}
+	publicPoint := append(append([]byte{0x04}, x...), y...)
+	return NewPublicKey(PublicKeyOpts{
+		PublicPoint:   publicPoint,
+		IDRequirement: keyID,
+		CustomKID:     protoKey.GetCustomKid().GetValue(),
+		HasCustomKID:  protoKey.GetCustomKid() != nil,
+		Parameters:    params,
+	})
+}
+
+type publicKeyParser struct{}
+
+var _ protoserialization.KeyParser = (*publicKeyParser)(nil)
+
+func (s *publicKeyParser) ParseKey(keySerialization *protoserialization.KeySerialization) (key.Key, error) {
+	if keySerialization == nil {
+		return nil, fmt.Errorf("key serialization is nil")
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetTypeUrl() != verifierTypeURL {
+		return nil, fmt.Errorf("invalid key type URL: %v", keyData.GetTypeUrl())
+	}
+	if keyData.GetKeyMaterialType() != tinkpb.KeyData_ASYMMETRIC_PUBLIC {
+		return nil, fmt.Errorf("invalid key material type: %v", keyData.GetKeyMaterialType())
+	}
+	protoKey := new(jwtecdsapb.JwtEcdsaPublicKey)
+	if err := proto.Unmarshal(keyData.GetValue(), protoKey); err != nil {
+		return nil, err
+	}
+	// keySerialization.IDRequirement() returns zero if the key doesn't have a key requirement.
+	keyID, _ := keySerialization.IDRequirement()
+	return newPublicKeyFromProto(protoKey, keySerialization.OutputPrefixType(), keyID)
+}
+
+type privateKeySerializer struct{}
+
+var _ protoserialization.KeySerializer = (*privateKeySerializer)(nil)
+
+func (s *privateKeySerializer) SerializeKey(key key.Key) (*protoserialization.KeySerialization, error) {
+	privateKey, ok := key.(*PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid key type: %T, want *jwtecdsa.PrivateKey", key)
+	}
+	// This is nil if PrivateKey was created as a struct literal.
+	if privateKey.publicKey == nil {
+		return nil, fmt.Errorf("invalid key: public key is nil")
+	}
+	params := privateKey.publicKey.parameters
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(params.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	protoPublicKey, err := createProtoPublicKey(privateKey.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	coordinateSize, err := coordinateSizeForAlgorithm(params.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	keyValue, err := bigIntBytesToFixedSize(privateKey.PrivateKeyValue().Data(insecuresecretdataaccess.Token{}), coordinateSize+1)
+	if err != nil {
+		return nil, err
+	}
+	protoKey := &jwtecdsapb.JwtEcdsaPrivateKey{
+		Version:   privateKeyProtoVersion,
+		PublicKey: protoPublicKey,
+		KeyValue:  keyValue,
+	}
+	serializedKey, err := proto.Marshal(protoKey)
+	if err != nil {
+		return nil, err
+	}
+	// idRequirement is zero if the key doesn't have a key requirement.
+	idRequirement, _ := privateKey.IDRequirement()
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         signerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}
+	return protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+}
+
+type privateKeyParser struct{}
+
+var _ protoserialization.KeyParser = (*privateKeyParser)(nil)
+
+func (s *privateKeyParser) ParseKey(keySerialization *protoserialization.KeySerialization) (key.Key, error) {
+	if keySerialization == nil {
+		return nil, fmt.Errorf("key serialization is nil")
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetTypeUrl() != signerTypeURL {
+		return nil, fmt.Errorf("invalid key type URL: %v", keyData.GetTypeUrl())
+	}
+	if keyData.GetKeyMaterialType() != tinkpb.KeyData_ASYMMETRIC_PRIVATE {
+		return nil, fmt.Errorf("invalid key material type: %v", keyData.GetKeyMaterialType())
+	}
+	protoKey := new(jwtecdsapb.JwtEcdsaPrivateKey)
+	if err := proto.Unmarshal(keyData.GetValue(), protoKey); err != nil {
+		return nil, err
+	}
+	if protoKey.GetVersion() != privateKeyProtoVersion {
+		return nil, fmt.Errorf("private key has unsupported version: %v", protoKey.GetVersion())
+	}
+	// keySerialization.IDRequirement() returns zero if the key doesn't have a key requirement.
+	keyID, _ := keySerialization.IDRequirement()
+	publicKey, err := newPublicKeyFromProto(protoKey.GetPublicKey(), keySerialization.OutputPrefixType(), keyID)
+	if err != nil {
+		return nil, err
+	}
+	coordinateSize, err := coordinateSizeForAlgorithm(publicKey.parameters.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	keyValue, err := bigIntBytesToFixedSize(protoKey.GetKeyValue(), coordinateSize)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKeyFromPublicKey(publicKey, secretdata.NewBytesFromData(keyValue, insecuresecretdataaccess.Token{}))
+}
+
+type parametersSerializer struct{}
+
+var _ protoserialization.ParametersSerializer = (*parametersSerializer)(nil)
+
+func (s *parametersSerializer) Serialize(parameters key.Parameters) (*tinkpb.KeyTemplate, error) {
+	params, ok := parameters.(*Parameters)
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters type: got %T, want *jwtecdsa.Parameters", parameters)
+	}
+	if err := validateParameters(params); err != nil {
+		return nil, err
+	}
+	if params.KIDStrategy() == CustomKID {
+		return nil, fmt.Errorf("parameters with %v cannot be serialized to a key template", CustomKID)
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(params.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := protoAlgorithmFromAlgorithm(params.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	serializedFormat, err := proto.Marshal(&jwtecdsapb.JwtEcdsaKeyFormat{
+		Version:   privateKeyProtoVersion,
+		Algorithm: algorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          signerTypeURL,
+		OutputPrefixType: outputPrefixType,
+		Value:            serializedFormat,
+	}, nil
+}
+
+type parametersParser struct{}
+
+var _ protoserialization.ParametersParser = (*parametersParser)(nil)
+
+func (s *parametersParser) Parse(keyTemplate *tinkpb.KeyTemplate) (key.Parameters, error) {
+	if keyTemplate.GetTypeUrl() != signerTypeURL {
+		return nil, fmt.Errorf("invalid type URL: got %q, want %q", keyTemplate.GetTypeUrl(), signerTypeURL)
+	}
+	format := new(jwtecdsapb.JwtEcdsaKeyFormat)
+	if err := proto.Unmarshal(keyTemplate.GetValue(), format); err != nil {
+		return nil, err
+	}
+	if format.GetVersion() != privateKeyProtoVersion {
+		return nil, fmt.Errorf("key format has unsupported version: %v", format.GetVersion())
+	}
+	kidStrategy, err := kidStrategyFromProto(keyTemplate.GetOutputPrefixType(), false)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := algorithmFromProto(format.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	return NewParameters(kidStrategy, algorithm)
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtecdsa
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	jwtecdsapb "github.com/tink-crypto/tink-go/v2/proto/jwt_ecdsa_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	t.Helper()
+	serialized, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("proto.Marshal(%v) err = %v, want nil", message, err)
+	}
+	return serialized
+}
+
+func mustCreateKeySerialization(t *testing.T, keyData *tinkpb.KeyData, outputPrefixType tinkpb.OutputPrefixType, idRequirement uint32) *protoserialization.KeySerialization {
+	t.Helper()
+	ks, err := protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+	if err != nil {
+		t.Fatalf("protoserialization.NewKeySerialization(%v, %v, %v) err = %v, want nil", keyData, outputPrefixType, idRequirement, err)
+	}
+	return ks
+}
+
+type testCase struct {
+	name                    string
+	privateKey              *PrivateKey
+	publicKeySerialization  *protoserialization.KeySerialization
+	privateKeySerialization *protoserialization.KeySerialization
+}
+
+func testCases(t *testing.T) []testCase {
+	t.Helper()
+	var tcs []testCase
+	for _, c := range []struct {
+		algorithm      Algorithm
+		protoAlgorithm jwtecdsapb.JwtEcdsaAlgorithm
+		curve          ecdh.Curve
+		coordinateSize int
+	}{
+		{ES256, jwtecdsapb.JwtEcdsaAlgorithm_ES256, ecdh.P256(), 32},
+		{ES384, jwtecdsapb.JwtEcdsaAlgorithm_ES384, ecdh.P384(), 48},
+		{ES512, jwtecdsapb.JwtEcdsaAlgorithm_ES512, ecdh.P521(), 66},
+	} {
+		ecdhPrivKey, err := c.curve.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey() err = %v, want nil", err)
+		}
+		point := ecdhPrivKey.PublicKey().Bytes()
+		x := append([]byte{0}, point[1:1+c.coordinateSize]...)
+		y := append([]byte{0}, point[1+c.coordinateSize:]...)
+		keyValue := append([]byte{0}, ecdhPrivKey.Bytes()...)
+		for _, k := range []struct {
+			kidStrategy      KIDStrategy
+			outputPrefixType tinkpb.OutputPrefixType
+			idRequirement    uint32
+			customKID        *jwtecdsapb.JwtEcdsaPublicKey_CustomKid
+		}{
+			{Base64EncodedKeyIDAsKID, tinkpb.OutputPrefixType_TINK, 0x01020304, nil},
+			{IgnoredKID, tinkpb.OutputPrefixType_RAW, 0, nil},
+			{CustomKID, tinkpb.OutputPrefixType_RAW, 0, &jwtecdsapb.JwtEcdsaPublicKey_CustomKid{Value: "custom"}},
+		} {
+			params, err := NewParameters(k.kidStrategy, c.algorithm)
+			if err != nil {
+				t.Fatalf("NewParameters() err = %v, want nil", err)
+			}
+			pubKey, err := NewPublicKey(PublicKeyOpts{
+				PublicPoint:   point,
+				IDRequirement: k.idRequirement,
+				CustomKID:     k.customKID.GetValue(),
+				HasCustomKID:  k.customKID != nil,
+				Parameters:    params,
+			})
+			if err != nil {
+				t.Fatalf("NewPublicKey() err = %v, want nil", err)
+			}
+			privKey, err := NewPrivateKeyFromPublicKey(pubKey, secretdata.NewBytesFromData(ecdhPrivKey.Bytes(), insecuresecretdataaccess.Token{}))
+			if err != nil {
+				t.Fatalf("NewPrivateKeyFromPublicKey() err = %v, want nil", err)
+			}
+			protoPublicKey := &jwtecdsapb.JwtEcdsaPublicKey{
+				Version:   0,
+				Algorithm: c.protoAlgorithm,
+				X:         x,
+				Y:         y,
+				CustomKid: k.customKID,
+			}
+			tcs = append(tcs, testCase{
+				name:       c.algorithm.String() + "_" + k.kidStrategy.String(),
+				privateKey: privKey,
+				publicKeySerialization: mustCreateKeySerialization(t, &tinkpb.KeyData{
+					TypeUrl:         verifierTypeURL,
+					Value:           mustMarshal(t, protoPublicKey),
+					KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+				}, k.outputPrefixType, k.idRequirement),
+				privateKeySerialization: mustCreateKeySerialization(t, &tinkpb.KeyData{
+					TypeUrl: signerTypeURL,
+					Value: mustMarshal(t, &jwtecdsapb.JwtEcdsaPrivateKey{
+						Version:   0,
+						PublicKey: protoPublicKey,
+						KeyValue:  keyValue,
+					}),
+					KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+				}, k.outputPrefixType, k.idRequirement),
+			})
+		}
+	}
+	return tcs
+}
+
+func TestSerializeAndParseKeys(t *testing.T) {
+	for _, tc := range testCases(t) {
+		t.Run(tc.name, func(t *testing.T) {
+			pubKey := tc.privateKey.publicKey
+
+			gotPublicSerialization, err := (&publicKeySerializer{}).SerializeKey(pubKey)
+			if err != nil {
+				t.Fatalf("publicKeySerializer.SerializeKey() err = %v, want nil", err)
+			}
+			if !gotPublicSerialization.Equal(tc.publicKeySerialization) {
+				t.Errorf("publicKeySerializer.SerializeKey() = %v, want %v", gotPublicSerialization, tc.publicKeySerialization)
+			}
+			gotPublicKey, err := (&publicKeyParser{}).ParseKey(tc.publicKeySerialization)
+			if err != nil {
+				t.Fatalf("publicKeyParser.ParseKey() err = %v, want nil", err)
+			}
+			if !gotPublicKey.Equal(pubKey) {
+				t.Errorf("publicKeyParser.ParseKey() = %v, want %v", gotPublicKey, pubKey)
+			}
+
+			gotPrivateSerialization, err := (&privateKeySerializer{}).SerializeKey(tc.privateKey)
+			if err != nil {
+				t.Fatalf("privateKeySerializer.SerializeKey() err = %v, want nil", err)
+			}
+			if !gotPrivateSerialization.Equal(tc.privateKeySerialization) {
+				t.Errorf("privateKeySerializer.SerializeKey() = %v, want %v", gotPrivateSerialization, tc.privateKeySerialization)
+			}
+			gotPrivateKey, err := (&privateKeyParser{}).ParseKey(tc.privateKeySerialization)
+			if err != nil {
+				t.Fatalf("privateKeyParser.ParseKey() err = %v, want nil", err)
+			}
+			if !gotPrivateKey.Equal(tc.privateKey) {
+				t.Errorf("privateKeyParser.ParseKey() = %v, want %v", gotPrivateKey, tc.privateKey)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyFails(t *testing.T) {
+	ecdhPrivKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() err = %v, want nil", err)
+	}
+	point := ecdhPrivKey.PublicKey().Bytes()
+	validKey := &jwtecdsapb.JwtEcdsaPublicKey{
+		Algorithm: jwtecdsapb.JwtEcdsaAlgorithm_ES256,
+		X:         point[1:33],
+		Y:         point[33:],
+	}
+	withCustomKID := proto.Clone(validKey).(*jwtecdsapb.JwtEcdsaPublicKey)
+	withCustomKID.CustomKid = &jwtecdsapb.JwtEcdsaPublicKey_CustomKid{Value: "kid"}
+	wrongVersion := proto.Clone(validKey).(*jwtecdsapb.JwtEcdsaPublicKey)
+	wrongVersion.Version = 1
+	unknownAlgorithm := proto.Clone(validKey).(*jwtecdsapb.JwtEcdsaPublicKey)
+	unknownAlgorithm.Algorithm = jwtecdsapb.JwtEcdsaAlgorithm_ES_UNKNOWN
+	wrongCurve := proto.Clone(validKey).(*jwtecdsapb.JwtEcdsaPublicKey)
+	wrongCurve.Algorithm = jwtecdsapb.JwtEcdsaAlgorithm_ES384
+
+	for _, tc := range []struct {
+		name             string
+		protoKey         *jwtecdsapb.JwtEcdsaPublicKey
+		outputPrefixType tinkpb.OutputPrefixType
+		typeURL          string
+	}{
+		{"TINK with custom kid", withCustomKID, tinkpb.OutputPrefixType_TINK, verifierTypeURL},
+		{"LEGACY", validKey, tinkpb.OutputPrefixType_LEGACY, verifierTypeURL},
+		{"CRUNCHY", validKey, tinkpb.OutputPrefixType_CRUNCHY, verifierTypeURL},
+		{"wrong version", wrongVersion, tinkpb.OutputPrefixType_RAW, verifierTypeURL},
+		{"unknown algorithm", unknownAlgorithm, tinkpb.OutputPrefixType_RAW, verifierTypeURL},
+		{"wrong curve", wrongCurve, tinkpb.OutputPrefixType_RAW, verifierTypeURL},
+		{"wrong type URL", validKey, tinkpb.OutputPrefixType_RAW, signerTypeURL},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var idRequirement uint32
+			if tc.outputPrefixType != tinkpb.OutputPrefixType_RAW {
+				idRequirement = 123
+			}
+			serialization := mustCreateKeySerialization(t, &tinkpb.KeyData{
+				TypeUrl:         tc.typeURL,
+				Value:           mustMarshal(t, tc.protoKey),
+				KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+			}, tc.outputPrefixType, idRequirement)
+			if _, err := (&publicKeyParser{}).ParseKey(serialization); err == nil {
+				t.Errorf("publicKeyParser.ParseKey() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSerializeParameters(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		parameters *Parameters
+		want       *tinkpb.KeyTemplate
+	}{
+		{
+			name:       "Base64EncodedKeyIDAsKID",
+			parameters: &Parameters{kidStrategy: Base64EncodedKeyIDAsKID, algorithm: ES384},
+			want: &tinkpb.KeyTemplate{
+				TypeUrl:          signerTypeURL,
+				OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+				Value:            mustMarshal(t, &jwtecdsapb.JwtEcdsaKeyFormat{Algorithm: jwtecdsapb.JwtEcdsaAlgorithm_ES384}),
+			},
+		},
+		{
+			name:       "IgnoredKID",
+			parameters: &Parameters{kidStrategy: IgnoredKID, algorithm: ES256},
+			want: &tinkpb.KeyTemplate{
+				TypeUrl:          signerTypeURL,
+				OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+				Value:            mustMarshal(t, &jwtecdsapb.JwtEcdsaKeyFormat{Algorithm: jwtecdsapb.JwtEcdsaAlgorithm_ES256}),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&parametersSerializer{}).Serialize(tc.parameters)
+			if err != nil {
+				t.Fatalf("parametersSerializer.Serialize() err = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("parametersSerializer.Serialize() diff (-want +got):\n%s", diff)
+			}
+			gotParams, err := (&parametersParser{}).Parse(got)
+			if err != nil {
+				t.Fatalf("parametersParser.Parse() err = %v, want nil", err)
+			}
+			if !gotParams.Equal(tc.parameters) {
+				t.Errorf("parametersParser.Parse() = %v, want %v", gotParams, tc.parameters)
+			}
+		})
+	}
+}
+
+func TestSerializeParametersFailsWithCustomKID(t *testing.T) {
+	params := &Parameters{kidStrategy: CustomKID, algorithm: ES256}
+	if _, err := (&parametersSerializer{}).Serialize(params); err == nil {
+		t.Errorf("parametersSerializer.Serialize(%v) err = nil, want error", params)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwthmac
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+// KIDStrategy defines how the "kid" header of a JWT is handled.
+//
+// There are three options:
+//
+//   - IgnoredKID: the "kid" header is not set when computing the MAC and is
+//     ignored when verifying.
+//   - Base64EncodedKeyIDAsKID: the "kid" header is set to the base64url
+//     encoding of the big endian key ID when computing the MAC, and is
+//     required to match when verifying.
+//   - CustomKID: the "kid" header is set to a fixed value chosen when the key
+//     is created. When verifying, the header is optional but must match if
+//     present.
+type KIDStrategy int
+
+const (
+	// UnknownKIDStrategy is the default value of KIDStrategy.
+	UnknownKIDStrategy KIDStrategy = iota
+	// Base64EncodedKeyIDAsKID sets the "kid" header to the base64url encoding
+	// of the big endian key ID.
+	Base64EncodedKeyIDAsKID
+	// IgnoredKID does not set the "kid" header and ignores it on verification.
+	IgnoredKID
+	// CustomKID sets the "kid" header to a value fixed at key creation time.
+	CustomKID
+)
+
+func (ks KIDStrategy) String() string {
+	switch ks {
+	case Base64EncodedKeyIDAsKID:
+		return "BASE64_ENCODED_KEY_ID_AS_KID"
+	case IgnoredKID:
+		return "IGNORED_KID"
+	case CustomKID:
+		return "CUSTOM_KID"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Algorithm is the JWA algorithm of a JWT HMAC key.
+type Algorithm int
+
+const (
+	// UnknownAlgorithm is the default value of Algorithm.
+	UnknownAlgorithm Algorithm = iota
+	// HS256 is HMAC using SHA-256.
+	HS256
+	// HS384 is HMAC using SHA-384.
+	HS384
+	// HS512 is HMAC using SHA-512.
+	HS512
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case HS256:
+		return "HS256"
+	case HS384:
+		return "HS384"
+	case HS512:
+		return "HS512"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// minKeySizeInBytes returns the minimum key size for the algorithm, which
+// equals the size of the hash output as recommended by RFC 7518.
+func minKeySizeInBytes(algorithm Algorithm) (int, error) {
+	switch algorithm {
+	case HS256:
+		return 32, nil
+	case HS384:
+		return 48, nil
+	case HS512:
+		return 64, nil
+	default:
+		return 0, fmt.Errorf("unsupported algorithm: %v", algorithm)
+	}
+}
+
+// Parameters represents the parameters of a JWT HMAC key.
+type Parameters struct {
+	keySizeInBytes int
+	kidStrategy    KIDStrategy
+	algorithm      Algorithm
+}
+
+var _ key.Parameters = (*Parameters)(nil)
+
+// KeySizeInBytes returns the key size in bytes.
+func (p *Parameters) KeySizeInBytes() int { return p.keySizeInBytes }
+
+// KIDStrategy returns the "kid" header strategy.
+func (p *Parameters) KIDStrategy() KIDStrategy { return p.kidStrategy }
+
+// Algorithm returns the JWA algorithm.
+func (p *Parameters) Algorithm() Algorithm { return p.algorithm }
+
+func validateParameters(p *Parameters) error {
+	if p == nil {
+		return fmt.Errorf("parameters is nil")
+	}
+	switch p.kidStrategy {
+	case Base64EncodedKeyIDAsKID, IgnoredKID, CustomKID:
+	default:
+		return fmt.Errorf("unsupported kid strategy: %v", p.kidStrategy)
+	}
+	minKeySize, err := minKeySizeInBytes(p.algorithm)
+	if err != nil {
+		return err
+	}
+	if p.keySizeInBytes < minKeySize {
+		return fmt.Errorf("invalid key size: %v, want >= %v for %v", p.keySizeInBytes, minKeySize, p.algorithm)
+	}
+	return nil
+}
+
+// NewParameters creates a new JWT HMAC Parameters value.
+//
+// keySizeInBytes must be at least the output size of the hash function used
+// by algorithm.
+func NewParameters(keySizeInBytes int, kidStrategy KIDStrategy, algorithm Algorithm) (*Parameters, error) {
+	p := &Parameters{
+		keySizeInBytes: keySizeInBytes,
+		kidStrategy:    kidStrategy,
+		algorithm:      algorithm,
+	}
+	if err := validateParameters(p); err != nil {
+		return nil, fmt.Errorf("jwthmac.NewParameters: %v", err)
+	}
+	return p, nil
+}
+
+// HasIDRequirement tells whether the key has an ID requirement.
+//
+// Only keys with the Base64EncodedKeyIDAsKID strategy have an ID requirement.
+func (p *Parameters) HasIDRequirement() bool { return p.kidStrategy == Base64EncodedKeyIDAsKID }
+
+// Equal tells whether this parameters value is equal to other.
+func (p *Parameters) Equal(other key.Parameters) bool {
+	that, ok := other.(*Parameters)
+	return ok && p.keySizeInBytes == that.keySizeInBytes &&
+		p.kidStrategy == that.kidStrategy &&
+		p.algorithm == that.algorithm
+}
+
+// computeKID returns the "kid" header value for a key with the given strategy.
+func computeKID(kidStrategy KIDStrategy, idRequirement uint32, customKID string, hasCustomKID bool) (string, bool, error) {
+	switch kidStrategy {
+	case Base64EncodedKeyIDAsKID:
+		if hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must not be set for %v", kidStrategy)
+		}
+		buf := binary.BigEndian.AppendUint32(nil, idRequirement)
+		return base64.RawURLEncoding.EncodeToString(buf), true, nil
+	case IgnoredKID:
+		if hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must not be set for %v", kidStrategy)
+		}
+		return "", false, nil
+	case CustomKID:
+		if !hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must be set for %v", kidStrategy)
+		}
+		return customKID, true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported kid strategy: %v", kidStrategy)
+	}
+}
+
+// Key represents a JWT HMAC key.
+type Key struct {
+	keyBytes      secretdata.Bytes
+	idRequirement uint32
+	kid           string
+	hasKID        bool
+	parameters    *Parameters
+}
+
+var _ key.Key = (*Key)(nil)
+
+// KeyOpts contains the options for creating a new [Key].
+type KeyOpts struct {
+	// KeyBytes is the HMAC key material. Its length must match
+	// Parameters.KeySizeInBytes().
+	KeyBytes secretdata.Bytes
+	// IDRequirement is the key ID. It must be zero unless the KID strategy is
+	// Base64EncodedKeyIDAsKID.
+	IDRequirement uint32
+	// CustomKID is the "kid" header value; only used if HasCustomKID is true.
+	CustomKID string
+	// HasCustomKID must be true if and only if the KID strategy is CustomKID.
+	HasCustomKID bool
+	// Parameters are the key parameters. They must be non-nil.
+	Parameters *Parameters
+}
+
+// NewKey creates a new JWT HMAC Key value.
+func NewKey(opts KeyOpts) (*Key, error) {
+	if err := validateParameters(opts.Parameters); err != nil {
+		return nil, fmt.Errorf("jwthmac.NewKey: %v", err)
+	}
+	if !opts.Parameters.HasIDRequirement() && opts.IDRequirement != 0 {
+		return nil, fmt.Errorf("jwthmac.NewKey: key ID must be zero for %v", opts.Parameters.KIDStrategy())
+	}
+	if opts.KeyBytes.Len() != opts.Parameters.KeySizeInBytes() {
+		return nil, fmt.Errorf("jwthmac.NewKey: key size = %v, want %v", opts.KeyBytes.Len(), opts.Parameters.KeySizeInBytes())
+	}
+	kid, hasKID, err := computeKID(opts.Parameters.KIDStrategy(), opts.IDRequirement, opts.CustomKID, opts.HasCustomKID)
+	if err != nil {
+		return nil, fmt.Errorf("jwthmac.NewKey: %v", err)
+	}
+	return &Key{
+		keyBytes:      opts.KeyBytes,
+		idRequirement: opts.IDRequirement,
+		kid:           kid,
+		hasKID:        hasKID,
+		parameters:    opts.Parameters,
+	}, nil
+}
+
+// KeyBytes returns the key material.
+//
+// This function provides access to partial key material. See
+// https://developers.google.com/tink/design/access_control#access_of_parts_of_a_key
+// for more information.
+func (k *Key) KeyBytes() secretdata.Bytes { return k.keyBytes }
+
+// KID returns the "kid" header value set by tokens computed with this key and
+// whether it is set.
+//
+// The second return value is false for keys with the IgnoredKID strategy.
+func (k *Key) KID() (string, bool) { return k.kid, k.hasKID }
+
+// Parameters returns the parameters of this key.
+func (k *Key) Parameters() key.Parameters { return k.parameters }
+
+// IDRequirement returns the key ID and whether it is required.
+func (k *Key) IDRequirement() (uint32, bool) {
+	return k.idRequirement, k.Parameters().HasIDRequirement()
+}
+
+// Equal tells whether this key value is equal to other.
+func (k *Key) Equal(other key.Key) bool {
+	that, ok := other.(*Key)
+	return ok && k.Parameters().Equal(that.Parameters()) &&
+		k.idRequirement == that.idRequirement &&
+		k.kid == that.kid && k.hasKID == that.hasKID &&
+		k.keyBytes.Equal(that.keyBytes)
+}
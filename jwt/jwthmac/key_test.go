@@ -0,0 +1,208 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwthmac_test
+
+import (
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/jwt/jwthmac"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+func mustCreateParameters(t *testing.T, keySize int, kidStrategy jwthmac.KIDStrategy, algorithm jwthmac.Algorithm) *jwthmac.Parameters {
+	t.Helper()
+	params, err := jwthmac.NewParameters(keySize, kidStrategy, algorithm)
+	if err != nil {
+		t.Fatalf("jwthmac.NewParameters(%v, %v, %v) err = %v, want nil", keySize, kidStrategy, algorithm, err)
+	}
+	return params
+}
+
+func mustRandomBytes(t *testing.T, size int) secretdata.Bytes {
+	t.Helper()
+	b, err := secretdata.NewBytesFromRand(uint32(size))
+	if err != nil {
+		t.Fatalf("secretdata.NewBytesFromRand(%v) err = %v, want nil", size, err)
+	}
+	return b
+}
+
+func TestNewParametersFails(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		keySize     int
+		kidStrategy jwthmac.KIDStrategy
+		algorithm   jwthmac.Algorithm
+	}{
+		{"unknown kid strategy", 32, jwthmac.UnknownKIDStrategy, jwthmac.HS256},
+		{"unknown algorithm", 32, jwthmac.IgnoredKID, jwthmac.UnknownAlgorithm},
+		{"HS256 key too short", 31, jwthmac.IgnoredKID, jwthmac.HS256},
+		{"HS384 key too short", 47, jwthmac.IgnoredKID, jwthmac.HS384},
+		{"HS512 key too short", 63, jwthmac.IgnoredKID, jwthmac.HS512},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jwthmac.NewParameters(tc.keySize, tc.kidStrategy, tc.algorithm); err == nil {
+				t.Errorf("jwthmac.NewParameters(%v, %v, %v) err = nil, want error", tc.keySize, tc.kidStrategy, tc.algorithm)
+			}
+		})
+	}
+}
+
+func TestNewParameters(t *testing.T) {
+	for _, tc := range []struct {
+		keySize   int
+		algorithm jwthmac.Algorithm
+	}{
+		{32, jwthmac.HS256},
+		{64, jwthmac.HS256},
+		{48, jwthmac.HS384},
+		{64, jwthmac.HS512},
+	} {
+		for _, kidStrategy := range []jwthmac.KIDStrategy{jwthmac.Base64EncodedKeyIDAsKID, jwthmac.IgnoredKID, jwthmac.CustomKID} {
+			t.Run(tc.algorithm.String()+"_"+kidStrategy.String(), func(t *testing.T) {
+				params := mustCreateParameters(t, tc.keySize, kidStrategy, tc.algorithm)
+				if got := params.KeySizeInBytes(); got != tc.keySize {
+					t.Errorf("params.KeySizeInBytes() = %v, want %v", got, tc.keySize)
+				}
+				if got := params.KIDStrategy(); got != kidStrategy {
+					t.Errorf("params.KIDStrategy() = %v, want %v", got, kidStrategy)
+				}
+				if got := params.Algorithm(); got != tc.algorithm {
+					t.Errorf("params.Algorithm() = %v, want %v", got, tc.algorithm)
+				}
+				if got, want := params.HasIDRequirement(), kidStrategy == jwthmac.Base64EncodedKeyIDAsKID; got != want {
+					t.Errorf("params.HasIDRequirement() = %v, want %v", got, want)
+				}
+				if !params.Equal(mustCreateParameters(t, tc.keySize, kidStrategy, tc.algorithm)) {
+					t.Errorf("params.Equal() = false, want true")
+				}
+				if params.Equal(mustCreateParameters(t, tc.keySize+1, kidStrategy, tc.algorithm)) {
+					t.Errorf("params.Equal() with different key size = true, want false")
+				}
+			})
+		}
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	keyBytes := mustRandomBytes(t, 32)
+	for _, tc := range []struct {
+		name       string
+		opts       jwthmac.KeyOpts
+		wantKID    string
+		wantHasKID bool
+	}{
+		{
+			name: "Base64EncodedKeyIDAsKID",
+			opts: jwthmac.KeyOpts{
+				KeyBytes:      keyBytes,
+				IDRequirement: 0x01020304,
+				Parameters:    mustCreateParameters(t, 32, jwthmac.Base64EncodedKeyIDAsKID, jwthmac.HS256),
+			},
+			wantKID:    "AQIDBA",
+			wantHasKID: true,
+		},
+		{
+			name: "IgnoredKID",
+			opts: jwthmac.KeyOpts{
+				KeyBytes:   keyBytes,
+				Parameters: mustCreateParameters(t, 32, jwthmac.IgnoredKID, jwthmac.HS256),
+			},
+		},
+		{
+			name: "CustomKID",
+			opts: jwthmac.KeyOpts{
+				KeyBytes:     keyBytes,
+				CustomKID:    "custom-kid",
+				HasCustomKID: true,
+				Parameters:   mustCreateParameters(t, 32, jwthmac.CustomKID, jwthmac.HS256),
+			},
+			wantKID:    "custom-kid",
+			wantHasKID: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := jwthmac.NewKey(tc.opts)
+			if err != nil {
+				t.Fatalf("jwthmac.NewKey() err = %v, want nil", err)
+			}
+			if kid, hasKID := k.KID(); kid != tc.wantKID || hasKID != tc.wantHasKID {
+				t.Errorf("k.KID() = %q, %v, want %q, %v", kid, hasKID, tc.wantKID, tc.wantHasKID)
+			}
+			if !k.KeyBytes().Equal(keyBytes) {
+				t.Errorf("k.KeyBytes() != keyBytes")
+			}
+			if idRequirement, _ := k.IDRequirement(); idRequirement != tc.opts.IDRequirement {
+				t.Errorf("k.IDRequirement() = %v, want %v", idRequirement, tc.opts.IDRequirement)
+			}
+			other, err := jwthmac.NewKey(tc.opts)
+			if err != nil {
+				t.Fatalf("jwthmac.NewKey() err = %v, want nil", err)
+			}
+			if !k.Equal(other) {
+				t.Errorf("k.Equal(other) = false, want true")
+			}
+		})
+	}
+}
+
+func TestNewKeyFails(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts jwthmac.KeyOpts
+	}{
+		{
+			name: "nil parameters",
+			opts: jwthmac.KeyOpts{KeyBytes: mustRandomBytes(t, 32)},
+		},
+		{
+			name: "wrong key size",
+			opts: jwthmac.KeyOpts{
+				KeyBytes:   mustRandomBytes(t, 33),
+				Parameters: mustCreateParameters(t, 32, jwthmac.IgnoredKID, jwthmac.HS256),
+			},
+		},
+		{
+			name: "ID requirement with IgnoredKID",
+			opts: jwthmac.KeyOpts{
+				KeyBytes:      mustRandomBytes(t, 32),
+				IDRequirement: 1,
+				Parameters:    mustCreateParameters(t, 32, jwthmac.IgnoredKID, jwthmac.HS256),
+			},
+		},
+		{
+			name: "missing custom kid",
+			opts: jwthmac.KeyOpts{
+				KeyBytes:   mustRandomBytes(t, 32),
+				Parameters: mustCreateParameters(t, 32, jwthmac.CustomKID, jwthmac.HS256),
+			},
+		},
+		{
+			name: "custom kid with Base64EncodedKeyIDAsKID",
+			opts: jwthmac.KeyOpts{
+				KeyBytes:     mustRandomBytes(t, 32),
+				CustomKID:    "kid",
+				HasCustomKID: true,
+				Parameters:   mustCreateParameters(t, 32, jwthmac.Base64EncodedKeyIDAsKID, jwthmac.HS256),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jwthmac.NewKey(tc.opts); err == nil {
+				t.Errorf("jwthmac.NewKey() err = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwthmac
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	jwthmacpb "github.com/tink-crypto/tink-go/v2/proto/jwt_hmac_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const (
+	typeURL = "type.googleapis.com/google.crypto.tink.JwtHmacKey"
+
+	// protoVersion is the accepted [jwthmacpb.JwtHmacKey] proto version.
+	protoVersion = 0
+)
+
+func protoAlgorithmFromAlgorithm(algorithm Algorithm) (jwthmacpb.JwtHmacAlgorithm, error) {
+	switch algorithm {
+	case HS256:
+		return jwthmacpb.JwtHmacAlgorithm_HS256, nil
+	case HS384:
+		return jwthmacpb.JwtHmacAlgorithm_HS384, nil
+	case HS512:
+		return jwthmacpb.JwtHmacAlgorithm_HS512, nil
+	default:
+		return jwthmacpb.JwtHmacAlgorithm_HS_UNKNOWN, fmt.Errorf("unknown algorithm: %v", algorithm)
+	}
+}
+
+func algorithmFromProto(algorithm jwthmacpb.JwtHmacAlgorithm) (Algorithm, error) {
+	switch algorithm {
+	case jwthmacpb.JwtHmacAlgorithm_HS256:
+		return HS256, nil
+	case jwthmacpb.JwtHmacAlgorithm_HS384:
+		return HS384, nil
+	case jwthmacpb.JwtHmacAlgorithm_HS512:
+		return HS512, nil
+	default:
+		return UnknownAlgorithm, fmt.Errorf("unknown algorithm: %v", algorithm)
+	}
+}
+
+func protoOutputPrefixTypeFromKIDStrategy(kidStrategy KIDStrategy) (tinkpb.OutputPrefixType, error) {
+	switch kidStrategy {
+	case Base64EncodedKeyIDAsKID:
+		return tinkpb.OutputPrefixType_TINK, nil
+	case IgnoredKID, CustomKID:
+		return tinkpb.OutputPrefixType_RAW, nil
+	default:
+		return tinkpb.OutputPrefixType_UNKNOWN_PREFIX, fmt.Errorf("unknown kid strategy: %v", kidStrategy)
+	}
+}
+
+func kidStrategyFromProto(outputPrefixType tinkpb.OutputPrefixType, hasCustomKID bool) (KIDStrategy, error) {
+	switch outputPrefixType {
+	case tinkpb.OutputPrefixType_TINK:
+		if hasCustomKID {
+			return UnknownKIDStrategy, fmt.Errorf("custom kid is not allowed for TINK keys")
+		}
+		return Base64EncodedKeyIDAsKID, nil
+	case tinkpb.OutputPrefixType_RAW:
+		if hasCustomKID {
+			return CustomKID, nil
+		}
+		return IgnoredKID, nil
+	default:
+		return UnknownKIDStrategy, fmt.Errorf("unsupported output prefix type: %v", outputPrefixType)
+	}
+}
+
+type keySerializer struct{}
+
+var _ protoserialization.KeySerializer = (*keySerializer)(nil)
+
+func (s *keySerializer) SerializeKey(key key.Key) (*protoserialization.KeySerialization, error) {
+	jwtHMACKey, ok := key.(*Key)
+	if !ok {
+		return nil, fmt.Errorf("invalid key type: %T, want *jwthmac.Key", key)
+	}
+	// This is nil if Key was created as a struct literal.
+	if jwtHMACKey.parameters == nil {
+		return nil, fmt.Errorf("invalid key: parameters is nil")
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(jwtHMACKey.parameters.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := protoAlgorithmFromAlgorithm(jwtHMACKey.parameters.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	protoKey := &jwthmacpb.JwtHmacKey{
+		Version:   protoVersion,
+		Algorithm: algorithm,
+		KeyValue:  jwtHMACKey.KeyBytes().Data(insecuresecretdataaccess.Token{}),
+	}
+	if jwtHMACKey.parameters.KIDStrategy() == CustomKID {
+		protoKey.CustomKid = &jwthmacpb.JwtHmacKey_CustomKid{Value: jwtHMACKey.kid}
+	}
+	serializedKey, err := proto.Marshal(protoKey)
+	if err != nil {
+		return nil, err
+	}
+	// idRequirement is zero if the key doesn't have a key requirement.
+	idRequirement, _ := jwtHMACKey.IDRequirement()
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}
+	return protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+}
+
+type keyParser struct{}
+
+var _ protoserialization.KeyParser = (*keyParser)(nil)
+
+func (s *keyParser) ParseKey(keySerialization *protoserialization.KeySerialization) (key.Key, error) {
+	if keySerialization == nil {
+		return nil, fmt.Errorf("key serialization is nil")
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetTypeUrl() != typeURL {
+		return nil, fmt.Errorf("invalid key type URL: %v", keyData.GetTypeUrl())
+	}
+	if keyData.GetKeyMaterialType() != tinkpb.KeyData_SYMMETRIC {
+		return nil, fmt.Errorf("invalid key material type: %v", keyData.GetKeyMaterialType())
+	}
+	protoKey := new(jwthmacpb.JwtHmacKey)
+	if err := proto.Unmarshal(keyData.GetValue(), protoKey); err != nil {
+		return nil, err
+	}
+	if protoKey.GetVersion() != protoVersion {
+		return nil, fmt.Errorf("key has unsupported version: %v", protoKey.GetVersion())
+	}
+	kidStrategy, err := kidStrategyFromProto(keySerialization.OutputPrefixType(), protoKey.GetCustomKid() != nil)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := algorithmFromProto(protoKey.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	params, err := NewParameters(len(protoKey.GetKeyValue()), kidStrategy, algorithm)
+	if err != nil {
+		return nil, err
+	}
+	// keySerialization.IDRequirement() returns zero if the key doesn't have a key requirement.
+	keyID, _ := keySerialization.IDRequirement()
+	return NewKey(KeyOpts{
+		KeyBytes:      secretdata.NewBytesFromData(protoKey.GetKeyValue(), insecuresecretdataaccess.Token{}),
+		IDRequirement: keyID,
+		CustomKID:     protoKey.GetCustomKid().GetValue(),
+		HasCustomKID:  protoKey.GetCustomKid() != nil,
+		Parameters:    params,
+	})
+}
+
+type parametersSerializer struct{}
+
+var _ protoserialization.ParametersSerializer = (*parametersSerializer)(nil)
+
+func (s *parametersSerializer) Serialize(parameters key.Parameters) (*tinkpb.KeyTemplate, error) {
+	params, ok := parameters.(*Parameters)
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters type: got %T, want *jwthmac.Parameters", parameters)
+	}
+	if err := validateParameters(params); err != nil {
+		return nil, err
+	}
+	if params.KIDStrategy() == CustomKID {
+		return nil, fmt.Errorf("parameters with %v cannot be serialized to a key template", CustomKID)
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(params.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := protoAlgorithmFromAlgorithm(params.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	serializedFormat, err := proto.Marshal(&jwthmacpb.JwtHmacKeyFormat{
+		Version:   protoVersion,
+		Algorithm: algorithm,
+		KeySize:   uint32(params.KeySizeInBytes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          typeURL,
+		OutputPrefixType: outputPrefixType,
+		Value:            serializedFormat,
+	}, nil
+}
+
+type parametersParser struct{}
+
+var _ protoserialization.ParametersParser = (*parametersParser)(nil)
+
+func (s *parametersParser) Parse(keyTemplate *tinkpb.KeyTemplate) (key.Parameters, error) {
+	if keyTemplate.GetTypeUrl() != typeURL {
+		return nil, fmt.Errorf("invalid type URL: got %q, want %q", keyTemplate.GetTypeUrl(), typeURL)
+	}
+	format := new(jwthmacpb.JwtHmacKeyFormat)
+	if err := proto.Unmarshal(keyTemplate.GetValue(), format); err != nil {
+		return nil, err
+	}
+	if format.GetVersion() != protoVersion {
+		return nil, fmt.Errorf("key format has unsupported version: %v", format.GetVersion())
+	}
+	kidStrategy, err := kidStrategyFromProto(keyTemplate.GetOutputPrefixType(), false)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := algorithmFromProto(format.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	return NewParameters(int(format.GetKeySize()), kidStrategy, algorithm)
+}
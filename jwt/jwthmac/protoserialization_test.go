@@ -0,0 +1,197 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwthmac
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	jwthmacpb "github.com/tink-crypto/tink-go/v2/proto/jwt_hmac_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	t.Helper()
+	serialized, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("proto.Marshal(%v) err = %v, want nil", message, err)
+	}
+	return serialized
+}
+
+func mustCreateKeySerialization(t *testing.T, keyData *tinkpb.KeyData, outputPrefixType tinkpb.OutputPrefixType, idRequirement uint32) *protoserialization.KeySerialization {
+	t.Helper()
+	ks, err := protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+	if err != nil {
+		t.Fatalf("protoserialization.NewKeySerialization(%v, %v, %v) err = %v, want nil", keyData, outputPrefixType, idRequirement, err)
+	}
+	return ks
+}
+
+func TestSerializeAndParseKey(t *testing.T) {
+	keyValue := bytes.Repeat([]byte{0x42}, 48)
+	for _, tc := range []struct {
+		name             string
+		kidStrategy      KIDStrategy
+		outputPrefixType tinkpb.OutputPrefixType
+		idRequirement    uint32
+		customKID        *jwthmacpb.JwtHmacKey_CustomKid
+	}{
+		{"Base64EncodedKeyIDAsKID", Base64EncodedKeyIDAsKID, tinkpb.OutputPrefixType_TINK, 0x01020304, nil},
+		{"IgnoredKID", IgnoredKID, tinkpb.OutputPrefixType_RAW, 0, nil},
+		{"CustomKID", CustomKID, tinkpb.OutputPrefixType_RAW, 0, &jwthmacpb.JwtHmacKey_CustomKid{Value: "custom"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params, err := NewParameters(len(keyValue), tc.kidStrategy, HS384)
+			if err != nil {
+				t.Fatalf("NewParameters() err = %v, want nil", err)
+			}
+			k, err := NewKey(KeyOpts{
+				KeyBytes:      secretdata.NewBytesFromData(keyValue, insecuresecretdataaccess.Token{}),
+				IDRequirement: tc.idRequirement,
+				CustomKID:     tc.customKID.GetValue(),
+				HasCustomKID:  tc.customKID != nil,
+				Parameters:    params,
+			})
+			if err != nil {
+				t.Fatalf("NewKey() err = %v, want nil", err)
+			}
+			wantSerialization := mustCreateKeySerialization(t, &tinkpb.KeyData{
+				TypeUrl: typeURL,
+				Value: mustMarshal(t, &jwthmacpb.JwtHmacKey{
+					Version:   0,
+					Algorithm: jwthmacpb.JwtHmacAlgorithm_HS384,
+					KeyValue:  keyValue,
+					CustomKid: tc.customKID,
+				}),
+				KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+			}, tc.outputPrefixType, tc.idRequirement)
+
+			got, err := (&keySerializer{}).SerializeKey(k)
+			if err != nil {
+				t.Fatalf("keySerializer.SerializeKey() err = %v, want nil", err)
+			}
+			if !got.Equal(wantSerialization) {
+				t.Errorf("keySerializer.SerializeKey() = %v, want %v", got, wantSerialization)
+			}
+			gotKey, err := (&keyParser{}).ParseKey(wantSerialization)
+			if err != nil {
+				t.Fatalf("keyParser.ParseKey() err = %v, want nil", err)
+			}
+			if !gotKey.Equal(k) {
+				t.Errorf("keyParser.ParseKey() = %v, want %v", gotKey, k)
+			}
+		})
+	}
+}
+
+func TestParseKeyFails(t *testing.T) {
+	validKey := &jwthmacpb.JwtHmacKey{
+		Algorithm: jwthmacpb.JwtHmacAlgorithm_HS256,
+		KeyValue:  bytes.Repeat([]byte{0x01}, 32),
+	}
+	withCustomKID := proto.Clone(validKey).(*jwthmacpb.JwtHmacKey)
+	withCustomKID.CustomKid = &jwthmacpb.JwtHmacKey_CustomKid{Value: "kid"}
+	wrongVersion := proto.Clone(validKey).(*jwthmacpb.JwtHmacKey)
+	wrongVersion.Version = 1
+	shortKey := proto.Clone(validKey).(*jwthmacpb.JwtHmacKey)
+	shortKey.KeyValue = shortKey.KeyValue[:31]
+	unknownAlgorithm := proto.Clone(validKey).(*jwthmacpb.JwtHmacKey)
+	unknownAlgorithm.Algorithm = jwthmacpb.JwtHmacAlgorithm_HS_UNKNOWN
+
+	for _, tc := range []struct {
+		name             string
+		protoKey         *jwthmacpb.JwtHmacKey
+		outputPrefixType tinkpb.OutputPrefixType
+	}{
+		{"TINK with custom kid", withCustomKID, tinkpb.OutputPrefixType_TINK},
+		{"LEGACY", validKey, tinkpb.OutputPrefixType_LEGACY},
+		{"wrong version", wrongVersion, tinkpb.OutputPrefixType_RAW},
+		{"key too short", shortKey, tinkpb.OutputPrefixType_RAW},
+		{"unknown algorithm", unknownAlgorithm, tinkpb.OutputPrefixType_RAW},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var idRequirement uint32
+			if tc.outputPrefixType != tinkpb.OutputPrefixType_RAW {
+				idRequirement = 123
+			}
+			serialization := mustCreateKeySerialization(t, &tinkpb.KeyData{
+				TypeUrl:         typeURL,
+				Value:           mustMarshal(t, tc.protoKey),
+				KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+			}, tc.outputPrefixType, idRequirement)
+			if _, err := (&keyParser{}).ParseKey(serialization); err == nil {
+				t.Errorf("keyParser.ParseKey() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSerializeAndParseParameters(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		parameters *Parameters
+		want       *tinkpb.KeyTemplate
+	}{
+		{
+			name:       "Base64EncodedKeyIDAsKID",
+			parameters: &Parameters{keySizeInBytes: 64, kidStrategy: Base64EncodedKeyIDAsKID, algorithm: HS512},
+			want: &tinkpb.KeyTemplate{
+				TypeUrl:          typeURL,
+				OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+				Value:            mustMarshal(t, &jwthmacpb.JwtHmacKeyFormat{Algorithm: jwthmacpb.JwtHmacAlgorithm_HS512, KeySize: 64}),
+			},
+		},
+		{
+			name:       "IgnoredKID",
+			parameters: &Parameters{keySizeInBytes: 32, kidStrategy: IgnoredKID, algorithm: HS256},
+			want: &tinkpb.KeyTemplate{
+				TypeUrl:          typeURL,
+				OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+				Value:            mustMarshal(t, &jwthmacpb.JwtHmacKeyFormat{Algorithm: jwthmacpb.JwtHmacAlgorithm_HS256, KeySize: 32}),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&parametersSerializer{}).Serialize(tc.parameters)
+			if err != nil {
+				t.Fatalf("parametersSerializer.Serialize() err = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("parametersSerializer.Serialize() diff (-want +got):\n%s", diff)
+			}
+			gotParams, err := (&parametersParser{}).Parse(got)
+			if err != nil {
+				t.Fatalf("parametersParser.Parse() err = %v, want nil", err)
+			}
+			if !gotParams.Equal(tc.parameters) {
+				t.Errorf("parametersParser.Parse() = %v, want %v", gotParams, tc.parameters)
+			}
+		})
+	}
+}
+
+func TestSerializeParametersFailsWithCustomKID(t *testing.T) {
+	params := &Parameters{keySizeInBytes: 32, kidStrategy: CustomKID, algorithm: HS256}
+	if _, err := (&parametersSerializer{}).Serialize(params); err == nil {
+		t.Errorf("parametersSerializer.Serialize(%v) err = nil, want error", params)
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtrsassapkcs1
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/signature"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+// KIDStrategy defines how the "kid" header of a JWT is handled.
+//
+// There are three options:
+//
+//   - IgnoredKID: the "kid" header is not set when signing and is ignored
+//     when verifying.
+//   - Base64EncodedKeyIDAsKID: the "kid" header is set to the base64url
+//     encoding of the big endian key ID when signing, and is required to match
+//     when verifying.
+//   - CustomKID: the "kid" header is set to a fixed value chosen when the key
+//     is created. When verifying, the header is optional but must match if
+//     present.
+type KIDStrategy int
+
+const (
+	// UnknownKIDStrategy is the default value of KIDStrategy.
+	UnknownKIDStrategy KIDStrategy = iota
+	// Base64EncodedKeyIDAsKID sets the "kid" header to the base64url encoding
+	// of the big endian key ID.
+	Base64EncodedKeyIDAsKID
+	// IgnoredKID does not set the "kid" header and ignores it on verification.
+	IgnoredKID
+	// CustomKID sets the "kid" header to a value fixed at key creation time.
+	CustomKID
+)
+
+func (ks KIDStrategy) String() string {
+	switch ks {
+	case Base64EncodedKeyIDAsKID:
+		return "BASE64_ENCODED_KEY_ID_AS_KID"
+	case IgnoredKID:
+		return "IGNORED_KID"
+	case CustomKID:
+		return "CUSTOM_KID"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Algorithm is the JWA algorithm of a JWT RSA-SSA-PKCS1 key.
+type Algorithm int
+
+const (
+	// UnknownAlgorithm is the default value of Algorithm.
+	UnknownAlgorithm Algorithm = iota
+	// RS256 is RSASSA-PKCS1-v1_5 using SHA-256.
+	RS256
+	// RS384 is RSASSA-PKCS1-v1_5 using SHA-384.
+	RS384
+	// RS512 is RSASSA-PKCS1-v1_5 using SHA-512.
+	RS512
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case RS256:
+		return "RS256"
+	case RS384:
+		return "RS384"
+	case RS512:
+		return "RS512"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func hashForAlgorithm(algorithm Algorithm) (string, error) {
+	switch algorithm {
+	case RS256:
+		return "SHA256", nil
+	case RS384:
+		return "SHA384", nil
+	case RS512:
+		return "SHA512", nil
+	default:
+		return "", fmt.Errorf("unsupported algorithm: %v", algorithm)
+	}
+}
+
+const (
+	f4          = 65537
+	maxExponent = 1<<31 - 1
+)
+
+// Parameters represents the parameters of a JWT RSA-SSA-PKCS1 key.
+type Parameters struct {
+	modulusSizeInBits int
+	publicExponent    int
+	kidStrategy       KIDStrategy
+	algorithm         Algorithm
+}
+
+var _ key.Parameters = (*Parameters)(nil)
+
+// ModulusSizeInBits returns the modulus size in bits.
+func (p *Parameters) ModulusSizeInBits() int { return p.modulusSizeInBits }
+
+// PublicExponent returns the public exponent.
+func (p *Parameters) PublicExponent() int { return p.publicExponent }
+
+// KIDStrategy returns the "kid" header strategy.
+func (p *Parameters) KIDStrategy() KIDStrategy { return p.kidStrategy }
+
+// Algorithm returns the JWA algorithm.
+func (p *Parameters) Algorithm() Algorithm { return p.algorithm }
+
+// ParametersOpts contains the options for creating new [Parameters].
+type ParametersOpts struct {
+	ModulusSizeInBits int
+	PublicExponent    int
+	KIDStrategy       KIDStrategy
+	Algorithm         Algorithm
+}
+
+func validateParameters(p *Parameters) error {
+	if p == nil {
+		return fmt.Errorf("parameters is nil")
+	}
+	// These are consistent with the checks by tink-java and tink-cc.
+	if p.modulusSizeInBits < 2048 {
+		return fmt.Errorf("invalid modulus size: %v, want >= 2048", p.modulusSizeInBits)
+	}
+	if p.publicExponent < f4 || p.publicExponent > maxExponent || p.publicExponent%2 != 1 {
+		return fmt.Errorf("invalid public exponent: %v", p.publicExponent)
+	}
+	switch p.kidStrategy {
+	case Base64EncodedKeyIDAsKID, IgnoredKID, CustomKID:
+	default:
+		return fmt.Errorf("unsupported kid strategy: %v", p.kidStrategy)
+	}
+	if _, err := hashForAlgorithm(p.algorithm); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NewParameters creates a new JWT RSA-SSA-PKCS1 Parameters value.
+func NewParameters(opts ParametersOpts) (*Parameters, error) {
+	p := &Parameters{
+		modulusSizeInBits: opts.ModulusSizeInBits,
+		publicExponent:    opts.PublicExponent,
+		kidStrategy:       opts.KIDStrategy,
+		algorithm:         opts.Algorithm,
+	}
+	if err := validateParameters(p); err != nil {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewParameters: %v", err)
+	}
+	return p, nil
+}
+
+// HasIDRequirement tells whether the key has an ID requirement.
+//
+// Only keys with the Base64EncodedKeyIDAsKID strategy have an ID requirement.
+func (p *Parameters) HasIDRequirement() bool { return p.kidStrategy == Base64EncodedKeyIDAsKID }
+
+// Equal tells whether this parameters value is equal to other.
+func (p *Parameters) Equal(other key.Parameters) bool {
+	that, ok := other.(*Parameters)
+	return ok && p.modulusSizeInBits == that.modulusSizeInBits &&
+		p.publicExponent == that.publicExponent &&
+		p.kidStrategy == that.kidStrategy &&
+		p.algorithm == that.algorithm
+}
+
+// computeKID returns the "kid" header value for a key with the given strategy.
+func computeKID(kidStrategy KIDStrategy, idRequirement uint32, customKID string, hasCustomKID bool) (string, bool, error) {
+	switch kidStrategy {
+	case Base64EncodedKeyIDAsKID:
+		if hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must not be set for %v", kidStrategy)
+		}
+		buf := binary.BigEndian.AppendUint32(nil, idRequirement)
+		return base64.RawURLEncoding.EncodeToString(buf), true, nil
+	case IgnoredKID:
+		if hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must not be set for %v", kidStrategy)
+		}
+		return "", false, nil
+	case CustomKID:
+		if !hasCustomKID {
+			return "", false, fmt.Errorf("custom kid must be set for %v", kidStrategy)
+		}
+		return customKID, true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported kid strategy: %v", kidStrategy)
+	}
+}
+
+// PublicKey represents a JWT RSA-SSA-PKCS1 public key.
+type PublicKey struct {
+	modulus       []byte // Big integer value in big-endian encoding.
+	idRequirement uint32
+	kid           string
+	hasKID        bool
+	parameters    *Parameters
+}
+
+var _ key.Key = (*PublicKey)(nil)
+
+// PublicKeyOpts contains the options for creating a new [PublicKey].
+type PublicKeyOpts struct {
+	// Modulus is the big-endian encoded modulus.
+	Modulus []byte
+	// IDRequirement is the key ID. It must be zero unless the KID strategy is
+	// Base64EncodedKeyIDAsKID.
+	IDRequirement uint32
+	// CustomKID is the "kid" header value; only used if HasCustomKID is true.
+	CustomKID string
+	// HasCustomKID must be true if and only if the KID strategy is CustomKID.
+	HasCustomKID bool
+	// Parameters are the key parameters. They must be non-nil.
+	Parameters *Parameters
+}
+
+// NewPublicKey creates a new JWT RSA-SSA-PKCS1 PublicKey value.
+func NewPublicKey(opts PublicKeyOpts) (*PublicKey, error) {
+	if err := validateParameters(opts.Parameters); err != nil {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewPublicKey: %v", err)
+	}
+	modulus := new(big.Int).SetBytes(opts.Modulus)
+	if modulus.BitLen() != opts.Parameters.ModulusSizeInBits() {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewPublicKey: invalid modulus bit-length: %v, want %v", modulus.BitLen(), opts.Parameters.ModulusSizeInBits())
+	}
+	if !opts.Parameters.HasIDRequirement() && opts.IDRequirement != 0 {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewPublicKey: key ID must be zero for %v", opts.Parameters.KIDStrategy())
+	}
+	kid, hasKID, err := computeKID(opts.Parameters.KIDStrategy(), opts.IDRequirement, opts.CustomKID, opts.HasCustomKID)
+	if err != nil {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewPublicKey: %v", err)
+	}
+	return &PublicKey{
+		modulus:       modulus.Bytes(),
+		idRequirement: opts.IDRequirement,
+		kid:           kid,
+		hasKID:        hasKID,
+		parameters:    opts.Parameters,
+	}, nil
+}
+
+// Modulus returns the public key modulus.
+func (k *PublicKey) Modulus() []byte { return bytes.Clone(k.modulus) }
+
+// KID returns the "kid" header value set by tokens signed with this key and
+// whether it is set.
+//
+// The second return value is false for keys with the IgnoredKID strategy.
+func (k *PublicKey) KID() (string, bool) { return k.kid, k.hasKID }
+
+// Parameters returns the parameters of this key.
+func (k *PublicKey) Parameters() key.Parameters { return k.parameters }
+
+// IDRequirement returns the key ID and whether it is required.
+func (k *PublicKey) IDRequirement() (uint32, bool) {
+	return k.idRequirement, k.Parameters().HasIDRequirement()
+}
+
+// Equal tells whether this key value is equal to other.
+func (k *PublicKey) Equal(other key.Key) bool {
+	that, ok := other.(*PublicKey)
+	return ok && k.parameters.Equal(that.parameters) &&
+		k.idRequirement == that.idRequirement &&
+		k.kid == that.kid && k.hasKID == that.hasKID &&
+		bytes.Equal(k.modulus, that.modulus)
+}
+
+// PrivateKey represents a JWT RSA-SSA-PKCS1 private key.
+type PrivateKey struct {
+	publicKey  *PublicKey
+	privateKey *rsa.PrivateKey
+}
+
+var _ key.Key = (*PrivateKey)(nil)
+
+// PrivateKeyValues contains the values of a private key.
+type PrivateKeyValues struct {
+	P, Q secretdata.Bytes
+	D    secretdata.Bytes
+	// dp, dq and QInv must be computed by the Go library.
+	// See https://pkg.go.dev/crypto/rsa#PrivateKey.
+}
+
+// privateKeySelfCheck signs a test message with a private key and verifies
+// the signature with the corresponding public key.
+func privateKeySelfCheck(privateKey *rsa.PrivateKey, algorithm Algorithm) error {
+	hash, err := hashForAlgorithm(algorithm)
+	if err != nil {
+		return err
+	}
+	signer, err := signature.New_RSA_SSA_PKCS1_Signer(hash, privateKey)
+	if err != nil {
+		return err
+	}
+	verifier, err := signature.New_RSA_SSA_PKCS1_Verifier(hash, &privateKey.PublicKey)
+	if err != nil {
+		return err
+	}
+	testMessage := []byte("Tink and Wycheproof.")
+	sig, err := signer.Sign(testMessage)
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(sig, testMessage)
+}
+
+// NewPrivateKey creates a new JWT RSA-SSA-PKCS1 PrivateKey value from a public
+// key and private key values.
+func NewPrivateKey(publicKey *PublicKey, opts PrivateKeyValues) (*PrivateKey, error) {
+	if publicKey == nil || publicKey.parameters == nil {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewPrivateKey: invalid public key")
+	}
+	privateKey := rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{
+			N: new(big.Int).SetBytes(publicKey.modulus),
+			E: publicKey.parameters.PublicExponent(),
+		},
+		D: new(big.Int).SetBytes(opts.D.Data(insecuresecretdataaccess.Token{})),
+		Primes: []*big.Int{
+			new(big.Int).SetBytes(opts.P.Data(insecuresecretdataaccess.Token{})),
+			new(big.Int).SetBytes(opts.Q.Data(insecuresecretdataaccess.Token{})),
+		},
+	}
+	if err := privateKey.Validate(); err != nil {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewPrivateKey: %v", err)
+	}
+	privateKey.Precompute()
+	if err := privateKeySelfCheck(&privateKey, publicKey.parameters.Algorithm()); err != nil {
+		return nil, fmt.Errorf("jwtrsassapkcs1.NewPrivateKey: %v", err)
+	}
+	return &PrivateKey{
+		publicKey:  publicKey,
+		privateKey: &privateKey,
+	}, nil
+}
+
+// P returns the prime P.
+func (k *PrivateKey) P() secretdata.Bytes {
+	return secretdata.NewBytesFromData(k.privateKey.Primes[0].Bytes(), insecuresecretdataaccess.Token{})
+}
+
+// Q returns the prime Q.
+func (k *PrivateKey) Q() secretdata.Bytes {
+	return secretdata.NewBytesFromData(k.privateKey.Primes[1].Bytes(), insecuresecretdataaccess.Token{})
+}
+
+// D returns the private exponent D.
+func (k *PrivateKey) D() secretdata.Bytes {
+	return secretdata.NewBytesFromData(k.privateKey.D.Bytes(), insecuresecretdataaccess.Token{})
+}
+
+// DP returns D mod (P-1).
+func (k *PrivateKey) DP() secretdata.Bytes {
+	return secretdata.NewBytesFromData(k.privateKey.Precomputed.Dp.Bytes(), insecuresecretdataaccess.Token{})
+}
+
+// DQ returns D mod (Q-1).
+func (k *PrivateKey) DQ() secretdata.Bytes {
+	return secretdata.NewBytesFromData(k.privateKey.Precomputed.Dq.Bytes(), insecuresecretdataaccess.Token{})
+}
+
+// QInv returns the inverse of Q mod P.
+func (k *PrivateKey) QInv() secretdata.Bytes {
+	return secretdata.NewBytesFromData(k.privateKey.Precomputed.Qinv.Bytes(), insecuresecretdataaccess.Token{})
+}
+
+// PublicKey returns the corresponding public key.
+func (k *PrivateKey) PublicKey() (key.Key, error) { return k.publicKey, nil }
+
+// Parameters returns the parameters of this key.
+func (k *PrivateKey) Parameters() key.Parameters { return k.publicKey.Parameters() }
+
+// IDRequirement returns the key ID and whether it is required.
+func (k *PrivateKey) IDRequirement() (uint32, bool) { return k.publicKey.IDRequirement() }
+
+// KID returns the "kid" header value and whether it is set.
+func (k *PrivateKey) KID() (string, bool) { return k.publicKey.KID() }
+
+// Equal tells whether this key value is equal to other.
+func (k *PrivateKey) Equal(other key.Key) bool {
+	that, ok := other.(*PrivateKey)
+	return ok && k.publicKey.Equal(that.publicKey) && k.privateKey.Equal(that.privateKey)
+}
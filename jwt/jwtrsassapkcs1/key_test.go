@@ -0,0 +1,205 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtrsassapkcs1_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/jwt/jwtrsassapkcs1"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+const f4 = 65537
+
+func mustCreateParameters(t *testing.T, opts jwtrsassapkcs1.ParametersOpts) *jwtrsassapkcs1.Parameters {
+	t.Helper()
+	params, err := jwtrsassapkcs1.NewParameters(opts)
+	if err != nil {
+		t.Fatalf("jwtrsassapkcs1.NewParameters(%v) err = %v, want nil", opts, err)
+	}
+	return params
+}
+
+func mustGenerateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() err = %v, want nil", err)
+	}
+	return k
+}
+
+func TestNewParametersFails(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts jwtrsassapkcs1.ParametersOpts
+	}{
+		{
+			name: "small modulus",
+			opts: jwtrsassapkcs1.ParametersOpts{ModulusSizeInBits: 1024, PublicExponent: f4, KIDStrategy: jwtrsassapkcs1.IgnoredKID, Algorithm: jwtrsassapkcs1.RS256},
+		},
+		{
+			name: "small exponent",
+			opts: jwtrsassapkcs1.ParametersOpts{ModulusSizeInBits: 2048, PublicExponent: 3, KIDStrategy: jwtrsassapkcs1.IgnoredKID, Algorithm: jwtrsassapkcs1.RS256},
+		},
+		{
+			name: "even exponent",
+			opts: jwtrsassapkcs1.ParametersOpts{ModulusSizeInBits: 2048, PublicExponent: f4 + 1, KIDStrategy: jwtrsassapkcs1.IgnoredKID, Algorithm: jwtrsassapkcs1.RS256},
+		},
+		{
+			name: "unknown kid strategy",
+			opts: jwtrsassapkcs1.ParametersOpts{ModulusSizeInBits: 2048, PublicExponent: f4, KIDStrategy: jwtrsassapkcs1.UnknownKIDStrategy, Algorithm: jwtrsassapkcs1.RS256},
+		},
+		{
+			name: "unknown algorithm",
+			opts: jwtrsassapkcs1.ParametersOpts{ModulusSizeInBits: 2048, PublicExponent: f4, KIDStrategy: jwtrsassapkcs1.IgnoredKID, Algorithm: jwtrsassapkcs1.UnknownAlgorithm},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jwtrsassapkcs1.NewParameters(tc.opts); err == nil {
+				t.Errorf("jwtrsassapkcs1.NewParameters(%v) err = nil, want error", tc.opts)
+			}
+		})
+	}
+}
+
+func TestNewParameters(t *testing.T) {
+	for _, kidStrategy := range []jwtrsassapkcs1.KIDStrategy{jwtrsassapkcs1.Base64EncodedKeyIDAsKID, jwtrsassapkcs1.IgnoredKID, jwtrsassapkcs1.CustomKID} {
+		for _, algorithm := range []jwtrsassapkcs1.Algorithm{jwtrsassapkcs1.RS256, jwtrsassapkcs1.RS384, jwtrsassapkcs1.RS512} {
+			t.Run(kidStrategy.String()+"_"+algorithm.String(), func(t *testing.T) {
+				opts := jwtrsassapkcs1.ParametersOpts{
+					ModulusSizeInBits: 3072,
+					PublicExponent:    f4,
+					KIDStrategy:       kidStrategy,
+					Algorithm:         algorithm,
+				}
+				params := mustCreateParameters(t, opts)
+				if params.ModulusSizeInBits() != 3072 || params.PublicExponent() != f4 ||
+					params.KIDStrategy() != kidStrategy || params.Algorithm() != algorithm {
+					t.Errorf("params = %v, want values from %v", params, opts)
+				}
+				if got, want := params.HasIDRequirement(), kidStrategy == jwtrsassapkcs1.Base64EncodedKeyIDAsKID; got != want {
+					t.Errorf("params.HasIDRequirement() = %v, want %v", got, want)
+				}
+				if !params.Equal(mustCreateParameters(t, opts)) {
+					t.Errorf("params.Equal() = false, want true")
+				}
+				opts.ModulusSizeInBits = 4096
+				if params.Equal(mustCreateParameters(t, opts)) {
+					t.Errorf("params.Equal() with different modulus size = true, want false")
+				}
+			})
+		}
+	}
+}
+
+func TestNewKeys(t *testing.T) {
+	rsaKey := mustGenerateRSAKey(t)
+	for _, tc := range []struct {
+		name          string
+		kidStrategy   jwtrsassapkcs1.KIDStrategy
+		idRequirement uint32
+		customKID     string
+		hasCustomKID  bool
+		wantKID       string
+		wantHasKID    bool
+	}{
+		{"Base64EncodedKeyIDAsKID", jwtrsassapkcs1.Base64EncodedKeyIDAsKID, 0x01020304, "", false, "AQIDBA", true},
+		{"IgnoredKID", jwtrsassapkcs1.IgnoredKID, 0, "", false, "", false},
+		{"CustomKID", jwtrsassapkcs1.CustomKID, 0, "custom", true, "custom", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params := mustCreateParameters(t, jwtrsassapkcs1.ParametersOpts{
+				ModulusSizeInBits: 2048,
+				PublicExponent:    f4,
+				KIDStrategy:       tc.kidStrategy,
+				Algorithm:         jwtrsassapkcs1.RS256,
+			})
+			pubKey, err := jwtrsassapkcs1.NewPublicKey(jwtrsassapkcs1.PublicKeyOpts{
+				Modulus:       rsaKey.N.Bytes(),
+				IDRequirement: tc.idRequirement,
+				CustomKID:     tc.customKID,
+				HasCustomKID:  tc.hasCustomKID,
+				Parameters:    params,
+			})
+			if err != nil {
+				t.Fatalf("jwtrsassapkcs1.NewPublicKey() err = %v, want nil", err)
+			}
+			if kid, hasKID := pubKey.KID(); kid != tc.wantKID || hasKID != tc.wantHasKID {
+				t.Errorf("pubKey.KID() = %q, %v, want %q, %v", kid, hasKID, tc.wantKID, tc.wantHasKID)
+			}
+			token := insecuresecretdataaccess.Token{}
+			privKey, err := jwtrsassapkcs1.NewPrivateKey(pubKey, jwtrsassapkcs1.PrivateKeyValues{
+				P: secretdata.NewBytesFromData(rsaKey.Primes[0].Bytes(), token),
+				Q: secretdata.NewBytesFromData(rsaKey.Primes[1].Bytes(), token),
+				D: secretdata.NewBytesFromData(rsaKey.D.Bytes(), token),
+			})
+			if err != nil {
+				t.Fatalf("jwtrsassapkcs1.NewPrivateKey() err = %v, want nil", err)
+			}
+			gotPubKey, err := privKey.PublicKey()
+			if err != nil {
+				t.Fatalf("privKey.PublicKey() err = %v, want nil", err)
+			}
+			if !gotPubKey.Equal(pubKey) {
+				t.Errorf("privKey.PublicKey() = %v, want %v", gotPubKey, pubKey)
+			}
+			if !privKey.DP().Equal(secretdata.NewBytesFromData(rsaKey.Precomputed.Dp.Bytes(), token)) {
+				t.Errorf("privKey.DP() doesn't match")
+			}
+			if idRequirement, _ := privKey.IDRequirement(); idRequirement != tc.idRequirement {
+				t.Errorf("privKey.IDRequirement() = %v, want %v", idRequirement, tc.idRequirement)
+			}
+		})
+	}
+}
+
+func TestNewKeysFails(t *testing.T) {
+	rsaKey := mustGenerateRSAKey(t)
+	otherRSAKey := mustGenerateRSAKey(t)
+	params := mustCreateParameters(t, jwtrsassapkcs1.ParametersOpts{
+		ModulusSizeInBits: 2048,
+		PublicExponent:    f4,
+		KIDStrategy:       jwtrsassapkcs1.IgnoredKID,
+		Algorithm:         jwtrsassapkcs1.RS256,
+	})
+	if _, err := jwtrsassapkcs1.NewPublicKey(jwtrsassapkcs1.PublicKeyOpts{Modulus: rsaKey.N.Bytes()}); err == nil {
+		t.Errorf("jwtrsassapkcs1.NewPublicKey() with nil parameters err = nil, want error")
+	}
+	if _, err := jwtrsassapkcs1.NewPublicKey(jwtrsassapkcs1.PublicKeyOpts{Modulus: rsaKey.N.Bytes()[1:], Parameters: params}); err == nil {
+		t.Errorf("jwtrsassapkcs1.NewPublicKey() with short modulus err = nil, want error")
+	}
+	if _, err := jwtrsassapkcs1.NewPublicKey(jwtrsassapkcs1.PublicKeyOpts{Modulus: rsaKey.N.Bytes(), IDRequirement: 1, Parameters: params}); err == nil {
+		t.Errorf("jwtrsassapkcs1.NewPublicKey() with ID requirement err = nil, want error")
+	}
+	if _, err := jwtrsassapkcs1.NewPublicKey(jwtrsassapkcs1.PublicKeyOpts{Modulus: rsaKey.N.Bytes(), CustomKID: "kid", HasCustomKID: true, Parameters: params}); err == nil {
+		t.Errorf("jwtrsassapkcs1.NewPublicKey() with custom kid err = nil, want error")
+	}
+	pubKey, err := jwtrsassapkcs1.NewPublicKey(jwtrsassapkcs1.PublicKeyOpts{Modulus: rsaKey.N.Bytes(), Parameters: params})
+	if err != nil {
+		t.Fatalf("jwtrsassapkcs1.NewPublicKey() err = %v, want nil", err)
+	}
+	token := insecuresecretdataaccess.Token{}
+	if _, err := jwtrsassapkcs1.NewPrivateKey(pubKey, jwtrsassapkcs1.PrivateKeyValues{
+		P: secretdata.NewBytesFromData(otherRSAKey.Primes[0].Bytes(), token),
+		Q: secretdata.NewBytesFromData(otherRSAKey.Primes[1].Bytes(), token),
+		D: secretdata.NewBytesFromData(otherRSAKey.D.Bytes(), token),
+	}); err == nil {
+		t.Errorf("jwtrsassapkcs1.NewPrivateKey() with mismatched values err = nil, want error")
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtrsassapkcs1
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	jwtrsapb "github.com/tink-crypto/tink-go/v2/proto/jwt_rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	t.Helper()
+	serialized, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatalf("proto.Marshal(%v) err = %v, want nil", message, err)
+	}
+	return serialized
+}
+
+func mustCreateKeySerialization(t *testing.T, keyData *tinkpb.KeyData, outputPrefixType tinkpb.OutputPrefixType, idRequirement uint32) *protoserialization.KeySerialization {
+	t.Helper()
+	ks, err := protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+	if err != nil {
+		t.Fatalf("protoserialization.NewKeySerialization(%v, %v, %v) err = %v, want nil", keyData, outputPrefixType, idRequirement, err)
+	}
+	return ks
+}
+
+func TestSerializeAndParseKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() err = %v, want nil", err)
+	}
+	e := new(big.Int).SetInt64(int64(rsaKey.E)).Bytes()
+	for _, tc := range []struct {
+		name             string
+		kidStrategy      KIDStrategy
+		outputPrefixType tinkpb.OutputPrefixType
+		idRequirement    uint32
+		customKID        *jwtrsapb.JwtRsaSsaPkcs1PublicKey_CustomKid
+	}{
+		{"Base64EncodedKeyIDAsKID", Base64EncodedKeyIDAsKID, tinkpb.OutputPrefixType_TINK, 0x01020304, nil},
+		{"IgnoredKID", IgnoredKID, tinkpb.OutputPrefixType_RAW, 0, nil},
+		{"CustomKID", CustomKID, tinkpb.OutputPrefixType_RAW, 0, &jwtrsapb.JwtRsaSsaPkcs1PublicKey_CustomKid{Value: "custom"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params, err := NewParameters(ParametersOpts{
+				ModulusSizeInBits: 2048,
+				PublicExponent:    rsaKey.E,
+				KIDStrategy:       tc.kidStrategy,
+				Algorithm:         RS384,
+			})
+			if err != nil {
+				t.Fatalf("NewParameters() err = %v, want nil", err)
+			}
+			pubKey, err := NewPublicKey(PublicKeyOpts{
+				Modulus:       rsaKey.N.Bytes(),
+				IDRequirement: tc.idRequirement,
+				CustomKID:     tc.customKID.GetValue(),
+				HasCustomKID:  tc.customKID != nil,
+				Parameters:    params,
+			})
+			if err != nil {
+				t.Fatalf("NewPublicKey() err = %v, want nil", err)
+			}
+			token := insecuresecretdataaccess.Token{}
+			privKey, err := NewPrivateKey(pubKey, PrivateKeyValues{
+				P: secretdata.NewBytesFromData(rsaKey.Primes[0].Bytes(), token),
+				Q: secretdata.NewBytesFromData(rsaKey.Primes[1].Bytes(), token),
+				D: secretdata.NewBytesFromData(rsaKey.D.Bytes(), token),
+			})
+			if err != nil {
+				t.Fatalf("NewPrivateKey() err = %v, want nil", err)
+			}
+			protoPublicKey := &jwtrsapb.JwtRsaSsaPkcs1PublicKey{
+				Algorithm: jwtrsapb.JwtRsaSsaPkcs1Algorithm_RS384,
+				N:         rsaKey.N.Bytes(),
+				E:         e,
+				CustomKid: tc.customKID,
+			}
+			wantPublicSerialization := mustCreateKeySerialization(t, &tinkpb.KeyData{
+				TypeUrl:         verifierTypeURL,
+				Value:           mustMarshal(t, protoPublicKey),
+				KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+			}, tc.outputPrefixType, tc.idRequirement)
+			wantPrivateSerialization := mustCreateKeySerialization(t, &tinkpb.KeyData{
+				TypeUrl: signerTypeURL,
+				Value: mustMarshal(t, &jwtrsapb.JwtRsaSsaPkcs1PrivateKey{
+					PublicKey: protoPublicKey,
+					D:         rsaKey.D.Bytes(),
+					P:         rsaKey.Primes[0].Bytes(),
+					Q:         rsaKey.Primes[1].Bytes(),
+					Dp:        rsaKey.Precomputed.Dp.Bytes(),
+					Dq:        rsaKey.Precomputed.Dq.Bytes(),
+					Crt:       rsaKey.Precomputed.Qinv.Bytes(),
+				}),
+				KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+			}, tc.outputPrefixType, tc.idRequirement)
+
+			gotPublicSerialization, err := (&publicKeySerializer{}).SerializeKey(pubKey)
+			if err != nil {
+				t.Fatalf("publicKeySerializer.SerializeKey() err = %v, want nil", err)
+			}
+			if !gotPublicSerialization.Equal(wantPublicSerialization) {
+				t.Errorf("publicKeySerializer.SerializeKey() = %v, want %v", gotPublicSerialization, wantPublicSerialization)
+			}
+			gotPublicKey, err := (&publicKeyParser{}).ParseKey(wantPublicSerialization)
+			if err != nil {
+				t.Fatalf("publicKeyParser.ParseKey() err = %v, want nil", err)
+			}
+			if !gotPublicKey.Equal(pubKey) {
+				t.Errorf("publicKeyParser.ParseKey() = %v, want %v", gotPublicKey, pubKey)
+			}
+			gotPrivateSerialization, err := (&privateKeySerializer{}).SerializeKey(privKey)
+			if err != nil {
+				t.Fatalf("privateKeySerializer.SerializeKey() err = %v, want nil", err)
+			}
+			if !gotPrivateSerialization.Equal(wantPrivateSerialization) {
+				t.Errorf("privateKeySerializer.SerializeKey() = %v, want %v", gotPrivateSerialization, wantPrivateSerialization)
+			}
+			gotPrivateKey, err := (&privateKeyParser{}).ParseKey(wantPrivateSerialization)
+			if err != nil {
+				t.Fatalf("privateKeyParser.ParseKey() err = %v, want nil", err)
+			}
+			if !gotPrivateKey.Equal(privKey) {
+				t.Errorf("privateKeyParser.ParseKey() = %v, want %v", gotPrivateKey, privKey)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyFails(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() err = %v, want nil", err)
+	}
+	validKey := &jwtrsapb.JwtRsaSsaPkcs1PublicKey{
+		Algorithm: jwtrsapb.JwtRsaSsaPkcs1Algorithm_RS256,
+		N:         rsaKey.N.Bytes(),
+		E:         new(big.Int).SetInt64(int64(rsaKey.E)).Bytes(),
+	}
+	withCustomKID := proto.Clone(validKey).(*jwtrsapb.JwtRsaSsaPkcs1PublicKey)
+	withCustomKID.CustomKid = &jwtrsapb.JwtRsaSsaPkcs1PublicKey_CustomKid{Value: "kid"}
+	wrongVersion := proto.Clone(validKey).(*jwtrsapb.JwtRsaSsaPkcs1PublicKey)
+	wrongVersion.Version = 1
+	unknownAlgorithm := proto.Clone(validKey).(*jwtrsapb.JwtRsaSsaPkcs1PublicKey)
+	unknownAlgorithm.Algorithm = jwtrsapb.JwtRsaSsaPkcs1Algorithm_RS_UNKNOWN
+
+	for _, tc := range []struct {
+		name             string
+		protoKey         *jwtrsapb.JwtRsaSsaPkcs1PublicKey
+		outputPrefixType tinkpb.OutputPrefixType
+	}{
+		{"TINK with custom kid", withCustomKID, tinkpb.OutputPrefixType_TINK},
+		{"LEGACY", validKey, tinkpb.OutputPrefixType_LEGACY},
+		{"wrong version", wrongVersion, tinkpb.OutputPrefixType_RAW},
+		{"unknown algorithm", unknownAlgorithm, tinkpb.OutputPrefixType_RAW},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var idRequirement uint32
+			if tc.outputPrefixType != tinkpb.OutputPrefixType_RAW {
+				idRequirement = 123
+			}
+			serialization := mustCreateKeySerialization(t, &tinkpb.KeyData{
+				TypeUrl:         verifierTypeURL,
+				Value:           mustMarshal(t, tc.protoKey),
+				KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+			}, tc.outputPrefixType, idRequirement)
+			if _, err := (&publicKeyParser{}).ParseKey(serialization); err == nil {
+				t.Errorf("publicKeyParser.ParseKey() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSerializeAndParseParameters(t *testing.T) {
+	params := &Parameters{
+		modulusSizeInBits: 3072,
+		publicExponent:    65537,
+		kidStrategy:       Base64EncodedKeyIDAsKID,
+		algorithm:         RS512,
+	}
+	want := &tinkpb.KeyTemplate{
+		TypeUrl:          signerTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+		Value: mustMarshal(t, &jwtrsapb.JwtRsaSsaPkcs1KeyFormat{
+			Algorithm:         jwtrsapb.JwtRsaSsaPkcs1Algorithm_RS512,
+			ModulusSizeInBits: 3072,
+			PublicExponent:    []byte{0x01, 0x00, 0x01},
+		}),
+	}
+	got, err := (&parametersSerializer{}).Serialize(params)
+	if err != nil {
+		t.Fatalf("parametersSerializer.Serialize() err = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("parametersSerializer.Serialize() diff (-want +got):\n%s", diff)
+	}
+	gotParams, err := (&parametersParser{}).Parse(got)
+	if err != nil {
+		t.Fatalf("parametersParser.Parse() err = %v, want nil", err)
+	}
+	if !gotParams.Equal(params) {
+		t.Errorf("parametersParser.Parse() = %v, want %v", gotParams, params)
+	}
+
+	params.kidStrategy = CustomKID
+	if _, err := (&parametersSerializer{}).Serialize(params); err == nil {
+		t.Errorf("parametersSerializer.Serialize(%v) err = nil, want error", params)
+	}
+}
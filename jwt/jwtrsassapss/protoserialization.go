@@ -0,0 +1,380 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtrsassapss
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	jwtrsapb "github.com/tink-crypto/tink-go/v2/proto/jwt_rsa_ssa_pss_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	signerTypeURL   = "type.googleapis.com/google.crypto.tink.JwtRsaSsaPssPrivateKey"
+	verifierTypeURL = "type.googleapis.com/google.crypto.tink.JwtRsaSsaPssPublicKey"
+
+	// publicKeyProtoVersion is the accepted [jwtrsapb.JwtRsaSsaPssPublicKey]
+	// proto version.
+	publicKeyProtoVersion = 0
+	// privateKeyProtoVersion is the accepted [jwtrsapb.JwtRsaSsaPssPrivateKey]
+	// proto version.
+	privateKeyProtoVersion = 0
+)
+
+func protoAlgorithmFromAlgorithm(algorithm Algorithm) (jwtrsapb.JwtRsaSsaPssAlgorithm, error) {
+	switch algorithm {
+	case PS256:
+		return jwtrsapb.JwtRsaSsaPssAlgorithm_PS256, nil
+	case PS384:
+		return jwtrsapb.JwtRsaSsaPssAlgorithm_PS384, nil
+	case PS512:
+		return jwtrsapb.JwtRsaSsaPssAlgorithm_PS512, nil
+	default:
+		return jwtrsapb.JwtRsaSsaPssAlgorithm_PS_UNKNOWN, fmt.Errorf("unknown algorithm: %v", algorithm)
+	}
+}
+
+func algorithmFromProto(algorithm jwtrsapb.JwtRsaSsaPssAlgorithm) (Algorithm, error) {
+	switch algorithm {
+	case jwtrsapb.JwtRsaSsaPssAlgorithm_PS256:
+		return PS256, nil
+	case jwtrsapb.JwtRsaSsaPssAlgorithm_PS384:
+		return PS384, nil
+	case jwtrsapb.JwtRsaSsaPssAlgorithm_PS512:
+		return PS512, nil
+	default:
+		return UnknownAlgorithm, fmt.Errorf("unknown algorithm: %v", algorithm)
+	}
+}
+
+func protoOutputPrefixTypeFromKIDStrategy(kidStrategy KIDStrategy) (tinkpb.OutputPrefixType, error) {
+	switch kidStrategy {
+	case Base64EncodedKeyIDAsKID:
+		return tinkpb.OutputPrefixType_TINK, nil
+	case IgnoredKID, CustomKID:
+		return tinkpb.OutputPrefixType_RAW, nil
+	default:
+		return tinkpb.OutputPrefixType_UNKNOWN_PREFIX, fmt.Errorf("unknown kid strategy: %v", kidStrategy)
+	}
+}
+
+func kidStrategyFromProto(outputPrefixType tinkpb.OutputPrefixType, hasCustomKID bool) (KIDStrategy, error) {
+	switch outputPrefixType {
+	case tinkpb.OutputPrefixType_TINK:
+		if hasCustomKID {
+			return UnknownKIDStrategy, fmt.Errorf("custom kid is not allowed for TINK keys")
+		}
+		return Base64EncodedKeyIDAsKID, nil
+	case tinkpb.OutputPrefixType_RAW:
+		if hasCustomKID {
+			return CustomKID, nil
+		}
+		return IgnoredKID, nil
+	default:
+		return UnknownKIDStrategy, fmt.Errorf("unsupported output prefix type: %v", outputPrefixType)
+	}
+}
+
+func createProtoPublicKey(k *PublicKey) (*jwtrsapb.JwtRsaSsaPssPublicKey, error) {
+	algorithm, err := protoAlgorithmFromAlgorithm(k.parameters.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	protoKey := &jwtrsapb.JwtRsaSsaPssPublicKey{
+		Version:   publicKeyProtoVersion,
+		Algorithm: algorithm,
+		N:         k.Modulus(),
+		E:         new(big.Int).SetUint64(uint64(k.parameters.PublicExponent())).Bytes(),
+	}
+	if k.parameters.KIDStrategy() == CustomKID {
+		protoKey.CustomKid = &jwtrsapb.JwtRsaSsaPssPublicKey_CustomKid{Value: k.kid}
+	}
+	return protoKey, nil
+}
+
+func newPublicKeyFromProto(protoKey *jwtrsapb.JwtRsaSsaPssPublicKey, outputPrefixType tinkpb.OutputPrefixType, keyID uint32) (*PublicKey, error) {
+	if protoKey.GetVersion() != publicKeyProtoVersion {
+		return nil, fmt.Errorf("public key has unsupported version: %v", protoKey.GetVersion())
+	}
+	kidStrategy, err := kidStrategyFromProto(outputPrefixType, protoKey.GetCustomKid() != nil)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := algorithmFromProto(protoKey.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	// Tolerate leading zeros in modulus encoding.
+	modulus := new(big.Int).SetBytes(protoKey.GetN())
+	exponent := new(big.Int).SetBytes(protoKey.GetE())
+	if !exponent.IsInt64() {
+		return nil, fmt.Errorf("public exponent can't fit in a 64 bit integer")
+	}
+	params, err := NewParameters(ParametersOpts{
+		ModulusSizeInBits: modulus.BitLen(),
+		PublicExponent:    int(exponent.Int64()),
+		KIDStrategy:       kidStrategy,
+		Algorithm:         algorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewPublicKey(PublicKeyOpts{
+		Modulus:       modulus.Bytes(),
+		IDRequirement: keyID,
+		CustomKID:     protoKey.GetCustomKid().GetValue(),
+		HasCustomKID:  protoKey.GetCustomKid() != nil,
+		Parameters:    params,
+	})
+}
+
+type publicKeySerializer struct{}
+
+var _ protoserialization.KeySerializer = (*publicKeySerializer)(nil)
+
+func (s *publicKeySerializer) SerializeKey(key key.Key) (*protoserialization.KeySerialization, error) {
+	publicKey, ok := key.(*PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid key type: %T, want *jwtrsassapss.PublicKey", key)
+	}
+	// This is nil if PublicKey was created as a struct literal.
+	if publicKey.parameters == nil {
+		return nil, fmt.Errorf("invalid key: parameters is nil")
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(publicKey.parameters.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	protoKey, err := createProtoPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(protoKey)
+	if err != nil {
+		return nil, err
+	}
+	// idRequirement is zero if the key doesn't have a key requirement.
+	idRequirement, _ := publicKey.IDRequirement()
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         verifierTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}
+	return protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+}
+
+type publicKeyParser struct{}
+
+var _ protoserialization.KeyParser = (*publicKeyParser)(nil)
+
+func (s *publicKeyParser) ParseKey(keySerialization *protoserialization.KeySerialization) (key.Key, error) {
+	if keySerialization == nil {
+		return nil, fmt.Errorf("key serialization is nil")
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetTypeUrl() != verifierTypeURL {
+		return nil, fmt.Errorf("invalid key type URL: %v", keyData.GetTypeUrl())
+	}
+	if keyData.GetKeyMaterialType() != tinkpb.KeyData_ASYMMETRIC_PUBLIC {
+		return nil, fmt.Errorf("invalid key material type: %v", keyData.GetKeyMaterialType())
+	}
+	protoKey := new(jwtrsapb.JwtRsaSsaPssPublicKey)
+	if err := proto.Unmarshal(keyData.GetValue(), protoKey); err != nil {
+		return nil, err
+	}
+	// keySerialization.IDRequirement() returns zero if the key doesn't have a key requirement.
+	keyID, _ := keySerialization.IDRequirement()
+	return newPublicKeyFromProto(protoKey, keySerialization.OutputPrefixType(), keyID)
+}
+
+type privateKeySerializer struct{}
+
+var _ protoserialization.KeySerializer = (*privateKeySerializer)(nil)
+
+func (s *privateKeySerializer) SerializeKey(key key.Key) (*protoserialization.KeySerialization, error) {
+	privateKey, ok := key.(*PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid key type: %T, want *jwtrsassapss.PrivateKey", key)
+	}
+	// This is nil if PrivateKey was created as a struct literal.
+	if privateKey.publicKey == nil {
+		return nil, fmt.Errorf("invalid key: public key is nil")
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(privateKey.publicKey.parameters.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	protoPublicKey, err := createProtoPublicKey(privateKey.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	token := insecuresecretdataaccess.Token{}
+	protoKey := &jwtrsapb.JwtRsaSsaPssPrivateKey{
+		Version:   privateKeyProtoVersion,
+		PublicKey: protoPublicKey,
+		D:         privateKey.D().Data(token),
+		P:         privateKey.P().Data(token),
+		Q:         privateKey.Q().Data(token),
+		Dp:        privateKey.DP().Data(token),
+		Dq:        privateKey.DQ().Data(token),
+		Crt:       privateKey.QInv().Data(token),
+	}
+	serializedKey, err := proto.Marshal(protoKey)
+	if err != nil {
+		return nil, err
+	}
+	// idRequirement is zero if the key doesn't have a key requirement.
+	idRequirement, _ := privateKey.IDRequirement()
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         signerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}
+	return protoserialization.NewKeySerialization(keyData, outputPrefixType, idRequirement)
+}
+
+type privateKeyParser struct{}
+
+var _ protoserialization.KeyParser = (*privateKeyParser)(nil)
+
+func removeLeadingZeros(keyBytes []byte) []byte {
+	return new(big.Int).SetBytes(keyBytes).Bytes()
+}
+
+func (s *privateKeyParser) ParseKey(keySerialization *protoserialization.KeySerialization) (key.Key, error) {
+	if keySerialization == nil {
+		return nil, fmt.Errorf("key serialization is nil")
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetTypeUrl() != signerTypeURL {
+		return nil, fmt.Errorf("invalid key type URL: %v", keyData.GetTypeUrl())
+	}
+	if keyData.GetKeyMaterialType() != tinkpb.KeyData_ASYMMETRIC_PRIVATE {
+		return nil, fmt.Errorf("invalid key material type: %v", keyData.GetKeyMaterialType())
+	}
+	protoKey := new(jwtrsapb.JwtRsaSsaPssPrivateKey)
+	if err := proto.Unmarshal(keyData.GetValue(), protoKey); err != nil {
+		return nil, err
+	}
+	if protoKey.GetVersion() != privateKeyProtoVersion {
+		return nil, fmt.Errorf("private key has unsupported version: %v", protoKey.GetVersion())
+	}
+	// keySerialization.IDRequirement() returns zero if the key doesn't have a key requirement.
+	keyID, _ := keySerialization.IDRequirement()
+	publicKey, err := newPublicKeyFromProto(protoKey.GetPublicKey(), keySerialization.OutputPrefixType(), keyID)
+	if err != nil {
+		return nil, err
+	}
+	token := insecuresecretdataaccess.Token{}
+	privateKey, err := NewPrivateKey(publicKey, PrivateKeyValues{
+		P: secretdata.NewBytesFromData(protoKey.GetP(), token),
+		Q: secretdata.NewBytesFromData(protoKey.GetQ(), token),
+		D: secretdata.NewBytesFromData(protoKey.GetD(), token),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the precomputed values match the ones in the proto.
+	if !privateKey.DP().Equal(secretdata.NewBytesFromData(removeLeadingZeros(protoKey.GetDp()), token)) {
+		return nil, fmt.Errorf("private key DP doesn't match")
+	}
+	if !privateKey.DQ().Equal(secretdata.NewBytesFromData(removeLeadingZeros(protoKey.GetDq()), token)) {
+		return nil, fmt.Errorf("private key DQ doesn't match")
+	}
+	if !privateKey.QInv().Equal(secretdata.NewBytesFromData(removeLeadingZeros(protoKey.GetCrt()), token)) {
+		return nil, fmt.Errorf("private key QInv doesn't match")
+	}
+	return privateKey, nil
+}
+
+type parametersSerializer struct{}
+
+var _ protoserialization.ParametersSerializer = (*parametersSerializer)(nil)
+
+func (s *parametersSerializer) Serialize(parameters key.Parameters) (*tinkpb.KeyTemplate, error) {
+	params, ok := parameters.(*Parameters)
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters type: got %T, want *jwtrsassapss.Parameters", parameters)
+	}
+	if err := validateParameters(params); err != nil {
+		return nil, err
+	}
+	if params.KIDStrategy() == CustomKID {
+		return nil, fmt.Errorf("parameters with %v cannot be serialized to a key template", CustomKID)
+	}
+	outputPrefixType, err := protoOutputPrefixTypeFromKIDStrategy(params.KIDStrategy())
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := protoAlgorithmFromAlgorithm(params.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	serializedFormat, err := proto.Marshal(&jwtrsapb.JwtRsaSsaPssKeyFormat{
+		Version:           privateKeyProtoVersion,
+		Algorithm:         algorithm,
+		ModulusSizeInBits: uint32(params.ModulusSizeInBits()),
+		PublicExponent:    new(big.Int).SetUint64(uint64(params.PublicExponent())).Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          signerTypeURL,
+		OutputPrefixType: outputPrefixType,
+		Value:            serializedFormat,
+	}, nil
+}
+
+type parametersParser struct{}
+
+var _ protoserialization.ParametersParser = (*parametersParser)(nil)
+
+func (s *parametersParser) Parse(keyTemplate *tinkpb.KeyTemplate) (key.Parameters, error) {
+	if keyTemplate.GetTypeUrl() != signerTypeURL {
+		return nil, fmt.Errorf("invalid type URL: got %q, want %q", keyTemplate.GetTypeUrl(), signerTypeURL)
+	}
+	format := new(jwtrsapb.JwtRsaSsaPssKeyFormat)
+	if err := proto.Unmarshal(keyTemplate.GetValue(), format); err != nil {
+		return nil, err
+	}
+	if format.GetVersion() != privateKeyProtoVersion {
+		return nil, fmt.Errorf("key format has unsupported version: %v", format.GetVersion())
+	}
+	kidStrategy, err := kidStrategyFromProto(keyTemplate.GetOutputPrefixType(), false)
+	if err != nil {
+		return nil, err
+	}
+	algorithm, err := algorithmFromProto(format.GetAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(format.GetPublicExponent())
+	if !exponent.IsInt64() {
+		return nil, fmt.Errorf("public exponent can't fit in a 64 bit integer")
+	}
+	return NewParameters(ParametersOpts{
+		ModulusSizeInBits: int(format.GetModulusSizeInBits()),
+		PublicExponent:    int(exponent.Int64()),
+		KIDStrategy:       kidStrategy,
+		Algorithm:         algorithm,
+	})
+}
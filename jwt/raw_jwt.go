@@ -31,6 +31,12 @@ const (
 	claimIssuedAt   = "iat"
 	claimJWTID      = "jti"
 
+	headerAlgorithm = "alg"
+	headerType      = "typ"
+	headerKeyID     = "kid"
+	headerCritical  = "crit"
+	headerBase64    = "b64"
+
 	jwtTimestampMax = 253402300799
 	jwtTimestampMin = 0
 )
@@ -55,12 +61,19 @@ type RawJWTOptions struct {
 
 	TypeHeader        *string
 	WithoutExpiration bool
+
+	// CustomHeaders are additional header parameters, such as "cty" or
+	// "x5t#S256". They are part of the protected header and therefore covered
+	// by the signature or MAC. The headers "alg", "typ", "kid", "crit" and "b64"
+	// are reserved and can't be set.
+	CustomHeaders map[string]any
 }
 
 // RawJWT is an unsigned JSON Web Token (JWT), https://tools.ietf.org/html/rfc7519.
 type RawJWT struct {
-	jsonpb     *spb.Struct
-	typeHeader *string
+	jsonpb        *spb.Struct
+	typeHeader    *string
+	customHeaders map[string]*spb.Value
 }
 
 // NewRawJWT constructs a new RawJWT token based on the RawJwtOptions provided.
@@ -75,15 +88,26 @@ func NewRawJWT(opts *RawJWTOptions) (*RawJWT, error) {
 	if err := validatePayload(payload); err != nil {
 		return nil, err
 	}
+	customHeaders, err := createCustomHeaders(opts.CustomHeaders)
+	if err != nil {
+		return nil, err
+	}
 	return &RawJWT{
-		jsonpb:     payload,
-		typeHeader: opts.TypeHeader,
+		jsonpb:        payload,
+		typeHeader:    opts.TypeHeader,
+		customHeaders: customHeaders,
 	}, nil
 }
 
 // NewRawJWTFromJSON builds a RawJWT from a marshaled JSON.
 // Users shouldn't call this function and instead use NewRawJWT.
 func NewRawJWTFromJSON(typeHeader *string, jsonPayload []byte) (*RawJWT, error) {
+	return newRawJWTFromJSON(typeHeader, nil, jsonPayload)
+}
+
+// newRawJWTFromJSON builds a RawJWT from a marshaled JSON payload and the
+// header parameters that were decoded together with it.
+func newRawJWTFromJSON(typeHeader *string, customHeaders map[string]*spb.Value, jsonPayload []byte) (*RawJWT, error) {
 	payload := &spb.Struct{}
 	if err := payload.UnmarshalJSON(jsonPayload); err != nil {
 		return nil, err
@@ -92,8 +116,9 @@ func NewRawJWTFromJSON(typeHeader *string, jsonPayload []byte) (*RawJWT, error)
 		return nil, err
 	}
 	return &RawJWT{
-		jsonpb:     payload,
-		typeHeader: typeHeader,
+		jsonpb:        payload,
+		typeHeader:    typeHeader,
+		customHeaders: customHeaders,
 	}, nil
 }
 
@@ -115,6 +140,44 @@ func (r *RawJWT) TypeHeader() (string, error) {
 	return *r.typeHeader, nil
 }
 
+// HasCustomHeader returns whether a RawJWT contains the custom header parameter name.
+func (r *RawJWT) HasCustomHeader(name string) bool {
+	_, ok := r.customHeaders[name]
+	return ok
+}
+
+// CustomHeader returns the value of a custom header parameter or an error if it isn't present.
+// JSON values are returned as in [spb.Value.AsInterface].
+func (r *RawJWT) CustomHeader(name string) (any, error) {
+	val, ok := r.customHeaders[name]
+	if !ok {
+		return nil, fmt.Errorf("no header %q present", name)
+	}
+	return val.AsInterface(), nil
+}
+
+// StringHeader returns a custom header parameter of type string or an error if it isn't present or isn't a string.
+func (r *RawJWT) StringHeader(name string) (string, error) {
+	val, ok := r.customHeaders[name]
+	if !ok {
+		return "", fmt.Errorf("no header %q present", name)
+	}
+	str, ok := val.Kind.(*spb.Value_StringValue)
+	if !ok {
+		return "", fmt.Errorf("header %q isn't a string", name)
+	}
+	return str.StringValue, nil
+}
+
+// CustomHeaderNames returns a list with the name of custom header parameters in a RawJWT.
+func (r *RawJWT) CustomHeaderNames() []string {
+	names := []string{}
+	for key := range r.customHeaders {
+		names = append(names, key)
+	}
+	return names
+}
+
 // HasAudiences checks whether a JWT contains the audience claim ('aud').
 func (r *RawJWT) HasAudiences() bool {
 	return r.hasField(claimAudience)
@@ -495,6 +558,24 @@ func validateCustomClaims(cc map[string]any) error {
 	return nil
 }
 
+func createCustomHeaders(headers map[string]any) (map[string]*spb.Value, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+	customHeaders := make(map[string]*spb.Value, len(headers))
+	for k, v := range headers {
+		if isReservedHeader(k) {
+			return nil, fmt.Errorf("header %q is reserved, it can't be declared as a custom header", k)
+		}
+		val, err := spb.NewValue(v)
+		if err != nil {
+			return nil, err
+		}
+		customHeaders[k] = val
+	}
+	return customHeaders, nil
+}
+
 func setTimeValue(p *spb.Struct, claim string, val *time.Time) {
 	if val == nil {
 		return
@@ -540,3 +621,7 @@ func isRegisteredStringClaim(c string) bool {
 func isRegisteredTimeClaim(c string) bool {
 	return c == claimExpiration || c == claimNotBefore || c == claimIssuedAt
 }
+
+func isReservedHeader(h string) bool {
+	return h == headerAlgorithm || h == headerType || h == headerKeyID || h == headerCritical || h == headerBase64
+}
@@ -845,3 +845,59 @@ func TestGettingRegisteredClaimsThroughCustomFails(t *testing.T) {
 		}
 	}
 }
+
+func TestCustomHeaders(t *testing.T) {
+	opts := &jwt.RawJWTOptions{
+		WithoutExpiration: true,
+		CustomHeaders: map[string]any{
+			"cty":      "application/example",
+			"x5t#S256": "aGVsbG8",
+			"app":      map[string]any{"version": 2.0},
+		},
+	}
+	token, err := jwt.NewRawJWT(opts)
+	if err != nil {
+		t.Fatalf("jwt.NewRawJWT() err = %v, want nil", err)
+	}
+	if !token.HasCustomHeader("cty") {
+		t.Errorf("token.HasCustomHeader(%q) = false, want true", "cty")
+	}
+	if token.HasCustomHeader("enc") {
+		t.Errorf("token.HasCustomHeader(%q) = true, want false", "enc")
+	}
+	cty, err := token.StringHeader("cty")
+	if err != nil {
+		t.Fatalf("token.StringHeader(%q) err = %v, want nil", "cty", err)
+	}
+	if cty != "application/example" {
+		t.Errorf("token.StringHeader(%q) = %q, want %q", "cty", cty, "application/example")
+	}
+	if _, err := token.StringHeader("app"); err == nil {
+		t.Errorf("token.StringHeader(%q) err = nil, want error", "app")
+	}
+	app, err := token.CustomHeader("app")
+	if err != nil {
+		t.Fatalf("token.CustomHeader(%q) err = %v, want nil", "app", err)
+	}
+	if diff := cmp.Diff(map[string]any{"version": 2.0}, app); diff != "" {
+		t.Errorf("token.CustomHeader(%q) diff (-want +got):\n%s", "app", diff)
+	}
+	if _, err := token.CustomHeader("enc"); err == nil {
+		t.Errorf("token.CustomHeader(%q) err = nil, want error", "enc")
+	}
+	if diff := cmp.Diff([]string{"app", "cty", "x5t#S256"}, token.CustomHeaderNames(), cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("token.CustomHeaderNames() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestReservedCustomHeadersFail(t *testing.T) {
+	for _, h := range []string{"alg", "typ", "kid", "crit", "b64"} {
+		opts := &jwt.RawJWTOptions{
+			WithoutExpiration: true,
+			CustomHeaders:     map[string]any{h: "value"},
+		}
+		if _, err := jwt.NewRawJWT(opts); err == nil {
+			t.Errorf("jwt.NewRawJWT() with custom header %q err = nil, want error", h)
+		}
+	}
+}
@@ -49,6 +49,26 @@ func (v *VerifiedJWT) TypeHeader() (string, error) {
 	return v.token.TypeHeader()
 }
 
+// HasCustomHeader returns whether a VerifiedJWT contains the custom header parameter name.
+func (v *VerifiedJWT) HasCustomHeader(name string) bool {
+	return v.token.HasCustomHeader(name)
+}
+
+// CustomHeader returns the value of a custom header parameter or an error if it isn't present.
+func (v *VerifiedJWT) CustomHeader(name string) (any, error) {
+	return v.token.CustomHeader(name)
+}
+
+// StringHeader returns a custom header parameter of type string or an error if it isn't present or isn't a string.
+func (v *VerifiedJWT) StringHeader(name string) (string, error) {
+	return v.token.StringHeader(name)
+}
+
+// CustomHeaderNames returns a list with the name of custom header parameters in a VerifiedJWT.
+func (v *VerifiedJWT) CustomHeaderNames() []string {
+	return v.token.CustomHeaderNames()
+}
+
 // HasAudiences checks whether a JWT contains the audience claim ('aud').
 func (v *VerifiedJWT) HasAudiences() bool {
 	return v.token.HasAudiences()
@@ -0,0 +1,254 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// BuilderEntry describes a key to be added to a keyset by a [Builder].
+//
+// An entry either generates a new key from parameters or imports an existing
+// key. By default, the key is enabled, not primary and gets a random ID, or
+// its ID requirement if it has one.
+type BuilderEntry struct {
+	parameters key.Parameters
+	key        key.Key
+	status     KeyStatus
+	isPrimary  bool
+	hasFixedID bool
+	fixedID    uint32
+}
+
+// NewBuilderEntryFromParameters returns an entry that generates a new key
+// with the given parameters.
+func NewBuilderEntryFromParameters(parameters key.Parameters) *BuilderEntry {
+	return &BuilderEntry{parameters: parameters, status: Enabled}
+}
+
+// NewBuilderEntryFromKey returns an entry that imports k.
+func NewBuilderEntryFromKey(k key.Key) *BuilderEntry {
+	return &BuilderEntry{key: k, status: Enabled}
+}
+
+// WithFixedID sets the key ID of the entry. If the key of the entry has an
+// ID requirement, id must match it.
+func (e *BuilderEntry) WithFixedID(id uint32) *BuilderEntry {
+	e.hasFixedID = true
+	e.fixedID = id
+	return e
+}
+
+// WithRandomID makes the entry use a random key ID, unless the key has an ID
+// requirement. This is the default.
+func (e *BuilderEntry) WithRandomID() *BuilderEntry {
+	e.hasFixedID = false
+	e.fixedID = 0
+	return e
+}
+
+// WithStatus sets the status of the entry. Only [Enabled] and [Disabled] are
+// allowed.
+func (e *BuilderEntry) WithStatus(status KeyStatus) *BuilderEntry {
+	e.status = status
+	return e
+}
+
+// MakePrimary makes the entry the primary key of the keyset.
+func (e *BuilderEntry) MakePrimary() *BuilderEntry {
+	e.isPrimary = true
+	return e
+}
+
+// Builder builds a keyset handle from a list of entries.
+//
+// Example:
+//
+//	h, err := keyset.NewBuilder().
+//		AddEntry(keyset.NewBuilderEntryFromParameters(params).MakePrimary()).
+//		AddEntry(keyset.NewBuilderEntryFromKey(oldKey).WithStatus(keyset.Disabled)).
+//		Build()
+type Builder struct {
+	entries []*BuilderEntry
+}
+
+// NewBuilder returns an empty [Builder].
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// AddEntry appends entry to the keyset. Keys appear in the keyset in the
+// order in which they are added.
+func (b *Builder) AddEntry(entry *BuilderEntry) *Builder {
+	b.entries = append(b.entries, entry)
+	return b
+}
+
+// Build validates the entries, generates the keys of entries created from
+// parameters and returns a handle to the resulting keyset.
+//
+// It fails if there isn't exactly one primary entry, if the primary isn't
+// enabled, or if key IDs collide or don't match the ID requirement of their
+// keys.
+func (b *Builder) Build(opts ...Option) (*Handle, error) {
+	if len(b.entries) == 0 {
+		return nil, errors.New("keyset.Builder: no entries")
+	}
+	ids, err := b.assignIDs()
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Builder: %v", err)
+	}
+	now := time.Now().Unix()
+	ks := &tinkpb.Keyset{}
+	metadata := make(map[uint32]keyMetadata)
+	hasPrimary := false
+	for i, entry := range b.entries {
+		if entry.status != Enabled && entry.status != Disabled {
+			return nil, fmt.Errorf("keyset.Builder: entry %d has invalid status %v", i, entry.status)
+		}
+		if entry.isPrimary {
+			if hasPrimary {
+				return nil, errors.New("keyset.Builder: multiple primary entries")
+			}
+			if entry.status != Enabled {
+				return nil, fmt.Errorf("keyset.Builder: primary entry %d is not enabled", i)
+			}
+			hasPrimary = true
+			ks.PrimaryKeyId = ids[i]
+		}
+		protoKey, err := entry.protoKey(ids[i])
+		if err != nil {
+			return nil, fmt.Errorf("keyset.Builder: entry %d: %v", i, err)
+		}
+		md := keyMetadata{creationTime: now}
+		if entry.isPrimary {
+			md.activationTime = now
+		}
+		metadata[ids[i]] = md
+		ks.Key = append(ks.Key, protoKey)
+	}
+	if !hasPrimary {
+		return nil, errors.New("keyset.Builder: no primary entry")
+	}
+	h, err := newWithOptions(ks, append([]Option{withKeyMetadata(metadata)}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Builder: %v", err)
+	}
+	return h, nil
+}
+
+// idRequirement returns the ID requirement of the entry's key. Keys generated
+// from parameters take whatever ID the builder assigns.
+func (e *BuilderEntry) idRequirement() (uint32, bool, error) {
+	switch {
+	case e.key != nil && e.parameters == nil:
+		id, required := e.key.IDRequirement()
+		return id, required, nil
+	case e.parameters != nil && e.key == nil:
+		return 0, false, nil
+	default:
+		return 0, false, errors.New("entry must have either a key or parameters")
+	}
+}
+
+// assignIDs returns the key ID of each entry.
+func (b *Builder) assignIDs() ([]uint32, error) {
+	ids := make([]uint32, len(b.entries))
+	assigned := make([]bool, len(b.entries))
+	used := make(map[uint32]int)
+	use := func(i int, id uint32) error {
+		if j, found := used[id]; found {
+			return fmt.Errorf("entries %d and %d have the same key ID %d", j, i, id)
+		}
+		used[id] = i
+		ids[i] = id
+		assigned[i] = true
+		return nil
+	}
+	for i, entry := range b.entries {
+		if entry == nil {
+			return nil, fmt.Errorf("entry %d is nil", i)
+		}
+		id, required, err := entry.idRequirement()
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %v", i, err)
+		}
+		switch {
+		case required && entry.hasFixedID && entry.fixedID != id:
+			return nil, fmt.Errorf("entry %d has fixed ID %d but its key requires ID %d", i, entry.fixedID, id)
+		case required:
+			err = use(i, id)
+		case entry.hasFixedID:
+			err = use(i, entry.fixedID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range b.entries {
+		if assigned[i] {
+			continue
+		}
+		for {
+			id := random.GetRandomUint32()
+			if _, found := used[id]; !found {
+				use(i, id)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (e *BuilderEntry) protoKey(id uint32) (*tinkpb.Keyset_Key, error) {
+	protoStatus, err := keyStatusToProto(e.status)
+	if err != nil {
+		return nil, err
+	}
+	if e.key != nil {
+		keySerialization, err := protoserialization.SerializeKey(e.key)
+		if err != nil {
+			return nil, err
+		}
+		return &tinkpb.Keyset_Key{
+			KeyData:          keySerialization.KeyData(),
+			Status:           protoStatus,
+			KeyId:            id,
+			OutputPrefixType: keySerialization.OutputPrefixType(),
+		}, nil
+	}
+	keyTemplate, err := protoserialization.SerializeParameters(e.parameters)
+	if err != nil {
+		return nil, err
+	}
+	keyData, err := registry.NewKeyData(keyTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.Keyset_Key{
+		KeyData:          keyData,
+		Status:           protoStatus,
+		KeyId:            id,
+		OutputPrefixType: keyTemplate.GetOutputPrefixType(),
+	}, nil
+}
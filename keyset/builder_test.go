@@ -0,0 +1,171 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func registerBuilderTestSerializers(t *testing.T) {
+	t.Helper()
+	if err := protoserialization.RegisterParametersSerializer[*testParams](&testParametersSerializer{}); err != nil {
+		t.Fatalf("protoserialization.RegisterParametersSerializer[*testParams](&testParametersSerializer{}) err = %q, want nil", err)
+	}
+	t.Cleanup(protoserialization.UnregisterParametersSerializer[*testParams])
+	if err := protoserialization.RegisterKeySerializer[*testKey](&testKeySerializer{}); err != nil {
+		t.Fatalf("protoserialization.RegisterKeySerializer[*testKey](&testKeySerializer{}) err = %q, want nil", err)
+	}
+	t.Cleanup(protoserialization.UnregisterKeySerializer[*testKey])
+}
+
+func TestBuilderBuild(t *testing.T) {
+	registerBuilderTestSerializers(t)
+
+	h, err := keyset.NewBuilder().
+		AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).WithFixedID(123)).
+		AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).MakePrimary()).
+		AddEntry(keyset.NewBuilderEntryFromKey(&testKey{
+			params: testParameters{hasIDRequirement: true},
+			id:     456,
+		}).WithStatus(keyset.Disabled)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() err = %v, want nil", err)
+	}
+	ks := testkeyset.KeysetMaterial(h)
+	if len(ks.GetKey()) != 3 {
+		t.Fatalf("len(ks.GetKey()) = %d, want 3", len(ks.GetKey()))
+	}
+	if got, want := ks.GetKey()[0].GetKeyId(), uint32(123); got != want {
+		t.Errorf("ks.GetKey()[0].GetKeyId() = %d, want %d", got, want)
+	}
+	if got, want := ks.GetPrimaryKeyId(), ks.GetKey()[1].GetKeyId(); got != want {
+		t.Errorf("ks.GetPrimaryKeyId() = %d, want %d", got, want)
+	}
+	if ks.GetKey()[1].GetKeyId() == 123 || ks.GetKey()[1].GetKeyId() == 456 {
+		t.Errorf("ks.GetKey()[1].GetKeyId() = %d, want a random ID distinct from the others", ks.GetKey()[1].GetKeyId())
+	}
+	if got, want := ks.GetKey()[2].GetKeyId(), uint32(456); got != want {
+		t.Errorf("ks.GetKey()[2].GetKeyId() = %d, want %d", got, want)
+	}
+	if got, want := ks.GetKey()[2].GetStatus(), tinkpb.KeyStatusType_DISABLED; got != want {
+		t.Errorf("ks.GetKey()[2].GetStatus() = %v, want %v", got, want)
+	}
+	for i := 0; i < h.Len(); i++ {
+		entry, err := h.Entry(i)
+		if err != nil {
+			t.Fatalf("h.Entry(%d) err = %v, want nil", i, err)
+		}
+		if entry.CreationTime().IsZero() {
+			t.Errorf("h.Entry(%d).CreationTime() is zero, want non-zero", i)
+		}
+		if got, want := entry.ActivationTime().IsZero(), !entry.IsPrimary(); got != want {
+			t.Errorf("h.Entry(%d).ActivationTime().IsZero() = %v, want %v", i, got, want)
+		}
+	}
+
+	// testParametersSerializer serializes to an HMAC key template.
+	if _, err := mac.New(h); err != nil {
+		t.Errorf("mac.New(h) err = %v, want nil", err)
+	}
+}
+
+func TestBuilderBuildFails(t *testing.T) {
+	registerBuilderTestSerializers(t)
+
+	keyWithID := func(id uint32) *testKey {
+		return &testKey{params: testParameters{hasIDRequirement: true}, id: id}
+	}
+	for _, tc := range []struct {
+		name    string
+		builder *keyset.Builder
+		wantErr string
+	}{
+		{
+			name:    "empty",
+			builder: keyset.NewBuilder(),
+			wantErr: "no entries",
+		},
+		{
+			name: "no primary",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{})),
+			wantErr: "no primary",
+		},
+		{
+			name: "multiple primaries",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).MakePrimary()).
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).MakePrimary()),
+			wantErr: "multiple primary",
+		},
+		{
+			name: "disabled primary",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).WithStatus(keyset.Disabled).MakePrimary()),
+			wantErr: "not enabled",
+		},
+		{
+			name: "destroyed status",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).WithStatus(keyset.Destroyed).MakePrimary()),
+			wantErr: "invalid status",
+		},
+		{
+			name: "fixed IDs collide",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).WithFixedID(1).MakePrimary()).
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).WithFixedID(1)),
+			wantErr: "same key ID 1",
+		},
+		{
+			name: "fixed ID collides with ID requirement",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromParameters(&testParams{}).WithFixedID(7).MakePrimary()).
+				AddEntry(keyset.NewBuilderEntryFromKey(keyWithID(7))),
+			wantErr: "same key ID 7",
+		},
+		{
+			name: "fixed ID does not match ID requirement",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromKey(keyWithID(7)).WithFixedID(8).MakePrimary()),
+			wantErr: "requires ID 7",
+		},
+		{
+			name: "nil key",
+			builder: keyset.NewBuilder().
+				AddEntry(keyset.NewBuilderEntryFromKey(nil).MakePrimary()),
+			wantErr: "either a key or parameters",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.builder.Build()
+			if err == nil {
+				t.Fatalf("Build() err = nil, want error")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Build() err = %q, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
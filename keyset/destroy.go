@@ -0,0 +1,132 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// Destroy destroys the key with given keyID: its key material is removed from
+// the keyset, but the key stays in the keyset with status [Destroyed], its key
+// ID, type URL and output prefix type. This keeps a record that the key
+// existed. If they can be serialized, the parameters of the key are kept in
+// the metadata of the keyset, see [Handle.Metadata].
+//
+// Returns an error if the key is not found, it is the primary key or it is
+// already destroyed.
+func (km *Manager) Destroy(keyID uint32) error {
+	if km.ks == nil {
+		return errors.New("keyset.Manager: cannot destroy key, no keyset")
+	}
+	if km.ks.PrimaryKeyId == keyID {
+		return errors.New("keyset.Manager: cannot destroy the primary key")
+	}
+	protoKey := km.keyByID(keyID)
+	if protoKey == nil {
+		return fmt.Errorf("keyset.Manager: key with id %d not found", keyID)
+	}
+	if protoKey.GetStatus() != tinkpb.KeyStatusType_ENABLED && protoKey.GetStatus() != tinkpb.KeyStatusType_DISABLED {
+		return fmt.Errorf("keyset.Manager: cannot destroy key with id %d with status %s", keyID, protoKey.GetStatus())
+	}
+	md := km.metadata[keyID]
+	md.destroyedParameters = destroyedKeyParameters(protoKey)
+	km.metadata[keyID] = md
+	protoKey.KeyData = &tinkpb.KeyData{
+		TypeUrl:         protoKey.GetKeyData().GetTypeUrl(),
+		KeyMaterialType: protoKey.GetKeyData().GetKeyMaterialType(),
+	}
+	protoKey.Status = tinkpb.KeyStatusType_DESTROYED
+	return nil
+}
+
+// destroyedKeyParameters returns the serialized parameters of protoKey, or nil
+// if they can't be serialized.
+func destroyedKeyParameters(protoKey *tinkpb.Keyset_Key) *tinkpb.KeyTemplate {
+	keyID := protoKey.GetKeyId()
+	if protoKey.GetOutputPrefixType() == tinkpb.OutputPrefixType_RAW {
+		keyID = 0
+	}
+	keySerialization, err := protoserialization.NewKeySerialization(protoKey.GetKeyData(), protoKey.GetOutputPrefixType(), keyID)
+	if err != nil {
+		return nil
+	}
+	k, err := protoserialization.ParseKey(keySerialization)
+	if err != nil {
+		return nil
+	}
+	keyTemplate, err := protoserialization.SerializeParameters(k.Parameters())
+	if err != nil {
+		return nil
+	}
+	return keyTemplate
+}
+
+// isDestroyedKey returns true if protoKey is destroyed and has no key
+// material.
+func isDestroyedKey(protoKey *tinkpb.Keyset_Key) bool {
+	return protoKey.GetStatus() == tinkpb.KeyStatusType_DESTROYED && len(protoKey.GetKeyData().GetValue()) == 0
+}
+
+// destroyedKey is the key of an entry whose key material was destroyed. It
+// only retains the metadata of the key.
+type destroyedKey struct {
+	protoKey   *tinkpb.Keyset_Key
+	parameters key.Parameters
+}
+
+var _ key.Key = (*destroyedKey)(nil)
+
+func newDestroyedKey(protoKey *tinkpb.Keyset_Key) *destroyedKey {
+	return &destroyedKey{
+		protoKey: &tinkpb.Keyset_Key{
+			KeyData:          proto.Clone(protoKey.GetKeyData()).(*tinkpb.KeyData),
+			OutputPrefixType: protoKey.GetOutputPrefixType(),
+			KeyId:            protoKey.GetKeyId(),
+		},
+	}
+}
+
+// withParameters returns a copy of k with the parameters serialized in
+// keyTemplate. The parameters are informational, so k is returned unchanged if
+// they can't be parsed.
+func (k *destroyedKey) withParameters(keyTemplate *tinkpb.KeyTemplate) *destroyedKey {
+	if keyTemplate == nil {
+		return k
+	}
+	parameters, err := protoserialization.ParseParameters(keyTemplate)
+	if err != nil {
+		return k
+	}
+	return &destroyedKey{protoKey: k.protoKey, parameters: parameters}
+}
+
+// Parameters returns the parameters of the destroyed key, or nil if they are
+// unknown.
+func (k *destroyedKey) Parameters() key.Parameters { return k.parameters }
+
+func (k *destroyedKey) IDRequirement() (uint32, bool) {
+	return k.protoKey.GetKeyId(), k.protoKey.GetOutputPrefixType() != tinkpb.OutputPrefixType_RAW
+}
+
+func (k *destroyedKey) Equal(other key.Key) bool {
+	that, ok := other.(*destroyedKey)
+	return ok && proto.Equal(k.protoKey, that.protoKey)
+}
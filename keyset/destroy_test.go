@@ -0,0 +1,237 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/aead/aesgcm"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+	"github.com/tink-crypto/tink-go/v2/testutil"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func TestManagerDestroy(t *testing.T) {
+	km := keyset.NewManager()
+	primaryKeyID, err := km.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(primaryKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	keyID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	ciphertext := mustEncrypt(t, h, []byte("plaintext"))
+	entry, err := h.Entry(1)
+	if err != nil {
+		t.Fatalf("h.Entry(1) err = %v, want nil", err)
+	}
+	params := entry.Key().Parameters()
+
+	if err := km.Destroy(keyID); err != nil {
+		t.Fatalf("km.Destroy() err = %v, want nil", err)
+	}
+	destroyed, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+
+	protoKey := testkeyset.KeysetMaterial(destroyed).GetKey()[1]
+	if protoKey.GetKeyId() != keyID || protoKey.GetStatus() != tinkpb.KeyStatusType_DESTROYED {
+		t.Errorf("protoKey = %v, want a destroyed key with ID %d", protoKey, keyID)
+	}
+	if len(protoKey.GetKeyData().GetValue()) != 0 {
+		t.Errorf("len(protoKey.GetKeyData().GetValue()) = %d, want 0", len(protoKey.GetKeyData().GetValue()))
+	}
+	if protoKey.GetKeyData().GetTypeUrl() != testutil.AESGCMTypeURL {
+		t.Errorf("protoKey.GetKeyData().GetTypeUrl() = %q, want %q", protoKey.GetKeyData().GetTypeUrl(), testutil.AESGCMTypeURL)
+	}
+
+	// The metadata survives writing and reading the keyset.
+	kek := newPolicyTestKEK(t)
+	buf := &keyset.MemReaderWriter{}
+	if err := destroyed.Write(buf, kek); err != nil {
+		t.Fatalf("destroyed.Write() err = %v, want nil", err)
+	}
+	read, err := keyset.Read(buf, kek, keyset.WithMetadata(destroyed.Metadata()))
+	if err != nil {
+		t.Fatalf("keyset.Read() err = %v, want nil", err)
+	}
+	entry, err = read.Entry(1)
+	if err != nil {
+		t.Fatalf("read.Entry(1) err = %v, want nil", err)
+	}
+	if entry.KeyID() != keyID || entry.KeyStatus() != keyset.Destroyed {
+		t.Errorf("entry = (%d, %v), want (%d, Destroyed)", entry.KeyID(), entry.KeyStatus(), keyID)
+	}
+	if _, ok := entry.Key().Parameters().(*aesgcm.Parameters); !ok || !entry.Key().Parameters().Equal(params) {
+		t.Errorf("entry.Key().Parameters() = %v, want %v", entry.Key().Parameters(), params)
+	}
+	if entry.CreationTime().IsZero() {
+		t.Errorf("entry.CreationTime() is zero, want creation time")
+	}
+
+	// Primitives skip the destroyed key.
+	a, err := aead.New(read)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(ciphertext, nil); err != nil {
+		t.Errorf("a.Decrypt() err = %v, want nil", err)
+	}
+}
+
+func TestManagerDestroyFails(t *testing.T) {
+	km := keyset.NewManager()
+	primaryKeyID, err := km.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(primaryKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	keyID, err := km.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.Destroy(primaryKeyID); err == nil {
+		t.Errorf("km.Destroy(primaryKeyID) err = nil, want error")
+	}
+	if err := km.Destroy(keyID + 1); err == nil {
+		t.Errorf("km.Destroy(keyID + 1) err = nil, want error")
+	}
+	if err := km.Destroy(keyID); err != nil {
+		t.Fatalf("km.Destroy(keyID) err = %v, want nil", err)
+	}
+	if err := km.Destroy(keyID); err == nil {
+		t.Errorf("km.Destroy(keyID) twice err = nil, want error")
+	}
+	if err := km.Enable(keyID); err == nil {
+		t.Errorf("km.Enable(keyID) err = nil, want error")
+	}
+}
+
+func TestHandleRejectsDestroyedKeyWithoutKeyData(t *testing.T) {
+	ks := testutil.NewKeyset(1, []*tinkpb.Keyset_Key{
+		testutil.NewKey(testutil.NewAESGCMKeyData(16), tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_TINK),
+		&tinkpb.Keyset_Key{Status: tinkpb.KeyStatusType_DESTROYED, KeyId: 2, OutputPrefixType: tinkpb.OutputPrefixType_TINK},
+	})
+	if _, err := testkeyset.NewHandle(ks); err == nil {
+		t.Errorf("testkeyset.NewHandle() err = nil, want error")
+	}
+}
+
+func TestDestroyedKeyParametersAreOnlyInMetadata(t *testing.T) {
+	km := keyset.NewManager()
+	primaryKeyID, err := km.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(primaryKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	keyID, err := km.Add(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.Destroy(keyID); err != nil {
+		t.Fatalf("km.Destroy() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+
+	// The keyset only keeps what other Tink implementations expect of a
+	// destroyed key.
+	want := &tinkpb.Keyset_Key{
+		KeyData: &tinkpb.KeyData{
+			TypeUrl:         testutil.AESGCMTypeURL,
+			KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+		},
+		Status:           tinkpb.KeyStatusType_DESTROYED,
+		KeyId:            keyID,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+	if got := testkeyset.KeysetMaterial(h).GetKey()[1]; !proto.Equal(got, want) {
+		t.Errorf("destroyed key = %v, want %v", got, want)
+	}
+
+	withoutMetadata, err := testkeyset.NewHandle(testkeyset.KeysetMaterial(h))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v, want nil", err)
+	}
+	entry, err := withoutMetadata.Entry(1)
+	if err != nil {
+		t.Fatalf("withoutMetadata.Entry(1) err = %v, want nil", err)
+	}
+	if entry.KeyStatus() != keyset.Destroyed || entry.Key().Parameters() != nil {
+		t.Errorf("entry = (%v, %v), want (Destroyed, nil)", entry.KeyStatus(), entry.Key().Parameters())
+	}
+	if _, err := aead.New(withoutMetadata); err != nil {
+		t.Errorf("aead.New() err = %v, want nil", err)
+	}
+}
+
+func TestPublicWithDestroyedKey(t *testing.T) {
+	km := keyset.NewManager()
+	primaryKeyID, err := km.Add(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(primaryKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	keyID, err := km.Add(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.Destroy(keyID); err != nil {
+		t.Fatalf("km.Destroy() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	public, err := h.Public()
+	if err != nil {
+		t.Fatalf("h.Public() err = %v, want nil", err)
+	}
+	buf := &keyset.MemReaderWriter{}
+	if err := public.WriteWithNoSecrets(buf); err != nil {
+		t.Fatalf("public.WriteWithNoSecrets() err = %v, want nil", err)
+	}
+	read, err := keyset.ReadWithNoSecrets(buf)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithNoSecrets() err = %v, want nil", err)
+	}
+	if read.Len() != 2 {
+		t.Errorf("read.Len() = %d, want 2", read.Len())
+	}
+	if _, err := signature.NewVerifier(read); err != nil {
+		t.Errorf("signature.NewVerifier() err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// Fingerprint is a stable identifier of a key, computed as the SHA-256 hash
+// of a canonical proto serialization of the key.
+//
+// Unlike key IDs, fingerprints don't depend on the keyset a key is in, nor on
+// its output prefix, so they can be used to correlate keys across keysets and
+// systems. The fingerprint of a private key is the fingerprint of its public
+// key, so it can be computed and shared by holders of either.
+type Fingerprint [sha256.Size]byte
+
+// String returns the hex encoding of f.
+func (f Fingerprint) String() string {
+	return hex.EncodeToString(f[:])
+}
+
+// KeyFingerprint returns the fingerprint of k. There must be a proto
+// serializer registered for k, which is the case for all keys in a [Handle]
+// except destroyed ones, which have no fingerprint.
+func KeyFingerprint(k key.Key) (Fingerprint, error) {
+	if k == nil {
+		return Fingerprint{}, fmt.Errorf("keyset.KeyFingerprint: nil key")
+	}
+	if _, ok := k.(*destroyedKey); ok {
+		return Fingerprint{}, fmt.Errorf("keyset.KeyFingerprint: destroyed keys have no key material")
+	}
+	keySerialization, err := protoserialization.SerializeKey(k)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("keyset.KeyFingerprint: %v", err)
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetKeyMaterialType() == tinkpb.KeyData_ASYMMETRIC_PRIVATE {
+		keyData, err = publicKeyData(k, keyData)
+		if err != nil {
+			return Fingerprint{}, fmt.Errorf("keyset.KeyFingerprint: %v", err)
+		}
+	}
+	canonical, err := canonicalKeyData(keyData)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("keyset.KeyFingerprint: %v", err)
+	}
+	return sha256.Sum256(canonical), nil
+}
+
+// Fingerprint returns the fingerprint of the key of the entry.
+func (e *Entry) Fingerprint() (Fingerprint, error) {
+	return KeyFingerprint(e.Key())
+}
+
+// EntryByFingerprint returns the entry whose key has fingerprint f.
+//
+// It fails if there is no such entry, or if there are several, which happens
+// when the same key is in the keyset with different output prefixes.
+func (h *Handle) EntryByFingerprint(f Fingerprint) (*Entry, error) {
+	if h == nil {
+		return nil, fmt.Errorf("keyset.Handle: nil handle")
+	}
+	var found *Entry
+	for _, entry := range h.entries {
+		if _, ok := entry.Key().(*destroyedKey); ok {
+			continue
+		}
+		entryFingerprint, err := entry.Fingerprint()
+		if err != nil {
+			return nil, fmt.Errorf("keyset.Handle: %v", err)
+		}
+		if entryFingerprint != f {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("keyset.Handle: keys %d and %d have fingerprint %s", found.KeyID(), entry.KeyID(), f)
+		}
+		found = entry
+	}
+	if found == nil {
+		return nil, fmt.Errorf("keyset.Handle: no key with fingerprint %s", f)
+	}
+	return found, nil
+}
+
+// publicKeyData returns the serialized public key of the private key k, whose
+// serialization is keyData.
+func publicKeyData(k key.Key, keyData *tinkpb.KeyData) (*tinkpb.KeyData, error) {
+	if privKey, ok := k.(privateKey); ok {
+		publicKey, err := privKey.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		publicKeySerialization, err := protoserialization.SerializeKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		return publicKeySerialization.KeyData(), nil
+	}
+	// Keys without a typed implementation are only supported through their
+	// key manager.
+	km, err := registry.GetKeyManager(keyData.GetTypeUrl())
+	if err != nil {
+		return nil, err
+	}
+	pkm, ok := km.(registry.PrivateKeyManager)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a private key manager", keyData.GetTypeUrl())
+	}
+	return pkm.PublicKeyData(keyData.GetValue())
+}
+
+// canonicalKeyData returns a deterministic serialization of keyData. If the
+// key proto type is linked into the binary, the key proto is re-serialized
+// deterministically too.
+func canonicalKeyData(keyData *tinkpb.KeyData) ([]byte, error) {
+	value := keyData.GetValue()
+	if mt, err := protoregistry.GlobalTypes.FindMessageByURL(keyData.GetTypeUrl()); err == nil {
+		m := mt.New().Interface()
+		if err := proto.Unmarshal(value, m); err != nil {
+			return nil, err
+		}
+		value, err = proto.MarshalOptions{Deterministic: true}.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return proto.MarshalOptions{Deterministic: true}.Marshal(&tinkpb.KeyData{
+		TypeUrl:         keyData.GetTypeUrl(),
+		Value:           value,
+		KeyMaterialType: keyData.GetKeyMaterialType(),
+	})
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+	"github.com/tink-crypto/tink-go/v2/testutil"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func mustFingerprint(t *testing.T, h *keyset.Handle, i int) keyset.Fingerprint {
+	t.Helper()
+	entry, err := h.Entry(i)
+	if err != nil {
+		t.Fatalf("h.Entry(%d) err = %v, want nil", i, err)
+	}
+	fingerprint, err := entry.Fingerprint()
+	if err != nil {
+		t.Fatalf("entry.Fingerprint() err = %v, want nil", err)
+	}
+	return fingerprint
+}
+
+func TestFingerprintIsIndependentOfKeyIDAndPrefix(t *testing.T) {
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	keyData := testkeyset.KeysetMaterial(h).GetKey()[0].GetKeyData()
+	// The same key with a different key ID and output prefix, and another key.
+	other, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	otherKeyData := testkeyset.KeysetMaterial(other).GetKey()[0].GetKeyData()
+	imported, err := testkeyset.NewHandle(testutil.NewKeyset(1, []*tinkpb.Keyset_Key{
+		testutil.NewKey(keyData, tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_RAW),
+		testutil.NewKey(otherKeyData, tinkpb.KeyStatusType_ENABLED, 2, tinkpb.OutputPrefixType_TINK),
+	}))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v, want nil", err)
+	}
+
+	fingerprint := mustFingerprint(t, h, 0)
+	if got := mustFingerprint(t, imported, 0); got != fingerprint {
+		t.Errorf("fingerprint of imported key = %s, want %s", got, fingerprint)
+	}
+	if got := mustFingerprint(t, imported, 1); got == fingerprint {
+		t.Errorf("fingerprint of other key = %s, want different from %s", got, fingerprint)
+	}
+
+	entry, err := imported.EntryByFingerprint(fingerprint)
+	if err != nil {
+		t.Fatalf("imported.EntryByFingerprint() err = %v, want nil", err)
+	}
+	if entry.KeyID() != 1 {
+		t.Errorf("entry.KeyID() = %d, want 1", entry.KeyID())
+	}
+}
+
+func TestFingerprintOfPrivateKeyIsFingerprintOfPublicKey(t *testing.T) {
+	privateHandle, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v, want nil", err)
+	}
+	if got, want := mustFingerprint(t, privateHandle, 0), mustFingerprint(t, publicHandle, 0); got != want {
+		t.Errorf("private key fingerprint = %s, want public key fingerprint %s", got, want)
+	}
+	primary, err := publicHandle.Primary()
+	if err != nil {
+		t.Fatalf("publicHandle.Primary() err = %v, want nil", err)
+	}
+	fingerprint, err := keyset.KeyFingerprint(primary.Key())
+	if err != nil {
+		t.Fatalf("keyset.KeyFingerprint() err = %v, want nil", err)
+	}
+	if _, err := privateHandle.EntryByFingerprint(fingerprint); err != nil {
+		t.Errorf("privateHandle.EntryByFingerprint() err = %v, want nil", err)
+	}
+}
+
+func TestEntryByFingerprintFails(t *testing.T) {
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	if _, err := h.EntryByFingerprint(keyset.Fingerprint{}); err == nil {
+		t.Errorf("h.EntryByFingerprint() err = nil, want error")
+	}
+
+	keyData := testkeyset.KeysetMaterial(h).GetKey()[0].GetKeyData()
+	duplicated, err := testkeyset.NewHandle(testutil.NewKeyset(1, []*tinkpb.Keyset_Key{
+		testutil.NewKey(keyData, tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_TINK),
+		testutil.NewKey(keyData, tinkpb.KeyStatusType_ENABLED, 2, tinkpb.OutputPrefixType_RAW),
+	}))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v, want nil", err)
+	}
+	if _, err := duplicated.EntryByFingerprint(mustFingerprint(t, h, 0)); err == nil {
+		t.Errorf("duplicated.EntryByFingerprint() err = nil, want error")
+	}
+	if _, err := keyset.KeyFingerprint(nil); err == nil {
+		t.Errorf("keyset.KeyFingerprint(nil) err = nil, want error")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
@@ -78,9 +79,12 @@ type Entry struct {
 	isPrimary bool
 	keyID     uint32
 	status    KeyStatus
+	metadata  keyMetadata
 }
 
-// Key returns the key.
+// Key returns the key. The key of a destroyed entry whose key material was
+// removed, e.g. by [Manager.Destroy], only has parameters, which are nil if
+// unknown, e.g. because the keyset was read without its [Handle.Metadata].
 func (e *Entry) Key() key.Key {
 	return e.key
 }
@@ -100,6 +104,27 @@ func (e *Entry) KeyStatus() KeyStatus {
 	return e.status
 }
 
+// CreationTime returns the time at which the key was added to the keyset.
+// The zero time is returned if it is unknown, e.g. because the keyset was
+// read without its [Handle.Metadata].
+func (e *Entry) CreationTime() time.Time {
+	return unixTime(e.metadata.creationTime)
+}
+
+// ActivationTime returns the time at which the key was last made primary.
+// The zero time is returned if the key has never been primary or the time is
+// unknown.
+func (e *Entry) ActivationTime() time.Time {
+	return unixTime(e.metadata.activationTime)
+}
+
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
 func keyStatusFromProto(status tinkpb.KeyStatusType) (KeyStatus, error) {
 	switch status {
 	case tinkpb.KeyStatusType_ENABLED:
@@ -132,6 +157,11 @@ func entryToProtoKey(entry *Entry) (*tinkpb.Keyset_Key, error) {
 	if err != nil {
 		return nil, err
 	}
+	if k, ok := entry.Key().(*destroyedKey); ok {
+		protoKey := proto.Clone(k.protoKey).(*tinkpb.Keyset_Key)
+		protoKey.Status = protoKeyStatus
+		return protoKey, nil
+	}
 	protoKeySerialization, err := protoserialization.SerializeKey(entry.Key())
 	if err != nil {
 		return nil, err
@@ -172,6 +202,22 @@ func newWithOptions(ks *tinkpb.Keyset, opts ...Option) (*Handle, error) {
 	entries := make([]*Entry, len(ks.GetKey()))
 	var primaryKeyEntry *Entry = nil
 	for i, protoKey := range ks.GetKey() {
+		keyStatus, err := keyStatusFromProto(protoKey.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = &Entry{
+			isPrimary: protoKey.GetKeyId() == ks.GetPrimaryKeyId(),
+			keyID:     protoKey.GetKeyId(),
+			status:    keyStatus,
+		}
+		if protoKey.GetKeyId() == ks.GetPrimaryKeyId() {
+			primaryKeyEntry = entries[i]
+		}
+		if isDestroyedKey(protoKey) {
+			entries[i].key = newDestroyedKey(protoKey)
+			continue
+		}
 		protoKeyData := protoKey.GetKeyData()
 		keyID := protoKey.GetKeyId()
 		if protoKey.GetOutputPrefixType() == tinkpb.OutputPrefixType_RAW {
@@ -185,19 +231,7 @@ func newWithOptions(ks *tinkpb.Keyset, opts ...Option) (*Handle, error) {
 		if err != nil {
 			return nil, err
 		}
-		keyStatus, err := keyStatusFromProto(protoKey.GetStatus())
-		if err != nil {
-			return nil, err
-		}
-		entries[i] = &Entry{
-			key:       key,
-			isPrimary: protoKey.GetKeyId() == ks.GetPrimaryKeyId(),
-			keyID:     protoKey.GetKeyId(),
-			status:    keyStatus,
-		}
-		if protoKey.GetKeyId() == ks.GetPrimaryKeyId() {
-			primaryKeyEntry = entries[i]
-		}
+		entries[i].key = key
 	}
 	h := &Handle{
 		entries:          entries,
@@ -231,8 +265,10 @@ func NewHandle(kt *tinkpb.KeyTemplate) (*Handle, error) {
 
 // NewHandleWithNoSecrets creates a new instance of KeysetHandle from the
 // the given keyset which does not contain any secret key material.
-func NewHandleWithNoSecrets(ks *tinkpb.Keyset) (*Handle, error) {
-	handle, err := newWithOptions(ks)
+//
+// opts are applied to the returned handle, e.g. [WithPolicy].
+func NewHandleWithNoSecrets(ks *tinkpb.Keyset, opts ...Option) (*Handle, error) {
+	handle, err := newWithOptions(ks, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("keyset.Handle: cannot generate new keyset: %s", err)
 	}
@@ -244,12 +280,14 @@ func NewHandleWithNoSecrets(ks *tinkpb.Keyset) (*Handle, error) {
 }
 
 // Read tries to create a Handle from an encrypted keyset obtained via reader.
-func Read(reader Reader, masterKey tink.AEAD) (*Handle, error) {
-	return ReadWithAssociatedData(reader, masterKey, []byte{})
+//
+// opts are applied to the returned handle, e.g. [WithPolicy].
+func Read(reader Reader, masterKey tink.AEAD, opts ...Option) (*Handle, error) {
+	return ReadWithAssociatedData(reader, masterKey, []byte{}, opts...)
 }
 
 // ReadWithAssociatedData tries to create a Handle from an encrypted keyset obtained via reader using the provided associated data.
-func ReadWithAssociatedData(reader Reader, masterKey tink.AEAD, associatedData []byte) (*Handle, error) {
+func ReadWithAssociatedData(reader Reader, masterKey tink.AEAD, associatedData []byte, opts ...Option) (*Handle, error) {
 	encryptedKeyset, err := reader.ReadEncrypted()
 	if err != nil {
 		return nil, err
@@ -258,12 +296,18 @@ func ReadWithAssociatedData(reader Reader, masterKey tink.AEAD, associatedData [
 	if err != nil {
 		return nil, err
 	}
-	return newWithOptions(protoKeyset)
+	return newWithOptions(protoKeyset, opts...)
 }
 
 // ReadWithContext creates a keyset.Handle from an encrypted keyset obtained via
 // reader using the provided AEADWithContext.
-func ReadWithContext(ctx context.Context, reader Reader, keyEncryptionAEAD tink.AEADWithContext, associatedData []byte) (*Handle, error) {
+//
+// Keysets written with [Handle.WriteWithKEKs] with a threshold of 1 can be
+// read with any one of their KEKs.
+//
+// opts are applied to the returned handle, e.g. [WithAnnotations] or
+// [WithPolicy].
+func ReadWithContext(ctx context.Context, reader Reader, keyEncryptionAEAD tink.AEADWithContext, associatedData []byte, opts ...Option) (*Handle, error) {
 	encryptedKeyset, err := reader.ReadEncrypted()
 	if err != nil {
 		return nil, err
@@ -272,16 +316,16 @@ func ReadWithContext(ctx context.Context, reader Reader, keyEncryptionAEAD tink.
 	if err != nil {
 		return nil, err
 	}
-	return newWithOptions(protoKeyset)
+	return newWithOptions(protoKeyset, opts...)
 }
 
 // ReadWithNoSecrets tries to create a keyset.Handle from a keyset obtained via reader.
-func ReadWithNoSecrets(reader Reader) (*Handle, error) {
+func ReadWithNoSecrets(reader Reader, opts ...Option) (*Handle, error) {
 	protoKeyset, err := reader.Read()
 	if err != nil {
 		return nil, err
 	}
-	return NewHandleWithNoSecrets(protoKeyset)
+	return NewHandleWithNoSecrets(protoKeyset, opts...)
 }
 
 // Primary returns the primary key of the keyset.
@@ -323,6 +367,14 @@ func (h *Handle) Public() (*Handle, error) {
 	entries := make([]*Entry, h.Len())
 	var primaryKeyEntry *Entry = nil
 	for i, entry := range h.entries {
+		if _, ok := entry.Key().(*destroyedKey); ok {
+			// Destroyed keys have no key material to derive a public key from.
+			entries[i] = entry
+			if entry.isPrimary {
+				primaryKeyEntry = entries[i]
+			}
+			continue
+		}
 		privateKey, ok := entry.Key().(privateKey)
 		if !ok {
 			return nil, fmt.Errorf("keyset.Handle: keyset contains a non-private key")
@@ -336,6 +388,7 @@ func (h *Handle) Public() (*Handle, error) {
 			isPrimary: entry.isPrimary,
 			keyID:     entry.keyID,
 			status:    entry.status,
+			metadata:  entry.metadata,
 		}
 		if entry.isPrimary {
 			primaryKeyEntry = entries[i]
@@ -572,7 +625,7 @@ func primitives[T any](h *Handle, km registry.KeyManager, opts ...PrimitivesOpti
 // and keys of an unknown type.
 func hasSecrets(ks *tinkpb.Keyset) bool {
 	for _, k := range ks.GetKey() {
-		if k.GetKeyData() == nil {
+		if k.GetKeyData() == nil || isDestroyedKey(k) {
 			continue
 		}
 		switch k.GetKeyData().GetKeyMaterialType() {
@@ -603,6 +656,9 @@ func decryptWithContext(ctx context.Context, encryptedKeyset *tinkpb.EncryptedKe
 	if encryptedKeyset == nil || keyEncryptionAEAD == nil {
 		return nil, fmt.Errorf("keyset.Handle: invalid encrypted keyset")
 	}
+	if isMultiKEKEncryptedKeyset(encryptedKeyset) {
+		return decryptWithKEKs(ctx, encryptedKeyset, []tink.AEADWithContext{keyEncryptionAEAD}, associatedData)
+	}
 	decrypted, err := keyEncryptionAEAD.DecryptWithContext(ctx, encryptedKeyset.GetEncryptedKeyset(), associatedData)
 	if err != nil {
 		return nil, fmt.Errorf("keyset.Handle: decryption failed: %v", err)
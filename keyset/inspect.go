@@ -0,0 +1,200 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// KeysetDescription is a description of a keyset for review by humans. It
+// does not contain any secret key material.
+type KeysetDescription struct {
+	PrimaryKeyID uint32              `json:"primaryKeyId"`
+	Entries      []*EntryDescription `json:"entries"`
+}
+
+// EntryDescription describes a single entry of a keyset.
+type EntryDescription struct {
+	KeyID            uint32 `json:"keyId"`
+	Status           string `json:"status"`
+	IsPrimary        bool   `json:"isPrimary"`
+	TypeURL          string `json:"typeUrl"`
+	ParametersType   string `json:"parametersType"`
+	OutputPrefixType string `json:"outputPrefixType"`
+	// Parameters maps the non-secret fields of the key's proto serialization
+	// to their values, e.g. "params.hash" to "SHA256". Byte string fields,
+	// which hold key material, are only described by their length, e.g.
+	// "key_value.length".
+	Parameters map[string]any `json:"parameters,omitempty"`
+	// PublicFingerprint is the [Fingerprint] of the key. Only set for
+	// asymmetric keys that haven't been destroyed.
+	PublicFingerprint string `json:"publicFingerprint,omitempty"`
+	// CreationTime and ActivationTime are in RFC 3339 format, empty if
+	// unknown.
+	CreationTime   string `json:"creationTime,omitempty"`
+	ActivationTime string `json:"activationTime,omitempty"`
+}
+
+// Inspect returns a description of the keyset in h.
+func Inspect(h *Handle) (*KeysetDescription, error) {
+	if h == nil {
+		return nil, fmt.Errorf("keyset.Inspect: nil handle")
+	}
+	desc := &KeysetDescription{}
+	for _, entry := range h.entries {
+		protoKey, err := entryToProtoKey(entry)
+		if err != nil {
+			return nil, fmt.Errorf("keyset.Inspect: %v", err)
+		}
+		entryDesc := &EntryDescription{
+			KeyID:            entry.KeyID(),
+			Status:           entry.KeyStatus().String(),
+			IsPrimary:        entry.IsPrimary(),
+			TypeURL:          protoKey.GetKeyData().GetTypeUrl(),
+			ParametersType:   fmt.Sprintf("%T", entry.Key().Parameters()),
+			OutputPrefixType: protoKey.GetOutputPrefixType().String(),
+			Parameters:       describeKeyData(protoKey.GetKeyData()),
+			CreationTime:     formatTime(entry.CreationTime()),
+			ActivationTime:   formatTime(entry.ActivationTime()),
+		}
+		fingerprint, err := publicFingerprint(entry, protoKey.GetKeyData())
+		if err != nil {
+			return nil, fmt.Errorf("keyset.Inspect: key %d: %v", entry.KeyID(), err)
+		}
+		entryDesc.PublicFingerprint = fingerprint
+		if entry.IsPrimary() {
+			desc.PrimaryKeyID = entry.KeyID()
+		}
+		desc.Entries = append(desc.Entries, entryDesc)
+	}
+	return desc, nil
+}
+
+// JSON returns the description in indented JSON.
+func (d *KeysetDescription) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// String returns the description as human-readable text.
+func (d *KeysetDescription) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "primary key ID: %d\n", d.PrimaryKeyID)
+	for _, e := range d.Entries {
+		fmt.Fprintf(&b, "key %d", e.KeyID)
+		if e.IsPrimary {
+			b.WriteString(" (primary)")
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "  status: %s\n", e.Status)
+		fmt.Fprintf(&b, "  type URL: %s\n", e.TypeURL)
+		fmt.Fprintf(&b, "  parameters type: %s\n", e.ParametersType)
+		fmt.Fprintf(&b, "  output prefix type: %s\n", e.OutputPrefixType)
+		if e.CreationTime != "" {
+			fmt.Fprintf(&b, "  creation time: %s\n", e.CreationTime)
+		}
+		if e.ActivationTime != "" {
+			fmt.Fprintf(&b, "  activation time: %s\n", e.ActivationTime)
+		}
+		if e.PublicFingerprint != "" {
+			fmt.Fprintf(&b, "  public fingerprint: %s\n", e.PublicFingerprint)
+		}
+		if len(e.Parameters) > 0 {
+			b.WriteString("  parameters:\n")
+			names := make([]string, 0, len(e.Parameters))
+			for name := range e.Parameters {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(&b, "    %s: %v\n", name, e.Parameters[name])
+			}
+		}
+	}
+	return b.String()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// describeKeyData returns the non-secret fields of the key proto in keyData,
+// or nil if the proto type isn't linked into the binary.
+func describeKeyData(keyData *tinkpb.KeyData) map[string]any {
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(keyData.GetTypeUrl())
+	if err != nil {
+		return nil
+	}
+	m := mt.New().Interface()
+	if err := proto.Unmarshal(keyData.GetValue(), m); err != nil {
+		return nil
+	}
+	fields := make(map[string]any)
+	describeMessage(m.ProtoReflect(), "", fields)
+	return fields
+}
+
+func describeMessage(m protoreflect.Message, prefix string, fields map[string]any) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := prefix + string(fd.Name())
+		switch {
+		case fd.Name() == "version":
+		case fd.IsList() || fd.IsMap():
+			// Key protos have no repeated fields that describe parameters.
+		case fd.Kind() == protoreflect.MessageKind:
+			describeMessage(v.Message(), name+".", fields)
+		case fd.Kind() == protoreflect.BytesKind:
+			fields[name+".length"] = len(v.Bytes())
+		case fd.Kind() == protoreflect.EnumKind:
+			if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+				fields[name] = string(ev.Name())
+			} else {
+				fields[name] = int32(v.Enum())
+			}
+		default:
+			fields[name] = v.Interface()
+		}
+		return true
+	})
+}
+
+// publicFingerprint returns the fingerprint of entry, or "" if it isn't an
+// asymmetric key or was destroyed.
+func publicFingerprint(entry *Entry, keyData *tinkpb.KeyData) (string, error) {
+	if _, ok := entry.Key().(*destroyedKey); ok {
+		return "", nil
+	}
+	switch keyData.GetKeyMaterialType() {
+	case tinkpb.KeyData_ASYMMETRIC_PUBLIC, tinkpb.KeyData_ASYMMETRIC_PRIVATE:
+		fingerprint, err := entry.Fingerprint()
+		if err != nil {
+			return "", err
+		}
+		return fingerprint.String(), nil
+	default:
+		return "", nil
+	}
+}
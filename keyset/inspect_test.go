@@ -0,0 +1,148 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+	gcmpb "github.com/tink-crypto/tink-go/v2/proto/aes_gcm_go_proto"
+)
+
+func TestInspect(t *testing.T) {
+	km := keyset.NewManager()
+	macKeyID, err := km.Add(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	aeadKeyID, err := km.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(aeadKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	if err := km.Disable(macKeyID); err != nil {
+		t.Fatalf("km.Disable() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+
+	desc, err := keyset.Inspect(h)
+	if err != nil {
+		t.Fatalf("keyset.Inspect() err = %v, want nil", err)
+	}
+	if desc.PrimaryKeyID != aeadKeyID {
+		t.Errorf("desc.PrimaryKeyID = %d, want %d", desc.PrimaryKeyID, aeadKeyID)
+	}
+	if len(desc.Entries) != 2 {
+		t.Fatalf("len(desc.Entries) = %d, want 2", len(desc.Entries))
+	}
+	macEntry, aeadEntry := desc.Entries[0], desc.Entries[1]
+	if macEntry.Status != "Disabled" || macEntry.IsPrimary {
+		t.Errorf("macEntry = %+v, want a disabled non-primary entry", macEntry)
+	}
+	if got, want := macEntry.Parameters["params.hash"], "SHA256"; got != want {
+		t.Errorf("macEntry.Parameters[%q] = %v, want %v", "params.hash", got, want)
+	}
+	if !aeadEntry.IsPrimary || aeadEntry.Status != "Enabled" || aeadEntry.OutputPrefixType != "TINK" {
+		t.Errorf("aeadEntry = %+v, want an enabled primary entry with TINK prefix", aeadEntry)
+	}
+	if got, want := aeadEntry.Parameters["key_value.length"], 16; got != want {
+		t.Errorf("aeadEntry.Parameters[%q] = %v, want %v", "key_value.length", got, want)
+	}
+	if aeadEntry.PublicFingerprint != "" {
+		t.Errorf("aeadEntry.PublicFingerprint = %q, want empty", aeadEntry.PublicFingerprint)
+	}
+	if aeadEntry.CreationTime == "" || aeadEntry.ActivationTime == "" {
+		t.Errorf("aeadEntry = %+v, want creation and activation times", aeadEntry)
+	}
+
+	text := desc.String()
+	if !strings.Contains(text, "(primary)") || !strings.Contains(text, "params.hash: SHA256") {
+		t.Errorf("desc.String() = %q, want it to describe the primary and the hash", text)
+	}
+	jsonDesc, err := desc.JSON()
+	if err != nil {
+		t.Fatalf("desc.JSON() err = %v, want nil", err)
+	}
+	got := &keyset.KeysetDescription{}
+	if err := json.Unmarshal(jsonDesc, got); err != nil {
+		t.Fatalf("json.Unmarshal() err = %v, want nil", err)
+	}
+	if got.PrimaryKeyID != aeadKeyID || len(got.Entries) != 2 {
+		t.Errorf("json.Unmarshal(desc.JSON()) = %+v, want the same keyset", got)
+	}
+
+	// The description must not contain the key material.
+	aesKey := &gcmpb.AesGcmKey{}
+	if err := proto.Unmarshal(testkeyset.KeysetMaterial(h).GetKey()[1].GetKeyData().GetValue(), aesKey); err != nil {
+		t.Fatalf("proto.Unmarshal() err = %v, want nil", err)
+	}
+	for _, encoded := range []string{
+		hex.EncodeToString(aesKey.GetKeyValue()),
+		base64.StdEncoding.EncodeToString(aesKey.GetKeyValue()),
+	} {
+		if strings.Contains(text, encoded) || strings.Contains(string(jsonDesc), encoded) {
+			t.Errorf("description contains the key material %q", encoded)
+		}
+	}
+}
+
+func TestInspectPublicFingerprint(t *testing.T) {
+	privateHandle, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v, want nil", err)
+	}
+	privateDesc, err := keyset.Inspect(privateHandle)
+	if err != nil {
+		t.Fatalf("keyset.Inspect(privateHandle) err = %v, want nil", err)
+	}
+	publicDesc, err := keyset.Inspect(publicHandle)
+	if err != nil {
+		t.Fatalf("keyset.Inspect(publicHandle) err = %v, want nil", err)
+	}
+	privateFingerprint := privateDesc.Entries[0].PublicFingerprint
+	if privateFingerprint == "" {
+		t.Fatalf("privateDesc.Entries[0].PublicFingerprint is empty, want fingerprint")
+	}
+	if got := publicDesc.Entries[0].PublicFingerprint; got != privateFingerprint {
+		t.Errorf("publicDesc.Entries[0].PublicFingerprint = %q, want %q", got, privateFingerprint)
+	}
+	if _, found := privateDesc.Entries[0].Parameters["key_value.length"]; !found {
+		t.Errorf("privateDesc.Entries[0].Parameters = %v, want key_value.length", privateDesc.Entries[0].Parameters)
+	}
+}
+
+func TestInspectFailsWithNilHandle(t *testing.T) {
+	if _, err := keyset.Inspect(nil); err == nil {
+		t.Errorf("keyset.Inspect(nil) err = nil, want error")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 	"github.com/tink-crypto/tink-go/v2/core/registry"
@@ -33,6 +34,7 @@ import (
 type Manager struct {
 	ks                *tinkpb.Keyset
 	unavailableKeyIDs map[uint32]bool // set of key IDs that are not available for new keys
+	metadata          map[uint32]keyMetadata
 }
 
 // NewManager creates a new instance with an empty Keyset.
@@ -40,6 +42,7 @@ func NewManager() *Manager {
 	ret := new(Manager)
 	ret.ks = new(tinkpb.Keyset)
 	ret.unavailableKeyIDs = make(map[uint32]bool)
+	ret.metadata = make(map[uint32]keyMetadata)
 	return ret
 }
 
@@ -51,6 +54,10 @@ func NewManagerFromHandle(kh *Handle) *Manager {
 	for _, key := range ret.ks.Key {
 		ret.unavailableKeyIDs[key.KeyId] = true
 	}
+	ret.metadata = make(map[uint32]keyMetadata)
+	for _, entry := range kh.entries {
+		ret.metadata[entry.keyID] = entry.metadata
+	}
 	return ret
 }
 
@@ -58,6 +65,12 @@ func NewManagerFromHandle(kh *Handle) *Manager {
 // the key is enabled on creation, but not set to primary.
 // It returns the ID of the new key
 func (km *Manager) Add(kt *tinkpb.KeyTemplate) (uint32, error) {
+	return km.addAt(kt, time.Now())
+}
+
+// addAt adds a fresh key using the given key template and records now as its
+// creation time.
+func (km *Manager) addAt(kt *tinkpb.KeyTemplate, now time.Time) (uint32, error) {
 	if kt == nil {
 		return 0, errors.New("keyset.Manager: key template is nil")
 	}
@@ -79,6 +92,7 @@ func (km *Manager) Add(kt *tinkpb.KeyTemplate) (uint32, error) {
 		OutputPrefixType: kt.OutputPrefixType,
 	}
 	km.ks.Key = append(km.ks.Key, key)
+	km.metadata[keyID] = keyMetadata{creationTime: now.Unix()}
 	return keyID, nil
 }
 
@@ -115,6 +129,7 @@ func (km *Manager) AddKey(key key.Key) (uint32, error) {
 		OutputPrefixType: keySerialization.OutputPrefixType(),
 		KeyData:          keySerialization.KeyData(),
 	})
+	km.metadata[keyID] = keyMetadata{creationTime: time.Now().Unix()}
 	return keyID, nil
 }
 
@@ -131,6 +146,12 @@ func (km *Manager) AddNewKeyFromParameters(parameters key.Parameters) (uint32, e
 // SetPrimary sets the key with given keyID as primary.
 // Returns an error if the key is not found or not enabled.
 func (km *Manager) SetPrimary(keyID uint32) error {
+	return km.setPrimaryAt(keyID, time.Now())
+}
+
+// setPrimaryAt sets the key with given keyID as primary and, unless it
+// already is the primary, records now as its activation time.
+func (km *Manager) setPrimaryAt(keyID uint32, now time.Time) error {
 	if km.ks == nil {
 		return errors.New("keyset.Manager: cannot set primary key to nil keyset")
 	}
@@ -139,6 +160,10 @@ func (km *Manager) SetPrimary(keyID uint32) error {
 			continue
 		}
 		if key.Status == tinkpb.KeyStatusType_ENABLED {
+			if md := km.metadata[keyID]; km.ks.PrimaryKeyId != keyID || md.activationTime == 0 {
+				md.activationTime = now.Unix()
+				km.metadata[keyID] = md
+			}
 			km.ks.PrimaryKeyId = keyID
 			return nil
 		}
@@ -210,6 +235,7 @@ func (km *Manager) Delete(keyID uint32) error {
 		return fmt.Errorf("keyset.Manager: key with id %d not found", keyID)
 	}
 	km.ks.Key = slices.Delete(km.ks.Key, deleteIdx, deleteIdx+1)
+	delete(km.metadata, keyID)
 	// NOTE: not removing the ID from unavailableKeyIDs on purpose to avoid reusing the keyID right
 	// away.
 	return nil
@@ -219,7 +245,7 @@ func (km *Manager) Delete(keyID uint32) error {
 func (km *Manager) Handle() (*Handle, error) {
 	// Make a copy of the keyset to keep it
 	ks := proto.Clone(km.ks).(*tinkpb.Keyset)
-	return newWithOptions(ks)
+	return newWithOptions(ks, withKeyMetadata(km.metadata))
 }
 
 // newRandomKeyID generates a key id that has not been used by any key in the keyset.
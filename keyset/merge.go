@@ -0,0 +1,141 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// ImportEntry adds the key of entry, which usually comes from another keyset
+// handle, to the keyset. Unlike [Manager.AddKey], the key keeps the key ID,
+// status, output prefix and timestamps it has in entry. The key is not made
+// primary.
+//
+// It fails if the keyset already has a key with the same ID. Because output
+// prefixes are derived from key IDs, this also rules out output prefix
+// collisions.
+func (km *Manager) ImportEntry(entry *Entry) error {
+	if km.ks == nil {
+		return errors.New("keyset.Manager: cannot import entry to nil keyset")
+	}
+	if entry == nil {
+		return errors.New("keyset.Manager: nil entry")
+	}
+	protoKey, err := entryToProtoKey(entry)
+	if err != nil {
+		return fmt.Errorf("keyset.Manager: %v", err)
+	}
+	if km.keyByID(protoKey.GetKeyId()) != nil {
+		return fmt.Errorf("keyset.Manager: keyset already has a key with ID %d", protoKey.GetKeyId())
+	}
+	km.ks.Key = append(km.ks.Key, protoKey)
+	km.unavailableKeyIDs[protoKey.GetKeyId()] = true
+	km.metadata[protoKey.GetKeyId()] = entry.metadata
+	return nil
+}
+
+func (km *Manager) keyByID(keyID uint32) *tinkpb.Keyset_Key {
+	for _, key := range km.ks.GetKey() {
+		if key.GetKeyId() == keyID {
+			return key
+		}
+	}
+	return nil
+}
+
+// Merge returns a handle with the keys of primary followed by the keys of
+// others, e.g. to consolidate several keysets into one that can decrypt the
+// ciphertexts of all of them. The keys keep their key IDs, statuses and
+// output prefixes, and the primary key of the result is the primary key of
+// primary.
+//
+// A key that is in several of the handles with the same key ID and output
+// prefix type is only added once, with the status it has in the first handle.
+// Merge fails if two different keys have the same key ID.
+func Merge(primary *Handle, others ...*Handle) (*Handle, error) {
+	if primary == nil {
+		return nil, errors.New("keyset.Merge: nil handle")
+	}
+	km := NewManagerFromHandle(primary)
+	for _, other := range others {
+		if other == nil {
+			return nil, errors.New("keyset.Merge: nil handle")
+		}
+		for _, entry := range other.entries {
+			protoKey, err := entryToProtoKey(entry)
+			if err != nil {
+				return nil, fmt.Errorf("keyset.Merge: %v", err)
+			}
+			if existing := km.keyByID(entry.KeyID()); existing != nil {
+				if existing.GetOutputPrefixType() == protoKey.GetOutputPrefixType() &&
+					proto.Equal(existing.GetKeyData(), protoKey.GetKeyData()) {
+					continue
+				}
+				return nil, fmt.Errorf("keyset.Merge: key ID %d is used by different keys", entry.KeyID())
+			}
+			if err := km.ImportEntry(entry); err != nil {
+				return nil, fmt.Errorf("keyset.Merge: %v", err)
+			}
+		}
+	}
+	h, err := km.Handle()
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Merge: %v", err)
+	}
+	return h, nil
+}
+
+// Extract returns a handle with the keys of h whose IDs are primaryKeyID or
+// in otherKeyIDs, e.g. to share only the primary key with a partner. The keys
+// keep their key IDs, statuses and output prefixes, and the key with ID
+// primaryKeyID becomes the primary key of the result.
+func (h *Handle) Extract(primaryKeyID uint32, otherKeyIDs ...uint32) (*Handle, error) {
+	if h == nil {
+		return nil, errors.New("keyset.Handle: nil handle")
+	}
+	wanted := map[uint32]bool{primaryKeyID: true}
+	for _, keyID := range otherKeyIDs {
+		wanted[keyID] = true
+	}
+	km := NewManager()
+	for _, entry := range h.entries {
+		if !wanted[entry.KeyID()] {
+			continue
+		}
+		if err := km.ImportEntry(entry); err != nil {
+			return nil, fmt.Errorf("keyset.Handle: %v", err)
+		}
+		delete(wanted, entry.KeyID())
+		if entry.IsPrimary() {
+			// Keep the activation time of the current primary key.
+			km.ks.PrimaryKeyId = entry.KeyID()
+		}
+	}
+	for keyID := range wanted {
+		return nil, fmt.Errorf("keyset.Handle: key with id %d not found", keyID)
+	}
+	if err := km.SetPrimary(primaryKeyID); err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %v", err)
+	}
+	extracted, err := km.Handle()
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %v", err)
+	}
+	return extracted, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+	"github.com/tink-crypto/tink-go/v2/testutil"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// newAEADKeyset returns a handle with AES-GCM keys with the given IDs. The
+// first one is the primary key.
+func newAEADKeyset(t *testing.T, keyIDs ...uint32) *keyset.Handle {
+	t.Helper()
+	var keys []*tinkpb.Keyset_Key
+	for _, keyID := range keyIDs {
+		keys = append(keys, testutil.NewKey(testutil.NewAESGCMKeyData(16), tinkpb.KeyStatusType_ENABLED, keyID, tinkpb.OutputPrefixType_TINK))
+	}
+	h, err := testkeyset.NewHandle(testutil.NewKeyset(keyIDs[0], keys))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v, want nil", err)
+	}
+	return h
+}
+
+func mustEncrypt(t *testing.T, h *keyset.Handle, plaintext []byte) []byte {
+	t.Helper()
+	a, err := aead.New(h)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	ciphertext, err := a.Encrypt(plaintext, nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	return ciphertext
+}
+
+func TestMerge(t *testing.T) {
+	h1 := newAEADKeyset(t, 1, 2)
+	h2 := newAEADKeyset(t, 3)
+	plaintext := []byte("plaintext")
+	ciphertext1 := mustEncrypt(t, h1, plaintext)
+	ciphertext2 := mustEncrypt(t, h2, plaintext)
+
+	// h1 is passed twice to check that identical keys are only added once.
+	merged, err := keyset.Merge(h1, h2, h1)
+	if err != nil {
+		t.Fatalf("keyset.Merge() err = %v, want nil", err)
+	}
+	ks := testkeyset.KeysetMaterial(merged)
+	if ks.GetPrimaryKeyId() != 1 {
+		t.Errorf("ks.GetPrimaryKeyId() = %d, want 1", ks.GetPrimaryKeyId())
+	}
+	var keyIDs []uint32
+	for _, key := range ks.GetKey() {
+		keyIDs = append(keyIDs, key.GetKeyId())
+	}
+	if len(keyIDs) != 3 || keyIDs[0] != 1 || keyIDs[1] != 2 || keyIDs[2] != 3 {
+		t.Errorf("key IDs = %v, want [1 2 3]", keyIDs)
+	}
+
+	a, err := aead.New(merged)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	for _, ciphertext := range [][]byte{ciphertext1, ciphertext2} {
+		got, err := a.Decrypt(ciphertext, nil)
+		if err != nil {
+			t.Fatalf("a.Decrypt() err = %v, want nil", err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("a.Decrypt() = %q, want %q", got, plaintext)
+		}
+	}
+}
+
+func TestMergeFailsWithKeyIDConflict(t *testing.T) {
+	if _, err := keyset.Merge(newAEADKeyset(t, 1, 2), newAEADKeyset(t, 2)); err == nil {
+		t.Errorf("keyset.Merge() err = nil, want error")
+	}
+	if _, err := keyset.Merge(nil); err == nil {
+		t.Errorf("keyset.Merge(nil) err = nil, want error")
+	}
+}
+
+func TestExtract(t *testing.T) {
+	km := keyset.NewManagerFromHandle(newAEADKeyset(t, 1, 2, 3))
+	if err := km.Disable(3); err != nil {
+		t.Fatalf("km.Disable() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+
+	primaryOnly, err := h.Extract(1)
+	if err != nil {
+		t.Fatalf("h.Extract(1) err = %v, want nil", err)
+	}
+	if primaryOnly.Len() != 1 {
+		t.Errorf("primaryOnly.Len() = %d, want 1", primaryOnly.Len())
+	}
+	if _, err := aead.New(primaryOnly); err != nil {
+		t.Errorf("aead.New(primaryOnly) err = %v, want nil", err)
+	}
+
+	extracted, err := h.Extract(2, 3)
+	if err != nil {
+		t.Fatalf("h.Extract(2, 3) err = %v, want nil", err)
+	}
+	primary, err := extracted.Primary()
+	if err != nil {
+		t.Fatalf("extracted.Primary() err = %v, want nil", err)
+	}
+	if primary.KeyID() != 2 {
+		t.Errorf("primary.KeyID() = %d, want 2", primary.KeyID())
+	}
+	entry, err := extracted.Entry(1)
+	if err != nil {
+		t.Fatalf("extracted.Entry(1) err = %v, want nil", err)
+	}
+	if entry.KeyID() != 3 || entry.KeyStatus() != keyset.Disabled {
+		t.Errorf("entry = (%d, %v), want (3, Disabled)", entry.KeyID(), entry.KeyStatus())
+	}
+
+	if _, err := h.Extract(1, 4); err == nil {
+		t.Errorf("h.Extract(1, 4) err = nil, want error")
+	}
+	if _, err := h.Extract(3); err == nil {
+		t.Errorf("h.Extract(3) with disabled primary err = nil, want error")
+	}
+}
+
+func TestManagerImportEntry(t *testing.T) {
+	source := newAEADKeyset(t, 5)
+	entry, err := source.Entry(0)
+	if err != nil {
+		t.Fatalf("source.Entry(0) err = %v, want nil", err)
+	}
+	km := keyset.NewManagerFromHandle(newAEADKeyset(t, 1))
+	if err := km.ImportEntry(entry); err != nil {
+		t.Fatalf("km.ImportEntry() err = %v, want nil", err)
+	}
+	if err := km.ImportEntry(entry); err == nil {
+		t.Errorf("km.ImportEntry() of the same entry twice err = nil, want error")
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	ks := testkeyset.KeysetMaterial(h)
+	if len(ks.GetKey()) != 2 || ks.GetKey()[1].GetKeyId() != 5 {
+		t.Errorf("ks.GetKey() = %v, want the imported key with ID 5", ks.GetKey())
+	}
+	ciphertext := mustEncrypt(t, source, []byte("plaintext"))
+	a, err := aead.New(h)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	if _, err := a.Decrypt(ciphertext, nil); err != nil {
+		t.Errorf("a.Decrypt() err = %v, want nil", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"fmt"
+
+	metadatapb "github.com/tink-crypto/tink-go/v2/proto/keyset_metadata_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// keyMetadata is information about a key that isn't part of the Keyset proto,
+// which is shared with the other Tink implementations.
+type keyMetadata struct {
+	// creationTime and activationTime are in seconds since the Unix epoch;
+	// zero if unknown.
+	creationTime   int64
+	activationTime int64
+	// destroyedParameters are the parameters of a destroyed key, if known.
+	destroyedParameters *tinkpb.KeyTemplate
+}
+
+// Metadata returns the metadata of the keys in h, such as their creation and
+// activation times and the parameters of destroyed keys. It doesn't contain
+// any key material.
+//
+// The metadata isn't written by [Handle.Write] and the other functions that
+// serialize the keyset, because it is specific to Tink Go. To keep it, store
+// it next to the keyset and pass it to [WithMetadata] when reading the keyset.
+func (h *Handle) Metadata() *metadatapb.KeysetMetadata {
+	md := &metadatapb.KeysetMetadata{}
+	for _, entry := range h.entries {
+		if entry.metadata == (keyMetadata{}) {
+			continue
+		}
+		md.Key = append(md.Key, &metadatapb.KeysetMetadata_Key{
+			KeyId:                  entry.keyID,
+			CreationTime:           entry.metadata.creationTime,
+			ActivationTime:         entry.metadata.activationTime,
+			DestroyedKeyParameters: entry.metadata.destroyedParameters,
+		})
+	}
+	return md
+}
+
+// WithMetadata sets the metadata of the keys of a keyset handle, as returned
+// by [Handle.Metadata]. Metadata of keys that aren't in the keyset is ignored.
+func WithMetadata(md *metadatapb.KeysetMetadata) Option {
+	return option(func(h *Handle) error {
+		keys := make(map[uint32]keyMetadata)
+		for _, k := range md.GetKey() {
+			if _, found := keys[k.GetKeyId()]; found {
+				return fmt.Errorf("metadata contains key ID %d more than once", k.GetKeyId())
+			}
+			keys[k.GetKeyId()] = keyMetadata{
+				creationTime:        k.GetCreationTime(),
+				activationTime:      k.GetActivationTime(),
+				destroyedParameters: k.GetDestroyedKeyParameters(),
+			}
+		}
+		return withKeyMetadata(keys).set(h)
+	})
+}
+
+// withKeyMetadata sets the metadata of the entries of a keyset handle by key
+// ID.
+func withKeyMetadata(keys map[uint32]keyMetadata) Option {
+	return option(func(h *Handle) error {
+		for _, entry := range h.entries {
+			md, found := keys[entry.keyID]
+			if !found {
+				continue
+			}
+			entry.metadata = md
+			if k, ok := entry.key.(*destroyedKey); ok {
+				entry.key = k.withParameters(md.destroyedParameters)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/insecurecleartextkeyset"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+
+	metadatapb "github.com/tink-crypto/tink-go/v2/proto/keyset_metadata_go_proto"
+)
+
+func TestMetadata(t *testing.T) {
+	km := keyset.NewManager()
+	primaryKeyID, err := km.Add(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(primaryKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	keyID, err := km.Add(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	md := h.Metadata()
+	if len(md.GetKey()) != 2 {
+		t.Fatalf("len(h.Metadata().GetKey()) = %d, want 2", len(md.GetKey()))
+	}
+	for i, wantKeyID := range []uint32{primaryKeyID, keyID} {
+		k := md.GetKey()[i]
+		if k.GetKeyId() != wantKeyID || k.GetCreationTime() == 0 {
+			t.Errorf("h.Metadata().GetKey()[%d] = %v, want key %d with a creation time", i, k, wantKeyID)
+		}
+		if got, want := k.GetActivationTime() != 0, wantKeyID == primaryKeyID; got != want {
+			t.Errorf("h.Metadata().GetKey()[%d] has activation time = %v, want %v", i, got, want)
+		}
+	}
+
+	// The metadata isn't part of the keyset.
+	ks := insecurecleartextkeyset.KeysetMaterial(h)
+	withoutMetadata, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() err = %v, want nil", err)
+	}
+	if got := withoutMetadata.Metadata(); len(got.GetKey()) != 0 {
+		t.Errorf("withoutMetadata.Metadata() = %v, want empty", got)
+	}
+
+	withMetadata, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks}, keyset.WithMetadata(md))
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() err = %v, want nil", err)
+	}
+	if diff := cmp.Diff(md, withMetadata.Metadata(), protocmp.Transform()); diff != "" {
+		t.Errorf("withMetadata.Metadata() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithMetadataIgnoresUnknownKeys(t *testing.T) {
+	h := newAEADKeyset(t, 1, 2)
+	md := &metadatapb.KeysetMetadata{
+		Key: []*metadatapb.KeysetMetadata_Key{
+			{KeyId: 2, CreationTime: 1700000000},
+			{KeyId: 3, CreationTime: 1600000000},
+		},
+	}
+	got, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: insecurecleartextkeyset.KeysetMaterial(h)}, keyset.WithMetadata(md))
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() err = %v, want nil", err)
+	}
+	entry, err := got.Entry(1)
+	if err != nil {
+		t.Fatalf("got.Entry(1) err = %v, want nil", err)
+	}
+	if want := time.Unix(1700000000, 0); !entry.CreationTime().Equal(want) {
+		t.Errorf("entry.CreationTime() = %v, want %v", entry.CreationTime(), want)
+	}
+	want := &metadatapb.KeysetMetadata{
+		Key: []*metadatapb.KeysetMetadata_Key{{KeyId: 2, CreationTime: 1700000000}},
+	}
+	if diff := cmp.Diff(want, got.Metadata(), protocmp.Transform()); diff != "" {
+		t.Errorf("got.Metadata() diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestWithMetadataFailsWithDuplicateKeyIDs(t *testing.T) {
+	h := newAEADKeyset(t, 1)
+	md := &metadatapb.KeysetMetadata{
+		Key: []*metadatapb.KeysetMetadata_Key{
+			{KeyId: 1, CreationTime: 1700000000},
+			{KeyId: 1, CreationTime: 1600000000},
+		},
+	}
+	if _, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: insecurecleartextkeyset.KeysetMaterial(h)}, keyset.WithMetadata(md)); err == nil {
+		t.Errorf("insecurecleartextkeyset.Read() err = nil, want error")
+	}
+}
+
+func TestMetadataIsKeptByManagerOperations(t *testing.T) {
+	km := keyset.NewManager()
+	keyID, err := km.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(keyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	want := h.Metadata()
+
+	// Through a new manager.
+	got, err := keyset.NewManagerFromHandle(h).Handle()
+	if err != nil {
+		t.Fatalf("keyset.NewManagerFromHandle(h).Handle() err = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, got.Metadata(), protocmp.Transform()); diff != "" {
+		t.Errorf("NewManagerFromHandle: Metadata() diff (-want +got):\n%s", diff)
+	}
+
+	// Through Merge and Extract, which import the entries of other handles.
+	merged, err := keyset.Merge(newAEADKeyset(t, 1), h)
+	if err != nil {
+		t.Fatalf("keyset.Merge() err = %v, want nil", err)
+	}
+	extracted, err := merged.Extract(keyID)
+	if err != nil {
+		t.Fatalf("merged.Extract() err = %v, want nil", err)
+	}
+	if diff := cmp.Diff(want, extracted.Metadata(), protocmp.Transform()); diff != "" {
+		t.Errorf("Merge and Extract: Metadata() diff (-want +got):\n%s", diff)
+	}
+}
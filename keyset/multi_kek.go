@@ -0,0 +1,263 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	internalaead "github.com/tink-crypto/tink-go/v2/internal/aead"
+	"github.com/tink-crypto/tink-go/v2/internal/shamir"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// A keyset encrypted under several key encryption keys (KEKs) is stored in the
+// encrypted_keyset field of an EncryptedKeyset, so that it can be read and
+// written by the usual readers and writers. Its format is:
+//
+//	header || nonce || ciphertext || tag
+//	header = magic || threshold || n || n * (uint32 length || wrapped share)
+//
+// where the keyset is encrypted with AES-256-GCM under a random wrapping key,
+// the wrapping key is split into n shares with Shamir's secret sharing, and
+// share i is encrypted with the i-th KEK. The associated data of the AES-GCM
+// encryption is the header followed by the caller's associated data, so that
+// the header cannot be modified. Lengths are big-endian. With a threshold of
+// 1, each share is the wrapping key itself.
+var multiKEKMagic = []byte{0x7f, 'M', 'K', 'E', 'K', 0x01}
+
+const (
+	multiKEKWrappingKeySize = 32
+	maxLengthWrappedShare   = 4096
+)
+
+// WriteWithKEKs encrypts the keyset under each of keyEncryptionAEADs and
+// writes it to writer, so that it can be read back with any threshold of the
+// KEKs.
+//
+// With a threshold of 1, the keyset can be read with [ReadWithContext] using
+// any one of the KEKs, for example a cloud KMS key or an offline recovery key.
+// With a larger threshold, [ReadWithKEKs] must be given at least threshold of
+// the KEKs. associatedData must be given again when reading the keyset.
+func (h *Handle) WriteWithKEKs(ctx context.Context, writer Writer, keyEncryptionAEADs []tink.AEADWithContext, threshold int, associatedData []byte) error {
+	if h == nil {
+		return fmt.Errorf("keyset.Handle: nil handle")
+	}
+	protoKeyset, err := entriesToProtoKeyset(h.entries)
+	if err != nil {
+		return err
+	}
+	encrypted, err := encryptWithKEKs(ctx, protoKeyset, keyEncryptionAEADs, threshold, associatedData)
+	if err != nil {
+		return err
+	}
+	return writer.WriteEncrypted(encrypted)
+}
+
+// ReadWithKEKs creates a keyset.Handle from a keyset written with
+// [Handle.WriteWithKEKs], using any of keyEncryptionAEADs that can decrypt
+// a share of the wrapping key. At least as many KEKs as the threshold used
+// when writing must be available.
+func ReadWithKEKs(ctx context.Context, reader Reader, keyEncryptionAEADs []tink.AEADWithContext, associatedData []byte) (*Handle, error) {
+	encryptedKeyset, err := reader.ReadEncrypted()
+	if err != nil {
+		return nil, err
+	}
+	if !isMultiKEKEncryptedKeyset(encryptedKeyset) {
+		return nil, errors.New("keyset.Handle: keyset is not encrypted with multiple KEKs")
+	}
+	protoKeyset, err := decryptWithKEKs(ctx, encryptedKeyset, keyEncryptionAEADs, associatedData)
+	if err != nil {
+		return nil, err
+	}
+	return newWithOptions(protoKeyset)
+}
+
+// isMultiKEKEncryptedKeyset reports whether encryptedKeyset was written with
+// WriteWithKEKs. Keysets encrypted with a single KEK whose ciphertext happens
+// to start with the magic bytes are still read as such, unless they also parse
+// as a multi-KEK keyset.
+func isMultiKEKEncryptedKeyset(encryptedKeyset *tinkpb.EncryptedKeyset) bool {
+	_, err := parseMultiKEKKeyset(encryptedKeyset.GetEncryptedKeyset())
+	return err == nil
+}
+
+// multiKEKKeyset is a parsed keyset encrypted with multiple KEKs.
+type multiKEKKeyset struct {
+	header        []byte
+	threshold     int
+	wrappedShares [][]byte
+	// ciphertext is the nonce, encrypted keyset and tag.
+	ciphertext []byte
+}
+
+// multiKEKAssociatedData returns the associated data used to encrypt the
+// keyset under the wrapping key.
+func multiKEKAssociatedData(header, associatedData []byte) []byte {
+	ad := make([]byte, 0, len(header)+len(associatedData))
+	ad = append(ad, header...)
+	return append(ad, associatedData...)
+}
+
+func encryptWithKEKs(ctx context.Context, keyset *tinkpb.Keyset, keyEncryptionAEADs []tink.AEADWithContext, threshold int, associatedData []byte) (*tinkpb.EncryptedKeyset, error) {
+	if len(keyEncryptionAEADs) == 0 {
+		return nil, errors.New("keyset.Handle: no key encryption AEAD")
+	}
+	serializedKeyset, err := proto.Marshal(keyset)
+	if err != nil {
+		return nil, errInvalidKeyset
+	}
+	wrappingKey := make([]byte, multiKEKWrappingKeySize)
+	if _, err := rand.Read(wrappingKey); err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %v", err)
+	}
+	shares, err := shamir.Split(wrappingKey, len(keyEncryptionAEADs), threshold)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %v", err)
+	}
+	res := append([]byte{}, multiKEKMagic...)
+	res = append(res, byte(threshold), byte(len(shares)))
+	for i, kek := range keyEncryptionAEADs {
+		share := shares[i]
+		if threshold == 1 {
+			share = wrappingKey
+		}
+		wrapped, err := kek.EncryptWithContext(ctx, share, associatedData)
+		if err != nil {
+			return nil, fmt.Errorf("keyset.Handle: encryption with KEK %d failed: %v", i, err)
+		}
+		if len(wrapped) > maxLengthWrappedShare {
+			return nil, fmt.Errorf("keyset.Handle: wrapped share too large; got %d, want at most %d", len(wrapped), maxLengthWrappedShare)
+		}
+		res = binary.BigEndian.AppendUint32(res, uint32(len(wrapped)))
+		res = append(res, wrapped...)
+	}
+	wrappingAEAD, err := internalaead.NewAESGCM(wrappingKey)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %v", err)
+	}
+	ciphertext, err := wrappingAEAD.Encrypt(serializedKeyset, multiKEKAssociatedData(res, associatedData))
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %v", err)
+	}
+	res = append(res, ciphertext...)
+	return &tinkpb.EncryptedKeyset{
+		EncryptedKeyset: res,
+		KeysetInfo:      getKeysetInfo(keyset),
+	}, nil
+}
+
+func parseMultiKEKKeyset(b []byte) (*multiKEKKeyset, error) {
+	errInvalid := errors.New("keyset.Handle: invalid multi-KEK encrypted keyset")
+	if !bytes.HasPrefix(b, multiKEKMagic) {
+		return nil, errInvalid
+	}
+	encrypted := b
+	b = b[len(multiKEKMagic):]
+	if len(b) < 2 {
+		return nil, errInvalid
+	}
+	parsed := &multiKEKKeyset{threshold: int(b[0])}
+	n := int(b[1])
+	b = b[2:]
+	if parsed.threshold < 1 || parsed.threshold > n {
+		return nil, errInvalid
+	}
+	for i := 0; i < n; i++ {
+		if len(b) < 4 {
+			return nil, errInvalid
+		}
+		l := binary.BigEndian.Uint32(b)
+		b = b[4:]
+		if l == 0 || l > maxLengthWrappedShare || int(l) > len(b) {
+			return nil, errInvalid
+		}
+		parsed.wrappedShares = append(parsed.wrappedShares, b[:l])
+		b = b[l:]
+	}
+	if len(b) < internalaead.AESGCMIVSize+internalaead.AESGCMTagSize {
+		return nil, errInvalid
+	}
+	parsed.header = encrypted[:len(encrypted)-len(b)]
+	parsed.ciphertext = b
+	return parsed, nil
+}
+
+// decryptWithKEKs decrypts shares with keyEncryptionAEADs until threshold
+// shares are known, then decrypts the keyset.
+func decryptWithKEKs(ctx context.Context, encryptedKeyset *tinkpb.EncryptedKeyset, keyEncryptionAEADs []tink.AEADWithContext, associatedData []byte) (*tinkpb.Keyset, error) {
+	parsed, err := parseMultiKEKKeyset(encryptedKeyset.GetEncryptedKeyset())
+	if err != nil {
+		return nil, err
+	}
+	var shares [][]byte
+	decrypted := make([]bool, len(parsed.wrappedShares))
+	for _, kek := range keyEncryptionAEADs {
+		if kek == nil {
+			continue
+		}
+		// Each KEK can decrypt at most one share; try the remaining ones in order.
+		for i, wrapped := range parsed.wrappedShares {
+			if decrypted[i] {
+				continue
+			}
+			share, err := kek.DecryptWithContext(ctx, wrapped, associatedData)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return nil, fmt.Errorf("keyset.Handle: decryption failed: %v", ctxErr)
+				}
+				continue
+			}
+			decrypted[i] = true
+			shares = append(shares, share)
+			break
+		}
+		if len(shares) == parsed.threshold {
+			break
+		}
+	}
+	if len(shares) < parsed.threshold {
+		return nil, fmt.Errorf("keyset.Handle: decryption failed: %d of %d required KEKs available", len(shares), parsed.threshold)
+	}
+	wrappingKey := shares[0]
+	if parsed.threshold > 1 {
+		if wrappingKey, err = shamir.Combine(shares); err != nil {
+			return nil, fmt.Errorf("keyset.Handle: decryption failed: %v", err)
+		}
+	}
+	if len(wrappingKey) != multiKEKWrappingKeySize {
+		return nil, errors.New("keyset.Handle: decryption failed: invalid wrapping key")
+	}
+	wrappingAEAD, err := internalaead.NewAESGCM(wrappingKey)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: decryption failed: %v", err)
+	}
+	serializedKeyset, err := wrappingAEAD.Decrypt(parsed.ciphertext, multiKEKAssociatedData(parsed.header, associatedData))
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: decryption failed: %v", err)
+	}
+	keyset := new(tinkpb.Keyset)
+	if err := proto.Unmarshal(serializedKeyset, keyset); err != nil {
+		return nil, errInvalidKeyset
+	}
+	return keyset, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/insecurecleartextkeyset"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func newKEKs(t *testing.T, n int) []tink.AEADWithContext {
+	t.Helper()
+	var keks []tink.AEADWithContext
+	for i := 0; i < n; i++ {
+		keyURI, err := fakekms.NewKeyURI()
+		if err != nil {
+			t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+		}
+		kek, err := fakekms.NewAEADWithContext(keyURI)
+		if err != nil {
+			t.Fatalf("fakekms.NewAEADWithContext() err = %v, want nil", err)
+		}
+		keks = append(keks, kek)
+	}
+	return keks
+}
+
+func writeWithKEKs(t *testing.T, handle *keyset.Handle, keks []tink.AEADWithContext, threshold int, associatedData []byte) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := handle.WriteWithKEKs(context.Background(), keyset.NewBinaryWriter(buf), keks, threshold, associatedData); err != nil {
+		t.Fatalf("handle.WriteWithKEKs() err = %v, want nil", err)
+	}
+	return buf.Bytes()
+}
+
+func assertSameKeyset(t *testing.T, got, want *keyset.Handle) {
+	t.Helper()
+	if !proto.Equal(insecurecleartextkeyset.KeysetMaterial(got), insecurecleartextkeyset.KeysetMaterial(want)) {
+		t.Error("keyset read back differs from the keyset written")
+	}
+}
+
+func TestWriteWithKEKsReadWithAnyKEK(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	keks := newKEKs(t, 3)
+	associatedData := []byte("associatedData")
+	encrypted := writeWithKEKs(t, handle, keks, 1, associatedData)
+
+	for i, kek := range keks {
+		got, err := keyset.ReadWithContext(context.Background(), keyset.NewBinaryReader(bytes.NewReader(encrypted)), kek, associatedData)
+		if err != nil {
+			t.Fatalf("keyset.ReadWithContext() with KEK %d err = %v, want nil", i, err)
+		}
+		assertSameKeyset(t, got, handle)
+	}
+	if _, err := keyset.ReadWithContext(context.Background(), keyset.NewBinaryReader(bytes.NewReader(encrypted)), keks[0], []byte("invalid")); err == nil {
+		t.Error("keyset.ReadWithContext() with invalid associated data err = nil, want error")
+	}
+	other := newKEKs(t, 1)[0]
+	if _, err := keyset.ReadWithContext(context.Background(), keyset.NewBinaryReader(bytes.NewReader(encrypted)), other, associatedData); err == nil {
+		t.Error("keyset.ReadWithContext() with unrelated KEK err = nil, want error")
+	}
+}
+
+func TestWriteWithKEKsAuthenticatesHeader(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	keks := newKEKs(t, 2)
+	encrypted := new(tinkpb.EncryptedKeyset)
+	if err := proto.Unmarshal(writeWithKEKs(t, handle, keks, 1, nil), encrypted); err != nil {
+		t.Fatalf("proto.Unmarshal() err = %v, want nil", err)
+	}
+	// Modify the last byte of the second wrapped share, which reading with
+	// the first KEK doesn't decrypt.
+	b := encrypted.GetEncryptedKeyset()
+	const sharesOffset = 6 + 2
+	firstShareLength := int(binary.BigEndian.Uint32(b[sharesOffset:]))
+	secondShareOffset := sharesOffset + 4 + firstShareLength
+	secondShareLength := int(binary.BigEndian.Uint32(b[secondShareOffset:]))
+	b[secondShareOffset+4+secondShareLength-1] ^= 1
+	tampered, err := proto.Marshal(encrypted)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	if _, err := keyset.ReadWithKEKs(context.Background(), keyset.NewBinaryReader(bytes.NewReader(tampered)), keks[:1], nil); err == nil {
+		t.Error("keyset.ReadWithKEKs() with modified header err = nil, want error")
+	}
+}
+
+func TestWriteWithKEKsThreshold(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	keks := newKEKs(t, 3)
+	encrypted := writeWithKEKs(t, handle, keks, 2, nil)
+
+	for _, subset := range [][]tink.AEADWithContext{
+		{keks[0], keks[1]},
+		{keks[2], keks[0]},
+		{keks[1], keks[2]},
+		keks,
+	} {
+		got, err := keyset.ReadWithKEKs(context.Background(), keyset.NewBinaryReader(bytes.NewReader(encrypted)), subset, nil)
+		if err != nil {
+			t.Fatalf("keyset.ReadWithKEKs() err = %v, want nil", err)
+		}
+		assertSameKeyset(t, got, handle)
+	}
+	// One KEK is not enough.
+	if _, err := keyset.ReadWithKEKs(context.Background(), keyset.NewBinaryReader(bytes.NewReader(encrypted)), keks[:1], nil); err == nil {
+		t.Error("keyset.ReadWithKEKs() with one KEK err = nil, want error")
+	}
+	if _, err := keyset.ReadWithContext(context.Background(), keyset.NewBinaryReader(bytes.NewReader(encrypted)), keks[0], nil); err == nil {
+		t.Error("keyset.ReadWithContext() with threshold 2 err = nil, want error")
+	}
+	// The same KEK given twice counts once.
+	if _, err := keyset.ReadWithKEKs(context.Background(), keyset.NewBinaryReader(bytes.NewReader(encrypted)), []tink.AEADWithContext{keks[0], keks[0]}, nil); err == nil {
+		t.Error("keyset.ReadWithKEKs() with a repeated KEK err = nil, want error")
+	}
+}
+
+func TestWriteWithKEKsJSON(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	keks := newKEKs(t, 2)
+	buf := new(bytes.Buffer)
+	if err := handle.WriteWithKEKs(context.Background(), keyset.NewJSONWriter(buf), keks, 1, nil); err != nil {
+		t.Fatalf("handle.WriteWithKEKs() err = %v, want nil", err)
+	}
+	got, err := keyset.ReadWithKEKs(context.Background(), keyset.NewJSONReader(buf), keks[1:], nil)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithKEKs() err = %v, want nil", err)
+	}
+	assertSameKeyset(t, got, handle)
+}
+
+func TestWriteWithKEKsInvalidArguments(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	keks := newKEKs(t, 2)
+	for _, tc := range []struct {
+		name      string
+		keks      []tink.AEADWithContext
+		threshold int
+	}{
+		{"no KEKs", nil, 1},
+		{"zero threshold", keks, 0},
+		{"threshold above number of KEKs", keks, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := handle.WriteWithKEKs(context.Background(), keyset.NewBinaryWriter(buf), tc.keks, tc.threshold, nil); err == nil {
+				t.Error("handle.WriteWithKEKs() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestReadWithKEKsRejectsSingleKEKKeyset(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	kek := newKEKs(t, 1)[0]
+	buf := new(bytes.Buffer)
+	if err := handle.WriteWithContext(context.Background(), keyset.NewBinaryWriter(buf), kek, nil); err != nil {
+		t.Fatalf("handle.WriteWithContext() err = %v, want nil", err)
+	}
+	if _, err := keyset.ReadWithKEKs(context.Background(), keyset.NewBinaryReader(buf), []tink.AEADWithContext{kek}, nil); err == nil {
+		t.Error("keyset.ReadWithKEKs() with single-KEK keyset err = nil, want error")
+	}
+}
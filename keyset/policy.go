@@ -0,0 +1,306 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/internal/fips140"
+	aescmacpb "github.com/tink-crypto/tink-go/v2/proto/aes_cmac_go_proto"
+	aescmacprfpb "github.com/tink-crypto/tink-go/v2/proto/aes_cmac_prf_go_proto"
+	ctrhmacpb "github.com/tink-crypto/tink-go/v2/proto/aes_ctr_hmac_aead_go_proto"
+	ctrhmacstreamingpb "github.com/tink-crypto/tink-go/v2/proto/aes_ctr_hmac_streaming_go_proto"
+	gcmpb "github.com/tink-crypto/tink-go/v2/proto/aes_gcm_go_proto"
+	gcmhkdfstreamingpb "github.com/tink-crypto/tink-go/v2/proto/aes_gcm_hkdf_streaming_go_proto"
+	gcmsivpb "github.com/tink-crypto/tink-go/v2/proto/aes_gcm_siv_go_proto"
+	aessivpb "github.com/tink-crypto/tink-go/v2/proto/aes_siv_go_proto"
+	commonpb "github.com/tink-crypto/tink-go/v2/proto/common_go_proto"
+	ecdsapb "github.com/tink-crypto/tink-go/v2/proto/ecdsa_go_proto"
+	hkdfprfpb "github.com/tink-crypto/tink-go/v2/proto/hkdf_prf_go_proto"
+	hmacpb "github.com/tink-crypto/tink-go/v2/proto/hmac_go_proto"
+	hmacprfpb "github.com/tink-crypto/tink-go/v2/proto/hmac_prf_go_proto"
+	jwtrsapkcs1pb "github.com/tink-crypto/tink-go/v2/proto/jwt_rsa_ssa_pkcs1_go_proto"
+	jwtrsapsspb "github.com/tink-crypto/tink-go/v2/proto/jwt_rsa_ssa_pss_go_proto"
+	rsapkcs1pb "github.com/tink-crypto/tink-go/v2/proto/rsa_ssa_pkcs1_go_proto"
+	rsapsspb "github.com/tink-crypto/tink-go/v2/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const typeURLPrefix = "type.googleapis.com/google.crypto.tink."
+
+// isFIPSCompatible reports whether the key manager registered for typeURL
+// declares that its primitives only use FIPS 140-2 approved algorithms. Key
+// types without a registered key manager are not FIPS compatible.
+func isFIPSCompatible(typeURL string) bool {
+	km, err := registry.GetKeyManager(typeURL)
+	if err != nil {
+		return false
+	}
+	c, ok := km.(fips140.CompatibleKeyManager)
+	return ok && c.FIPSCompatible()
+}
+
+// Policy restricts the keys a keyset may contain. The zero value allows any
+// key.
+//
+// A policy is typically enforced when a keyset is loaded, by passing
+// [WithPolicy] to [Read], [ReadWithContext] or [NewHandleWithNoSecrets]. All
+// keys are checked, including disabled ones, since they may be enabled again.
+//
+// Size and hash restrictions only apply to key types whose key material
+// policies know how to inspect; keys of other types are only subject to
+// AllowedTypeURLs, FIPSOnly and DisallowLegacyPrefixes.
+type Policy struct {
+	// AllowedTypeURLs, if not empty, lists the only key types allowed.
+	AllowedTypeURLs []string
+	// FIPSOnly only allows key types whose key managers declare that their
+	// primitives use FIPS 140-2 approved algorithms: AES-GCM,
+	// AES-CTR-HMAC-AEAD, HMAC, AES-CMAC, HMAC-PRF, AES-CMAC-PRF, AES-KWP,
+	// ECDSA, RSA-SSA-PKCS1, RSA-SSA-PSS and their JWT variants. The key
+	// managers must be registered, so the packages implementing the key types
+	// must be linked into the binary.
+	FIPSOnly bool
+	// DisallowLegacyPrefixes rejects keys with the LEGACY or CRUNCHY output
+	// prefix types.
+	DisallowLegacyPrefixes bool
+	// DisallowSHA1 rejects keys that use SHA-1, for example HMAC-SHA1.
+	DisallowSHA1 bool
+	// MinAESKeySize is the minimum size in bytes of AES keys. For example, 32
+	// rejects AES-128.
+	MinAESKeySize int
+	// MinRSAModulusBits is the minimum size in bits of RSA moduli.
+	MinRSAModulusBits int
+}
+
+// WithPolicy makes loading a keyset handle fail if the keyset violates policy.
+func WithPolicy(policy *Policy) Option {
+	return option(func(h *Handle) error {
+		return policy.Check(h)
+	})
+}
+
+// Check returns an error listing all the keys in h that violate p, or nil if
+// there are none.
+func (p *Policy) Check(h *Handle) error {
+	if p == nil {
+		return fmt.Errorf("keyset.Policy: nil policy")
+	}
+	if h == nil {
+		return fmt.Errorf("keyset.Policy: nil handle")
+	}
+	var violations []string
+	for _, entry := range h.entries {
+		protoKey, err := entryToProtoKey(entry)
+		if err != nil {
+			return fmt.Errorf("keyset.Policy: %v", err)
+		}
+		for _, v := range p.violations(protoKey) {
+			violations = append(violations, fmt.Sprintf("key %d: %s", entry.KeyID(), v))
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("keyset.Policy: keyset violates policy: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+func (p *Policy) violations(protoKey *tinkpb.Keyset_Key) []string {
+	var violations []string
+	typeURL := protoKey.GetKeyData().GetTypeUrl()
+	if len(p.AllowedTypeURLs) > 0 && !contains(p.AllowedTypeURLs, typeURL) {
+		violations = append(violations, fmt.Sprintf("key type %s is not allowed", typeURL))
+	}
+	if p.FIPSOnly && !isFIPSCompatible(typeURL) {
+		violations = append(violations, fmt.Sprintf("key type %s is not FIPS approved", typeURL))
+	}
+	switch prefix := protoKey.GetOutputPrefixType(); prefix {
+	case tinkpb.OutputPrefixType_LEGACY, tinkpb.OutputPrefixType_CRUNCHY:
+		if p.DisallowLegacyPrefixes {
+			violations = append(violations, fmt.Sprintf("output prefix type %s is not allowed", prefix))
+		}
+	}
+	if !p.DisallowSHA1 && p.MinAESKeySize == 0 && p.MinRSAModulusBits == 0 {
+		return violations
+	}
+	props, err := inspectKeyData(protoKey.GetKeyData())
+	if err != nil {
+		return append(violations, fmt.Sprintf("cannot inspect key material: %v", err))
+	}
+	if p.DisallowSHA1 {
+		for _, hash := range props.hashes {
+			if hash == commonpb.HashType_SHA1 {
+				violations = append(violations, "SHA-1 is not allowed")
+				break
+			}
+		}
+	}
+	if props.aesKeySize > 0 && props.aesKeySize < p.MinAESKeySize {
+		violations = append(violations, fmt.Sprintf("AES key size %d is smaller than %d bytes", props.aesKeySize, p.MinAESKeySize))
+	}
+	if props.rsaModulusBits > 0 && props.rsaModulusBits < p.MinRSAModulusBits {
+		violations = append(violations, fmt.Sprintf("RSA modulus size %d is smaller than %d bits", props.rsaModulusBits, p.MinRSAModulusBits))
+	}
+	return violations
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// keyProperties are the properties of key material restricted by a Policy.
+// Zero values mean that the property doesn't apply to the key.
+type keyProperties struct {
+	aesKeySize     int
+	rsaModulusBits int
+	hashes         []commonpb.HashType
+}
+
+func rsaModulusBits(n []byte) int {
+	return new(big.Int).SetBytes(n).BitLen()
+}
+
+// inspectKeyData returns the properties of the key material in keyData. Key
+// types it doesn't know about have no properties.
+func inspectKeyData(keyData *tinkpb.KeyData) (*keyProperties, error) {
+	var m proto.Message
+	switch keyData.GetTypeUrl() {
+	case typeURLPrefix + "AesGcmKey":
+		m = &gcmpb.AesGcmKey{}
+	case typeURLPrefix + "AesGcmSivKey":
+		m = &gcmsivpb.AesGcmSivKey{}
+	case typeURLPrefix + "AesSivKey":
+		m = &aessivpb.AesSivKey{}
+	case typeURLPrefix + "AesCmacKey":
+		m = &aescmacpb.AesCmacKey{}
+	case typeURLPrefix + "AesCmacPrfKey":
+		m = &aescmacprfpb.AesCmacPrfKey{}
+	case typeURLPrefix + "AesCtrHmacAeadKey":
+		m = &ctrhmacpb.AesCtrHmacAeadKey{}
+	case typeURLPrefix + "AesGcmHkdfStreamingKey":
+		m = &gcmhkdfstreamingpb.AesGcmHkdfStreamingKey{}
+	case typeURLPrefix + "AesCtrHmacStreamingKey":
+		m = &ctrhmacstreamingpb.AesCtrHmacStreamingKey{}
+	case typeURLPrefix + "HmacKey":
+		m = &hmacpb.HmacKey{}
+	case typeURLPrefix + "HmacPrfKey":
+		m = &hmacprfpb.HmacPrfKey{}
+	case typeURLPrefix + "HkdfPrfKey":
+		m = &hkdfprfpb.HkdfPrfKey{}
+	case typeURLPrefix + "EcdsaPrivateKey":
+		m = &ecdsapb.EcdsaPrivateKey{}
+	case typeURLPrefix + "EcdsaPublicKey":
+		m = &ecdsapb.EcdsaPublicKey{}
+	case typeURLPrefix + "RsaSsaPkcs1PrivateKey":
+		m = &rsapkcs1pb.RsaSsaPkcs1PrivateKey{}
+	case typeURLPrefix + "RsaSsaPkcs1PublicKey":
+		m = &rsapkcs1pb.RsaSsaPkcs1PublicKey{}
+	case typeURLPrefix + "RsaSsaPssPrivateKey":
+		m = &rsapsspb.RsaSsaPssPrivateKey{}
+	case typeURLPrefix + "RsaSsaPssPublicKey":
+		m = &rsapsspb.RsaSsaPssPublicKey{}
+	case typeURLPrefix + "JwtRsaSsaPkcs1PrivateKey":
+		m = &jwtrsapkcs1pb.JwtRsaSsaPkcs1PrivateKey{}
+	case typeURLPrefix + "JwtRsaSsaPkcs1PublicKey":
+		m = &jwtrsapkcs1pb.JwtRsaSsaPkcs1PublicKey{}
+	case typeURLPrefix + "JwtRsaSsaPssPrivateKey":
+		m = &jwtrsapsspb.JwtRsaSsaPssPrivateKey{}
+	case typeURLPrefix + "JwtRsaSsaPssPublicKey":
+		m = &jwtrsapsspb.JwtRsaSsaPssPublicKey{}
+	default:
+		return &keyProperties{}, nil
+	}
+	if err := proto.Unmarshal(keyData.GetValue(), m); err != nil {
+		return nil, err
+	}
+	switch k := m.(type) {
+	case *gcmpb.AesGcmKey:
+		return &keyProperties{aesKeySize: len(k.GetKeyValue())}, nil
+	case *gcmsivpb.AesGcmSivKey:
+		return &keyProperties{aesKeySize: len(k.GetKeyValue())}, nil
+	case *aessivpb.AesSivKey:
+		// AES-SIV keys consist of two AES keys of equal size.
+		return &keyProperties{aesKeySize: len(k.GetKeyValue()) / 2}, nil
+	case *aescmacpb.AesCmacKey:
+		return &keyProperties{aesKeySize: len(k.GetKeyValue())}, nil
+	case *aescmacprfpb.AesCmacPrfKey:
+		return &keyProperties{aesKeySize: len(k.GetKeyValue())}, nil
+	case *ctrhmacpb.AesCtrHmacAeadKey:
+		return &keyProperties{
+			aesKeySize: len(k.GetAesCtrKey().GetKeyValue()),
+			hashes:     []commonpb.HashType{k.GetHmacKey().GetParams().GetHash()},
+		}, nil
+	case *gcmhkdfstreamingpb.AesGcmHkdfStreamingKey:
+		return &keyProperties{
+			aesKeySize: int(k.GetParams().GetDerivedKeySize()),
+			hashes:     []commonpb.HashType{k.GetParams().GetHkdfHashType()},
+		}, nil
+	case *ctrhmacstreamingpb.AesCtrHmacStreamingKey:
+		return &keyProperties{
+			aesKeySize: int(k.GetParams().GetDerivedKeySize()),
+			hashes: []commonpb.HashType{
+				k.GetParams().GetHkdfHashType(),
+				k.GetParams().GetHmacParams().GetHash(),
+			},
+		}, nil
+	case *hmacpb.HmacKey:
+		return &keyProperties{hashes: []commonpb.HashType{k.GetParams().GetHash()}}, nil
+	case *hmacprfpb.HmacPrfKey:
+		return &keyProperties{hashes: []commonpb.HashType{k.GetParams().GetHash()}}, nil
+	case *hkdfprfpb.HkdfPrfKey:
+		return &keyProperties{hashes: []commonpb.HashType{k.GetParams().GetHash()}}, nil
+	case *ecdsapb.EcdsaPrivateKey:
+		return &keyProperties{hashes: []commonpb.HashType{k.GetPublicKey().GetParams().GetHashType()}}, nil
+	case *ecdsapb.EcdsaPublicKey:
+		return &keyProperties{hashes: []commonpb.HashType{k.GetParams().GetHashType()}}, nil
+	case *rsapkcs1pb.RsaSsaPkcs1PrivateKey:
+		return &keyProperties{
+			rsaModulusBits: rsaModulusBits(k.GetPublicKey().GetN()),
+			hashes:         []commonpb.HashType{k.GetPublicKey().GetParams().GetHashType()},
+		}, nil
+	case *rsapkcs1pb.RsaSsaPkcs1PublicKey:
+		return &keyProperties{
+			rsaModulusBits: rsaModulusBits(k.GetN()),
+			hashes:         []commonpb.HashType{k.GetParams().GetHashType()},
+		}, nil
+	case *rsapsspb.RsaSsaPssPrivateKey:
+		return &keyProperties{
+			rsaModulusBits: rsaModulusBits(k.GetPublicKey().GetN()),
+			hashes:         []commonpb.HashType{k.GetPublicKey().GetParams().GetSigHash(), k.GetPublicKey().GetParams().GetMgf1Hash()},
+		}, nil
+	case *rsapsspb.RsaSsaPssPublicKey:
+		return &keyProperties{
+			rsaModulusBits: rsaModulusBits(k.GetN()),
+			hashes:         []commonpb.HashType{k.GetParams().GetSigHash(), k.GetParams().GetMgf1Hash()},
+		}, nil
+	case *jwtrsapkcs1pb.JwtRsaSsaPkcs1PrivateKey:
+		return &keyProperties{rsaModulusBits: rsaModulusBits(k.GetPublicKey().GetN())}, nil
+	case *jwtrsapkcs1pb.JwtRsaSsaPkcs1PublicKey:
+		return &keyProperties{rsaModulusBits: rsaModulusBits(k.GetN())}, nil
+	case *jwtrsapsspb.JwtRsaSsaPssPrivateKey:
+		return &keyProperties{rsaModulusBits: rsaModulusBits(k.GetPublicKey().GetN())}, nil
+	case *jwtrsapsspb.JwtRsaSsaPssPublicKey:
+		return &keyProperties{rsaModulusBits: rsaModulusBits(k.GetN())}, nil
+	}
+	return &keyProperties{}, nil
+}
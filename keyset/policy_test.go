@@ -0,0 +1,245 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	_ "github.com/tink-crypto/tink-go/v2/daead"
+	_ "github.com/tink-crypto/tink-go/v2/hybrid"
+	"github.com/tink-crypto/tink-go/v2/internal/fips140"
+	"github.com/tink-crypto/tink-go/v2/jwt"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/kwp"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	_ "github.com/tink-crypto/tink-go/v2/prf"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	_ "github.com/tink-crypto/tink-go/v2/streamingaead"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+	"github.com/tink-crypto/tink-go/v2/testutil"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	commonpb "github.com/tink-crypto/tink-go/v2/proto/common_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func newPolicyTestKEK(t *testing.T) tink.AEAD {
+	t.Helper()
+	kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	kek, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	return kek
+}
+
+// writePolicyTestKeyset writes an encrypted keyset with an AES-128-GCM key
+// with a LEGACY prefix, an HMAC-SHA1 key and a primary AES-256-GCM-SIV key.
+func writePolicyTestKeyset(t *testing.T, kek tink.AEAD) *keyset.MemReaderWriter {
+	t.Helper()
+	aesGCMSIVKey, err := proto.Marshal(testutil.NewAESGCMSIVKey(0, 32))
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	ks := testutil.NewKeyset(3, []*tinkpb.Keyset_Key{
+		testutil.NewKey(testutil.NewAESGCMKeyData(16), tinkpb.KeyStatusType_DISABLED, 1, tinkpb.OutputPrefixType_LEGACY),
+		testutil.NewKey(testutil.NewHMACKeyData(commonpb.HashType_SHA1, 16), tinkpb.KeyStatusType_ENABLED, 2, tinkpb.OutputPrefixType_TINK),
+		testutil.NewKey(testutil.NewKeyData(testutil.AESGCMSIVTypeURL, aesGCMSIVKey, tinkpb.KeyData_SYMMETRIC), tinkpb.KeyStatusType_ENABLED, 3, tinkpb.OutputPrefixType_TINK),
+	})
+	h, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v, want nil", err)
+	}
+	buf := &keyset.MemReaderWriter{}
+	if err := h.Write(buf, kek); err != nil {
+		t.Fatalf("h.Write() err = %v, want nil", err)
+	}
+	return buf
+}
+
+func TestReadWithPolicy(t *testing.T) {
+	kek := newPolicyTestKEK(t)
+	buf := writePolicyTestKeyset(t, kek)
+
+	for _, tc := range []struct {
+		name   string
+		policy *keyset.Policy
+	}{
+		{
+			name:   "empty",
+			policy: &keyset.Policy{},
+		},
+		{
+			name: "satisfied",
+			policy: &keyset.Policy{
+				AllowedTypeURLs: []string{
+					testutil.AESGCMTypeURL,
+					testutil.HMACTypeURL,
+					testutil.AESGCMSIVTypeURL,
+				},
+				MinAESKeySize: 16,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := keyset.Read(buf, kek, keyset.WithPolicy(tc.policy))
+			if err != nil {
+				t.Fatalf("keyset.Read() err = %v, want nil", err)
+			}
+			if h.Len() != 3 {
+				t.Errorf("h.Len() = %d, want 3", h.Len())
+			}
+		})
+	}
+}
+
+func TestReadWithPolicyReportsAllViolations(t *testing.T) {
+	kek := newPolicyTestKEK(t)
+	buf := writePolicyTestKeyset(t, kek)
+
+	policy := &keyset.Policy{
+		FIPSOnly:               true,
+		DisallowLegacyPrefixes: true,
+		DisallowSHA1:           true,
+		MinAESKeySize:          32,
+	}
+	_, err := keyset.Read(buf, kek, keyset.WithPolicy(policy))
+	if err == nil {
+		t.Fatalf("keyset.Read() err = nil, want error")
+	}
+	for _, want := range []string{
+		// The AES-GCM key parser maps the LEGACY prefix to CRUNCHY.
+		"key 1: output prefix type CRUNCHY is not allowed",
+		"key 1: AES key size 16 is smaller than 32 bytes",
+		"key 2: SHA-1 is not allowed",
+		"key 3: key type " + testutil.AESGCMSIVTypeURL + " is not FIPS approved",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("keyset.Read() err = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestReadWithNoSecretsWithPolicyChecksRSAModulus(t *testing.T) {
+	privateHandle, err := keyset.NewHandle(signature.RSA_SSA_PKCS1_3072_SHA256_F4_Key_Template())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v, want nil", err)
+	}
+	buf := &keyset.MemReaderWriter{}
+	if err := publicHandle.WriteWithNoSecrets(buf); err != nil {
+		t.Fatalf("publicHandle.WriteWithNoSecrets() err = %v, want nil", err)
+	}
+
+	if _, err := keyset.ReadWithNoSecrets(buf, keyset.WithPolicy(&keyset.Policy{MinRSAModulusBits: 3072})); err != nil {
+		t.Errorf("keyset.ReadWithNoSecrets() with MinRSAModulusBits 3072 err = %v, want nil", err)
+	}
+	if _, err := keyset.ReadWithNoSecrets(buf, keyset.WithPolicy(&keyset.Policy{MinRSAModulusBits: 4096})); err == nil {
+		t.Errorf("keyset.ReadWithNoSecrets() with MinRSAModulusBits 4096 err = nil, want error")
+	}
+	if err := (&keyset.Policy{FIPSOnly: true}).Check(privateHandle); err != nil {
+		t.Errorf("Check() with FIPSOnly err = %v, want nil", err)
+	}
+}
+
+func TestKeyManagersDeclareFIPSCompatibility(t *testing.T) {
+	const prefix = "type.googleapis.com/google.crypto.tink."
+	fipsCompatible := map[string]bool{
+		prefix + "AesGcmKey":                true,
+		prefix + "AesCtrHmacAeadKey":        true,
+		prefix + "HmacKey":                  true,
+		prefix + "AesCmacKey":               true,
+		prefix + "HmacPrfKey":               true,
+		prefix + "AesCmacPrfKey":            true,
+		prefix + "AesKwpKey":                true,
+		prefix + "EcdsaPrivateKey":          true,
+		prefix + "EcdsaPublicKey":           true,
+		prefix + "RsaSsaPkcs1PrivateKey":    true,
+		prefix + "RsaSsaPkcs1PublicKey":     true,
+		prefix + "RsaSsaPssPrivateKey":      true,
+		prefix + "RsaSsaPssPublicKey":       true,
+		prefix + "JwtHmacKey":               true,
+		prefix + "JwtEcdsaPrivateKey":       true,
+		prefix + "JwtEcdsaPublicKey":        true,
+		prefix + "JwtRsaSsaPkcs1PrivateKey": true,
+		prefix + "JwtRsaSsaPkcs1PublicKey":  true,
+		prefix + "JwtRsaSsaPssPrivateKey":   true,
+		prefix + "JwtRsaSsaPssPublicKey":    true,
+	}
+	typeURLs := []string{
+		prefix + "AesGcmSivKey",
+		prefix + "AesSivKey",
+		prefix + "AesCtrHmacStreamingKey",
+		prefix + "AesGcmHkdfStreamingKey",
+		prefix + "ChaCha20Poly1305Key",
+		prefix + "XChaCha20Poly1305Key",
+		prefix + "XAesGcmKey",
+		prefix + "HkdfPrfKey",
+		prefix + "Ed25519PrivateKey",
+		prefix + "Ed25519PublicKey",
+		prefix + "EciesAeadHkdfPrivateKey",
+		prefix + "EciesAeadHkdfPublicKey",
+		prefix + "HpkePrivateKey",
+		prefix + "HpkePublicKey",
+		prefix + "KmsEnvelopeAeadKey",
+	}
+	for typeURL := range fipsCompatible {
+		typeURLs = append(typeURLs, typeURL)
+	}
+	for _, typeURL := range typeURLs {
+		km, err := registry.GetKeyManager(typeURL)
+		if err != nil {
+			t.Errorf("registry.GetKeyManager(%q) err = %v, want nil", typeURL, err)
+			continue
+		}
+		c, ok := km.(fips140.CompatibleKeyManager)
+		if got := ok && c.FIPSCompatible(); got != fipsCompatible[typeURL] {
+			t.Errorf("key manager for %q is FIPS compatible = %v, want %v", typeURL, got, fipsCompatible[typeURL])
+		}
+	}
+}
+
+func TestFIPSOnlyPolicyAllowsFIPSCompatibleKeyTypes(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{name: "AES-CMAC", template: mac.AESCMACTag128KeyTemplate()},
+		{name: "AES-KWP", template: kwp.AES256KWPKeyTemplate()},
+		{name: "JWT-HMAC", template: jwt.HS256Template()},
+		{name: "JWT-ECDSA", template: jwt.ES256Template()},
+		{name: "JWT-RSA-SSA-PKCS1", template: jwt.RS256_2048_F4_Key_Template()},
+		{name: "JWT-RSA-SSA-PSS", template: jwt.PS256_2048_F4_Key_Template()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+			}
+			if err := (&keyset.Policy{FIPSOnly: true}).Check(h); err != nil {
+				t.Errorf("Check() with FIPSOnly err = %v, want nil", err)
+			}
+		})
+	}
+}
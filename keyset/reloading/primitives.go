@@ -0,0 +1,168 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reloading
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// The wrappers below are kept up to date by the source until they are closed
+// or garbage collected. Since the source only references their underlying
+// primitives, dropping a wrapper lets a finalizer unsubscribe it.
+
+// unsubscriber implements Close for the wrappers.
+type unsubscriber struct {
+	once        sync.Once
+	unsubscribe func()
+}
+
+// Close stops updating the primitive with the keysets loaded later by its
+// source. The primitive remains usable with the keyset it had.
+func (u *unsubscriber) Close() error {
+	u.once.Do(u.unsubscribe)
+	return nil
+}
+
+type reloadingAEAD struct {
+	current *atomic.Pointer[tink.AEAD]
+	unsubscriber
+}
+
+var _ tink.AEAD = (*reloadingAEAD)(nil)
+var _ io.Closer = (*reloadingAEAD)(nil)
+
+// NewAEAD returns a [tink.AEAD] primitive that uses the latest keyset of s.
+//
+// The returned primitive also implements [io.Closer]; closing it stops
+// updating it.
+func NewAEAD(s *Source) (tink.AEAD, error) {
+	current, unsubscribe, err := subscribe(s, aead.New)
+	if err != nil {
+		return nil, fmt.Errorf("reloading.NewAEAD: %v", err)
+	}
+	a := &reloadingAEAD{current: current, unsubscriber: unsubscriber{unsubscribe: unsubscribe}}
+	runtime.SetFinalizer(a, (*reloadingAEAD).Close)
+	return a, nil
+}
+
+func (a *reloadingAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	return (*a.current.Load()).Encrypt(plaintext, associatedData)
+}
+
+func (a *reloadingAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	return (*a.current.Load()).Decrypt(ciphertext, associatedData)
+}
+
+type reloadingMAC struct {
+	current *atomic.Pointer[tink.MAC]
+	unsubscriber
+}
+
+var _ tink.MAC = (*reloadingMAC)(nil)
+var _ io.Closer = (*reloadingMAC)(nil)
+
+// NewMAC returns a [tink.MAC] primitive that uses the latest keyset of s.
+//
+// The returned primitive also implements [io.Closer]; closing it stops
+// updating it.
+func NewMAC(s *Source) (tink.MAC, error) {
+	current, unsubscribe, err := subscribe(s, mac.New)
+	if err != nil {
+		return nil, fmt.Errorf("reloading.NewMAC: %v", err)
+	}
+	m := &reloadingMAC{current: current, unsubscriber: unsubscriber{unsubscribe: unsubscribe}}
+	runtime.SetFinalizer(m, (*reloadingMAC).Close)
+	return m, nil
+}
+
+func (m *reloadingMAC) ComputeMAC(data []byte) ([]byte, error) {
+	return (*m.current.Load()).ComputeMAC(data)
+}
+
+func (m *reloadingMAC) VerifyMAC(mac, data []byte) error {
+	return (*m.current.Load()).VerifyMAC(mac, data)
+}
+
+type reloadingSigner struct {
+	current *atomic.Pointer[tink.Signer]
+	unsubscriber
+}
+
+var _ tink.Signer = (*reloadingSigner)(nil)
+var _ io.Closer = (*reloadingSigner)(nil)
+
+// NewSigner returns a [tink.Signer] primitive that uses the latest keyset of
+// s. The keyset must contain private keys.
+//
+// The returned primitive also implements [io.Closer]; closing it stops
+// updating it.
+func NewSigner(s *Source) (tink.Signer, error) {
+	current, unsubscribe, err := subscribe(s, signature.NewSigner)
+	if err != nil {
+		return nil, fmt.Errorf("reloading.NewSigner: %v", err)
+	}
+	signer := &reloadingSigner{current: current, unsubscriber: unsubscriber{unsubscribe: unsubscribe}}
+	runtime.SetFinalizer(signer, (*reloadingSigner).Close)
+	return signer, nil
+}
+
+func (s *reloadingSigner) Sign(data []byte) ([]byte, error) {
+	return (*s.current.Load()).Sign(data)
+}
+
+type reloadingVerifier struct {
+	current *atomic.Pointer[tink.Verifier]
+	unsubscriber
+}
+
+var _ tink.Verifier = (*reloadingVerifier)(nil)
+var _ io.Closer = (*reloadingVerifier)(nil)
+
+// NewVerifier returns a [tink.Verifier] primitive that uses the public keys of
+// the latest keyset of s. The keyset may contain private or public keys.
+//
+// The returned primitive also implements [io.Closer]; closing it stops
+// updating it.
+func NewVerifier(s *Source) (tink.Verifier, error) {
+	current, unsubscribe, err := subscribe(s, newVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("reloading.NewVerifier: %v", err)
+	}
+	v := &reloadingVerifier{current: current, unsubscriber: unsubscriber{unsubscribe: unsubscribe}}
+	runtime.SetFinalizer(v, (*reloadingVerifier).Close)
+	return v, nil
+}
+
+func (v *reloadingVerifier) Verify(signature, data []byte) error {
+	return (*v.current.Load()).Verify(signature, data)
+}
+
+// newVerifier creates a verifier from h, which may contain private keys.
+func newVerifier(h *keyset.Handle) (tink.Verifier, error) {
+	if publicHandle, err := h.Public(); err == nil {
+		h = publicHandle
+	}
+	return signature.NewVerifier(h)
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reloading provides keyset handles and primitives that follow an
+// encrypted keyset which is periodically re-read from a backend.
+//
+// A [Source] fetches the serialized encrypted keyset from a [Backend],
+// decrypts it with [keyset.ReadWithContext] and validates it. Primitives
+// created with [NewAEAD], [NewMAC], [NewSigner] and [NewVerifier] always use
+// the latest keyset of their source: when a new keyset is loaded, their
+// underlying primitive set is swapped atomically. Calls that are in flight
+// during a swap complete with the previous primitive set. The primitives also
+// implement [io.Closer]: closing one, or dropping all references to it, stops
+// updating it.
+//
+// Example:
+//
+//	source, err := reloading.NewSource(ctx, reloading.FileBackend(path), kekAEAD,
+//		reloading.WithRefreshInterval(time.Minute))
+//	if err != nil {
+//		return err
+//	}
+//	defer source.Close()
+//	a, err := reloading.NewAEAD(source)
+//	if err != nil {
+//		return err
+//	}
+//	// a uses the keyset stored in path, re-read every minute.
+package reloading
+
+import (
+	"context"
+	"os"
+)
+
+// Backend fetches the serialized encrypted keyset.
+//
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// BackendFunc is a [Backend] implemented by a callback.
+type BackendFunc func(ctx context.Context) ([]byte, error)
+
+// Fetch calls f(ctx).
+func (f BackendFunc) Fetch(ctx context.Context) ([]byte, error) { return f(ctx) }
+
+type fileBackend struct {
+	path string
+}
+
+// FileBackend returns a [Backend] that reads the encrypted keyset from the
+// file at path.
+//
+// The file is read in full on every fetch; changes are detected by
+// comparing its contents with the previously loaded keyset. Writers should
+// replace the file atomically, e.g. by renaming a temporary file over it, so
+// that a partially written keyset is never read.
+func FileBackend(path string) Backend {
+	return &fileBackend{path: path}
+}
+
+func (b *fileBackend) Fetch(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(b.path)
+}
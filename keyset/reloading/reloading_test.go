@@ -0,0 +1,507 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reloading_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/internal/internalregistry"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/keyset/reloading"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
+	"github.com/tink-crypto/tink-go/v2/testing/fakemonitoring"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const kekURI = "fake-kms://CM2b3_MDElQKSAowdHlwZS5nb29nbGVhcGlzLmNvbS9nb29nbGUuY3J5cHRvLnRpbmsuQWVzR2NtS2V5EhIaEIK75t5L-adlUwVhWvRuWUwYARABGM2b3_MDIAE"
+
+func mustKEK(t *testing.T) tink.AEADWithContext {
+	t.Helper()
+	kek, err := fakekms.NewAEADWithContext(kekURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewAEADWithContext() err = %v, want nil", err)
+	}
+	return kek
+}
+
+func mustEncryptKeyset(t *testing.T, h *keyset.Handle, kek tink.AEADWithContext) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := h.WriteWithContext(context.Background(), keyset.NewBinaryWriter(buf), kek, nil); err != nil {
+		t.Fatalf("h.WriteWithContext() err = %v, want nil", err)
+	}
+	return buf.Bytes()
+}
+
+// mustRotate returns a copy of h with a new primary key generated from
+// template.
+func mustRotate(t *testing.T, h *keyset.Handle, template *tinkpb.KeyTemplate) *keyset.Handle {
+	t.Helper()
+	km := keyset.NewManagerFromHandle(h)
+	keyID, err := km.Add(template)
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(keyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	rotated, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	return rotated
+}
+
+func mustNewHandle(t *testing.T, template *tinkpb.KeyTemplate) *keyset.Handle {
+	t.Helper()
+	h, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	return h
+}
+
+func primaryKeyID(t *testing.T, h *keyset.Handle) uint32 {
+	t.Helper()
+	entry, err := h.Primary()
+	if err != nil {
+		t.Fatalf("h.Primary() err = %v, want nil", err)
+	}
+	return entry.KeyID()
+}
+
+// memBackend is a Backend whose keyset can be replaced by tests.
+type memBackend struct {
+	mu         sync.Mutex
+	serialized []byte
+	err        error
+}
+
+func (b *memBackend) Fetch(ctx context.Context) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.serialized, b.err
+}
+
+func (b *memBackend) set(serialized []byte, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.serialized, b.err = serialized, err
+}
+
+func TestFileBackendReloadsAEAD(t *testing.T) {
+	ctx := context.Background()
+	kek := mustKEK(t)
+	path := filepath.Join(t.TempDir(), "keyset.bin")
+	h1 := mustNewHandle(t, aead.AES128GCMKeyTemplate())
+	if err := os.WriteFile(path, mustEncryptKeyset(t, h1, kek), 0600); err != nil {
+		t.Fatalf("os.WriteFile() err = %v, want nil", err)
+	}
+
+	source, err := reloading.NewSource(ctx, reloading.FileBackend(path), kek)
+	if err != nil {
+		t.Fatalf("reloading.NewSource() err = %v, want nil", err)
+	}
+	defer source.Close()
+	a, err := reloading.NewAEAD(source)
+	if err != nil {
+		t.Fatalf("reloading.NewAEAD() err = %v, want nil", err)
+	}
+	plaintext := []byte("plaintext")
+	associatedData := []byte("associatedData")
+	ciphertext1, err := a.Encrypt(plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+
+	h2 := mustRotate(t, h1, aead.AES256GCMKeyTemplate())
+	if err := os.WriteFile(path, mustEncryptKeyset(t, h2, kek), 0600); err != nil {
+		t.Fatalf("os.WriteFile() err = %v, want nil", err)
+	}
+	if err := source.Reload(ctx); err != nil {
+		t.Fatalf("source.Reload() err = %v, want nil", err)
+	}
+	if got, want := primaryKeyID(t, source.Handle()), primaryKeyID(t, h2); got != want {
+		t.Errorf("primary key ID after reload = %v, want %v", got, want)
+	}
+
+	ciphertext2, err := a.Encrypt(plaintext, associatedData)
+	if err != nil {
+		t.Fatalf("a.Encrypt() err = %v, want nil", err)
+	}
+	// ciphertext2 must be encrypted with the new primary key, which the
+	// old keyset doesn't contain.
+	oldAEAD, err := aead.New(h1)
+	if err != nil {
+		t.Fatalf("aead.New(h1) err = %v, want nil", err)
+	}
+	if _, err := oldAEAD.Decrypt(ciphertext2, associatedData); err == nil {
+		t.Errorf("oldAEAD.Decrypt(ciphertext2) err = nil, want error")
+	}
+	for _, ciphertext := range [][]byte{ciphertext1, ciphertext2} {
+		decrypted, err := a.Decrypt(ciphertext, associatedData)
+		if err != nil {
+			t.Fatalf("a.Decrypt() err = %v, want nil", err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Errorf("a.Decrypt() = %q, want %q", decrypted, plaintext)
+		}
+	}
+}
+
+func TestReloadKeepsPreviousKeysetOnFailure(t *testing.T) {
+	ctx := context.Background()
+	kek := mustKEK(t)
+	h := mustNewHandle(t, aead.AES128GCMKeyTemplate())
+	validKeyset := mustEncryptKeyset(t, h, kek)
+	macKeyset := mustEncryptKeyset(t, mustNewHandle(t, mac.HMACSHA256Tag256KeyTemplate()), kek)
+	otherKEK, err := fakekms.NewAEADWithContext("fake-kms://CM2x7NUGEmQKWAowdHlwZS5nb29nbGVhcGlzLmNvbS9nb29nbGUuY3J5cHRvLnRpbmsuQWVzR2NtS2V5EiIaIPh5Rhmj9p2IA1qo_DVw4UGuLl3dVADyewnBk1-wp7FvGAEQARjNsezVBiAB")
+	if err != nil {
+		t.Fatalf("fakekms.NewAEADWithContext() err = %v, want nil", err)
+	}
+	backend := &memBackend{serialized: validKeyset}
+
+	source, err := reloading.NewSource(ctx, backend, kek, reloading.WithValidator(func(h *keyset.Handle) error {
+		if h.Len() > 1 {
+			return errors.New("too many keys")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("reloading.NewSource() err = %v, want nil", err)
+	}
+	defer source.Close()
+	a, err := reloading.NewAEAD(source)
+	if err != nil {
+		t.Fatalf("reloading.NewAEAD() err = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		serialized []byte
+		fetchErr   error
+	}{
+		{
+			name:     "fetch error",
+			fetchErr: errors.New("unavailable"),
+		},
+		{
+			name:       "invalid keyset",
+			serialized: []byte("invalid"),
+		},
+		{
+			name:       "wrong KEK",
+			serialized: mustEncryptKeyset(t, h, otherKEK),
+		},
+		{
+			name:       "rejected by validator",
+			serialized: mustEncryptKeyset(t, mustRotate(t, h, aead.AES256GCMKeyTemplate()), kek),
+		},
+		{
+			name:       "wrong primitive",
+			serialized: macKeyset,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			backend.set(tc.serialized, tc.fetchErr)
+			if err := source.Reload(ctx); err == nil {
+				t.Errorf("source.Reload() err = nil, want error")
+			}
+			if got, want := primaryKeyID(t, source.Handle()), primaryKeyID(t, h); got != want {
+				t.Errorf("primary key ID = %v, want %v", got, want)
+			}
+			if _, err := a.Encrypt([]byte("plaintext"), nil); err != nil {
+				t.Errorf("a.Encrypt() err = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestRefreshInterval(t *testing.T) {
+	ctx := context.Background()
+	kek := mustKEK(t)
+	h1 := mustNewHandle(t, aead.AES128GCMKeyTemplate())
+	backend := &memBackend{serialized: mustEncryptKeyset(t, h1, kek)}
+	source, err := reloading.NewSource(ctx, backend, kek, reloading.WithRefreshInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("reloading.NewSource() err = %v, want nil", err)
+	}
+	defer source.Close()
+
+	h2 := mustRotate(t, h1, aead.AES128GCMKeyTemplate())
+	backend.set(mustEncryptKeyset(t, h2, kek), nil)
+	want := primaryKeyID(t, h2)
+	deadline := time.Now().Add(10 * time.Second)
+	for primaryKeyID(t, source.Handle()) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("keyset was not reloaded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := source.Close(); err != nil {
+		t.Errorf("source.Close() err = %v, want nil", err)
+	}
+	// Close is idempotent.
+	if err := source.Close(); err != nil {
+		t.Errorf("source.Close() err = %v, want nil", err)
+	}
+}
+
+func TestMACSignerVerifier(t *testing.T) {
+	ctx := context.Background()
+	kek := mustKEK(t)
+
+	macHandle := mustNewHandle(t, mac.HMACSHA256Tag256KeyTemplate())
+	macSource, err := reloading.NewSource(ctx, &memBackend{serialized: mustEncryptKeyset(t, macHandle, kek)}, kek)
+	if err != nil {
+		t.Fatalf("reloading.NewSource() err = %v, want nil", err)
+	}
+	m, err := reloading.NewMAC(macSource)
+	if err != nil {
+		t.Fatalf("reloading.NewMAC() err = %v, want nil", err)
+	}
+	data := []byte("data")
+	tag, err := m.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("m.ComputeMAC() err = %v, want nil", err)
+	}
+	if err := m.VerifyMAC(tag, data); err != nil {
+		t.Errorf("m.VerifyMAC() err = %v, want nil", err)
+	}
+
+	signatureHandle := mustNewHandle(t, signature.ECDSAP256KeyTemplate())
+	signatureSource, err := reloading.NewSource(ctx, &memBackend{serialized: mustEncryptKeyset(t, signatureHandle, kek)}, kek)
+	if err != nil {
+		t.Fatalf("reloading.NewSource() err = %v, want nil", err)
+	}
+	signer, err := reloading.NewSigner(signatureSource)
+	if err != nil {
+		t.Fatalf("reloading.NewSigner() err = %v, want nil", err)
+	}
+	verifier, err := reloading.NewVerifier(signatureSource)
+	if err != nil {
+		t.Fatalf("reloading.NewVerifier() err = %v, want nil", err)
+	}
+	sig, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v, want nil", err)
+	}
+	if err := verifier.Verify(sig, data); err != nil {
+		t.Errorf("verifier.Verify() err = %v, want nil", err)
+	}
+	if _, err := reloading.NewAEAD(signatureSource); err == nil {
+		t.Errorf("reloading.NewAEAD(signatureSource) err = nil, want error")
+	}
+}
+
+// newPublicKeysetSource returns a source of a private signature keyset and a
+// function that makes its backend serve only the public keys, for which no
+// signer can be created.
+func newPublicKeysetSource(t *testing.T) (*reloading.Source, func()) {
+	t.Helper()
+	kek := mustKEK(t)
+	privateHandle := mustNewHandle(t, signature.ECDSAP256KeyTemplate())
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v, want nil", err)
+	}
+	backend := &memBackend{serialized: mustEncryptKeyset(t, privateHandle, kek)}
+	source, err := reloading.NewSource(context.Background(), backend, kek)
+	if err != nil {
+		t.Fatalf("reloading.NewSource() err = %v, want nil", err)
+	}
+	return source, func() { backend.set(mustEncryptKeyset(t, publicHandle, kek), nil) }
+}
+
+func TestClosedPrimitiveIsNotUpdated(t *testing.T) {
+	ctx := context.Background()
+	source, servePublicKeys := newPublicKeysetSource(t)
+	signer, err := reloading.NewSigner(source)
+	if err != nil {
+		t.Fatalf("reloading.NewSigner() err = %v, want nil", err)
+	}
+	servePublicKeys()
+	if err := source.Reload(ctx); err == nil {
+		t.Fatalf("source.Reload() with a subscribed signer err = nil, want error")
+	}
+	closer, ok := signer.(io.Closer)
+	if !ok {
+		t.Fatalf("signer doesn't implement io.Closer")
+	}
+	if err := closer.Close(); err != nil {
+		t.Fatalf("signer.Close() err = %v, want nil", err)
+	}
+	if err := source.Reload(ctx); err != nil {
+		t.Fatalf("source.Reload() with a closed signer err = %v, want nil", err)
+	}
+	// The closed signer keeps using the keyset it had.
+	if _, err := signer.Sign([]byte("data")); err != nil {
+		t.Errorf("signer.Sign() err = %v, want nil", err)
+	}
+	// Close is idempotent.
+	if err := closer.Close(); err != nil {
+		t.Errorf("signer.Close() err = %v, want nil", err)
+	}
+}
+
+func TestDroppedPrimitiveIsNotUpdated(t *testing.T) {
+	ctx := context.Background()
+	source, servePublicKeys := newPublicKeysetSource(t)
+	if _, err := reloading.NewSigner(source); err != nil {
+		t.Fatalf("reloading.NewSigner() err = %v, want nil", err)
+	}
+	servePublicKeys()
+	// The dropped signer is unsubscribed by a finalizer once it is garbage
+	// collected, after which the public keyset can be loaded.
+	deadline := time.Now().Add(10 * time.Second)
+	for source.Reload(ctx) != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped signer is still updated by the source")
+		}
+		runtime.GC()
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReloadIsMonitored(t *testing.T) {
+	defer internalregistry.ClearMonitoringClient()
+	client := fakemonitoring.NewClient("fake-client")
+	if err := internalregistry.RegisterMonitoringClient(client); err != nil {
+		t.Fatalf("internalregistry.RegisterMonitoringClient() err = %v, want nil", err)
+	}
+	ctx := context.Background()
+	kek := mustKEK(t)
+	h1 := mustNewHandle(t, aead.AES128GCMKeyTemplate())
+	backend := &memBackend{serialized: mustEncryptKeyset(t, h1, kek)}
+	annotations := map[string]string{"foo": "bar"}
+	source, err := reloading.NewSource(ctx, backend, kek, reloading.WithAnnotations(annotations))
+	if err != nil {
+		t.Fatalf("reloading.NewSource() err = %v, want nil", err)
+	}
+
+	// Reloading an unchanged keyset is not logged.
+	if err := source.Reload(ctx); err != nil {
+		t.Fatalf("source.Reload() err = %v, want nil", err)
+	}
+	if got := len(client.Events()); got != 0 {
+		t.Errorf("len(client.Events()) = %v, want 0", got)
+	}
+
+	h2 := mustRotate(t, h1, aead.AES128GCMKeyTemplate())
+	serialized := mustEncryptKeyset(t, h2, kek)
+	backend.set(serialized, nil)
+	if err := source.Reload(ctx); err != nil {
+		t.Fatalf("source.Reload() err = %v, want nil", err)
+	}
+	events := client.Events()
+	if len(events) != 1 {
+		t.Fatalf("len(client.Events()) = %v, want 1", len(events))
+	}
+	if got, want := events[0].KeyID, primaryKeyID(t, h2); got != want {
+		t.Errorf("events[0].KeyID = %v, want %v", got, want)
+	}
+	if got, want := events[0].NumBytes, len(serialized); got != want {
+		t.Errorf("events[0].NumBytes = %v, want %v", got, want)
+	}
+	if got, want := events[0].Context.Primitive, "keyset_source"; got != want {
+		t.Errorf("events[0].Context.Primitive = %q, want %q", got, want)
+	}
+	if got, want := events[0].Context.APIFunction, "reload"; got != want {
+		t.Errorf("events[0].Context.APIFunction = %q, want %q", got, want)
+	}
+	if got, want := events[0].Context.KeysetInfo.Annotations, annotations; got["foo"] != want["foo"] {
+		t.Errorf("events[0].Context.KeysetInfo.Annotations = %v, want %v", got, want)
+	}
+
+	backend.set([]byte("invalid"), nil)
+	if err := source.Reload(ctx); err == nil {
+		t.Fatalf("source.Reload() err = nil, want error")
+	}
+	if got := len(client.Failures()); got != 1 {
+		t.Errorf("len(client.Failures()) = %v, want 1", got)
+	}
+}
+
+func TestNewSourceFails(t *testing.T) {
+	ctx := context.Background()
+	kek := mustKEK(t)
+	validBackend := &memBackend{serialized: mustEncryptKeyset(t, mustNewHandle(t, aead.AES128GCMKeyTemplate()), kek)}
+	for _, tc := range []struct {
+		name    string
+		backend reloading.Backend
+		kek     tink.AEADWithContext
+		opts    []reloading.Option
+	}{
+		{
+			name: "nil backend",
+			kek:  kek,
+		},
+		{
+			name:    "nil KEK",
+			backend: validBackend,
+		},
+		{
+			name:    "missing file",
+			backend: reloading.FileBackend(filepath.Join(t.TempDir(), "missing")),
+			kek:     kek,
+		},
+		{
+			name: "callback error",
+			backend: reloading.BackendFunc(func(ctx context.Context) ([]byte, error) {
+				return nil, errors.New("unavailable")
+			}),
+			kek: kek,
+		},
+		{
+			name:    "JSON format for binary keyset",
+			backend: validBackend,
+			kek:     kek,
+			opts:    []reloading.Option{reloading.WithJSONFormat()},
+		},
+		{
+			name:    "invalid refresh interval",
+			backend: validBackend,
+			kek:     kek,
+			opts:    []reloading.Option{reloading.WithRefreshInterval(0)},
+		},
+		{
+			name:    "rejected by validator",
+			backend: validBackend,
+			kek:     kek,
+			opts: []reloading.Option{reloading.WithValidator(func(*keyset.Handle) error {
+				return errors.New("rejected")
+			})},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := reloading.NewSource(ctx, tc.backend, tc.kek, tc.opts...); err == nil {
+				t.Errorf("reloading.NewSource() err = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reloading
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tink-crypto/tink-go/v2/internal/internalregistry"
+	"github.com/tink-crypto/tink-go/v2/internal/monitoringutil"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/monitoring"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// Option configures a [Source].
+type Option func(*sourceOptions) error
+
+type sourceOptions struct {
+	associatedData  []byte
+	newReader       func(io.Reader) keyset.Reader
+	validate        func(*keyset.Handle) error
+	annotations     map[string]string
+	refreshInterval time.Duration
+}
+
+// WithAssociatedData sets the associated data used to decrypt the keyset.
+func WithAssociatedData(associatedData []byte) Option {
+	return func(o *sourceOptions) error {
+		o.associatedData = bytes.Clone(associatedData)
+		return nil
+	}
+}
+
+// WithJSONFormat makes the source parse the keyset with
+// [keyset.NewJSONReader]. By default, the binary format is used.
+func WithJSONFormat() Option {
+	return func(o *sourceOptions) error {
+		o.newReader = func(r io.Reader) keyset.Reader { return keyset.NewJSONReader(r) }
+		return nil
+	}
+}
+
+// WithValidator sets a function that is called on every newly read keyset
+// handle. A keyset for which validate returns an error is not used.
+func WithValidator(validate func(*keyset.Handle) error) Option {
+	return func(o *sourceOptions) error {
+		if validate == nil {
+			return errors.New("validator is nil")
+		}
+		o.validate = validate
+		return nil
+	}
+}
+
+// WithAnnotations adds monitoring annotations to the keyset handles of the
+// source.
+//
+// As for [keyset.WithAnnotations], the annotations enable monitoring of the
+// primitives created from the source. In addition, every reload that fetches
+// a changed keyset, as well as every failed reload, is logged through the
+// registered monitoring client with primitive "keyset_source" and API
+// function "reload".
+func WithAnnotations(annotations map[string]string) Option {
+	return func(o *sourceOptions) error {
+		if o.annotations != nil {
+			return errors.New("annotations are already set")
+		}
+		o.annotations = annotations
+		return nil
+	}
+}
+
+// WithRefreshInterval makes the source reload the keyset every interval in
+// the background, until [Source.Close] is called.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(o *sourceOptions) error {
+		if interval <= 0 {
+			return fmt.Errorf("refresh interval must be positive, got %v", interval)
+		}
+		o.refreshInterval = interval
+		return nil
+	}
+}
+
+// subscriber creates the primitive of a wrapper for a new handle. It returns
+// a function that makes the wrapper use the new primitive.
+type subscriber func(h *keyset.Handle) (commit func(), err error)
+
+// Source provides the latest version of an encrypted keyset read from a
+// [Backend].
+//
+// Source is safe for concurrent use.
+type Source struct {
+	backend Backend
+	kek     tink.AEADWithContext
+	opts    sourceOptions
+
+	handle atomic.Pointer[keyset.Handle]
+
+	// mu serializes reloads and the registration of subscribers.
+	mu               sync.Mutex
+	serialized       []byte
+	subscribers      map[uint64]subscriber
+	nextSubscriberID uint64
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSource creates a new [Source] and loads the keyset from backend. The
+// keyset is decrypted with kek.
+//
+// If [WithRefreshInterval] is given, the keyset is reloaded in the background
+// and [Source.Close] must be called to stop reloading.
+func NewSource(ctx context.Context, backend Backend, kek tink.AEADWithContext, opts ...Option) (*Source, error) {
+	if backend == nil {
+		return nil, errors.New("reloading.NewSource: backend is nil")
+	}
+	if kek == nil {
+		return nil, errors.New("reloading.NewSource: kek is nil")
+	}
+	s := &Source{
+		backend: backend,
+		kek:     kek,
+		opts: sourceOptions{
+			newReader: func(r io.Reader) keyset.Reader { return keyset.NewBinaryReader(r) },
+		},
+		subscribers: make(map[uint64]subscriber),
+	}
+	for _, opt := range opts {
+		if err := opt(&s.opts); err != nil {
+			return nil, fmt.Errorf("reloading.NewSource: %v", err)
+		}
+	}
+	serialized, err := backend.Fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reloading.NewSource: %v", err)
+	}
+	h, err := s.read(ctx, serialized)
+	if err != nil {
+		return nil, fmt.Errorf("reloading.NewSource: %v", err)
+	}
+	s.handle.Store(h)
+	s.serialized = serialized
+	if s.opts.refreshInterval > 0 {
+		refreshCtx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+		s.done = make(chan struct{})
+		go s.refresh(refreshCtx)
+	}
+	return s, nil
+}
+
+// Handle returns the handle of the latest successfully loaded keyset.
+func (s *Source) Handle() *keyset.Handle { return s.handle.Load() }
+
+// Reload fetches the keyset from the backend and, if it changed, makes the
+// source and all primitives created from it use the new keyset.
+//
+// The new keyset is only used if it can be decrypted, passes validation and
+// primitives can be created from it for every wrapper of the source;
+// otherwise the previous keyset stays in use and an error is returned.
+func (s *Source) Reload(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	serialized, err := s.backend.Fetch(ctx)
+	if err != nil {
+		s.logFailure()
+		return fmt.Errorf("reloading: %v", err)
+	}
+	if bytes.Equal(serialized, s.serialized) {
+		return nil
+	}
+	h, err := s.read(ctx, serialized)
+	if err != nil {
+		s.logFailure()
+		return fmt.Errorf("reloading: %v", err)
+	}
+	commits := make([]func(), 0, len(s.subscribers))
+	for _, sub := range s.subscribers {
+		commit, err := sub(h)
+		if err != nil {
+			s.logFailure()
+			return fmt.Errorf("reloading: %v", err)
+		}
+		commits = append(commits, commit)
+	}
+	s.handle.Store(h)
+	for _, commit := range commits {
+		commit()
+	}
+	s.serialized = serialized
+	s.logSuccess(h, len(serialized))
+	return nil
+}
+
+// Close stops reloading the keyset in the background. Primitives created from
+// the source remain usable with the latest keyset.
+func (s *Source) Close() error {
+	s.closeOnce.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+			<-s.done
+		}
+	})
+	return nil
+}
+
+func (s *Source) refresh(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.opts.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Failures are reported through monitoring; the previous keyset
+			// stays in use.
+			s.Reload(ctx)
+		}
+	}
+}
+
+func (s *Source) read(ctx context.Context, serialized []byte) (*keyset.Handle, error) {
+	var opts []keyset.Option
+	if s.opts.annotations != nil {
+		opts = append(opts, keyset.WithAnnotations(s.opts.annotations))
+	}
+	h, err := keyset.ReadWithContext(ctx, s.opts.newReader(bytes.NewReader(serialized)), s.kek, s.opts.associatedData, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.Primary(); err != nil {
+		return nil, err
+	}
+	if s.opts.validate != nil {
+		if err := s.opts.validate(h); err != nil {
+			return nil, fmt.Errorf("keyset validation failed: %v", err)
+		}
+	}
+	return h, nil
+}
+
+// subscribe creates a primitive from the current handle with newPrimitive
+// and keeps it up to date with the handles loaded later, until unsubscribe is
+// called.
+func subscribe[T any](s *Source, newPrimitive func(*keyset.Handle) (T, error)) (current *atomic.Pointer[T], unsubscribe func(), err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := newPrimitive(s.handle.Load())
+	if err != nil {
+		return nil, nil, err
+	}
+	current = new(atomic.Pointer[T])
+	current.Store(&p)
+	id := s.nextSubscriberID
+	s.nextSubscriberID++
+	s.subscribers[id] = func(h *keyset.Handle) (func(), error) {
+		p, err := newPrimitive(h)
+		if err != nil {
+			return nil, err
+		}
+		return func() { current.Store(&p) }, nil
+	}
+	unsubscribe = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subscribers, id)
+	}
+	return current, unsubscribe, nil
+}
+
+func (s *Source) logger(h *keyset.Handle) monitoring.Logger {
+	if len(s.opts.annotations) == 0 {
+		return &monitoringutil.DoNothingLogger{}
+	}
+	keysetInfo, err := monitoringutil.KeysetInfoFromProto(s.opts.annotations, h.KeysetInfo())
+	if err != nil {
+		return &monitoringutil.DoNothingLogger{}
+	}
+	logger, err := internalregistry.GetMonitoringClient().NewLogger(&monitoring.Context{
+		Primitive:   "keyset_source",
+		APIFunction: "reload",
+		KeysetInfo:  keysetInfo,
+	})
+	if err != nil {
+		return &monitoringutil.DoNothingLogger{}
+	}
+	return logger
+}
+
+func (s *Source) logSuccess(h *keyset.Handle, numBytes int) {
+	s.logger(h).Log(h.KeysetInfo().GetPrimaryKeyId(), numBytes)
+}
+
+func (s *Source) logFailure() {
+	s.logger(s.handle.Load()).LogFailure()
+}
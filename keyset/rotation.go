@@ -0,0 +1,239 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// RotationPolicy describes when keys of a keyset are created, promoted to
+// primary, disabled and deleted. It is applied with [Manager.ApplyPolicy].
+//
+// All ages are measured from the creation time of a key. A zero RotateAfter,
+// DisableAfter or DeleteAfter disables the corresponding transition, while a
+// zero PromoteAfter makes new keys primary as soon as they are added. For
+// example, the policy
+//
+//	&keyset.RotationPolicy{
+//		KeyTemplate:  aead.AES256GCMKeyTemplate(),
+//		RotateAfter:  80 * 24 * time.Hour,
+//		PromoteAfter: 7 * 24 * time.Hour,
+//		DisableAfter: 180 * 24 * time.Hour,
+//		DeleteAfter:  365 * 24 * time.Hour,
+//	}
+//
+// adds a new key once the newest key is 80 days old, makes it primary after
+// 7 days, which gives readers of the keyset time to pick it up, and disables
+// and deletes non-primary keys that are 180 and 365 days old respectively.
+type RotationPolicy struct {
+	// KeyTemplate is the template of new keys. Exactly one of KeyTemplate
+	// and Parameters must be set if RotateAfter is non-zero.
+	KeyTemplate *tinkpb.KeyTemplate
+	// Parameters are the parameters of new keys.
+	Parameters key.Parameters
+
+	// RotateAfter is the age of the newest key after which a new key is
+	// added. If the keyset is empty and a key template or parameters are
+	// set, a key is added regardless.
+	RotateAfter time.Duration
+	// PromoteAfter is the age after which a key that is newer than the
+	// primary is made primary. If the keyset has no primary, the newest
+	// enabled key is made primary regardless. If it is zero, new keys are
+	// promoted immediately, without giving readers time to pick them up.
+	PromoteAfter time.Duration
+	// DisableAfter is the age after which enabled non-primary keys are
+	// disabled.
+	DisableAfter time.Duration
+	// DeleteAfter is the age after which non-primary keys are deleted.
+	DeleteAfter time.Duration
+
+	// TimestampUnknownKeys makes [Manager.ApplyPolicy] set the creation time
+	// of keys without one, such as the keys of a keyset written before
+	// creation times were recorded, to the time the policy is applied. By
+	// default, ApplyPolicy fails for such keys instead, since a keyset read
+	// without its metadata would otherwise never age.
+	TimestampUnknownKeys bool
+}
+
+func (p *RotationPolicy) validate() error {
+	if p == nil {
+		return errors.New("policy is nil")
+	}
+	if p.RotateAfter < 0 || p.PromoteAfter < 0 || p.DisableAfter < 0 || p.DeleteAfter < 0 {
+		return errors.New("durations must not be negative")
+	}
+	if p.RotateAfter > 0 && (p.KeyTemplate == nil) == (p.Parameters == nil) {
+		return errors.New("exactly one of KeyTemplate and Parameters must be set")
+	}
+	if p.RotateAfter > 0 && p.PromoteAfter >= p.RotateAfter {
+		return errors.New("PromoteAfter must be shorter than RotateAfter")
+	}
+	return nil
+}
+
+func (p *RotationPolicy) keyTemplate() (*tinkpb.KeyTemplate, error) {
+	if p.KeyTemplate != nil {
+		return p.KeyTemplate, nil
+	}
+	return protoserialization.SerializeParameters(p.Parameters)
+}
+
+// RotationReport lists the transitions performed by [Manager.ApplyPolicy].
+type RotationReport struct {
+	// Added are the IDs of newly created keys.
+	Added []uint32
+	// Promoted are the IDs of keys that were made primary.
+	Promoted []uint32
+	// Disabled are the IDs of keys that were disabled.
+	Disabled []uint32
+	// Deleted are the IDs of keys that were deleted.
+	Deleted []uint32
+	// Timestamped are the IDs of keys without a creation time, which was set
+	// to the time the policy was applied because TimestampUnknownKeys is set.
+	// These keys are not otherwise transitioned in the same call.
+	Timestamped []uint32
+}
+
+// ApplyPolicy performs the transitions of policy that are due at now and
+// reports them.
+//
+// The transitions are applied in a fixed order: keys with unknown creation
+// time are timestamped if the policy allows it, a new key is added, the
+// newest pending key is promoted, and finally old non-primary keys are
+// disabled and deleted. The primary key is never disabled or deleted. Applying
+// the same policy twice at the same time is a no-op the second time.
+//
+// Creation times are kept in the metadata of the keyset, see
+// [Handle.Metadata]. To apply a policy to a stored keyset, store the metadata
+// along with it and read it back with [WithMetadata]. ApplyPolicy fails if a
+// key has no creation time, unless policy.TimestampUnknownKeys is set.
+//
+// If an error is returned, the keyset may have been partially updated.
+func (km *Manager) ApplyPolicy(now time.Time, policy *RotationPolicy) (*RotationReport, error) {
+	if km.ks == nil {
+		return nil, errors.New("keyset.Manager: cannot apply policy to nil keyset")
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("keyset.Manager: invalid rotation policy: %v", err)
+	}
+	report := &RotationReport{}
+	nowSeconds := now.Unix()
+	age := func(k *tinkpb.Keyset_Key) time.Duration {
+		return time.Duration(nowSeconds-km.creationTime(k)) * time.Second
+	}
+
+	var unknown []uint32
+	for _, k := range km.ks.Key {
+		if km.creationTime(k) == 0 {
+			unknown = append(unknown, k.GetKeyId())
+		}
+	}
+	if len(unknown) > 0 && !policy.TimestampUnknownKeys {
+		return nil, fmt.Errorf("keyset.Manager: keys %v have no creation time; read the keyset with its metadata or set TimestampUnknownKeys", unknown)
+	}
+	for _, keyID := range unknown {
+		md := km.metadata[keyID]
+		md.creationTime = nowSeconds
+		km.metadata[keyID] = md
+		report.Timestamped = append(report.Timestamped, keyID)
+	}
+
+	newest := km.newestEnabledKey()
+	bootstrap := len(km.ks.Key) == 0 && (policy.KeyTemplate != nil || policy.Parameters != nil)
+	if bootstrap || (policy.RotateAfter > 0 && newest != nil && age(newest) >= policy.RotateAfter) {
+		kt, err := policy.keyTemplate()
+		if err != nil {
+			return nil, fmt.Errorf("keyset.Manager: %v", err)
+		}
+		keyID, err := km.addAt(kt, now)
+		if err != nil {
+			return nil, err
+		}
+		report.Added = append(report.Added, keyID)
+	}
+
+	primary := km.primaryKey()
+	newest = km.newestEnabledKey()
+	if newest != nil && newest != primary &&
+		(primary == nil || (km.creationTime(newest) > km.creationTime(primary) && age(newest) >= policy.PromoteAfter)) {
+		if err := km.setPrimaryAt(newest.GetKeyId(), now); err != nil {
+			return nil, err
+		}
+		report.Promoted = append(report.Promoted, newest.GetKeyId())
+	}
+
+	var toDelete []uint32
+	for _, k := range km.ks.Key {
+		if k.GetKeyId() == km.ks.GetPrimaryKeyId() || slices.Contains(report.Timestamped, k.GetKeyId()) {
+			continue
+		}
+		if policy.DeleteAfter > 0 && age(k) >= policy.DeleteAfter {
+			toDelete = append(toDelete, k.GetKeyId())
+			continue
+		}
+		if policy.DisableAfter > 0 && age(k) >= policy.DisableAfter && k.GetStatus() == tinkpb.KeyStatusType_ENABLED {
+			if err := km.Disable(k.GetKeyId()); err != nil {
+				return nil, err
+			}
+			report.Disabled = append(report.Disabled, k.GetKeyId())
+		}
+	}
+	for _, keyID := range toDelete {
+		if err := km.Delete(keyID); err != nil {
+			return nil, err
+		}
+		report.Deleted = append(report.Deleted, keyID)
+	}
+	return report, nil
+}
+
+// primaryKey returns the primary key of the managed keyset, or nil.
+func (km *Manager) primaryKey() *tinkpb.Keyset_Key {
+	for _, k := range km.ks.Key {
+		if k.GetKeyId() == km.ks.GetPrimaryKeyId() {
+			return k
+		}
+	}
+	return nil
+}
+
+// newestEnabledKey returns the enabled key with the latest creation time. If
+// several keys have the same creation time, the last one in the keyset is
+// returned.
+func (km *Manager) newestEnabledKey() *tinkpb.Keyset_Key {
+	var newest *tinkpb.Keyset_Key
+	for _, k := range km.ks.Key {
+		if k.GetStatus() != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+		if newest == nil || km.creationTime(k) >= km.creationTime(newest) {
+			newest = k
+		}
+	}
+	return newest
+}
+
+// creationTime returns the creation time of k in seconds since the Unix
+// epoch, or zero if it is unknown.
+func (km *Manager) creationTime(k *tinkpb.Keyset_Key) int64 {
+	return km.metadata[k.GetKeyId()].creationTime
+}
@@ -0,0 +1,330 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/tink-crypto/tink-go/v2/insecurecleartextkeyset"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+)
+
+const day = 24 * time.Hour
+
+func testRotationPolicy() *keyset.RotationPolicy {
+	return &keyset.RotationPolicy{
+		KeyTemplate:  mac.HMACSHA256Tag128KeyTemplate(),
+		RotateAfter:  80 * day,
+		PromoteAfter: 7 * day,
+		DisableAfter: 180 * day,
+		DeleteAfter:  365 * day,
+	}
+}
+
+func mustApplyPolicy(t *testing.T, km *keyset.Manager, now time.Time, policy *keyset.RotationPolicy) *keyset.RotationReport {
+	t.Helper()
+	report, err := km.ApplyPolicy(now, policy)
+	if err != nil {
+		t.Fatalf("km.ApplyPolicy(%v) err = %v, want nil", now, err)
+	}
+	return report
+}
+
+func keyStatuses(t *testing.T, km *keyset.Manager) map[uint32]keyset.KeyStatus {
+	t.Helper()
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	statuses := make(map[uint32]keyset.KeyStatus)
+	for i := 0; i < h.Len(); i++ {
+		entry, err := h.Entry(i)
+		if err != nil {
+			t.Fatalf("h.Entry(%d) err = %v, want nil", i, err)
+		}
+		statuses[entry.KeyID()] = entry.KeyStatus()
+	}
+	return statuses
+}
+
+func TestAddAndSetPrimaryRecordTimestamps(t *testing.T) {
+	before := time.Now().Truncate(time.Second)
+	km := keyset.NewManager()
+	keyID, err := km.Add(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(keyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	after := time.Now()
+
+	// The timestamps are kept in the metadata, not in the keyset.
+	ks := insecurecleartextkeyset.KeysetMaterial(h)
+	h, err = insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks}, keyset.WithMetadata(h.Metadata()))
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() err = %v, want nil", err)
+	}
+	entry, err := h.Primary()
+	if err != nil {
+		t.Fatalf("h.Primary() err = %v, want nil", err)
+	}
+	for name, got := range map[string]time.Time{
+		"CreationTime":   entry.CreationTime(),
+		"ActivationTime": entry.ActivationTime(),
+	} {
+		if got.Before(before) || got.After(after) {
+			t.Errorf("entry.%s() = %v, want between %v and %v", name, got, before, after)
+		}
+	}
+}
+
+func TestApplyPolicyLifecycle(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	policy := testRotationPolicy()
+	km := keyset.NewManager()
+
+	report := mustApplyPolicy(t, km, start, policy)
+	if len(report.Added) != 1 || !slices.Equal(report.Promoted, report.Added) {
+		t.Fatalf("report = %+v, want one added and promoted key", report)
+	}
+	first := report.Added[0]
+
+	// Nothing is due before the rotation period has elapsed.
+	report = mustApplyPolicy(t, km, start.Add(79*day), policy)
+	if diff := cmp.Diff(&keyset.RotationReport{}, report, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("report at day 79 diff (-want +got):\n%s", diff)
+	}
+
+	report = mustApplyPolicy(t, km, start.Add(80*day), policy)
+	if len(report.Added) != 1 || len(report.Promoted) != 0 {
+		t.Fatalf("report at day 80 = %+v, want one added key", report)
+	}
+	second := report.Added[0]
+
+	report = mustApplyPolicy(t, km, start.Add(86*day), policy)
+	if len(report.Promoted) != 0 {
+		t.Errorf("report at day 86 = %+v, want no promotion", report)
+	}
+	report = mustApplyPolicy(t, km, start.Add(87*day), policy)
+	if diff := cmp.Diff(&keyset.RotationReport{Promoted: []uint32{second}}, report, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("report at day 87 diff (-want +got):\n%s", diff)
+	}
+
+	// The second key is rotated at day 160 and promoted at day 167.
+	report = mustApplyPolicy(t, km, start.Add(160*day), policy)
+	if len(report.Added) != 1 {
+		t.Fatalf("report at day 160 = %+v, want one added key", report)
+	}
+	third := report.Added[0]
+	mustApplyPolicy(t, km, start.Add(167*day), policy)
+
+	report = mustApplyPolicy(t, km, start.Add(180*day), policy)
+	if diff := cmp.Diff(&keyset.RotationReport{Disabled: []uint32{first}}, report, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("report at day 180 diff (-want +got):\n%s", diff)
+	}
+	want := map[uint32]keyset.KeyStatus{first: keyset.Disabled, second: keyset.Enabled, third: keyset.Enabled}
+	if diff := cmp.Diff(want, keyStatuses(t, km)); diff != "" {
+		t.Errorf("key statuses at day 180 diff (-want +got):\n%s", diff)
+	}
+
+	// Keep applying the policy daily until the first key is deleted.
+	var deleted []uint32
+	for d := 181; d <= 365; d++ {
+		report = mustApplyPolicy(t, km, start.Add(time.Duration(d)*day), policy)
+		deleted = append(deleted, report.Deleted...)
+		if slices.Contains(report.Deleted, first) && d != 365 {
+			t.Errorf("first key deleted at day %d, want day 365", d)
+		}
+	}
+	if !slices.Contains(deleted, first) {
+		t.Errorf("first key not deleted by day 365")
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	primary, err := h.Primary()
+	if err != nil {
+		t.Fatalf("h.Primary() err = %v, want nil", err)
+	}
+	// Keys are added every 80 days and promoted 7 days later, so the primary
+	// at day 365 is the key added at day 320.
+	if got, want := primary.CreationTime(), start.Add(320*day); !got.Equal(want) {
+		t.Errorf("primary.CreationTime() = %v, want %v", got, want)
+	}
+	if got, want := primary.ActivationTime(), start.Add(327*day); !got.Equal(want) {
+		t.Errorf("primary.ActivationTime() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyPolicyIsIdempotent(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	policy := testRotationPolicy()
+	km := keyset.NewManager()
+	mustApplyPolicy(t, km, start, policy)
+	mustApplyPolicy(t, km, start.Add(80*day), policy)
+	report := mustApplyPolicy(t, km, start.Add(80*day), policy)
+	if diff := cmp.Diff(&keyset.RotationReport{}, report, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("second report diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyPolicyWithZeroPromoteAfterPromotesImmediately(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	policy := testRotationPolicy()
+	policy.PromoteAfter = 0
+	km := keyset.NewManager()
+	mustApplyPolicy(t, km, start, policy)
+	report := mustApplyPolicy(t, km, start.Add(80*day), policy)
+	if len(report.Added) != 1 || !slices.Equal(report.Promoted, report.Added) {
+		t.Errorf("report at day 80 = %+v, want one added and promoted key", report)
+	}
+}
+
+func TestApplyPolicyTimestampsLegacyKeys(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	km := keyset.NewManager()
+	keyID, err := km.Add(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(keyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	// Read the keyset without its metadata, like a keyset written before
+	// timestamps were recorded.
+	ks := insecurecleartextkeyset.KeysetMaterial(h)
+	h, err = insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() err = %v, want nil", err)
+	}
+	km = keyset.NewManagerFromHandle(h)
+
+	// Keys without creation time are only timestamped if the policy allows it.
+	if _, err := km.ApplyPolicy(now, testRotationPolicy()); err == nil {
+		t.Fatalf("km.ApplyPolicy() with keys of unknown age err = nil, want error")
+	}
+	policy := testRotationPolicy()
+	policy.TimestampUnknownKeys = true
+	report := mustApplyPolicy(t, km, now, policy)
+	if diff := cmp.Diff(&keyset.RotationReport{Timestamped: []uint32{keyID}}, report, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("report diff (-want +got):\n%s", diff)
+	}
+	// The age of the key is counted from the time it was timestamped.
+	report = mustApplyPolicy(t, km, now.Add(80*day), testRotationPolicy())
+	if len(report.Added) != 1 {
+		t.Errorf("report = %+v, want one added key", report)
+	}
+}
+
+func TestApplyPolicyWithParameters(t *testing.T) {
+	if err := protoserialization.RegisterParametersSerializer[*testParams](&testParametersSerializer{}); err != nil {
+		t.Fatalf("protoserialization.RegisterParametersSerializer[*testParams](&testParametersSerializer{}) err = %q, want nil", err)
+	}
+	defer protoserialization.UnregisterParametersSerializer[*testParams]()
+	km := keyset.NewManager()
+	report := mustApplyPolicy(t, km, time.Unix(1700000000, 0), &keyset.RotationPolicy{
+		Parameters:  &testParams{hasIDRequirement: true},
+		RotateAfter: 90 * day,
+	})
+	if len(report.Added) != 1 || !slices.Equal(report.Promoted, report.Added) {
+		t.Fatalf("report = %+v, want one added and promoted key", report)
+	}
+	h, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	// testParametersSerializer serializes to an HMAC key template.
+	if _, err := mac.New(h); err != nil {
+		t.Errorf("mac.New(h) err = %v, want nil", err)
+	}
+}
+
+func TestApplyPolicyFailsWithInvalidPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy *keyset.RotationPolicy
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:   "negative duration",
+			policy: &keyset.RotationPolicy{KeyTemplate: mac.HMACSHA256Tag128KeyTemplate(), DeleteAfter: -day},
+		},
+		{
+			name:   "rotation without template",
+			policy: &keyset.RotationPolicy{RotateAfter: day},
+		},
+		{
+			name: "rotation with template and parameters",
+			policy: &keyset.RotationPolicy{
+				KeyTemplate: mac.HMACSHA256Tag128KeyTemplate(),
+				Parameters:  &testParams{},
+				RotateAfter: day,
+			},
+		},
+		{
+			name: "promotion after rotation",
+			policy: &keyset.RotationPolicy{
+				KeyTemplate:  mac.HMACSHA256Tag128KeyTemplate(),
+				RotateAfter:  day,
+				PromoteAfter: 2 * day,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := keyset.NewManager().ApplyPolicy(time.Now(), tc.policy); err == nil {
+				t.Errorf("km.ApplyPolicy() err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestApplyPolicyNeverDisablesPrimary(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	// Without rotation, the only key stays primary forever.
+	policy := &keyset.RotationPolicy{
+		KeyTemplate:  mac.HMACSHA256Tag128KeyTemplate(),
+		DisableAfter: day,
+		DeleteAfter:  2 * day,
+	}
+	km := keyset.NewManager()
+	mustApplyPolicy(t, km, start, policy)
+	report := mustApplyPolicy(t, km, start.Add(1000*day), policy)
+	if diff := cmp.Diff(&keyset.RotationReport{}, report, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("report diff (-want +got):\n%s", diff)
+	}
+	for keyID, status := range keyStatuses(t, km) {
+		if status != keyset.Enabled {
+			t.Errorf("key %d status = %v, want %v", keyID, status, keyset.Enabled)
+		}
+	}
+}
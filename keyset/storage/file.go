@@ -0,0 +1,444 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/tink"
+
+	metadatapb "github.com/tink-crypto/tink-go/v2/proto/keyset_metadata_go_proto"
+)
+
+const (
+	// defaultHistory is the number of previous versions kept by default.
+	defaultHistory = 10
+	// lockRetryInterval is how often a held lock is retried.
+	lockRetryInterval = 10 * time.Millisecond
+)
+
+// Option configures a [FileStore].
+type Option func(*storeOptions) error
+
+type storeOptions struct {
+	associatedData []byte
+	newReader      func(io.Reader) keyset.Reader
+	newWriter      func(io.Writer) keyset.Writer
+	history        int
+}
+
+// WithAssociatedData sets the associated data used to encrypt and decrypt
+// the keyset.
+func WithAssociatedData(associatedData []byte) Option {
+	return func(o *storeOptions) error {
+		o.associatedData = bytes.Clone(associatedData)
+		return nil
+	}
+}
+
+// WithJSONFormat makes the store write the keyset with
+// [keyset.NewJSONWriter] and read it with [keyset.NewJSONReader]. By
+// default, the binary format is used.
+func WithJSONFormat() Option {
+	return func(o *storeOptions) error {
+		o.newReader = func(r io.Reader) keyset.Reader { return keyset.NewJSONReader(r) }
+		o.newWriter = func(w io.Writer) keyset.Writer { return keyset.NewJSONWriter(w) }
+		return nil
+	}
+}
+
+// WithHistory sets the number of previous versions that are kept in
+// addition to the current one. Older versions are deleted after every
+// write. By default, 10 previous versions are kept.
+func WithHistory(n int) Option {
+	return func(o *storeOptions) error {
+		if n < 0 {
+			return fmt.Errorf("history must not be negative, got %d", n)
+		}
+		o.history = n
+		return nil
+	}
+}
+
+// FileStore stores an encrypted keyset in a file on local disk.
+//
+// Version v of the keyset is stored in the file "<path>.v<v>", together with
+// its [keyset.Handle.Metadata] in "<path>.v<v>.metadata", and a copy of the
+// current version is kept at path itself, so that readers which expect a
+// single file, such as reloading.FileBackend, always see the current keyset.
+// The versioned files are the source of truth: if a write is interrupted
+// after the new version was created, the copy at path is brought up to date
+// by the next write. All files are replaced by renaming a fully written
+// temporary file, so readers never see a partially written keyset.
+//
+// Access to the keyset is serialized with a lock on the file "<path>.lock",
+// both within a process and between processes that use a FileStore for the
+// same path. Stores on network file systems may not be protected by the
+// lock.
+//
+// FileStore is safe for concurrent use.
+type FileStore struct {
+	path string
+	kek  tink.AEADWithContext
+	opts storeOptions
+}
+
+// NewFileStore returns a [FileStore] for the keyset at path, which is
+// encrypted with kek. The directory of path must exist.
+func NewFileStore(path string, kek tink.AEADWithContext, opts ...Option) (*FileStore, error) {
+	if path == "" {
+		return nil, errors.New("storage.NewFileStore: path is empty")
+	}
+	if kek == nil {
+		return nil, errors.New("storage.NewFileStore: kek is nil")
+	}
+	s := &FileStore{
+		path: filepath.Clean(path),
+		kek:  kek,
+		opts: storeOptions{
+			newReader: func(r io.Reader) keyset.Reader { return keyset.NewBinaryReader(r) },
+			newWriter: func(w io.Writer) keyset.Writer { return keyset.NewBinaryWriter(w) },
+			history:   defaultHistory,
+		},
+	}
+	for _, opt := range opts {
+		if err := opt(&s.opts); err != nil {
+			return nil, fmt.Errorf("storage.NewFileStore: %v", err)
+		}
+	}
+	info, err := os.Stat(filepath.Dir(s.path))
+	if err != nil {
+		return nil, fmt.Errorf("storage.NewFileStore: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("storage.NewFileStore: %q is not a directory", filepath.Dir(s.path))
+	}
+	return s, nil
+}
+
+// Load returns the current keyset and its version. It returns an error
+// wrapping [ErrNotFound] if the store is empty.
+func (s *FileStore) Load(ctx context.Context) (*keyset.Handle, Version, error) {
+	unlock, err := s.lock(ctx, false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	defer unlock()
+	h, v, err := s.loadCurrent(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage.FileStore: %w", err)
+	}
+	if v == 0 {
+		return nil, 0, fmt.Errorf("storage.FileStore: %w", ErrNotFound)
+	}
+	return h, v, nil
+}
+
+// LoadVersion returns version v of the keyset. It returns an error wrapping
+// [ErrNotFound] if the version doesn't exist, or was deleted because it is
+// older than the kept history.
+func (s *FileStore) LoadVersion(ctx context.Context, v Version) (*keyset.Handle, error) {
+	unlock, err := s.lock(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	defer unlock()
+	h, err := s.read(ctx, v)
+	if err != nil {
+		return nil, fmt.Errorf("storage.FileStore: %w", err)
+	}
+	return h, nil
+}
+
+// Versions returns the stored versions of the keyset in increasing order.
+// The last one is the current version.
+func (s *FileStore) Versions(ctx context.Context) ([]Version, error) {
+	unlock, err := s.lock(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	defer unlock()
+	versions, err := s.versions()
+	if err != nil {
+		return nil, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	return versions, nil
+}
+
+// Store writes h as the new current keyset if the current version is
+// expected, and returns the version of h. Use 0 as expected version to write
+// the first keyset of an empty store.
+//
+// If the current version is not expected, for example because another
+// process wrote the keyset since it was loaded, nothing is written and an
+// error wrapping [ErrVersionMismatch] is returned. The caller should then
+// load the keyset again and retry.
+func (s *FileStore) Store(ctx context.Context, h *keyset.Handle, expected Version) (Version, error) {
+	if h == nil {
+		return 0, errors.New("storage.FileStore: handle is nil")
+	}
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return 0, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	defer unlock()
+	current, err := s.currentVersion()
+	if err != nil {
+		return 0, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	if current != expected {
+		return 0, fmt.Errorf("storage.FileStore: %w: current version is %d, expected %d", ErrVersionMismatch, current, expected)
+	}
+	if err := s.write(ctx, h, current+1); err != nil {
+		return 0, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	return current + 1, nil
+}
+
+// Update modifies the current keyset with f and writes the result as a new
+// version. The store stays locked while f runs, so concurrent updates of the
+// keyset are applied one after the other. If the store is empty, f gets an
+// empty manager.
+//
+// If f returns an error, nothing is written and the error is returned.
+// Otherwise, Update returns the new keyset and its version.
+//
+// Example:
+//
+//	h, version, err := store.Update(ctx, func(m *keyset.Manager) error {
+//		keyID, err := m.Add(aead.AES256GCMKeyTemplate())
+//		if err != nil {
+//			return err
+//		}
+//		return m.SetPrimary(keyID)
+//	})
+func (s *FileStore) Update(ctx context.Context, f func(m *keyset.Manager) error) (*keyset.Handle, Version, error) {
+	unlock, err := s.lock(ctx, true)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	defer unlock()
+	h, current, err := s.loadCurrent(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage.FileStore: %w", err)
+	}
+	m := keyset.NewManager()
+	if h != nil {
+		m = keyset.NewManagerFromHandle(h)
+	}
+	if err := f(m); err != nil {
+		return nil, 0, err
+	}
+	updated, err := m.Handle()
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	if err := s.write(ctx, updated, current+1); err != nil {
+		return nil, 0, fmt.Errorf("storage.FileStore: %v", err)
+	}
+	return updated, current + 1, nil
+}
+
+// lock acquires the lock of the store and returns a function that releases
+// it. It waits until the lock is available or ctx is done.
+func (s *FileStore) lock(ctx context.Context, exclusive bool) (unlock func(), err error) {
+	f, err := os.OpenFile(s.path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		locked, err := tryLockFile(f, exclusive)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("cannot lock %q: %v", f.Name(), err)
+		}
+		if locked {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, fmt.Errorf("cannot lock %q: %v", f.Name(), ctx.Err())
+		case <-time.After(lockRetryInterval):
+		}
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+func (s *FileStore) versionPath(v Version) string {
+	return fmt.Sprintf("%s.v%d", s.path, v)
+}
+
+func (s *FileStore) metadataPath(v Version) string {
+	return s.versionPath(v) + ".metadata"
+}
+
+// versions returns the versions that have a file, in increasing order.
+func (s *FileStore) versions() ([]Version, error) {
+	entries, err := os.ReadDir(filepath.Dir(s.path))
+	if err != nil {
+		return nil, err
+	}
+	prefix := filepath.Base(s.path) + ".v"
+	var versions []Version
+	for _, entry := range entries {
+		suffix, found := strings.CutPrefix(entry.Name(), prefix)
+		if !found || !entry.Type().IsRegular() {
+			continue
+		}
+		v, err := strconv.ParseUint(suffix, 10, 64)
+		// Ignore files that merely look like versions, such as "<path>.v01".
+		if err != nil || v == 0 || strconv.FormatUint(v, 10) != suffix {
+			continue
+		}
+		versions = append(versions, Version(v))
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+// currentVersion returns the latest version, or 0 if there is none.
+func (s *FileStore) currentVersion() (Version, error) {
+	versions, err := s.versions()
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// loadCurrent returns the current keyset and its version, or a nil handle and
+// version 0 if there is none.
+func (s *FileStore) loadCurrent(ctx context.Context) (*keyset.Handle, Version, error) {
+	v, err := s.currentVersion()
+	if err != nil || v == 0 {
+		return nil, 0, err
+	}
+	h, err := s.read(ctx, v)
+	if err != nil {
+		return nil, 0, err
+	}
+	return h, v, nil
+}
+
+func (s *FileStore) read(ctx context.Context, v Version) (*keyset.Handle, error) {
+	serialized, err := os.ReadFile(s.versionPath(v))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: version %d", ErrNotFound, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	md := &metadatapb.KeysetMetadata{}
+	serializedMetadata, err := os.ReadFile(s.metadataPath(v))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// The version was written without metadata.
+	case err != nil:
+		return nil, err
+	default:
+		if err := proto.Unmarshal(serializedMetadata, md); err != nil {
+			return nil, fmt.Errorf("cannot read metadata of version %d: %v", v, err)
+		}
+	}
+	h, err := keyset.ReadWithContext(ctx, s.opts.newReader(bytes.NewReader(serialized)), s.kek, s.opts.associatedData, keyset.WithMetadata(md))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read version %d: %v", v, err)
+	}
+	return h, nil
+}
+
+// write stores h as version v, updates the copy of the current keyset and
+// deletes the versions that are no longer kept. The caller must hold the
+// exclusive lock.
+func (s *FileStore) write(ctx context.Context, h *keyset.Handle, v Version) error {
+	buf := new(bytes.Buffer)
+	if err := h.WriteWithContext(ctx, s.opts.newWriter(buf), s.kek, s.opts.associatedData); err != nil {
+		return err
+	}
+	serializedMetadata, err := proto.Marshal(h.Metadata())
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomically(s.metadataPath(v), serializedMetadata); err != nil {
+		return err
+	}
+	// Creating the version file commits the write.
+	if err := writeFileAtomically(s.versionPath(v), buf.Bytes()); err != nil {
+		return err
+	}
+	if err := writeFileAtomically(s.path, buf.Bytes()); err != nil {
+		return fmt.Errorf("version %d was written, but %q wasn't updated: %v", v, s.path, err)
+	}
+	versions, err := s.versions()
+	if err != nil {
+		return err
+	}
+	for _, old := range versions {
+		if uint64(old)+uint64(s.opts.history) >= uint64(v) {
+			break
+		}
+		if err := os.Remove(s.versionPath(old)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.Remove(s.metadataPath(old)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFileAtomically replaces the file name with data. The data is written
+// to a temporary file in the same directory, which is then renamed to name.
+func writeFileAtomically(name string, data []byte) (err error) {
+	dir := filepath.Dir(name)
+	f, err := os.CreateTemp(dir, "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
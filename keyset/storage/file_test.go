@@ -0,0 +1,440 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/keyset/storage"
+	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+const kekURI = "fake-kms://CM2b3_MDElQKSAowdHlwZS5nb29nbGVhcGlzLmNvbS9nb29nbGUuY3J5cHRvLnRpbmsuQWVzR2NtS2V5EhIaEIK75t5L-adlUwVhWvRuWUwYARABGM2b3_MDIAE"
+
+func mustKEK(t *testing.T) tink.AEADWithContext {
+	t.Helper()
+	kek, err := fakekms.NewAEADWithContext(kekURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewAEADWithContext() err = %v, want nil", err)
+	}
+	return kek
+}
+
+func mustNewFileStore(t *testing.T, path string, opts ...storage.Option) *storage.FileStore {
+	t.Helper()
+	s, err := storage.NewFileStore(path, mustKEK(t), opts...)
+	if err != nil {
+		t.Fatalf("storage.NewFileStore() err = %v, want nil", err)
+	}
+	return s
+}
+
+// addPrimaryKey adds a new AES-128-GCM primary key.
+func addPrimaryKey(m *keyset.Manager) error {
+	keyID, err := m.Add(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		return err
+	}
+	return m.SetPrimary(keyID)
+}
+
+func assertSameKeyset(t *testing.T, got, want *keyset.Handle) {
+	t.Helper()
+	if !proto.Equal(testkeyset.KeysetMaterial(got), testkeyset.KeysetMaterial(want)) {
+		t.Errorf("keyset = %s, want %s", got, want)
+	}
+}
+
+func TestFileStoreUpdateAndLoad(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keyset.bin")
+	s := mustNewFileStore(t, path)
+
+	if _, _, err := s.Load(ctx); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("s.Load() err = %v, want ErrNotFound", err)
+	}
+
+	h1, v1, err := s.Update(ctx, addPrimaryKey)
+	if err != nil {
+		t.Fatalf("s.Update() err = %v, want nil", err)
+	}
+	if v1 != 1 {
+		t.Errorf("s.Update() version = %d, want 1", v1)
+	}
+	h2, v2, err := s.Update(ctx, addPrimaryKey)
+	if err != nil {
+		t.Fatalf("s.Update() err = %v, want nil", err)
+	}
+	if v2 != 2 {
+		t.Errorf("s.Update() version = %d, want 2", v2)
+	}
+	if h2.Len() != 2 {
+		t.Errorf("h2.Len() = %d, want 2", h2.Len())
+	}
+
+	got, v, err := s.Load(ctx)
+	if err != nil {
+		t.Fatalf("s.Load() err = %v, want nil", err)
+	}
+	if v != v2 {
+		t.Errorf("s.Load() version = %d, want %d", v, v2)
+	}
+	assertSameKeyset(t, got, h2)
+
+	got, err = s.LoadVersion(ctx, v1)
+	if err != nil {
+		t.Fatalf("s.LoadVersion(%d) err = %v, want nil", v1, err)
+	}
+	assertSameKeyset(t, got, h1)
+
+	// The current keyset can also be read directly from path.
+	serialized, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile() err = %v, want nil", err)
+	}
+	got, err = keyset.ReadWithContext(ctx, keyset.NewBinaryReader(bytes.NewReader(serialized)), mustKEK(t), nil)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithContext() err = %v, want nil", err)
+	}
+	assertSameKeyset(t, got, h2)
+
+	// The keyset can be used.
+	a, err := aead.New(got)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); err != nil {
+		t.Errorf("a.Encrypt() err = %v, want nil", err)
+	}
+}
+
+func TestFileStoreUpdateErrorWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	s := mustNewFileStore(t, filepath.Join(t.TempDir(), "keyset.bin"))
+	if _, _, err := s.Update(ctx, addPrimaryKey); err != nil {
+		t.Fatalf("s.Update() err = %v, want nil", err)
+	}
+	wantErr := errors.New("rotation failed")
+	_, _, err := s.Update(ctx, func(m *keyset.Manager) error {
+		if err := addPrimaryKey(m); err != nil {
+			return err
+		}
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("s.Update() err = %v, want %v", err, wantErr)
+	}
+	versions, err := s.Versions(ctx)
+	if err != nil {
+		t.Fatalf("s.Versions() err = %v, want nil", err)
+	}
+	if !slices.Equal(versions, []storage.Version{1}) {
+		t.Errorf("s.Versions() = %v, want [1]", versions)
+	}
+}
+
+func TestFileStoreStoreComparesVersions(t *testing.T) {
+	ctx := context.Background()
+	s := mustNewFileStore(t, filepath.Join(t.TempDir(), "keyset.bin"))
+	h, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+
+	if _, err := s.Store(ctx, h, 1); !errors.Is(err, storage.ErrVersionMismatch) {
+		t.Errorf("s.Store(1) on empty store err = %v, want ErrVersionMismatch", err)
+	}
+	v, err := s.Store(ctx, h, 0)
+	if err != nil {
+		t.Fatalf("s.Store(0) err = %v, want nil", err)
+	}
+	if v != 1 {
+		t.Errorf("s.Store(0) = %d, want 1", v)
+	}
+
+	// Two writers load the same version; only the first one succeeds.
+	loaded, loadedVersion, err := s.Load(ctx)
+	if err != nil {
+		t.Fatalf("s.Load() err = %v, want nil", err)
+	}
+	m := keyset.NewManagerFromHandle(loaded)
+	if err := addPrimaryKey(m); err != nil {
+		t.Fatalf("addPrimaryKey() err = %v, want nil", err)
+	}
+	rotated, err := m.Handle()
+	if err != nil {
+		t.Fatalf("m.Handle() err = %v, want nil", err)
+	}
+	if _, err := s.Store(ctx, rotated, loadedVersion); err != nil {
+		t.Fatalf("s.Store(%d) err = %v, want nil", loadedVersion, err)
+	}
+	if _, err := s.Store(ctx, loaded, loadedVersion); !errors.Is(err, storage.ErrVersionMismatch) {
+		t.Errorf("s.Store(%d) err = %v, want ErrVersionMismatch", loadedVersion, err)
+	}
+
+	got, v, err := s.Load(ctx)
+	if err != nil {
+		t.Fatalf("s.Load() err = %v, want nil", err)
+	}
+	if v != 2 {
+		t.Errorf("s.Load() version = %d, want 2", v)
+	}
+	assertSameKeyset(t, got, rotated)
+}
+
+func TestFileStoreKeepsHistory(t *testing.T) {
+	ctx := context.Background()
+	s := mustNewFileStore(t, filepath.Join(t.TempDir(), "keyset.bin"), storage.WithHistory(2))
+	var handles []*keyset.Handle
+	for i := 0; i < 5; i++ {
+		h, _, err := s.Update(ctx, addPrimaryKey)
+		if err != nil {
+			t.Fatalf("s.Update() err = %v, want nil", err)
+		}
+		handles = append(handles, h)
+	}
+	versions, err := s.Versions(ctx)
+	if err != nil {
+		t.Fatalf("s.Versions() err = %v, want nil", err)
+	}
+	if want := []storage.Version{3, 4, 5}; !slices.Equal(versions, want) {
+		t.Errorf("s.Versions() = %v, want %v", versions, want)
+	}
+	if _, err := s.LoadVersion(ctx, 2); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("s.LoadVersion(2) err = %v, want ErrNotFound", err)
+	}
+	got, err := s.LoadVersion(ctx, 3)
+	if err != nil {
+		t.Fatalf("s.LoadVersion(3) err = %v, want nil", err)
+	}
+	assertSameKeyset(t, got, handles[2])
+}
+
+func TestFileStoreWithoutHistory(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s := mustNewFileStore(t, filepath.Join(dir, "keyset.bin"), storage.WithHistory(0))
+	for i := 0; i < 3; i++ {
+		if _, _, err := s.Update(ctx, addPrimaryKey); err != nil {
+			t.Fatalf("s.Update() err = %v, want nil", err)
+		}
+	}
+	versions, err := s.Versions(ctx)
+	if err != nil {
+		t.Fatalf("s.Versions() err = %v, want nil", err)
+	}
+	if want := []storage.Version{3}; !slices.Equal(versions, want) {
+		t.Errorf("s.Versions() = %v, want %v", versions, want)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "keyset.bin*"))
+	if err != nil {
+		t.Fatalf("filepath.Glob() err = %v, want nil", err)
+	}
+	for i := range files {
+		files[i] = filepath.Base(files[i])
+	}
+	if want := []string{"keyset.bin", "keyset.bin.lock", "keyset.bin.v3", "keyset.bin.v3.metadata"}; !slices.Equal(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+}
+
+func TestFileStoreKeepsMetadata(t *testing.T) {
+	ctx := context.Background()
+	s := mustNewFileStore(t, filepath.Join(t.TempDir(), "keyset.bin"), storage.WithHistory(0))
+	start := time.Unix(1700000000, 0)
+	policy := &keyset.RotationPolicy{
+		KeyTemplate:  aead.AES128GCMKeyTemplate(),
+		RotateAfter:  80 * 24 * time.Hour,
+		PromoteAfter: 7 * 24 * time.Hour,
+	}
+	applyPolicy := func(now time.Time) *keyset.RotationReport {
+		t.Helper()
+		var report *keyset.RotationReport
+		if _, _, err := s.Update(ctx, func(m *keyset.Manager) error {
+			var err error
+			report, err = m.ApplyPolicy(now, policy)
+			return err
+		}); err != nil {
+			t.Fatalf("s.Update() err = %v, want nil", err)
+		}
+		return report
+	}
+	if report := applyPolicy(start); len(report.Added) != 1 {
+		t.Fatalf("report = %+v, want one added key", report)
+	}
+	// The creation time of the first key was stored, so it isn't timestamped
+	// again and is old enough to be rotated.
+	report := applyPolicy(start.Add(80 * 24 * time.Hour))
+	if len(report.Timestamped) != 0 || len(report.Added) != 1 {
+		t.Errorf("report = %+v, want one added key and no timestamped keys", report)
+	}
+	h, _, err := s.Load(ctx)
+	if err != nil {
+		t.Fatalf("s.Load() err = %v, want nil", err)
+	}
+	entry, err := h.Entry(0)
+	if err != nil {
+		t.Fatalf("h.Entry(0) err = %v, want nil", err)
+	}
+	if !entry.CreationTime().Equal(start) {
+		t.Errorf("entry.CreationTime() = %v, want %v", entry.CreationTime(), start)
+	}
+}
+
+func TestFileStoreConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keyset.bin")
+	// Separate stores for the same path behave like separate processes.
+	stores := []*storage.FileStore{mustNewFileStore(t, path), mustNewFileStore(t, path)}
+	const updatesPerStore = 10
+	var wg sync.WaitGroup
+	for _, s := range stores {
+		for i := 0; i < updatesPerStore; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, _, err := s.Update(ctx, addPrimaryKey); err != nil {
+					t.Errorf("s.Update() err = %v, want nil", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	h, v, err := stores[0].Load(ctx)
+	if err != nil {
+		t.Fatalf("s.Load() err = %v, want nil", err)
+	}
+	want := len(stores) * updatesPerStore
+	if v != storage.Version(want) {
+		t.Errorf("s.Load() version = %d, want %d", v, want)
+	}
+	if h.Len() != want {
+		t.Errorf("h.Len() = %d, want %d", h.Len(), want)
+	}
+}
+
+func TestFileStoreLockRespectsContext(t *testing.T) {
+	ctx := context.Background()
+	s := mustNewFileStore(t, filepath.Join(t.TempDir(), "keyset.bin"))
+	if _, _, err := s.Update(ctx, addPrimaryKey); err != nil {
+		t.Fatalf("s.Update() err = %v, want nil", err)
+	}
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Update(ctx, func(m *keyset.Manager) error {
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := s.Load(timeoutCtx); err == nil {
+		t.Errorf("s.Load() while locked err = nil, want error")
+	}
+	close(release)
+	<-done
+	if _, v, err := s.Load(ctx); err != nil || v != 2 {
+		t.Errorf("s.Load() = _, %d, %v, want 2, nil", v, err)
+	}
+}
+
+func TestFileStoreWithAssociatedDataAndJSONFormat(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keyset.json")
+	associatedData := []byte("associated data")
+	s := mustNewFileStore(t, path, storage.WithAssociatedData(associatedData), storage.WithJSONFormat())
+	h, _, err := s.Update(ctx, addPrimaryKey)
+	if err != nil {
+		t.Fatalf("s.Update() err = %v, want nil", err)
+	}
+	serialized, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile() err = %v, want nil", err)
+	}
+	got, err := keyset.ReadWithContext(ctx, keyset.NewJSONReader(bytes.NewReader(serialized)), mustKEK(t), associatedData)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithContext() err = %v, want nil", err)
+	}
+	assertSameKeyset(t, got, h)
+
+	// A store without the associated data can't decrypt the keyset.
+	other := mustNewFileStore(t, path, storage.WithJSONFormat())
+	if _, _, err := other.Load(ctx); err == nil {
+		t.Errorf("other.Load() err = nil, want error")
+	}
+}
+
+func TestFileStoreIgnoresUnrelatedFiles(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keyset.bin")
+	for _, name := range []string{"keyset.bin.v01", "keyset.bin.v0", "keyset.bin.vx", "other.bin.v7"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("not a keyset"), 0600); err != nil {
+			t.Fatalf("os.WriteFile() err = %v, want nil", err)
+		}
+	}
+	s := mustNewFileStore(t, path)
+	if _, _, err := s.Load(ctx); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("s.Load() err = %v, want ErrNotFound", err)
+	}
+	if _, v, err := s.Update(ctx, addPrimaryKey); err != nil || v != 1 {
+		t.Errorf("s.Update() = _, %d, %v, want 1, nil", v, err)
+	}
+}
+
+func TestNewFileStoreFails(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatalf("os.WriteFile() err = %v, want nil", err)
+	}
+	kek := mustKEK(t)
+	for _, tc := range []struct {
+		name string
+		path string
+		kek  tink.AEADWithContext
+		opts []storage.Option
+	}{
+		{name: "empty path", path: "", kek: kek},
+		{name: "nil kek", path: filepath.Join(dir, "keyset.bin"), kek: nil},
+		{name: "missing directory", path: filepath.Join(dir, "missing", "keyset.bin"), kek: kek},
+		{name: "parent is a file", path: filepath.Join(file, "keyset.bin"), kek: kek},
+		{name: "negative history", path: filepath.Join(dir, "keyset.bin"), kek: kek, opts: []storage.Option{storage.WithHistory(-1)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := storage.NewFileStore(tc.path, tc.kek, tc.opts...); err == nil {
+				t.Errorf("storage.NewFileStore() err = nil, want error")
+			}
+		})
+	}
+}
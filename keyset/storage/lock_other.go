@@ -0,0 +1,34 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package storage
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLockFile(f *os.File, exclusive bool) (bool, error) {
+	return false, errors.New("file locking is not supported on this platform")
+}
+
+func unlockFile(f *os.File) error {
+	return errors.New("file locking is not supported on this platform")
+}
+
+func syncDir(dir string) error {
+	return nil
+}
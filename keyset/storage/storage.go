@@ -0,0 +1,52 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage persists encrypted keysets.
+//
+// A [FileStore] keeps an encrypted keyset on local disk. Every write creates
+// a new numbered version of the keyset; the latest version is the current
+// keyset and a configurable number of previous versions is kept for
+// rollbacks and audits. Writes are atomic and serialized across processes
+// with a file lock. Concurrent read-modify-write cycles are detected with a
+// compare-and-swap on the version, or avoided altogether by
+// [FileStore.Update], which holds the lock while a [keyset.Manager] modifies
+// the keyset.
+//
+// Example:
+//
+//	store, err := storage.NewFileStore("/etc/keys/aead.keyset", kekAEAD)
+//	if err != nil {
+//		return err
+//	}
+//	_, _, err = store.Update(ctx, func(m *keyset.Manager) error {
+//		_, err := m.ApplyPolicy(time.Now(), policy)
+//		return err
+//	})
+package storage
+
+import (
+	"errors"
+)
+
+// Version identifies a stored version of a keyset. Versions start at 1 and
+// increase by one with every write. Version 0 stands for "no keyset".
+type Version uint64
+
+// ErrNotFound is returned when the store or the requested version doesn't
+// contain a keyset.
+var ErrNotFound = errors.New("keyset not found")
+
+// ErrVersionMismatch is returned when a keyset is written with an expected
+// version that is not the current version of the store.
+var ErrVersionMismatch = errors.New("keyset version mismatch")
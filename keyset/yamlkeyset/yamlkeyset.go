@@ -0,0 +1,309 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package yamlkeyset reads and writes keysets in YAML format, which is easy
+// to review in diffs, for example in configuration repositories.
+//
+// The YAML format is the proto3 JSON mapping of the keyset protos, written by
+// gopkg.in/yaml.v3 as block-style YAML with sorted keys so that it is stable
+// and diffs well. Byte fields are base64-encoded.
+//
+// In cleartext keysets, the key proto in each key data is expanded into a
+// "typedValue" mapping with its typed fields, instead of the serialized "value",
+// when the key proto type is linked into the binary and expanding it is
+// lossless. For example:
+//
+//	key:
+//	  - keyData:
+//	      keyMaterialType: ASYMMETRIC_PUBLIC
+//	      typeUrl: type.googleapis.com/google.crypto.tink.EcdsaPublicKey
+//	      typedValue:
+//	        params:
+//	          curve: NIST_P256
+//	          encoding: DER
+//	          hashType: SHA256
+//	        x: AKEvxh6cDcSXOBF1mT0ksw/fjDVKJJtnwFIhHRX2DfPk
+//	        "y": AJUcO3QqjbJHhR4ZPtvtQbIUREbwXc8phtH7GXqq4MeU
+//	    keyId: 1796440442
+//	    outputPrefixType: TINK
+//	    status: ENABLED
+//	primaryKeyId: 1796440442
+//
+// [Reader] and [Writer] implement [keyset.Reader] and [keyset.Writer], so they
+// can be used with [keyset.ReadWithNoSecrets], [keyset.Read] and the
+// corresponding methods of [keyset.Handle].
+package yamlkeyset
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// Reader deserializes a keyset from YAML format.
+type Reader struct {
+	r io.Reader
+}
+
+var _ keyset.Reader = (*Reader)(nil)
+
+// NewReader returns a new Reader that will read from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Read parses a (cleartext) keyset from the underlying io.Reader.
+func (r *Reader) Read() (*tinkpb.Keyset, error) {
+	doc, err := r.readDocument()
+	if err != nil {
+		return nil, err
+	}
+	if err := forEachKeyData(doc, collapseKeyData); err != nil {
+		return nil, err
+	}
+	ks := &tinkpb.Keyset{}
+	if err := unmarshalDocument(doc, ks); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// ReadEncrypted parses an EncryptedKeyset from the underlying io.Reader.
+func (r *Reader) ReadEncrypted() (*tinkpb.EncryptedKeyset, error) {
+	doc, err := r.readDocument()
+	if err != nil {
+		return nil, err
+	}
+	ks := &tinkpb.EncryptedKeyset{}
+	if err := unmarshalDocument(doc, ks); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func (r *Reader) readDocument() (map[string]any, error) {
+	b, err := io.ReadAll(r.r)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("yamlkeyset.Reader: %v", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("yamlkeyset.Reader: empty document")
+	}
+	return doc, nil
+}
+
+func unmarshalDocument(doc map[string]any, msg proto.Message) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := protojson.Unmarshal(b, msg); err != nil {
+		return fmt.Errorf("yamlkeyset.Reader: %v", err)
+	}
+	return nil
+}
+
+// Writer serializes a keyset into YAML format.
+type Writer struct {
+	w io.Writer
+}
+
+var _ keyset.Writer = (*Writer)(nil)
+
+// NewWriter returns a new Writer that will write to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes the keyset to the underlying io.Writer.
+func (w *Writer) Write(ks *tinkpb.Keyset) error {
+	doc, err := marshalDocument(ks)
+	if err != nil {
+		return err
+	}
+	if err := forEachKeyData(doc, expandKeyData); err != nil {
+		return err
+	}
+	return w.writeDocument(doc)
+}
+
+// WriteEncrypted writes the encrypted keyset to the underlying io.Writer.
+func (w *Writer) WriteEncrypted(ks *tinkpb.EncryptedKeyset) error {
+	doc, err := marshalDocument(ks)
+	if err != nil {
+		return err
+	}
+	return w.writeDocument(doc)
+}
+
+func (w *Writer) writeDocument(doc map[string]any) error {
+	e := yaml.NewEncoder(w.w)
+	e.SetIndent(2)
+	if err := e.Encode(yamlValue(doc)); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// yamlValue converts the JSON numbers in v, which yaml.v3 would write as
+// strings, to integers or floats.
+func yamlValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, value := range v {
+			v[name] = yamlValue(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = yamlValue(value)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+// marshalDocument returns the proto3 JSON mapping of msg as generic values.
+func marshalDocument(msg proto.Message) (map[string]any, error) {
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	v, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	doc, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("yamlkeyset.Writer: unexpected JSON value %v", v)
+	}
+	return doc, nil
+}
+
+func decodeJSON(b []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// forEachKeyData calls f on the key data mapping of each key in doc, the
+// generic representation of a Keyset.
+func forEachKeyData(doc map[string]any, f func(keyData map[string]any) error) error {
+	keys, _ := doc["key"].([]any)
+	for _, k := range keys {
+		key, ok := k.(map[string]any)
+		if !ok {
+			continue
+		}
+		keyData, ok := key["keyData"].(map[string]any)
+		if !ok {
+			continue
+		}
+		if err := f(keyData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandKeyData replaces the serialized key proto in keyData with its typed
+// fields, if that is lossless.
+func expandKeyData(keyData map[string]any) error {
+	typeURL, _ := keyData["typeUrl"].(string)
+	encodedValue, _ := keyData["value"].(string)
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil || encodedValue == "" {
+		return nil
+	}
+	value, err := base64.StdEncoding.DecodeString(encodedValue)
+	if err != nil {
+		return err
+	}
+	m := mt.New().Interface()
+	if err := proto.Unmarshal(value, m); err != nil {
+		return nil
+	}
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	// Unknown fields and non-canonical encodings don't survive JSON, so make
+	// sure the value can be restored exactly.
+	restored := mt.New().Interface()
+	if err := protojson.Unmarshal(b, restored); err != nil {
+		return nil
+	}
+	if reserialized, err := (proto.MarshalOptions{Deterministic: true}).Marshal(restored); err != nil || !bytes.Equal(reserialized, value) {
+		return nil
+	}
+	typed, err := decodeJSON(b)
+	if err != nil {
+		return err
+	}
+	delete(keyData, "value")
+	keyData["typedValue"] = typed
+	return nil
+}
+
+// collapseKeyData replaces the typed key fields in keyData, if any, with the
+// serialized key proto.
+func collapseKeyData(keyData map[string]any) error {
+	typed, found := keyData["typedValue"]
+	if !found {
+		return nil
+	}
+	typeURL, _ := keyData["typeUrl"].(string)
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return fmt.Errorf("yamlkeyset.Reader: unknown key type %q", typeURL)
+	}
+	b, err := json.Marshal(typed)
+	if err != nil {
+		return err
+	}
+	m := mt.New().Interface()
+	if err := protojson.Unmarshal(b, m); err != nil {
+		return fmt.Errorf("yamlkeyset.Reader: %v", err)
+	}
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	delete(keyData, "typedValue")
+	keyData["value"] = value
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yamlkeyset_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/keyset/yamlkeyset"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/testkeyset"
+	"github.com/tink-crypto/tink-go/v2/testutil"
+	"github.com/tink-crypto/tink-go/v2/tink"
+
+	commonpb "github.com/tink-crypto/tink-go/v2/proto/common_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func newKEK(t *testing.T) tink.AEAD {
+	t.Helper()
+	kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	kek, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v, want nil", err)
+	}
+	return kek
+}
+
+func TestYAMLIOUnencrypted(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := yamlkeyset.NewWriter(buf)
+	r := yamlkeyset.NewReader(buf)
+
+	manager := testutil.NewHMACKeysetManager()
+	h, err := manager.Handle()
+	if h == nil || err != nil {
+		t.Fatalf("cannot get keyset handle: %v", err)
+	}
+
+	ks1 := testkeyset.KeysetMaterial(h)
+	if err := w.Write(ks1); err != nil {
+		t.Fatalf("cannot write keyset: %v", err)
+	}
+	written := buf.String()
+	if !strings.Contains(written, "hash: SHA256") {
+		t.Errorf("written keyset %q doesn't contain the typed HMAC parameters", written)
+	}
+
+	ks2, err := r.Read()
+	if err != nil {
+		t.Fatalf("cannot read keyset: %v", err)
+	}
+	if !proto.Equal(ks1, ks2) {
+		t.Errorf("written keyset (%s) doesn't match read keyset (%s)", ks1, ks2)
+	}
+
+	// The output is canonical.
+	if err := w.Write(ks2); err != nil {
+		t.Fatalf("cannot write keyset: %v", err)
+	}
+	if got := buf.String(); got != written {
+		t.Errorf("second write = %q, want %q", got, written)
+	}
+}
+
+func TestYAMLIOWithNoSecrets(t *testing.T) {
+	privateHandle, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	h, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v, want nil", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := h.WriteWithNoSecrets(yamlkeyset.NewWriter(buf)); err != nil {
+		t.Fatalf("h.WriteWithNoSecrets() err = %v, want nil", err)
+	}
+	// "y" must be quoted, since it is a boolean in YAML 1.1.
+	if !strings.Contains(buf.String(), `"y": `) {
+		t.Errorf("written keyset %q doesn't contain the quoted key \"y\"", buf.String())
+	}
+	got, err := keyset.ReadWithNoSecrets(yamlkeyset.NewReader(buf))
+	if err != nil {
+		t.Fatalf("keyset.ReadWithNoSecrets() err = %v, want nil", err)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(got), testkeyset.KeysetMaterial(h)) {
+		t.Errorf("read keyset (%s) doesn't match written keyset (%s)", testkeyset.KeysetMaterial(got), testkeyset.KeysetMaterial(h))
+	}
+}
+
+func TestYAMLIOEncrypted(t *testing.T) {
+	kek := newKEK(t)
+	h, err := testutil.NewHMACKeysetManager().Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := h.Write(yamlkeyset.NewWriter(buf), kek); err != nil {
+		t.Fatalf("h.Write() err = %v, want nil", err)
+	}
+	got, err := keyset.Read(yamlkeyset.NewReader(buf), kek)
+	if err != nil {
+		t.Fatalf("keyset.Read() err = %v, want nil", err)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(got), testkeyset.KeysetMaterial(h)) {
+		t.Errorf("read keyset (%s) doesn't match written keyset (%s)", testkeyset.KeysetMaterial(got), testkeyset.KeysetMaterial(h))
+	}
+}
+
+func TestYAMLIOLargeEncryptedKeyset(t *testing.T) {
+	encrypted := make([]byte, 1<<20)
+	for i := range encrypted {
+		encrypted[i] = byte(i)
+	}
+	ks1 := &tinkpb.EncryptedKeyset{EncryptedKeyset: encrypted}
+	buf := new(bytes.Buffer)
+	if err := yamlkeyset.NewWriter(buf).WriteEncrypted(ks1); err != nil {
+		t.Fatalf("WriteEncrypted() err = %v, want nil", err)
+	}
+	ks2, err := yamlkeyset.NewReader(buf).ReadEncrypted()
+	if err != nil {
+		t.Fatalf("ReadEncrypted() err = %v, want nil", err)
+	}
+	if !proto.Equal(ks1, ks2) {
+		t.Errorf("read encrypted keyset doesn't match written encrypted keyset")
+	}
+}
+
+func TestYAMLIOKeepsValuesThatCannotBeExpanded(t *testing.T) {
+	// Trailing unknown fields would be lost by expanding the key proto.
+	hmacKey, err := proto.Marshal(testutil.NewHMACKey(commonpb.HashType_SHA256, 16))
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	hmacKeyWithUnknownField := append(hmacKey, 0xf8, 0x01, 0x01)
+	ks1 := testutil.NewKeyset(1, []*tinkpb.Keyset_Key{
+		testutil.NewKey(testutil.NewKeyData(testutil.HMACTypeURL, hmacKeyWithUnknownField, tinkpb.KeyData_SYMMETRIC), tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_TINK),
+		testutil.NewKey(testutil.NewKeyData("unknown.type.url", []byte{1, 2, 3}, tinkpb.KeyData_SYMMETRIC), tinkpb.KeyStatusType_ENABLED, 2, tinkpb.OutputPrefixType_RAW),
+	})
+	buf := new(bytes.Buffer)
+	if err := yamlkeyset.NewWriter(buf).Write(ks1); err != nil {
+		t.Fatalf("cannot write keyset: %v", err)
+	}
+	if strings.Contains(buf.String(), "typedValue") {
+		t.Errorf("written keyset %q contains typed values, want none", buf.String())
+	}
+	ks2, err := yamlkeyset.NewReader(buf).Read()
+	if err != nil {
+		t.Fatalf("cannot read keyset: %v", err)
+	}
+	if !proto.Equal(ks1, ks2) {
+		t.Errorf("written keyset (%s) doesn't match read keyset (%s)", ks1, ks2)
+	}
+}
+
+func TestYAMLReader(t *testing.T) {
+	yaml := `# A hand-written keyset.
+primaryKeyId: 42
+key:
+- keyId: 42
+  status: 'ENABLED'
+  outputPrefixType: "TINK"
+  keyData:
+    typeUrl: type.googleapis.com/google.crypto.tink.HmacKey
+    keyMaterialType: SYMMETRIC
+    typedValue:
+      params:
+        hash: SHA256
+        tagSize: 16
+      keyValue: "AAECAwQFBgcICQoLDA0ODw=="
+`
+	ks, err := yamlkeyset.NewReader(strings.NewReader(yaml)).Read()
+	if err != nil {
+		t.Fatalf("cannot read keyset: %v", err)
+	}
+	want := testutil.NewHMACKey(commonpb.HashType_SHA256, 16)
+	want.KeyValue = []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	serializedWant, err := proto.MarshalOptions{Deterministic: true}.Marshal(want)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v, want nil", err)
+	}
+	wantKeyset := testutil.NewKeyset(42, []*tinkpb.Keyset_Key{
+		testutil.NewKey(testutil.NewKeyData(testutil.HMACTypeURL, serializedWant, tinkpb.KeyData_SYMMETRIC), tinkpb.KeyStatusType_ENABLED, 42, tinkpb.OutputPrefixType_TINK),
+	})
+	if !proto.Equal(ks, wantKeyset) {
+		t.Errorf("read keyset (%s) doesn't match expected keyset (%s)", ks, wantKeyset)
+	}
+}
+
+func TestYAMLReaderRejectsInvalidDocuments(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		yaml string
+	}{
+		{name: "empty", yaml: ""},
+		{name: "scalar document", yaml: "42\n"},
+		{name: "bad indentation", yaml: "primaryKeyId: 42\n  key: []\n"},
+		{name: "duplicate key", yaml: "primaryKeyId: 42\nprimaryKeyId: 43\n"},
+		{name: "sequence document", yaml: "- 42\n"},
+		{name: "unknown field", yaml: "primaryKeyId: 42\nunknownField: 1\n"},
+		{name: "unknown key type", yaml: "key:\n  - keyData:\n      typeUrl: unknown\n      typedValue: {}\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := yamlkeyset.NewReader(strings.NewReader(tc.yaml)).Read(); err == nil {
+				t.Errorf("Read() err = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kwp
+
+import (
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+// Parameters specifies an AES-KWP key.
+//
+// AES-KWP keys have no ID requirement: wrapped keys never carry a prefix.
+type Parameters struct {
+	keySizeInBytes int
+}
+
+var _ key.Parameters = (*Parameters)(nil)
+
+// NewParameters creates a new AES-KWP Parameters object. keySizeInBytes must
+// be 16 or 32.
+func NewParameters(keySizeInBytes int) (*Parameters, error) {
+	if keySizeInBytes != 16 && keySizeInBytes != 32 {
+		return nil, fmt.Errorf("kwp.NewParameters: unsupported key size; want 16 or 32, got: %v", keySizeInBytes)
+	}
+	return &Parameters{keySizeInBytes: keySizeInBytes}, nil
+}
+
+// KeySizeInBytes returns the size of the key in bytes.
+func (p *Parameters) KeySizeInBytes() int { return p.keySizeInBytes }
+
+// HasIDRequirement returns false: AES-KWP keys have no ID requirement.
+func (p *Parameters) HasIDRequirement() bool { return false }
+
+// Equal returns whether this Parameters object is equal to other.
+func (p *Parameters) Equal(other key.Parameters) bool {
+	that, ok := other.(*Parameters)
+	return ok && p.keySizeInBytes == that.keySizeInBytes
+}
+
+// Key represents an AES-KWP key.
+type Key struct {
+	keyBytes   secretdata.Bytes
+	parameters *Parameters
+}
+
+var _ key.Key = (*Key)(nil)
+
+// NewKey creates a new AES-KWP key with keyBytes and parameters.
+func NewKey(keyBytes secretdata.Bytes, parameters *Parameters) (*Key, error) {
+	if parameters == nil {
+		return nil, fmt.Errorf("kwp.NewKey: parameters is nil")
+	}
+	if keyBytes.Len() != parameters.KeySizeInBytes() {
+		return nil, fmt.Errorf("kwp.NewKey: key.Len() = %v, want %v", keyBytes.Len(), parameters.KeySizeInBytes())
+	}
+	return &Key{
+		keyBytes:   keyBytes,
+		parameters: parameters,
+	}, nil
+}
+
+// KeyBytes returns the key material.
+//
+// This function provides access to partial key material. See
+// https://developers.google.com/tink/design/access_control#access_of_parts_of_a_key
+// for more information.
+func (k *Key) KeyBytes() secretdata.Bytes { return k.keyBytes }
+
+// Parameters returns the parameters of this key.
+func (k *Key) Parameters() key.Parameters { return k.parameters }
+
+// IDRequirement returns (0, false): AES-KWP keys have no ID requirement.
+func (k *Key) IDRequirement() (uint32, bool) { return 0, false }
+
+// Equal returns whether this key object is equal to other.
+func (k *Key) Equal(other key.Key) bool {
+	that, ok := other.(*Key)
+	return ok && k.parameters.Equal(that.parameters) && k.keyBytes.Equal(that.keyBytes)
+}
+
+func createKey(p key.Parameters, idRequirement uint32) (key.Key, error) {
+	kwpParams, ok := p.(*Parameters)
+	if !ok {
+		return nil, fmt.Errorf("key is of type %T; needed *kwp.Parameters", p)
+	}
+	if idRequirement != 0 {
+		return nil, fmt.Errorf("kwp keys have no ID requirement, got %v", idRequirement)
+	}
+	keyBytes, err := secretdata.NewBytesFromRand(uint32(kwpParams.KeySizeInBytes()))
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(keyBytes, kwpParams)
+}
+
+// KeyCreator returns a key creator function.
+//
+// It is *NOT* part of the public API.
+func KeyCreator(t internalapi.Token) func(p key.Parameters, idRequirement uint32) (key.Key, error) {
+	return createKey
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kwp
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+	kwppb "github.com/tink-crypto/tink-go/v2/proto/aes_kwp_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const (
+	keyVersion = 0
+	typeURL    = "type.googleapis.com/google.crypto.tink.AesKwpKey"
+)
+
+var errInvalidKeyFormat = fmt.Errorf("aes_kwp_key_manager: invalid key format")
+
+// keyManager is an implementation of KeyManager interface.
+// It generates new AesKwpKey keys and produces new instances of AES-KWP.
+type keyManager struct{}
+
+// Assert that keyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*keyManager)(nil)
+
+// Primitive creates a [tink.KeyWrap] for the given serialized AesKwpKey proto.
+func (km *keyManager) Primitive(serializedKey []byte) (any, error) {
+	keySerialization, err := protoserialization.NewKeySerialization(&tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, tinkpb.OutputPrefixType_RAW, 0)
+	if err != nil {
+		return nil, err
+	}
+	key, err := protoserialization.ParseKey(keySerialization)
+	if err != nil {
+		return nil, err
+	}
+	kwpKey, ok := key.(*Key)
+	if !ok {
+		return nil, fmt.Errorf("aes_kwp_key_manager: invalid key type: got %T, want %T", key, (*Key)(nil))
+	}
+	ret, err := NewKeyWrap(kwpKey)
+	if err != nil {
+		return nil, fmt.Errorf("aes_kwp_key_manager: %v", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to the given serialized AesKwpKeyFormat.
+func (km *keyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidKeyFormat
+	}
+	keyFormat := new(kwppb.AesKwpKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidKeyFormat
+	}
+	if keyFormat.GetKeySize() != 16 && keyFormat.GetKeySize() != 32 {
+		return nil, fmt.Errorf("aes_kwp_key_manager: invalid key format: unsupported key size %d; want 16 or 32", keyFormat.GetKeySize())
+	}
+	return &kwppb.AesKwpKey{
+		Version:  keyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.GetKeySize()),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to the given serialized
+// AesKwpKeyFormat.
+// It should be used solely by the key management API.
+func (km *keyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serializedKey,
+		KeyMaterialType: km.KeyMaterialType(),
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *keyManager) DoesSupport(typeURL string) bool { return typeURL == km.TypeURL() }
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *keyManager) TypeURL() string { return typeURL }
+
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *keyManager) FIPSCompatible() bool { return true }
+
+// KeyMaterialType returns the key material type of the key manager.
+func (km *keyManager) KeyMaterialType() tinkpb.KeyData_KeyMaterialType {
+	return tinkpb.KeyData_SYMMETRIC
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kwp_test
+
+import (
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/kwp"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+)
+
+func TestNewParameters(t *testing.T) {
+	for _, size := range []int{16, 32} {
+		p, err := kwp.NewParameters(size)
+		if err != nil {
+			t.Fatalf("kwp.NewParameters(%d) err = %v, want nil", size, err)
+		}
+		if p.KeySizeInBytes() != size {
+			t.Errorf("p.KeySizeInBytes() = %d, want %d", p.KeySizeInBytes(), size)
+		}
+		if p.HasIDRequirement() {
+			t.Error("p.HasIDRequirement() = true, want false")
+		}
+	}
+	for _, size := range []int{0, 15, 24, 64} {
+		if _, err := kwp.NewParameters(size); err == nil {
+			t.Errorf("kwp.NewParameters(%d) err = nil, want error", size)
+		}
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	params, err := kwp.NewParameters(32)
+	if err != nil {
+		t.Fatalf("kwp.NewParameters() err = %v, want nil", err)
+	}
+	keyBytes := secretdata.NewBytesFromData(make([]byte, 32), insecuresecretdataaccess.Token{})
+	k, err := kwp.NewKey(keyBytes, params)
+	if err != nil {
+		t.Fatalf("kwp.NewKey() err = %v, want nil", err)
+	}
+	if !k.KeyBytes().Equal(keyBytes) {
+		t.Error("k.KeyBytes() != keyBytes")
+	}
+	if _, hasID := k.IDRequirement(); hasID {
+		t.Error("k.IDRequirement() has ID requirement, want none")
+	}
+	other, err := kwp.NewKey(keyBytes, params)
+	if err != nil {
+		t.Fatalf("kwp.NewKey() err = %v, want nil", err)
+	}
+	if !k.Equal(other) {
+		t.Error("k.Equal(other) = false, want true")
+	}
+	if _, err := kwp.NewKey(secretdata.NewBytesFromData(make([]byte, 16), insecuresecretdataaccess.Token{}), params); err == nil {
+		t.Error("kwp.NewKey() with wrong key size err = nil, want error")
+	}
+	if _, err := kwp.NewKey(keyBytes, nil); err == nil {
+		t.Error("kwp.NewKey() with nil parameters err = nil, want error")
+	}
+}
+
+func TestKeysetFromParameters(t *testing.T) {
+	params, err := kwp.NewParameters(16)
+	if err != nil {
+		t.Fatalf("kwp.NewParameters() err = %v, want nil", err)
+	}
+	km := keyset.NewManager()
+	keyID, err := km.AddNewKeyFromParameters(params)
+	if err != nil {
+		t.Fatalf("km.AddNewKeyFromParameters() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(keyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	handle, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	entry, err := handle.Primary()
+	if err != nil {
+		t.Fatalf("handle.Primary() err = %v, want nil", err)
+	}
+	if !entry.Key().Parameters().Equal(params) {
+		t.Errorf("entry.Key().Parameters() = %v, want %v", entry.Key().Parameters(), params)
+	}
+}
+
+func TestBuilderEntryFromParameters(t *testing.T) {
+	params, err := kwp.NewParameters(32)
+	if err != nil {
+		t.Fatalf("kwp.NewParameters() err = %v, want nil", err)
+	}
+	handle, err := keyset.NewBuilder().
+		AddEntry(keyset.NewBuilderEntryFromParameters(params).MakePrimary()).
+		Build()
+	if err != nil {
+		t.Fatalf("keyset.NewBuilder().Build() err = %v, want nil", err)
+	}
+	entry, err := handle.Primary()
+	if err != nil {
+		t.Fatalf("handle.Primary() err = %v, want nil", err)
+	}
+	if _, ok := entry.Key().(*kwp.Key); !ok {
+		t.Errorf("entry.Key() is of type %T, want *kwp.Key", entry.Key())
+	}
+	if !entry.Key().Parameters().Equal(params) {
+		t.Errorf("entry.Key().Parameters() = %v, want %v", entry.Key().Parameters(), params)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kwp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/primitiveset"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/kwp/subtle"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// NewKeyWrap creates a [tink.KeyWrap] primitive from the given [Key].
+func NewKeyWrap(k *Key) (tink.KeyWrap, error) {
+	w, err := subtle.NewKWP(k.KeyBytes().Data(insecuresecretdataaccess.Token{}))
+	if err != nil {
+		return nil, fmt.Errorf("kwp.NewKeyWrap: %v", err)
+	}
+	return w, nil
+}
+
+func primitiveConstructor(k key.Key) (any, error) {
+	that, ok := k.(*Key)
+	if !ok {
+		return nil, fmt.Errorf("key is of type %T; needed *kwp.Key", k)
+	}
+	return NewKeyWrap(that)
+}
+
+// New returns a [tink.KeyWrap] primitive from the given keyset handle.
+//
+// Keys are wrapped with the primary key. Unwrapping tries the primary key
+// first, then the other enabled keys in keyset order.
+func New(handle *keyset.Handle) (tink.KeyWrap, error) {
+	ps, err := keyset.Primitives[tink.KeyWrap](handle, internalapi.Token{})
+	if err != nil {
+		return nil, fmt.Errorf("kwp_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedKeyWrap(ps)
+}
+
+// wrappedKeyWrap is a KeyWrap implementation that uses the underlying
+// primitive set.
+type wrappedKeyWrap struct {
+	primary tink.KeyWrap
+	others  []tink.KeyWrap
+}
+
+var _ tink.KeyWrap = (*wrappedKeyWrap)(nil)
+
+func extractKeyWrap(entry *primitiveset.Entry[tink.KeyWrap]) tink.KeyWrap {
+	if entry.FullPrimitive != nil {
+		return entry.FullPrimitive
+	}
+	return entry.Primitive
+}
+
+func newWrappedKeyWrap(ps *primitiveset.PrimitiveSet[tink.KeyWrap]) (*wrappedKeyWrap, error) {
+	if ps.Primary == nil {
+		return nil, errors.New("kwp_factory: no primary key")
+	}
+	w := &wrappedKeyWrap{primary: extractKeyWrap(ps.Primary)}
+	for _, entry := range ps.EntriesInKeysetOrder {
+		if entry.KeyID == ps.Primary.KeyID {
+			continue
+		}
+		w.others = append(w.others, extractKeyWrap(entry))
+	}
+	return w, nil
+}
+
+func (w *wrappedKeyWrap) Wrap(key []byte) ([]byte, error) {
+	return w.primary.Wrap(key)
+}
+
+func (w *wrappedKeyWrap) Unwrap(wrapped []byte) ([]byte, error) {
+	if key, err := w.primary.Unwrap(wrapped); err == nil {
+		return key, nil
+	}
+	for _, other := range w.others {
+		if key, err := other.Unwrap(wrapped); err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("kwp_factory: unwrapping failed")
+}
+
+// KeyWrapAEAD adapts a [tink.KeyWrap] to the [tink.AEAD] and
+// [tink.AEADWithContext] interfaces, so that it can be used as a key
+// encryption AEAD, for example with [keyset.Handle.Write] or
+// aead.NewKMSEnvelopeAEAD2.
+//
+// Since key wrapping does not support associated data, encryption and
+// decryption fail if associatedData is not empty. Plaintexts must be between
+// 16 and 8192 bytes long.
+type KeyWrapAEAD struct {
+	keyWrap tink.KeyWrap
+}
+
+var (
+	_ tink.AEAD            = (*KeyWrapAEAD)(nil)
+	_ tink.AEADWithContext = (*KeyWrapAEAD)(nil)
+)
+
+// AsAEAD returns a [KeyWrapAEAD] that wraps and unwraps with w.
+func AsAEAD(w tink.KeyWrap) *KeyWrapAEAD {
+	return &KeyWrapAEAD{keyWrap: w}
+}
+
+var errAssociatedData = errors.New("kwp: associated data is not supported by key wrapping")
+
+// Encrypt wraps plaintext. associatedData must be empty.
+func (a *KeyWrapAEAD) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	if len(associatedData) != 0 {
+		return nil, errAssociatedData
+	}
+	return a.keyWrap.Wrap(plaintext)
+}
+
+// Decrypt unwraps ciphertext. associatedData must be empty.
+func (a *KeyWrapAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if len(associatedData) != 0 {
+		return nil, errAssociatedData
+	}
+	return a.keyWrap.Unwrap(ciphertext)
+}
+
+// EncryptWithContext wraps plaintext unless ctx is done. associatedData must
+// be empty.
+func (a *KeyWrapAEAD) EncryptWithContext(ctx context.Context, plaintext, associatedData []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.Encrypt(plaintext, associatedData)
+}
+
+// DecryptWithContext unwraps ciphertext unless ctx is done. associatedData
+// must be empty.
+func (a *KeyWrapAEAD) DecryptWithContext(ctx context.Context, ciphertext, associatedData []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.Decrypt(ciphertext, associatedData)
+}
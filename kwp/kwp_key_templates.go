@@ -0,0 +1,48 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kwp
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/internal/tinkerror"
+	kwppb "github.com/tink-crypto/tink-go/v2/proto/aes_kwp_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// AES128KWPKeyTemplate is a KeyTemplate that generates an AES-KWP key with a
+// 16-byte wrapping key.
+func AES128KWPKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESKWPKeyTemplate(16)
+}
+
+// AES256KWPKeyTemplate is a KeyTemplate that generates an AES-KWP key with a
+// 32-byte wrapping key.
+func AES256KWPKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESKWPKeyTemplate(32)
+}
+
+func createAESKWPKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
+	serializedFormat, err := proto.Marshal(&kwppb.AesKwpKeyFormat{KeySize: keySize})
+	if err != nil {
+		tinkerror.Fail(fmt.Sprintf("failed to marshal key format: %s", err))
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          typeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		Value:            serializedFormat,
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kwp_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/kwp"
+	"github.com/tink-crypto/tink-go/v2/kwp/subtle"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+func TestNewKeyWrapIsCompatibleWithRawKWP(t *testing.T) {
+	params, err := kwp.NewParameters(32)
+	if err != nil {
+		t.Fatalf("kwp.NewParameters() err = %v, want nil", err)
+	}
+	rawKey := random.GetRandomBytes(32)
+	k, err := kwp.NewKey(secretdata.NewBytesFromData(rawKey, insecuresecretdataaccess.Token{}), params)
+	if err != nil {
+		t.Fatalf("kwp.NewKey() err = %v, want nil", err)
+	}
+	w, err := kwp.NewKeyWrap(k)
+	if err != nil {
+		t.Fatalf("kwp.NewKeyWrap() err = %v, want nil", err)
+	}
+	raw, err := subtle.NewKWP(rawKey)
+	if err != nil {
+		t.Fatalf("subtle.NewKWP() err = %v, want nil", err)
+	}
+	msg := random.GetRandomBytes(20)
+	got, err := w.Wrap(msg)
+	if err != nil {
+		t.Fatalf("w.Wrap() err = %v, want nil", err)
+	}
+	want, err := raw.Wrap(msg)
+	if err != nil {
+		t.Fatalf("raw.Wrap() err = %v, want nil", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("w.Wrap() = %x, want %x", got, want)
+	}
+	unwrapped, err := w.Unwrap(want)
+	if err != nil {
+		t.Fatalf("w.Unwrap() err = %v, want nil", err)
+	}
+	if !bytes.Equal(unwrapped, msg) {
+		t.Errorf("w.Unwrap() = %x, want %x", unwrapped, msg)
+	}
+}
+
+func newKeyWrap(t *testing.T, handle *keyset.Handle) tink.KeyWrap {
+	t.Helper()
+	w, err := kwp.New(handle)
+	if err != nil {
+		t.Fatalf("kwp.New() err = %v, want nil", err)
+	}
+	return w
+}
+
+func TestFactoryWrapUnwrapWithRotatedKeyset(t *testing.T) {
+	km := keyset.NewManager()
+	oldKeyID, err := km.Add(kwp.AES128KWPKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(oldKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	oldHandle, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+	newKeyID, err := km.Add(kwp.AES256KWPKeyTemplate())
+	if err != nil {
+		t.Fatalf("km.Add() err = %v, want nil", err)
+	}
+	if err := km.SetPrimary(newKeyID); err != nil {
+		t.Fatalf("km.SetPrimary() err = %v, want nil", err)
+	}
+	handle, err := km.Handle()
+	if err != nil {
+		t.Fatalf("km.Handle() err = %v, want nil", err)
+	}
+
+	key := random.GetRandomBytes(32)
+	oldWrapped, err := newKeyWrap(t, oldHandle).Wrap(key)
+	if err != nil {
+		t.Fatalf("Wrap() err = %v, want nil", err)
+	}
+	w := newKeyWrap(t, handle)
+	wrapped, err := w.Wrap(key)
+	if err != nil {
+		t.Fatalf("Wrap() err = %v, want nil", err)
+	}
+	if len(wrapped) != len(key)+8 {
+		t.Errorf("len(wrapped) = %d, want %d (no prefix)", len(wrapped), len(key)+8)
+	}
+	for _, c := range [][]byte{oldWrapped, wrapped} {
+		got, err := w.Unwrap(c)
+		if err != nil {
+			t.Fatalf("Unwrap() err = %v, want nil", err)
+		}
+		if !bytes.Equal(got, key) {
+			t.Errorf("Unwrap() = %x, want %x", got, key)
+		}
+	}
+	if _, err := newKeyWrap(t, oldHandle).Unwrap(wrapped); err == nil {
+		t.Error("Unwrap() with old keyset err = nil, want error")
+	}
+}
+
+func TestAsAEADWrapsKeysetHandle(t *testing.T) {
+	kekHandle, err := keyset.NewHandle(kwp.AES256KWPKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	kek := kwp.AsAEAD(newKeyWrap(t, kekHandle))
+
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := handle.Write(keyset.NewBinaryWriter(buf), kek); err != nil {
+		t.Fatalf("handle.Write() err = %v, want nil", err)
+	}
+	got, err := keyset.ReadWithContext(context.Background(), keyset.NewBinaryReader(buf), kek, nil)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithContext() err = %v, want nil", err)
+	}
+	if got.KeysetInfo().GetPrimaryKeyId() != handle.KeysetInfo().GetPrimaryKeyId() {
+		t.Error("keyset read back has a different primary key")
+	}
+	if err := handle.WriteWithAssociatedData(keyset.NewBinaryWriter(new(bytes.Buffer)), kek, []byte("associatedData")); err == nil {
+		t.Error("handle.WriteWithAssociatedData() with associated data err = nil, want error")
+	}
+}
+
+func TestAsAEADWrapsEnvelopeDEKs(t *testing.T) {
+	kekHandle, err := keyset.NewHandle(kwp.AES128KWPKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	kek := kwp.AsAEAD(newKeyWrap(t, kekHandle))
+	a, err := aead.NewKMSEnvelopeAEADWithContext(aead.AES256GCMKeyTemplate(), kek)
+	if err != nil {
+		t.Fatalf("aead.NewKMSEnvelopeAEADWithContext() err = %v, want nil", err)
+	}
+	plaintext := []byte("plaintext")
+	ciphertext, err := a.EncryptWithContext(context.Background(), plaintext, nil)
+	if err != nil {
+		t.Fatalf("a.EncryptWithContext() err = %v, want nil", err)
+	}
+	got, err := a.DecryptWithContext(context.Background(), ciphertext, nil)
+	if err != nil {
+		t.Fatalf("a.DecryptWithContext() err = %v, want nil", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("a.DecryptWithContext() = %q, want %q", got, plaintext)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.EncryptWithContext(ctx, plaintext, nil); err == nil {
+		t.Error("a.EncryptWithContext() with canceled context err = nil, want error")
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kwp
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
+	"github.com/tink-crypto/tink-go/v2/internal/protoserialization"
+	"github.com/tink-crypto/tink-go/v2/key"
+	"github.com/tink-crypto/tink-go/v2/secretdata"
+	kwppb "github.com/tink-crypto/tink-go/v2/proto/aes_kwp_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const (
+	// protoVersion is the accepted [kwppb.AesKwpKey] proto version.
+	//
+	// Currently, only version 0 is supported; other versions are rejected.
+	protoVersion = 0
+)
+
+type keySerializer struct{}
+
+var _ protoserialization.KeySerializer = (*keySerializer)(nil)
+
+func (s *keySerializer) SerializeKey(key key.Key) (*protoserialization.KeySerialization, error) {
+	actualKey, ok := key.(*Key)
+	if !ok {
+		return nil, fmt.Errorf("key is not a Key")
+	}
+	protoKey := &kwppb.AesKwpKey{
+		KeyValue: actualKey.KeyBytes().Data(insecuresecretdataaccess.Token{}),
+		Version:  protoVersion,
+	}
+	serializedKey, err := proto.Marshal(protoKey)
+	if err != nil {
+		return nil, err
+	}
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}
+	return protoserialization.NewKeySerialization(keyData, tinkpb.OutputPrefixType_RAW, 0)
+}
+
+type keyParser struct{}
+
+var _ protoserialization.KeyParser = (*keyParser)(nil)
+
+func (s *keyParser) ParseKey(keySerialization *protoserialization.KeySerialization) (key.Key, error) {
+	if keySerialization == nil {
+		return nil, fmt.Errorf("key serialization is nil")
+	}
+	keyData := keySerialization.KeyData()
+	if keyData.GetTypeUrl() != typeURL {
+		return nil, fmt.Errorf("key is not an AES-KWP key")
+	}
+	if keyData.GetKeyMaterialType() != tinkpb.KeyData_SYMMETRIC {
+		return nil, fmt.Errorf("key is not a SYMMETRIC key")
+	}
+	if keySerialization.OutputPrefixType() != tinkpb.OutputPrefixType_RAW {
+		return nil, fmt.Errorf("unsupported output prefix type: %v", keySerialization.OutputPrefixType())
+	}
+	protoKey := new(kwppb.AesKwpKey)
+	if err := proto.Unmarshal(keyData.GetValue(), protoKey); err != nil {
+		return nil, err
+	}
+	if protoKey.GetVersion() != protoVersion {
+		return nil, fmt.Errorf("key has unsupported version: %v", protoKey.GetVersion())
+	}
+	params, err := NewParameters(len(protoKey.GetKeyValue()))
+	if err != nil {
+		return nil, err
+	}
+	keyMaterial := secretdata.NewBytesFromData(protoKey.GetKeyValue(), insecuresecretdataaccess.Token{})
+	return NewKey(keyMaterial, params)
+}
+
+type parametersSerializer struct{}
+
+var _ protoserialization.ParametersSerializer = (*parametersSerializer)(nil)
+
+func (s *parametersSerializer) Serialize(parameters key.Parameters) (*tinkpb.KeyTemplate, error) {
+	actualParameters, ok := parameters.(*Parameters)
+	if !ok {
+		return nil, fmt.Errorf("invalid parameters type: got %T, want *kwp.Parameters", parameters)
+	}
+	serializedFormat, err := proto.Marshal(&kwppb.AesKwpKeyFormat{
+		KeySize: uint32(actualParameters.KeySizeInBytes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          typeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		Value:            serializedFormat,
+	}, nil
+}
+
+type parametersParser struct{}
+
+var _ protoserialization.ParametersParser = (*parametersParser)(nil)
+
+func (s *parametersParser) Parse(keyTemplate *tinkpb.KeyTemplate) (key.Parameters, error) {
+	if keyTemplate.GetTypeUrl() != typeURL {
+		return nil, fmt.Errorf("invalid type URL: got %q, want %q", keyTemplate.GetTypeUrl(), typeURL)
+	}
+	if keyTemplate.GetOutputPrefixType() != tinkpb.OutputPrefixType_RAW {
+		return nil, fmt.Errorf("unsupported output prefix type: %v", keyTemplate.GetOutputPrefixType())
+	}
+	format := new(kwppb.AesKwpKeyFormat)
+	if err := proto.Unmarshal(keyTemplate.GetValue(), format); err != nil {
+		return nil, err
+	}
+	if format.GetVersion() != 0 {
+		return nil, fmt.Errorf("unsupported kwppb.AesKwpKeyFormat version: got %q, want %q", format.GetVersion(), 0)
+	}
+	return NewParameters(int(format.GetKeySize()))
+}
@@ -105,6 +105,12 @@ func (km *aescmacKeyManager) TypeURL() string {
 	return cmacTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *aescmacKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 // validateKey validates the given AesCmacKey. It only validates the version of the
 // key because other parameters will be validated in primitive construction.
 func (km *aescmacKeyManager) validateKey(key *cmacpb.AesCmacKey) error {
@@ -108,6 +108,12 @@ func (km *hmacKeyManager) TypeURL() string {
 	return hmacTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *hmacKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 // KeyMaterialType returns the key material type of this key manager.
 func (km *hmacKeyManager) KeyMaterialType() tinkpb.KeyData_KeyMaterialType {
 	return tinkpb.KeyData_SYMMETRIC
@@ -0,0 +1,222 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/core/cryptofmt"
+	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/primitiveset"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/monitoring"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+// streamingMACPrimitive is a MAC that can also compute MACs incrementally.
+type streamingMACPrimitive interface {
+	tink.MAC
+	NewComputer() (tink.MACComputer, error)
+}
+
+// NewStreamingMAC creates a StreamingMAC primitive from the given keyset handle.
+//
+// The keyset must only contain keys that support incremental computation,
+// which is the case for HMAC and AES-CMAC keys.
+func NewStreamingMAC(handle *keyset.Handle) (tink.StreamingMAC, error) {
+	ps, err := keyset.Primitives[streamingMACPrimitive](handle, internalapi.Token{})
+	if err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedStreamingMAC(ps)
+}
+
+// wrappedStreamingMAC is a StreamingMAC implementation that uses the underlying
+// primitive set to compute and verify MACs.
+type wrappedStreamingMAC struct {
+	ps            *primitiveset.PrimitiveSet[streamingMACPrimitive]
+	computeLogger monitoring.Logger
+	verifyLogger  monitoring.Logger
+}
+
+var _ tink.StreamingMAC = (*wrappedStreamingMAC)(nil)
+
+func newWrappedStreamingMAC(ps *primitiveset.PrimitiveSet[streamingMACPrimitive]) (*wrappedStreamingMAC, error) {
+	computeLogger, verifyLogger, err := createLoggers(ps)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedStreamingMAC{
+		ps:            ps,
+		computeLogger: computeLogger,
+		verifyLogger:  verifyLogger,
+	}, nil
+}
+
+// NewComputer returns a MACComputer that uses the primary primitive. The MAC
+// returned by Finish is the concatenation of the primary's identifier and the
+// calculated mac.
+func (m *wrappedStreamingMAC) NewComputer() (tink.MACComputer, error) {
+	primary := m.ps.Primary
+	computer, err := primary.Primitive.NewComputer()
+	if err != nil {
+		m.computeLogger.LogFailure()
+		return nil, err
+	}
+	return &wrappedMACComputer{
+		computer: computer,
+		entry:    primary,
+		logger:   m.computeLogger,
+	}, nil
+}
+
+// NewVerifier returns a MACVerifier that checks mac against all keys that match
+// its prefix, and against all raw keys.
+func (m *wrappedStreamingMAC) NewVerifier(mac []byte) (tink.MACVerifier, error) {
+	// This also rejects raw MAC with size of 4 bytes or fewer. Those MACs are
+	// clearly insecure, thus should be discouraged.
+	prefixSize := cryptofmt.NonRawPrefixSize
+	if len(mac) <= prefixSize {
+		m.verifyLogger.LogFailure()
+		return nil, errInvalidMAC
+	}
+	var candidates []*macCandidate
+	// try non raw keys
+	prefix := mac[:prefixSize]
+	macNoPrefix := mac[prefixSize:]
+	entries, err := m.ps.EntriesForPrefix(string(prefix))
+	if err == nil {
+		for _, entry := range entries {
+			computer, err := entry.Primitive.NewComputer()
+			if err != nil {
+				m.verifyLogger.LogFailure()
+				return nil, err
+			}
+			candidates = append(candidates, &macCandidate{computer: computer, entry: entry, mac: macNoPrefix})
+		}
+	}
+	// try raw keys
+	entries, err = m.ps.RawEntries()
+	if err == nil {
+		for _, entry := range entries {
+			computer, err := entry.Primitive.NewComputer()
+			if err != nil {
+				m.verifyLogger.LogFailure()
+				return nil, err
+			}
+			candidates = append(candidates, &macCandidate{computer: computer, entry: entry, mac: mac})
+		}
+	}
+	return &wrappedMACVerifier{
+		candidates: candidates,
+		logger:     m.verifyLogger,
+	}, nil
+}
+
+// finishEntry finishes computer, taking care of the legacy prefix handling of entry.
+func finishEntry(computer tink.MACComputer, entry *primitiveset.Entry[streamingMACPrimitive]) ([]byte, error) {
+	if entry.PrefixType == tinkpb.OutputPrefixType_LEGACY {
+		if _, err := computer.Write([]byte{0}); err != nil {
+			return nil, err
+		}
+	}
+	return computer.Finish()
+}
+
+type wrappedMACComputer struct {
+	computer tink.MACComputer
+	entry    *primitiveset.Entry[streamingMACPrimitive]
+	logger   monitoring.Logger
+	written  int
+	finished bool
+}
+
+func (c *wrappedMACComputer) Write(data []byte) (int, error) {
+	if c.finished {
+		return 0, fmt.Errorf("mac_factory: computer already finished")
+	}
+	n, err := c.computer.Write(data)
+	c.written += n
+	return n, err
+}
+
+func (c *wrappedMACComputer) Finish() ([]byte, error) {
+	if c.finished {
+		return nil, fmt.Errorf("mac_factory: computer already finished")
+	}
+	c.finished = true
+	mac, err := finishEntry(c.computer, c.entry)
+	if err != nil {
+		c.logger.LogFailure()
+		return nil, err
+	}
+	c.logger.Log(c.entry.KeyID, c.written)
+	if len(c.entry.Prefix) == 0 {
+		return mac, nil
+	}
+	output := make([]byte, 0, len(c.entry.Prefix)+len(mac))
+	output = append(output, c.entry.Prefix...)
+	output = append(output, mac...)
+	return output, nil
+}
+
+// macCandidate is a key that might have computed the MAC being verified.
+type macCandidate struct {
+	computer tink.MACComputer
+	entry    *primitiveset.Entry[streamingMACPrimitive]
+	mac      []byte
+}
+
+type wrappedMACVerifier struct {
+	candidates []*macCandidate
+	logger     monitoring.Logger
+	written    int
+	finished   bool
+}
+
+func (v *wrappedMACVerifier) Write(data []byte) (int, error) {
+	if v.finished {
+		return 0, fmt.Errorf("mac_factory: verifier already finished")
+	}
+	for _, c := range v.candidates {
+		if _, err := c.computer.Write(data); err != nil {
+			return 0, err
+		}
+	}
+	v.written += len(data)
+	return len(data), nil
+}
+
+func (v *wrappedMACVerifier) Verify() error {
+	if v.finished {
+		return fmt.Errorf("mac_factory: verifier already finished")
+	}
+	v.finished = true
+	for _, c := range v.candidates {
+		computed, err := finishEntry(c.computer, c.entry)
+		if err != nil {
+			continue
+		}
+		if subtle.ConstantTimeCompare(computed, c.mac) == 1 {
+			v.logger.Log(c.entry.KeyID, v.written)
+			return nil
+		}
+	}
+	// nothing worked
+	v.logger.LogFailure()
+	return errInvalidMAC
+}
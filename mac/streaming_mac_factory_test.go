@@ -0,0 +1,252 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mac_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tink-crypto/tink-go/v2/insecurecleartextkeyset"
+	"github.com/tink-crypto/tink-go/v2/internal/internalregistry"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/monitoring"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+	"github.com/tink-crypto/tink-go/v2/testing/fakemonitoring"
+	"github.com/tink-crypto/tink-go/v2/tink"
+
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func computeStreaming(t *testing.T, p tink.StreamingMAC, data []byte, chunkSize int) []byte {
+	t.Helper()
+	computer, err := p.NewComputer()
+	if err != nil {
+		t.Fatalf("p.NewComputer() err = %v, want nil", err)
+	}
+	for i := 0; i < len(data); i += chunkSize {
+		if _, err := computer.Write(data[i:min(i+chunkSize, len(data))]); err != nil {
+			t.Fatalf("computer.Write() err = %v, want nil", err)
+		}
+	}
+	tag, err := computer.Finish()
+	if err != nil {
+		t.Fatalf("computer.Finish() err = %v, want nil", err)
+	}
+	return tag
+}
+
+func verifyStreaming(t *testing.T, p tink.StreamingMAC, tag, data []byte) error {
+	t.Helper()
+	verifier, err := p.NewVerifier(tag)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(verifier, bytes.NewReader(data)); err != nil {
+		t.Fatalf("io.Copy() err = %v, want nil", err)
+	}
+	return verifier.Verify()
+}
+
+func TestStreamingMACMatchesMAC(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"HMAC", mac.HMACSHA256Tag256KeyTemplate()},
+		{"AES-CMAC", mac.AESCMACTag128KeyTemplate()},
+	} {
+		for _, prefixType := range []tinkpb.OutputPrefixType{
+			tinkpb.OutputPrefixType_TINK,
+			tinkpb.OutputPrefixType_RAW,
+			tinkpb.OutputPrefixType_LEGACY,
+			tinkpb.OutputPrefixType_CRUNCHY,
+		} {
+			t.Run(tc.name+"_"+prefixType.String(), func(t *testing.T) {
+				template := tc.template
+				template.OutputPrefixType = prefixType
+				handle, err := keyset.NewHandle(template)
+				if err != nil {
+					t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+				}
+				p, err := mac.New(handle)
+				if err != nil {
+					t.Fatalf("mac.New() err = %v, want nil", err)
+				}
+				sp, err := mac.NewStreamingMAC(handle)
+				if err != nil {
+					t.Fatalf("mac.NewStreamingMAC() err = %v, want nil", err)
+				}
+				data := random.GetRandomBytes(1000)
+				want, err := p.ComputeMAC(data)
+				if err != nil {
+					t.Fatalf("p.ComputeMAC() err = %v, want nil", err)
+				}
+				got := computeStreaming(t, sp, data, 33)
+				if !bytes.Equal(got, want) {
+					t.Errorf("streaming MAC = %x, want %x", got, want)
+				}
+				if err := verifyStreaming(t, sp, want, data); err != nil {
+					t.Errorf("verifyStreaming() err = %v, want nil", err)
+				}
+				if err := p.VerifyMAC(got, data); err != nil {
+					t.Errorf("p.VerifyMAC() err = %v, want nil", err)
+				}
+				if err := verifyStreaming(t, sp, want, data[1:]); err == nil {
+					t.Errorf("verifyStreaming() with modified data err = nil, want error")
+				}
+			})
+		}
+	}
+}
+
+func TestStreamingMACVerifiesWithNonPrimaryKeys(t *testing.T) {
+	manager := keyset.NewManager()
+	rawTemplate := mac.HMACSHA256Tag256KeyTemplate()
+	rawTemplate.OutputPrefixType = tinkpb.OutputPrefixType_RAW
+	oldKeyID, err := manager.Add(rawTemplate)
+	if err != nil {
+		t.Fatalf("manager.Add() err = %v, want nil", err)
+	}
+	if err := manager.SetPrimary(oldKeyID); err != nil {
+		t.Fatalf("manager.SetPrimary() err = %v, want nil", err)
+	}
+	oldHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v, want nil", err)
+	}
+	newKeyID, err := manager.Add(mac.AESCMACTag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("manager.Add() err = %v, want nil", err)
+	}
+	if err := manager.SetPrimary(newKeyID); err != nil {
+		t.Fatalf("manager.SetPrimary() err = %v, want nil", err)
+	}
+	newHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v, want nil", err)
+	}
+	oldMAC, err := mac.NewStreamingMAC(oldHandle)
+	if err != nil {
+		t.Fatalf("mac.NewStreamingMAC() err = %v, want nil", err)
+	}
+	newMAC, err := mac.NewStreamingMAC(newHandle)
+	if err != nil {
+		t.Fatalf("mac.NewStreamingMAC() err = %v, want nil", err)
+	}
+	data := []byte("data")
+	if err := verifyStreaming(t, newMAC, computeStreaming(t, oldMAC, data, 1), data); err != nil {
+		t.Errorf("verifyStreaming() with old key err = %v, want nil", err)
+	}
+	if err := verifyStreaming(t, oldMAC, computeStreaming(t, newMAC, data, 1), data); err == nil {
+		t.Errorf("verifyStreaming() with unknown key err = nil, want error")
+	}
+}
+
+func TestStreamingMACFailures(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	p, err := mac.NewStreamingMAC(handle)
+	if err != nil {
+		t.Fatalf("mac.NewStreamingMAC() err = %v, want nil", err)
+	}
+	if _, err := p.NewVerifier([]byte{1, 2, 3, 4, 5}); err == nil {
+		t.Errorf("p.NewVerifier() with short mac err = nil, want error")
+	}
+	computer, err := p.NewComputer()
+	if err != nil {
+		t.Fatalf("p.NewComputer() err = %v, want nil", err)
+	}
+	if _, err := computer.Finish(); err != nil {
+		t.Fatalf("computer.Finish() err = %v, want nil", err)
+	}
+	if _, err := computer.Write([]byte("data")); err == nil {
+		t.Errorf("computer.Write() after Finish() err = nil, want error")
+	}
+	if _, err := computer.Finish(); err == nil {
+		t.Errorf("computer.Finish() after Finish() err = nil, want error")
+	}
+
+	signatureHandle, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	if _, err := mac.NewStreamingMAC(signatureHandle); err == nil {
+		t.Errorf("mac.NewStreamingMAC() with signature keyset err = nil, want error")
+	}
+}
+
+func TestStreamingMACMonitoringLogsTotalBytes(t *testing.T) {
+	defer internalregistry.ClearMonitoringClient()
+	client := fakemonitoring.NewClient("fake-client")
+	if err := internalregistry.RegisterMonitoringClient(client); err != nil {
+		t.Fatalf("internalregistry.RegisterMonitoringClient() err = %v, want nil", err)
+	}
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	// Annotations are only supported throught the `insecurecleartextkeyset` API.
+	buff := &bytes.Buffer{}
+	if err := insecurecleartextkeyset.Write(kh, keyset.NewBinaryWriter(buff)); err != nil {
+		t.Fatalf("insecurecleartextkeyset.Write() err = %v, want nil", err)
+	}
+	annotations := map[string]string{"foo": "bar"}
+	mh, err := insecurecleartextkeyset.Read(keyset.NewBinaryReader(buff), keyset.WithAnnotations(annotations))
+	if err != nil {
+		t.Fatalf("insecurecleartextkeyset.Read() err = %v, want nil", err)
+	}
+	p, err := mac.NewStreamingMAC(mh)
+	if err != nil {
+		t.Fatalf("mac.NewStreamingMAC() err = %v, want nil", err)
+	}
+	data := random.GetRandomBytes(100)
+	tag := computeStreaming(t, p, data, 7)
+	if err := verifyStreaming(t, p, tag, data); err != nil {
+		t.Fatalf("verifyStreaming() err = %v, want nil", err)
+	}
+	wantKeysetInfo := monitoring.NewKeysetInfo(
+		annotations,
+		kh.KeysetInfo().GetPrimaryKeyId(),
+		[]*monitoring.Entry{
+			{
+				KeyID:     kh.KeysetInfo().GetPrimaryKeyId(),
+				Status:    monitoring.Enabled,
+				KeyType:   "tink.HmacKey",
+				KeyPrefix: "TINK",
+			},
+		},
+	)
+	want := []*fakemonitoring.LogEvent{
+		&fakemonitoring.LogEvent{
+			Context:  monitoring.NewContext("mac", "compute", wantKeysetInfo),
+			KeyID:    kh.KeysetInfo().GetPrimaryKeyId(),
+			NumBytes: len(data),
+		},
+		&fakemonitoring.LogEvent{
+			Context:  monitoring.NewContext("mac", "verify", wantKeysetInfo),
+			KeyID:    kh.KeysetInfo().GetPrimaryKeyId(),
+			NumBytes: len(data),
+		},
+	}
+	if got := client.Events(); !cmp.Equal(got, want) {
+		t.Errorf("got = %v, want = %v, with diff: %v", got, want, cmp.Diff(got, want))
+	}
+}
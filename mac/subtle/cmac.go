@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/tink-crypto/tink-go/v2/internal/mac/aescmac"
+	"github.com/tink-crypto/tink-go/v2/tink"
 
 	// Placeholder for internal crypto/subtle allowlist, please ignore.
 )
@@ -69,6 +70,32 @@ func (a AESCMAC) VerifyMAC(mac, data []byte) error {
 	return nil
 }
 
+// NewComputer returns a tink.MACComputer that computes the same MAC as
+// ComputeMAC, but over data that is written to it incrementally.
+func (a AESCMAC) NewComputer() (tink.MACComputer, error) {
+	return &aesCMACComputer{
+		computation: a.cmac.NewComputation(),
+		tagLength:   a.tagLength,
+	}, nil
+}
+
+type aesCMACComputer struct {
+	computation *aescmac.Computation
+	tagLength   uint32
+}
+
+func (c *aesCMACComputer) Write(data []byte) (int, error) {
+	return c.computation.Write(data)
+}
+
+func (c *aesCMACComputer) Finish() ([]byte, error) {
+	tag, err := c.computation.Finish()
+	if err != nil {
+		return nil, err
+	}
+	return tag[:c.tagLength], nil
+}
+
 // ValidateCMACParams validates the parameters for an AES-CMAC against the
 // recommended parameters.
 func ValidateCMACParams(keySize, tagSize uint32) error {
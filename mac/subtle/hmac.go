@@ -20,6 +20,7 @@ import (
 	"hash"
 
 	"github.com/tink-crypto/tink-go/v2/internal/mac/hmac"
+	"github.com/tink-crypto/tink-go/v2/tink"
 )
 
 var errHMACInvalidInput = errors.New("HMAC: invalid input")
@@ -55,3 +56,9 @@ func (h *HMAC) ComputeMAC(data []byte) ([]byte, error) {
 func (h *HMAC) VerifyMAC(mac []byte, data []byte) error {
 	return h.hmac.VerifyMAC(mac, data)
 }
+
+// NewComputer returns a tink.MACComputer that computes the same MAC as
+// ComputeMAC, but over data that is written to it incrementally.
+func (h *HMAC) NewComputer() (tink.MACComputer, error) {
+	return h.hmac.NewComputation()
+}
@@ -100,6 +100,12 @@ func (km *aescmacprfKeyManager) TypeURL() string {
 	return aescmacprfTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *aescmacprfKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 // validateKey validates the given AESCMACPRFKey. It only validates the version of the
 // key because other parameters will be validated in primitive construction.
 func (km *aescmacprfKeyManager) validateKey(key *cmacpb.AesCmacPrfKey) error {
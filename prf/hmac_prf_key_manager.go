@@ -107,6 +107,12 @@ func (km *hmacprfKeyManager) TypeURL() string {
 	return hmacprfTypeURL
 }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *hmacprfKeyManager) FIPSCompatible() bool {
+	return true
+}
+
 // validateKey validates the given HMACPRFKey. It only validates the version of the
 // key because other parameters will be validated in primitive construction.
 func (km *hmacprfKeyManager) validateKey(key *hmacpb.HmacPrfKey) error {
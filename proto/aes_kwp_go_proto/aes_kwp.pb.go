@@ -0,0 +1,217 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.0
+// 	protoc        (unknown)
+// source: third_party/tink/proto/aes_kwp.proto
+
+package aes_kwp_go_proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AES-KWP key wrapping (NIST SP 800-38F, RFC 5649).
+type AesKwpKeyFormat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only valid values are: 16 and 32.
+	KeySize       uint32 `protobuf:"varint,1,opt,name=key_size,json=keySize,proto3" json:"key_size,omitempty"`
+	Version       uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AesKwpKeyFormat) Reset() {
+	*x = AesKwpKeyFormat{}
+	mi := &file_third_party_tink_proto_aes_kwp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AesKwpKeyFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AesKwpKeyFormat) ProtoMessage() {}
+
+func (x *AesKwpKeyFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_aes_kwp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AesKwpKeyFormat.ProtoReflect.Descriptor instead.
+func (*AesKwpKeyFormat) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_aes_kwp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AesKwpKeyFormat) GetKeySize() uint32 {
+	if x != nil {
+		return x.KeySize
+	}
+	return 0
+}
+
+func (x *AesKwpKeyFormat) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type AesKwpKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	KeyValue      []byte                 `protobuf:"bytes,2,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"` // Placeholder for ctype and debug_redact.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AesKwpKey) Reset() {
+	*x = AesKwpKey{}
+	mi := &file_third_party_tink_proto_aes_kwp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AesKwpKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AesKwpKey) ProtoMessage() {}
+
+func (x *AesKwpKey) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_aes_kwp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AesKwpKey.ProtoReflect.Descriptor instead.
+func (*AesKwpKey) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_aes_kwp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AesKwpKey) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *AesKwpKey) GetKeyValue() []byte {
+	if x != nil {
+		return x.KeyValue
+	}
+	return nil
+}
+
+var File_third_party_tink_proto_aes_kwp_proto protoreflect.FileDescriptor
+
+var file_third_party_tink_proto_aes_kwp_proto_rawDesc = []byte{
+	0x0a, 0x24, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x2f, 0x74, 0x69,
+	0x6e, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x65, 0x73, 0x5f, 0x6b, 0x77, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69, 0x6e, 0x6b, 0x22, 0x46, 0x0a, 0x0f, 0x41, 0x65,
+	0x73, 0x4b, 0x77, 0x70, 0x4b, 0x65, 0x79, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x6b, 0x65, 0x79, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x22, 0x42, 0x0a, 0x09, 0x41, 0x65, 0x73, 0x4b, 0x77, 0x70, 0x4b, 0x65, 0x79, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x6b, 0x65, 0x79,
+	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6b, 0x65,
+	0x79, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x5a, 0x0a, 0x1c, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69, 0x6e, 0x6b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2d, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f,
+	0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2d, 0x67, 0x6f, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x61, 0x65, 0x73, 0x5f, 0x6b, 0x77, 0x70, 0x5f, 0x67, 0x6f, 0x5f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_third_party_tink_proto_aes_kwp_proto_rawDescOnce sync.Once
+	file_third_party_tink_proto_aes_kwp_proto_rawDescData = file_third_party_tink_proto_aes_kwp_proto_rawDesc
+)
+
+func file_third_party_tink_proto_aes_kwp_proto_rawDescGZIP() []byte {
+	file_third_party_tink_proto_aes_kwp_proto_rawDescOnce.Do(func() {
+		file_third_party_tink_proto_aes_kwp_proto_rawDescData = protoimpl.X.CompressGZIP(file_third_party_tink_proto_aes_kwp_proto_rawDescData)
+	})
+	return file_third_party_tink_proto_aes_kwp_proto_rawDescData
+}
+
+var file_third_party_tink_proto_aes_kwp_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_third_party_tink_proto_aes_kwp_proto_goTypes = []any{
+	(*AesKwpKeyFormat)(nil), // 0: google.crypto.tink.AesKwpKeyFormat
+	(*AesKwpKey)(nil),       // 1: google.crypto.tink.AesKwpKey
+}
+var file_third_party_tink_proto_aes_kwp_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_third_party_tink_proto_aes_kwp_proto_init() }
+func file_third_party_tink_proto_aes_kwp_proto_init() {
+	if File_third_party_tink_proto_aes_kwp_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_third_party_tink_proto_aes_kwp_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_third_party_tink_proto_aes_kwp_proto_goTypes,
+		DependencyIndexes: file_third_party_tink_proto_aes_kwp_proto_depIdxs,
+		MessageInfos:      file_third_party_tink_proto_aes_kwp_proto_msgTypes,
+	}.Build()
+	File_third_party_tink_proto_aes_kwp_proto = out.File
+	file_third_party_tink_proto_aes_kwp_proto_rawDesc = nil
+	file_third_party_tink_proto_aes_kwp_proto_goTypes = nil
+	file_third_party_tink_proto_aes_kwp_proto_depIdxs = nil
+}
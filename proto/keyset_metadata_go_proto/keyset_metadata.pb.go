@@ -0,0 +1,247 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.0
+// 	protoc        (unknown)
+// source: third_party/tink/proto/keyset_metadata.proto
+
+package keyset_metadata_go_proto
+
+import (
+	tink_go_proto "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Information about the keys of a google.crypto.tink.Keyset that Tink Go keeps
+// next to the keyset. It doesn't contain any key material.
+type KeysetMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []*KeysetMetadata_Key  `protobuf:"bytes,1,rep,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeysetMetadata) Reset() {
+	*x = KeysetMetadata{}
+	mi := &file_third_party_tink_proto_keyset_metadata_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeysetMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeysetMetadata) ProtoMessage() {}
+
+func (x *KeysetMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_keyset_metadata_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeysetMetadata.ProtoReflect.Descriptor instead.
+func (*KeysetMetadata) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_keyset_metadata_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KeysetMetadata) GetKey() []*KeysetMetadata_Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type KeysetMetadata_Key struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifies the key within the keyset.
+	KeyId uint32 `protobuf:"varint,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// Time at which the key was added to the keyset, in seconds since the
+	// Unix epoch. Zero if unknown.
+	CreationTime int64 `protobuf:"varint,2,opt,name=creation_time,json=creationTime,proto3" json:"creation_time,omitempty"`
+	// Time at which the key was last made primary, in seconds since the Unix
+	// epoch. Zero if the key has never been primary or the time is unknown.
+	ActivationTime int64 `protobuf:"varint,3,opt,name=activation_time,json=activationTime,proto3" json:"activation_time,omitempty"`
+	// Parameters of a DESTROYED key, whose key material has been removed from
+	// the keyset.
+	DestroyedKeyParameters *tink_go_proto.KeyTemplate `protobuf:"bytes,4,opt,name=destroyed_key_parameters,json=destroyedKeyParameters,proto3" json:"destroyed_key_parameters,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *KeysetMetadata_Key) Reset() {
+	*x = KeysetMetadata_Key{}
+	mi := &file_third_party_tink_proto_keyset_metadata_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeysetMetadata_Key) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeysetMetadata_Key) ProtoMessage() {}
+
+func (x *KeysetMetadata_Key) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_keyset_metadata_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeysetMetadata_Key.ProtoReflect.Descriptor instead.
+func (*KeysetMetadata_Key) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_keyset_metadata_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *KeysetMetadata_Key) GetKeyId() uint32 {
+	if x != nil {
+		return x.KeyId
+	}
+	return 0
+}
+
+func (x *KeysetMetadata_Key) GetCreationTime() int64 {
+	if x != nil {
+		return x.CreationTime
+	}
+	return 0
+}
+
+func (x *KeysetMetadata_Key) GetActivationTime() int64 {
+	if x != nil {
+		return x.ActivationTime
+	}
+	return 0
+}
+
+func (x *KeysetMetadata_Key) GetDestroyedKeyParameters() *tink_go_proto.KeyTemplate {
+	if x != nil {
+		return x.DestroyedKeyParameters
+	}
+	return nil
+}
+
+var File_third_party_tink_proto_keyset_metadata_proto protoreflect.FileDescriptor
+
+var file_third_party_tink_proto_keyset_metadata_proto_rawDesc = []byte{
+	0x0a, 0x2c, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x2f, 0x74, 0x69,
+	0x6e, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x65, 0x79, 0x73, 0x65, 0x74, 0x5f,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x19,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69,
+	0x6e, 0x6b, 0x2e, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x1a, 0x21, 0x74, 0x68, 0x69, 0x72, 0x64,
+	0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x99, 0x02, 0x0a,
+	0x0e, 0x4b, 0x65, 0x79, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x3f, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69, 0x6e,
+	0x6b, 0x2e, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2e, 0x4b, 0x65, 0x79, 0x73, 0x65, 0x74, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x1a, 0xc5, 0x01, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x6b, 0x65, 0x79, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x12,
+	0x23, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x59, 0x0a,
+	0x18, 0x64, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e,
+	0x74, 0x69, 0x6e, 0x6b, 0x2e, 0x4b, 0x65, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x52, 0x16, 0x64, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x42, 0x42, 0x5a, 0x40, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2d, 0x63, 0x72, 0x79, 0x70,
+	0x74, 0x6f, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2d, 0x67, 0x6f, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x65, 0x79, 0x73, 0x65, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x5f, 0x67, 0x6f, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_third_party_tink_proto_keyset_metadata_proto_rawDescOnce sync.Once
+	file_third_party_tink_proto_keyset_metadata_proto_rawDescData = file_third_party_tink_proto_keyset_metadata_proto_rawDesc
+)
+
+func file_third_party_tink_proto_keyset_metadata_proto_rawDescGZIP() []byte {
+	file_third_party_tink_proto_keyset_metadata_proto_rawDescOnce.Do(func() {
+		file_third_party_tink_proto_keyset_metadata_proto_rawDescData = protoimpl.X.CompressGZIP(file_third_party_tink_proto_keyset_metadata_proto_rawDescData)
+	})
+	return file_third_party_tink_proto_keyset_metadata_proto_rawDescData
+}
+
+var file_third_party_tink_proto_keyset_metadata_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_third_party_tink_proto_keyset_metadata_proto_goTypes = []any{
+	(*KeysetMetadata)(nil),            // 0: google.crypto.tink.golang.KeysetMetadata
+	(*KeysetMetadata_Key)(nil),        // 1: google.crypto.tink.golang.KeysetMetadata.Key
+	(*tink_go_proto.KeyTemplate)(nil), // 2: google.crypto.tink.KeyTemplate
+}
+var file_third_party_tink_proto_keyset_metadata_proto_depIdxs = []int32{
+	1, // 0: google.crypto.tink.golang.KeysetMetadata.key:type_name -> google.crypto.tink.golang.KeysetMetadata.Key
+	2, // 1: google.crypto.tink.golang.KeysetMetadata.Key.destroyed_key_parameters:type_name -> google.crypto.tink.KeyTemplate
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_third_party_tink_proto_keyset_metadata_proto_init() }
+func file_third_party_tink_proto_keyset_metadata_proto_init() {
+	if File_third_party_tink_proto_keyset_metadata_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_third_party_tink_proto_keyset_metadata_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_third_party_tink_proto_keyset_metadata_proto_goTypes,
+		DependencyIndexes: file_third_party_tink_proto_keyset_metadata_proto_depIdxs,
+		MessageInfos:      file_third_party_tink_proto_keyset_metadata_proto_msgTypes,
+	}.Build()
+	File_third_party_tink_proto_keyset_metadata_proto = out.File
+	file_third_party_tink_proto_keyset_metadata_proto_rawDesc = nil
+	file_third_party_tink_proto_keyset_metadata_proto_goTypes = nil
+	file_third_party_tink_proto_keyset_metadata_proto_depIdxs = nil
+}
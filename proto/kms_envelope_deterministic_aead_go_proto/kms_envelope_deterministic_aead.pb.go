@@ -0,0 +1,251 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.0
+// 	protoc        (unknown)
+// source: third_party/tink/proto/kms_envelope_deterministic_aead.proto
+
+package kms_envelope_deterministic_aead_go_proto
+
+import (
+	tink_go_proto "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type KmsEnvelopeDeterministicAeadKeyFormat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required.
+	// The location of the KEK in a remote KMS.
+	KekUri string `protobuf:"bytes,1,opt,name=kek_uri,json=kekUri,proto3" json:"kek_uri,omitempty"`
+	// Key template of the Data Encryption Key, e.g., AesSivKeyFormat.
+	// Required.
+	DekTemplate   *tink_go_proto.KeyTemplate `protobuf:"bytes,2,opt,name=dek_template,json=dekTemplate,proto3" json:"dek_template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KmsEnvelopeDeterministicAeadKeyFormat) Reset() {
+	*x = KmsEnvelopeDeterministicAeadKeyFormat{}
+	mi := &file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KmsEnvelopeDeterministicAeadKeyFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KmsEnvelopeDeterministicAeadKeyFormat) ProtoMessage() {}
+
+func (x *KmsEnvelopeDeterministicAeadKeyFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KmsEnvelopeDeterministicAeadKeyFormat.ProtoReflect.Descriptor instead.
+func (*KmsEnvelopeDeterministicAeadKeyFormat) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KmsEnvelopeDeterministicAeadKeyFormat) GetKekUri() string {
+	if x != nil {
+		return x.KekUri
+	}
+	return ""
+}
+
+func (x *KmsEnvelopeDeterministicAeadKeyFormat) GetDekTemplate() *tink_go_proto.KeyTemplate {
+	if x != nil {
+		return x.DekTemplate
+	}
+	return nil
+}
+
+type KmsEnvelopeDeterministicAeadKey struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The key format also contains the params.
+	Params *KmsEnvelopeDeterministicAeadKeyFormat `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	// The serialized DEK key, encrypted with the KEK. Unlike with
+	// KmsEnvelopeAeadKey, the DEK is fixed for the lifetime of the key so that
+	// encryption is deterministic.
+	EncryptedDek  []byte `protobuf:"bytes,3,opt,name=encrypted_dek,json=encryptedDek,proto3" json:"encrypted_dek,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KmsEnvelopeDeterministicAeadKey) Reset() {
+	*x = KmsEnvelopeDeterministicAeadKey{}
+	mi := &file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KmsEnvelopeDeterministicAeadKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KmsEnvelopeDeterministicAeadKey) ProtoMessage() {}
+
+func (x *KmsEnvelopeDeterministicAeadKey) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KmsEnvelopeDeterministicAeadKey.ProtoReflect.Descriptor instead.
+func (*KmsEnvelopeDeterministicAeadKey) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *KmsEnvelopeDeterministicAeadKey) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *KmsEnvelopeDeterministicAeadKey) GetParams() *KmsEnvelopeDeterministicAeadKeyFormat {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *KmsEnvelopeDeterministicAeadKey) GetEncryptedDek() []byte {
+	if x != nil {
+		return x.EncryptedDek
+	}
+	return nil
+}
+
+var File_third_party_tink_proto_kms_envelope_deterministic_aead_proto protoreflect.FileDescriptor
+
+var file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDesc = []byte{
+	0x0a, 0x3c, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x2f, 0x74, 0x69,
+	0x6e, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x6d, 0x73, 0x5f, 0x65, 0x6e, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x73,
+	0x74, 0x69, 0x63, 0x5f, 0x61, 0x65, 0x61, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69,
+	0x6e, 0x6b, 0x1a, 0x21, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x2f,
+	0x74, 0x69, 0x6e, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84, 0x01, 0x0a, 0x25, 0x4b, 0x6d, 0x73, 0x45, 0x6e, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x44, 0x65, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74,
+	0x69, 0x63, 0x41, 0x65, 0x61, 0x64, 0x4b, 0x65, 0x79, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x6b, 0x65, 0x6b, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6b, 0x65, 0x6b, 0x55, 0x72, 0x69, 0x12, 0x42, 0x0a, 0x0c, 0x64, 0x65, 0x6b, 0x5f,
+	0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74,
+	0x69, 0x6e, 0x6b, 0x2e, 0x4b, 0x65, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52,
+	0x0b, 0x64, 0x65, 0x6b, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0xb3, 0x01, 0x0a,
+	0x1f, 0x4b, 0x6d, 0x73, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x44, 0x65, 0x74, 0x65,
+	0x72, 0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x69, 0x63, 0x41, 0x65, 0x61, 0x64, 0x4b, 0x65, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x51, 0x0a, 0x06, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69, 0x6e, 0x6b, 0x2e,
+	0x4b, 0x6d, 0x73, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x44, 0x65, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x69, 0x63, 0x41, 0x65, 0x61, 0x64, 0x4b, 0x65, 0x79, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x23, 0x0a,
+	0x0d, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x64, 0x65, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x44,
+	0x65, 0x6b, 0x42, 0x72, 0x0a, 0x1c, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69, 0x6e, 0x6b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x50, 0x01, 0x5a, 0x50, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2d, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f, 0x74, 0x69, 0x6e,
+	0x6b, 0x2d, 0x67, 0x6f, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x6d,
+	0x73, 0x5f, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x69, 0x73, 0x74, 0x69, 0x63, 0x5f, 0x61, 0x65, 0x61, 0x64, 0x5f, 0x67, 0x6f,
+	0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescOnce sync.Once
+	file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescData = file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDesc
+)
+
+func file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescGZIP() []byte {
+	file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescOnce.Do(func() {
+		file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescData = protoimpl.X.CompressGZIP(file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescData)
+	})
+	return file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDescData
+}
+
+var file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_goTypes = []any{
+	(*KmsEnvelopeDeterministicAeadKeyFormat)(nil), // 0: google.crypto.tink.KmsEnvelopeDeterministicAeadKeyFormat
+	(*KmsEnvelopeDeterministicAeadKey)(nil),       // 1: google.crypto.tink.KmsEnvelopeDeterministicAeadKey
+	(*tink_go_proto.KeyTemplate)(nil),             // 2: google.crypto.tink.KeyTemplate
+}
+var file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_depIdxs = []int32{
+	2, // 0: google.crypto.tink.KmsEnvelopeDeterministicAeadKeyFormat.dek_template:type_name -> google.crypto.tink.KeyTemplate
+	0, // 1: google.crypto.tink.KmsEnvelopeDeterministicAeadKey.params:type_name -> google.crypto.tink.KmsEnvelopeDeterministicAeadKeyFormat
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_init() }
+func file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_init() {
+	if File_third_party_tink_proto_kms_envelope_deterministic_aead_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_goTypes,
+		DependencyIndexes: file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_depIdxs,
+		MessageInfos:      file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_msgTypes,
+	}.Build()
+	File_third_party_tink_proto_kms_envelope_deterministic_aead_proto = out.File
+	file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_rawDesc = nil
+	file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_goTypes = nil
+	file_third_party_tink_proto_kms_envelope_deterministic_aead_proto_depIdxs = nil
+}
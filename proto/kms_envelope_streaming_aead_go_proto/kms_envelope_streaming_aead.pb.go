@@ -0,0 +1,240 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.0
+// 	protoc        (unknown)
+// source: third_party/tink/proto/kms_envelope_streaming_aead.proto
+
+package kms_envelope_streaming_aead_go_proto
+
+import (
+	tink_go_proto "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type KmsEnvelopeStreamingAeadKeyFormat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required.
+	// The location of the KEK in a remote KMS.
+	KekUri string `protobuf:"bytes,1,opt,name=kek_uri,json=kekUri,proto3" json:"kek_uri,omitempty"`
+	// Key template of the streaming Data Encryption Key, e.g.,
+	// AesGcmHkdfStreamingKeyFormat.
+	// Required.
+	StreamingDekTemplate *tink_go_proto.KeyTemplate `protobuf:"bytes,2,opt,name=streaming_dek_template,json=streamingDekTemplate,proto3" json:"streaming_dek_template,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *KmsEnvelopeStreamingAeadKeyFormat) Reset() {
+	*x = KmsEnvelopeStreamingAeadKeyFormat{}
+	mi := &file_third_party_tink_proto_kms_envelope_streaming_aead_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KmsEnvelopeStreamingAeadKeyFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KmsEnvelopeStreamingAeadKeyFormat) ProtoMessage() {}
+
+func (x *KmsEnvelopeStreamingAeadKeyFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_kms_envelope_streaming_aead_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KmsEnvelopeStreamingAeadKeyFormat.ProtoReflect.Descriptor instead.
+func (*KmsEnvelopeStreamingAeadKeyFormat) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KmsEnvelopeStreamingAeadKeyFormat) GetKekUri() string {
+	if x != nil {
+		return x.KekUri
+	}
+	return ""
+}
+
+func (x *KmsEnvelopeStreamingAeadKeyFormat) GetStreamingDekTemplate() *tink_go_proto.KeyTemplate {
+	if x != nil {
+		return x.StreamingDekTemplate
+	}
+	return nil
+}
+
+// There is no actual key material in the key.
+type KmsEnvelopeStreamingAeadKey struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The key format also contains the params.
+	Params        *KmsEnvelopeStreamingAeadKeyFormat `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KmsEnvelopeStreamingAeadKey) Reset() {
+	*x = KmsEnvelopeStreamingAeadKey{}
+	mi := &file_third_party_tink_proto_kms_envelope_streaming_aead_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KmsEnvelopeStreamingAeadKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KmsEnvelopeStreamingAeadKey) ProtoMessage() {}
+
+func (x *KmsEnvelopeStreamingAeadKey) ProtoReflect() protoreflect.Message {
+	mi := &file_third_party_tink_proto_kms_envelope_streaming_aead_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KmsEnvelopeStreamingAeadKey.ProtoReflect.Descriptor instead.
+func (*KmsEnvelopeStreamingAeadKey) Descriptor() ([]byte, []int) {
+	return file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *KmsEnvelopeStreamingAeadKey) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *KmsEnvelopeStreamingAeadKey) GetParams() *KmsEnvelopeStreamingAeadKeyFormat {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+var File_third_party_tink_proto_kms_envelope_streaming_aead_proto protoreflect.FileDescriptor
+
+var file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDesc = []byte{
+	0x0a, 0x38, 0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x2f, 0x74, 0x69,
+	0x6e, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x6d, 0x73, 0x5f, 0x65, 0x6e, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f,
+	0x61, 0x65, 0x61, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69, 0x6e, 0x6b, 0x1a, 0x21,
+	0x74, 0x68, 0x69, 0x72, 0x64, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x79, 0x2f, 0x74, 0x69, 0x6e, 0x6b,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x93, 0x01, 0x0a, 0x21, 0x4b, 0x6d, 0x73, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70,
+	0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x41, 0x65, 0x61, 0x64, 0x4b, 0x65,
+	0x79, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6b, 0x65, 0x6b, 0x5f, 0x75,
+	0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6b, 0x65, 0x6b, 0x55, 0x72, 0x69,
+	0x12, 0x55, 0x0a, 0x16, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x65,
+	0x6b, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f,
+	0x2e, 0x74, 0x69, 0x6e, 0x6b, 0x2e, 0x4b, 0x65, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x52, 0x14, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x6b, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x86, 0x01, 0x0a, 0x1b, 0x4b, 0x6d, 0x73, 0x45,
+	0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67,
+	0x41, 0x65, 0x61, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x4d, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x35, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x6f, 0x2e, 0x74, 0x69, 0x6e, 0x6b, 0x2e, 0x4b, 0x6d, 0x73, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f,
+	0x70, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x41, 0x65, 0x61, 0x64, 0x4b,
+	0x65, 0x79, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x42, 0x6e, 0x0a, 0x1c, 0x63, 0x6f, 0x6d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x74, 0x69, 0x6e, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x50, 0x01, 0x5a, 0x4c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74,
+	0x69, 0x6e, 0x6b, 0x2d, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f, 0x74, 0x69, 0x6e, 0x6b, 0x2d,
+	0x67, 0x6f, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x6d, 0x73, 0x5f,
+	0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69,
+	0x6e, 0x67, 0x5f, 0x61, 0x65, 0x61, 0x64, 0x5f, 0x67, 0x6f, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescOnce sync.Once
+	file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescData = file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDesc
+)
+
+func file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescGZIP() []byte {
+	file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescOnce.Do(func() {
+		file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescData = protoimpl.X.CompressGZIP(file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescData)
+	})
+	return file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDescData
+}
+
+var file_third_party_tink_proto_kms_envelope_streaming_aead_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_third_party_tink_proto_kms_envelope_streaming_aead_proto_goTypes = []any{
+	(*KmsEnvelopeStreamingAeadKeyFormat)(nil), // 0: google.crypto.tink.KmsEnvelopeStreamingAeadKeyFormat
+	(*KmsEnvelopeStreamingAeadKey)(nil),       // 1: google.crypto.tink.KmsEnvelopeStreamingAeadKey
+	(*tink_go_proto.KeyTemplate)(nil),         // 2: google.crypto.tink.KeyTemplate
+}
+var file_third_party_tink_proto_kms_envelope_streaming_aead_proto_depIdxs = []int32{
+	2, // 0: google.crypto.tink.KmsEnvelopeStreamingAeadKeyFormat.streaming_dek_template:type_name -> google.crypto.tink.KeyTemplate
+	0, // 1: google.crypto.tink.KmsEnvelopeStreamingAeadKey.params:type_name -> google.crypto.tink.KmsEnvelopeStreamingAeadKeyFormat
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_third_party_tink_proto_kms_envelope_streaming_aead_proto_init() }
+func file_third_party_tink_proto_kms_envelope_streaming_aead_proto_init() {
+	if File_third_party_tink_proto_kms_envelope_streaming_aead_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_third_party_tink_proto_kms_envelope_streaming_aead_proto_goTypes,
+		DependencyIndexes: file_third_party_tink_proto_kms_envelope_streaming_aead_proto_depIdxs,
+		MessageInfos:      file_third_party_tink_proto_kms_envelope_streaming_aead_proto_msgTypes,
+	}.Build()
+	File_third_party_tink_proto_kms_envelope_streaming_aead_proto = out.File
+	file_third_party_tink_proto_kms_envelope_streaming_aead_proto_rawDesc = nil
+	file_third_party_tink_proto_kms_envelope_streaming_aead_proto_goTypes = nil
+	file_third_party_tink_proto_kms_envelope_streaming_aead_proto_depIdxs = nil
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
 	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/signature"
 	"github.com/tink-crypto/tink-go/v2/key"
 	"github.com/tink-crypto/tink-go/v2/signature/subtle"
 	"github.com/tink-crypto/tink-go/v2/tink"
@@ -34,6 +35,7 @@ type signer struct {
 }
 
 var _ tink.Signer = (*signer)(nil)
+var _ tink.StreamingSigner = (*signer)(nil)
 
 // NewSigner creates a new instance of [Signer].
 //
@@ -71,6 +73,16 @@ func (e *signer) Sign(data []byte) ([]byte, error) {
 	return slices.Concat(e.prefix, rawSignature), nil
 }
 
+// NewSigningWriter returns a [tink.SigningWriter] that computes the same
+// signature as Sign over all the data written to it.
+func (e *signer) NewSigningWriter() (tink.SigningWriter, error) {
+	var suffix []byte
+	if e.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return signature.NewDigestSigningWriter(e.impl.NewHash(), e.prefix, suffix, e.impl.SignDigest), nil
+}
+
 func signerConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PrivateKey)
 	if !ok {
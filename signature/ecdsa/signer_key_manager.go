@@ -150,6 +150,10 @@ func (km *signerKeyManager) DoesSupport(typeURL string) bool {
 // TypeURL returns the key type of keys managed by this key manager.
 func (km *signerKeyManager) TypeURL() string { return signerTypeURL }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *signerKeyManager) FIPSCompatible() bool { return true }
+
 // validateKey validates the given [ecdsapb.EcdsaPrivateKey].
 func (km *signerKeyManager) validateKey(key *ecdsapb.EcdsaPrivateKey) error {
 	if err := keyset.ValidateKeyVersion(key.Version, signerKeyVersion); err != nil {
@@ -20,6 +20,7 @@ import (
 	"slices"
 
 	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/signature"
 	"github.com/tink-crypto/tink-go/v2/key"
 	signaturesubtle "github.com/tink-crypto/tink-go/v2/signature/subtle"
 	"github.com/tink-crypto/tink-go/v2/tink"
@@ -35,6 +36,7 @@ type verifier struct {
 }
 
 var _ tink.Verifier = (*verifier)(nil)
+var _ tink.StreamingVerifier = (*verifier)(nil)
 
 // NewVerifier creates a new ECDSA Verifier.
 //
@@ -74,6 +76,22 @@ func (e *verifier) Verify(signatureBytes, data []byte) error {
 	return e.impl.Verify(signatureBytes[len(e.prefix):], toSign)
 }
 
+// NewVerifyingWriter returns a [tink.VerifyingWriter] that checks whether
+// signatureBytes is a valid signature for all the data written to it.
+func (e *verifier) NewVerifyingWriter(signatureBytes []byte) (tink.VerifyingWriter, error) {
+	if !bytes.HasPrefix(signatureBytes, e.prefix) {
+		return nil, fmt.Errorf("ecdsa_verifier: invalid signature prefix")
+	}
+	signatureNoPrefix := slices.Clone(signatureBytes[len(e.prefix):])
+	var suffix []byte
+	if e.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return signature.NewDigestVerifyingWriter(e.impl.NewHash(), suffix, func(digest []byte) error {
+		return e.impl.VerifyDigest(signatureNoPrefix, digest)
+	}), nil
+}
+
 func verifierConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PublicKey)
 	if !ok {
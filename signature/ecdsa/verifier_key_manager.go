@@ -79,6 +79,10 @@ func (km *verifierKeyManager) DoesSupport(typeURL string) bool {
 // TypeURL returns the key type of keys managed by this key manager.
 func (km *verifierKeyManager) TypeURL() string { return verifierTypeURL }
 
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *verifierKeyManager) FIPSCompatible() bool { return true }
+
 // validateKey validates the given [ecdsapb.EcdsaPublicKey].
 func (km *verifierKeyManager) validateKey(key *ecdsapb.EcdsaPublicKey) error {
 	if err := keyset.ValidateKeyVersion(key.Version, verifierKeyVersion); err != nil {
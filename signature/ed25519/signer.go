@@ -15,12 +15,15 @@
 package ed25519
 
 import (
+	"crypto"
 	"crypto/ed25519"
+	"crypto/sha512"
 	"fmt"
 	"slices"
 
 	"github.com/tink-crypto/tink-go/v2/insecuresecretdataaccess"
 	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/signature"
 	"github.com/tink-crypto/tink-go/v2/key"
 	"github.com/tink-crypto/tink-go/v2/tink"
 )
@@ -33,6 +36,10 @@ type signer struct {
 }
 
 var _ tink.Signer = (*signer)(nil)
+var _ tink.StreamingSigner = (*signer)(nil)
+
+// ph is the Ed25519ph (RFC 8032, Section 5.1) option used for streaming.
+var ph = &ed25519.Options{Hash: crypto.SHA512}
 
 // NewSigner creates a new [tink.Signer] for ED25519.
 //
@@ -61,6 +68,22 @@ func (e *signer) Sign(data []byte) ([]byte, error) {
 	return slices.Concat(e.prefix, r), nil
 }
 
+// NewSigningWriter returns a [tink.SigningWriter] that signs all the data
+// written to it with Ed25519ph, the prehashed variant of Ed25519.
+//
+// Ed25519ph is a separate signature scheme: its signatures are not valid
+// Ed25519 signatures of the same data and can only be verified with
+// [verifier.NewVerifyingWriter].
+func (e *signer) NewSigningWriter() (tink.SigningWriter, error) {
+	var suffix []byte
+	if e.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return signature.NewDigestSigningWriter(sha512.New(), e.prefix, suffix, func(digest []byte) ([]byte, error) {
+		return e.privateKey.Sign(nil, digest, ph)
+	}), nil
+}
+
 func signerConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PrivateKey)
 	if !ok {
@@ -17,10 +17,12 @@ package ed25519
 import (
 	"bytes"
 	"crypto/ed25519"
+	"crypto/sha512"
 	"fmt"
 	"slices"
 
 	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	internalsignature "github.com/tink-crypto/tink-go/v2/internal/signature"
 	"github.com/tink-crypto/tink-go/v2/key"
 	"github.com/tink-crypto/tink-go/v2/tink"
 )
@@ -33,6 +35,7 @@ type verifier struct {
 }
 
 var _ tink.Verifier = (*verifier)(nil)
+var _ tink.StreamingVerifier = (*verifier)(nil)
 
 // NewVerifier creates a new [tink.Verifier] for ED25519.
 //
@@ -67,6 +70,30 @@ func (e *verifier) Verify(signature, data []byte) error {
 	return nil
 }
 
+// NewVerifyingWriter returns a [tink.VerifyingWriter] that checks whether
+// signature is a valid Ed25519ph signature for all the data written to it.
+//
+// Plain Ed25519 signatures are rejected, see [signer.NewSigningWriter].
+func (e *verifier) NewVerifyingWriter(signature []byte) (tink.VerifyingWriter, error) {
+	if !bytes.HasPrefix(signature, e.prefix) {
+		return nil, fmt.Errorf("ed25519: the signature doesn't have the expected prefix")
+	}
+	signatureNoPrefix := slices.Clone(signature[len(e.prefix):])
+	if len(signatureNoPrefix) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("ed25519: the length of the signature is not %d", ed25519.SignatureSize)
+	}
+	var suffix []byte
+	if e.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return internalsignature.NewDigestVerifyingWriter(sha512.New(), suffix, func(digest []byte) error {
+		if err := ed25519.VerifyWithOptions(e.publicKey, digest, signatureNoPrefix, ph); err != nil {
+			return fmt.Errorf("ed25519: invalid signature")
+		}
+		return nil
+	}), nil
+}
+
 func verifierConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PublicKey)
 	if !ok {
@@ -26,12 +26,13 @@ import (
 
 // signer is an implementation of [tink.Signer] for RSA-SSA-PKCS1.
 type signer struct {
-	rawSigner tink.Signer
+	rawSigner *signature.RSA_SSA_PKCS1_Signer
 	prefix    []byte
 	variant   Variant
 }
 
 var _ tink.Signer = (*signer)(nil)
+var _ tink.StreamingSigner = (*signer)(nil)
 
 // NewSigner returns a new [tink.Signer] that implements the primitive
 // described by privateKey.
@@ -60,6 +61,16 @@ func (s *signer) Sign(data []byte) ([]byte, error) {
 	return slices.Concat(s.prefix, sig), nil
 }
 
+// NewSigningWriter returns a [tink.SigningWriter] that computes the same
+// signature as Sign over all the data written to it.
+func (s *signer) NewSigningWriter() (tink.SigningWriter, error) {
+	var suffix []byte
+	if s.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return signature.NewDigestSigningWriter(s.rawSigner.NewHash(), s.prefix, suffix, s.rawSigner.SignDigest), nil
+}
+
 func signerConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PrivateKey)
 	if !ok {
@@ -166,3 +166,7 @@ func (km *signerKeyManager) DoesSupport(typeURL string) bool { return typeURL ==
 
 // TypeURL returns the key type of keys managed by this key manager.
 func (km *signerKeyManager) TypeURL() string { return signerTypeURL }
+
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *signerKeyManager) FIPSCompatible() bool { return true }
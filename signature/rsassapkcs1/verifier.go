@@ -29,12 +29,13 @@ import (
 
 // verifier is an implementation of [tink.Verifier] for RSA-SSA-PKCS1.
 type verifier struct {
-	rawVerifier tink.Verifier
+	rawVerifier *signature.RSA_SSA_PKCS1_Verifier
 	variant     Variant
 	prefix      []byte
 }
 
 var _ tink.Verifier = (*verifier)(nil)
+var _ tink.StreamingVerifier = (*verifier)(nil)
 
 // NewVerifier returns a new [tink.Verifier] that implements the primitive
 // described by pubKey.
@@ -66,6 +67,22 @@ func (v *verifier) Verify(sig, data []byte) error {
 	return v.rawVerifier.Verify(signatureWithoutPrefix, toVerify)
 }
 
+// NewVerifyingWriter returns a [tink.VerifyingWriter] that checks whether sig
+// is a valid signature for all the data written to it.
+func (v *verifier) NewVerifyingWriter(sig []byte) (tink.VerifyingWriter, error) {
+	if !bytes.HasPrefix(sig, v.prefix) {
+		return nil, fmt.Errorf("signature does not start with prefix")
+	}
+	signatureWithoutPrefix := slices.Clone(sig[len(v.prefix):])
+	var suffix []byte
+	if v.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return signature.NewDigestVerifyingWriter(v.rawVerifier.NewHash(), suffix, func(digest []byte) error {
+		return v.rawVerifier.VerifyDigest(signatureWithoutPrefix, digest)
+	}), nil
+}
+
 func verifierConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PublicKey)
 	if !ok {
@@ -82,3 +82,9 @@ func (km *verifierKeyManager) DoesSupport(typeURL string) bool {
 func (km *verifierKeyManager) TypeURL() string {
 	return verifierTypeURL
 }
+
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *verifierKeyManager) FIPSCompatible() bool {
+	return true
+}
@@ -32,6 +32,7 @@ type signer struct {
 }
 
 var _ tink.Signer = (*signer)(nil)
+var _ tink.StreamingSigner = (*signer)(nil)
 
 // NewSigner creates a new [tink.Signer] that implements a full RSA-SSA-PSS
 // primitive from the given [PrivateKey].
@@ -61,6 +62,16 @@ func (s *signer) Sign(data []byte) ([]byte, error) {
 	return slices.Concat(s.prefix, signature), nil
 }
 
+// NewSigningWriter returns a [tink.SigningWriter] that computes the same
+// signature as Sign over all the data written to it.
+func (s *signer) NewSigningWriter() (tink.SigningWriter, error) {
+	var suffix []byte
+	if s.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return signature.NewDigestSigningWriter(s.rawSigner.NewHash(), s.prefix, suffix, s.rawSigner.SignDigest), nil
+}
+
 func signerConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PrivateKey)
 	if !ok {
@@ -169,3 +169,9 @@ func (km *signerKeyManager) DoesSupport(typeURL string) bool {
 func (km *signerKeyManager) TypeURL() string {
 	return signerTypeURL
 }
+
+// FIPSCompatible reports that the primitives of this key manager only use
+// FIPS 140-2 approved algorithms.
+func (km *signerKeyManager) FIPSCompatible() bool {
+	return true
+}
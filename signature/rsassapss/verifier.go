@@ -35,6 +35,7 @@ type verifier struct {
 }
 
 var _ tink.Verifier = (*verifier)(nil)
+var _ tink.StreamingVerifier = (*verifier)(nil)
 
 // NewVerifier creates a new [tink.Verifier] that implements a full RSA-SSA-PSS
 // primitive from the given [PublicKey].
@@ -66,6 +67,22 @@ func (v *verifier) Verify(signatureBytes, data []byte) error {
 	return v.rawVerifier.Verify(signatureWithoutPrefix, toVerify)
 }
 
+// NewVerifyingWriter returns a [tink.VerifyingWriter] that checks whether sig
+// is a valid signature for all the data written to it.
+func (v *verifier) NewVerifyingWriter(sig []byte) (tink.VerifyingWriter, error) {
+	if !bytes.HasPrefix(sig, v.prefix) {
+		return nil, fmt.Errorf("signature does not start with prefix %x", v.prefix)
+	}
+	signatureWithoutPrefix := slices.Clone(sig[len(v.prefix):])
+	var suffix []byte
+	if v.variant == VariantLegacy {
+		suffix = []byte{0}
+	}
+	return signature.NewDigestVerifyingWriter(v.rawVerifier.NewHash(), suffix, func(digest []byte) error {
+		return v.rawVerifier.VerifyDigest(signatureWithoutPrefix, digest)
+	}), nil
+}
+
 func verifierConstructor(key key.Key) (any, error) {
 	that, ok := key.(*PublicKey)
 	if !ok {
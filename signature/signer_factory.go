@@ -95,7 +95,7 @@ func newWrappedSigner(ps *primitiveset.PrimitiveSet[tink.Signer]) (*wrappedSigne
 	}, nil
 }
 
-func createSignerLogger(ps *primitiveset.PrimitiveSet[tink.Signer]) (monitoring.Logger, error) {
+func createSignerLogger[T any](ps *primitiveset.PrimitiveSet[T]) (monitoring.Logger, error) {
 	// Only keysets which contain annotations are monitored.
 	if len(ps.Annotations) == 0 {
 		return &monitoringutil.DoNothingLogger{}, nil
@@ -0,0 +1,285 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/mac"
+	"github.com/tink-crypto/tink-go/v2/signature"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func withOutputPrefixType(template *tinkpb.KeyTemplate, outputPrefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	t := proto.Clone(template).(*tinkpb.KeyTemplate)
+	t.OutputPrefixType = outputPrefixType
+	return t
+}
+
+func mustCreateStreamingKeysets(t *testing.T, template *tinkpb.KeyTemplate) (*keyset.Handle, *keyset.Handle) {
+	t.Helper()
+	privHandle, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	pubHandle, err := privHandle.Public()
+	if err != nil {
+		t.Fatalf("privHandle.Public() err = %v, want nil", err)
+	}
+	return privHandle, pubHandle
+}
+
+func streamingSign(t *testing.T, handle *keyset.Handle, data []byte) []byte {
+	t.Helper()
+	s, err := signature.NewStreamingSigner(handle)
+	if err != nil {
+		t.Fatalf("signature.NewStreamingSigner() err = %v, want nil", err)
+	}
+	w, err := s.NewSigningWriter()
+	if err != nil {
+		t.Fatalf("s.NewSigningWriter() err = %v, want nil", err)
+	}
+	// Use a small buffer so that the data is written in several chunks.
+	if _, err := io.CopyBuffer(w, bytes.NewReader(data), make([]byte, 100)); err != nil {
+		t.Fatalf("io.CopyBuffer() err = %v, want nil", err)
+	}
+	sig, err := w.Sign()
+	if err != nil {
+		t.Fatalf("w.Sign() err = %v, want nil", err)
+	}
+	return sig
+}
+
+func streamingVerify(t *testing.T, handle *keyset.Handle, sig, data []byte) error {
+	t.Helper()
+	v, err := signature.NewStreamingVerifier(handle)
+	if err != nil {
+		t.Fatalf("signature.NewStreamingVerifier() err = %v, want nil", err)
+	}
+	w, err := v.NewVerifyingWriter(sig)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, bytes.NewReader(data)); err != nil {
+		t.Fatalf("io.Copy() err = %v, want nil", err)
+	}
+	return w.Verify()
+}
+
+func TestStreamingSignVerify(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"ECDSA_P256", signature.ECDSAP256KeyTemplate()},
+		{"ECDSA_P256_RAW", signature.ECDSAP256RawKeyTemplate()},
+		{"ECDSA_P384_LEGACY", withOutputPrefixType(signature.ECDSAP384SHA384KeyTemplate(), tinkpb.OutputPrefixType_LEGACY)},
+		{"ECDSA_P521_CRUNCHY", withOutputPrefixType(signature.ECDSAP521KeyTemplate(), tinkpb.OutputPrefixType_CRUNCHY)},
+		{"RSA_SSA_PKCS1", signature.RSA_SSA_PKCS1_3072_SHA256_F4_Key_Template()},
+		{"RSA_SSA_PKCS1_LEGACY", withOutputPrefixType(signature.RSA_SSA_PKCS1_3072_SHA256_F4_Key_Template(), tinkpb.OutputPrefixType_LEGACY)},
+		{"RSA_SSA_PSS", signature.RSA_SSA_PSS_3072_SHA256_32_F4_Key_Template()},
+		{"RSA_SSA_PSS_RAW", signature.RSA_SSA_PSS_3072_SHA256_32_F4_Raw_Key_Template()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			privHandle, pubHandle := mustCreateStreamingKeysets(t, tc.template)
+			data := random.GetRandomBytes(1211)
+			sig := streamingSign(t, privHandle, data)
+
+			if err := streamingVerify(t, pubHandle, sig, data); err != nil {
+				t.Errorf("streamingVerify() err = %v, want nil", err)
+			}
+			// Hash-then-sign signatures are the same as the non-streaming ones.
+			verifier, err := signature.NewVerifier(pubHandle)
+			if err != nil {
+				t.Fatalf("signature.NewVerifier() err = %v, want nil", err)
+			}
+			if err := verifier.Verify(sig, data); err != nil {
+				t.Errorf("verifier.Verify() err = %v, want nil", err)
+			}
+			signer, err := signature.NewSigner(privHandle)
+			if err != nil {
+				t.Fatalf("signature.NewSigner() err = %v, want nil", err)
+			}
+			nonStreamingSig, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v, want nil", err)
+			}
+			if err := streamingVerify(t, pubHandle, nonStreamingSig, data); err != nil {
+				t.Errorf("streamingVerify() of non-streaming signature err = %v, want nil", err)
+			}
+
+			if err := streamingVerify(t, pubHandle, sig, append(data, 'x')); err == nil {
+				t.Errorf("streamingVerify() with modified data err = nil, want error")
+			}
+			if err := streamingVerify(t, pubHandle, sig, data[1:]); err == nil {
+				t.Errorf("streamingVerify() with truncated data err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestStreamingSignatureHasOutputPrefix(t *testing.T) {
+	privHandle, _ := mustCreateStreamingKeysets(t, signature.ECDSAP256KeyTemplate())
+	sig := streamingSign(t, privHandle, []byte("data"))
+	entry, err := privHandle.Primary()
+	if err != nil {
+		t.Fatalf("privHandle.Primary() err = %v, want nil", err)
+	}
+	keyID := entry.KeyID()
+	wantPrefix := []byte{1, byte(keyID >> 24), byte(keyID >> 16), byte(keyID >> 8), byte(keyID)}
+	if !bytes.HasPrefix(sig, wantPrefix) {
+		t.Errorf("sig = %x, want prefix %x", sig, wantPrefix)
+	}
+}
+
+func TestStreamingEd25519UsesPrehashScheme(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{
+		signature.ED25519KeyTemplate(),
+		signature.ED25519KeyWithoutPrefixTemplate(),
+		withOutputPrefixType(signature.ED25519KeyTemplate(), tinkpb.OutputPrefixType_LEGACY),
+	} {
+		t.Run(template.GetOutputPrefixType().String(), func(t *testing.T) {
+			privHandle, pubHandle := mustCreateStreamingKeysets(t, template)
+			data := random.GetRandomBytes(1211)
+			sig := streamingSign(t, privHandle, data)
+			if err := streamingVerify(t, pubHandle, sig, data); err != nil {
+				t.Errorf("streamingVerify() err = %v, want nil", err)
+			}
+			if err := streamingVerify(t, pubHandle, sig, append(data, 'x')); err == nil {
+				t.Errorf("streamingVerify() with modified data err = nil, want error")
+			}
+
+			// Ed25519ph signatures are not Ed25519 signatures, and vice versa.
+			verifier, err := signature.NewVerifier(pubHandle)
+			if err != nil {
+				t.Fatalf("signature.NewVerifier() err = %v, want nil", err)
+			}
+			if err := verifier.Verify(sig, data); err == nil {
+				t.Errorf("verifier.Verify() of Ed25519ph signature err = nil, want error")
+			}
+			signer, err := signature.NewSigner(privHandle)
+			if err != nil {
+				t.Fatalf("signature.NewSigner() err = %v, want nil", err)
+			}
+			ed25519Sig, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v, want nil", err)
+			}
+			if err := streamingVerify(t, pubHandle, ed25519Sig, data); err == nil {
+				t.Errorf("streamingVerify() of Ed25519 signature err = nil, want error")
+			}
+		})
+	}
+}
+
+func TestStreamingVerifyWithRotatedKeyset(t *testing.T) {
+	manager := keyset.NewManager()
+	oldKeyID, err := manager.Add(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("manager.Add() err = %v, want nil", err)
+	}
+	if err := manager.SetPrimary(oldKeyID); err != nil {
+		t.Fatalf("manager.SetPrimary() err = %v, want nil", err)
+	}
+	oldHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v, want nil", err)
+	}
+	data := []byte("release artifact")
+	oldSig := streamingSign(t, oldHandle, data)
+
+	newKeyID, err := manager.Add(signature.RSA_SSA_PSS_3072_SHA256_32_F4_Raw_Key_Template())
+	if err != nil {
+		t.Fatalf("manager.Add() err = %v, want nil", err)
+	}
+	if err := manager.SetPrimary(newKeyID); err != nil {
+		t.Fatalf("manager.SetPrimary() err = %v, want nil", err)
+	}
+	newHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v, want nil", err)
+	}
+	newSig := streamingSign(t, newHandle, data)
+	pubHandle, err := newHandle.Public()
+	if err != nil {
+		t.Fatalf("newHandle.Public() err = %v, want nil", err)
+	}
+	for _, sig := range [][]byte{oldSig, newSig} {
+		if err := streamingVerify(t, pubHandle, sig, data); err != nil {
+			t.Errorf("streamingVerify() err = %v, want nil", err)
+		}
+	}
+}
+
+func TestStreamingVerifyRejectsShortSignature(t *testing.T) {
+	_, pubHandle := mustCreateStreamingKeysets(t, signature.ECDSAP256KeyTemplate())
+	if err := streamingVerify(t, pubHandle, []byte{1, 2}, []byte("data")); err == nil {
+		t.Errorf("streamingVerify() with short signature err = nil, want error")
+	}
+}
+
+func TestStreamingWriterCannotBeReused(t *testing.T) {
+	privHandle, pubHandle := mustCreateStreamingKeysets(t, signature.ECDSAP256KeyTemplate())
+	s, err := signature.NewStreamingSigner(privHandle)
+	if err != nil {
+		t.Fatalf("signature.NewStreamingSigner() err = %v, want nil", err)
+	}
+	w, err := s.NewSigningWriter()
+	if err != nil {
+		t.Fatalf("s.NewSigningWriter() err = %v, want nil", err)
+	}
+	sig, err := w.Sign()
+	if err != nil {
+		t.Fatalf("w.Sign() err = %v, want nil", err)
+	}
+	if _, err := w.Write([]byte("data")); err == nil {
+		t.Errorf("w.Write() after Sign() err = nil, want error")
+	}
+	if _, err := w.Sign(); err == nil {
+		t.Errorf("w.Sign() after Sign() err = nil, want error")
+	}
+	v, err := signature.NewStreamingVerifier(pubHandle)
+	if err != nil {
+		t.Fatalf("signature.NewStreamingVerifier() err = %v, want nil", err)
+	}
+	vw, err := v.NewVerifyingWriter(sig)
+	if err != nil {
+		t.Fatalf("v.NewVerifyingWriter() err = %v, want nil", err)
+	}
+	if err := vw.Verify(); err != nil {
+		t.Errorf("vw.Verify() err = %v, want nil", err)
+	}
+	if _, err := vw.Write([]byte("data")); err == nil {
+		t.Errorf("vw.Write() after Verify() err = nil, want error")
+	}
+}
+
+func TestNewStreamingSignerFailsWithUnsupportedKeys(t *testing.T) {
+	handle, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v, want nil", err)
+	}
+	if _, err := signature.NewStreamingSigner(handle); err == nil {
+		t.Errorf("signature.NewStreamingSigner() err = nil, want error")
+	}
+	if _, err := signature.NewStreamingVerifier(handle); err == nil {
+		t.Errorf("signature.NewStreamingVerifier() err = nil, want error")
+	}
+}
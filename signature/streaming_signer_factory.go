@@ -0,0 +1,108 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/primitiveset"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/monitoring"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// streamingSignerPrimitive is a Signer that can also sign data incrementally.
+type streamingSignerPrimitive interface {
+	tink.Signer
+	tink.StreamingSigner
+}
+
+// NewStreamingSigner returns a StreamingSigner primitive from the given keyset
+// handle.
+//
+// The keyset must only contain ECDSA, Ed25519, RSA-SSA-PKCS1 or RSA-SSA-PSS
+// keys. Note that Ed25519 keys produce Ed25519ph signatures, which can only be
+// verified with [NewStreamingVerifier].
+func NewStreamingSigner(handle *keyset.Handle) (tink.StreamingSigner, error) {
+	ps, err := keyset.Primitives[streamingSignerPrimitive](handle, internalapi.Token{})
+	if err != nil {
+		return nil, fmt.Errorf("public_key_sign_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedStreamingSigner(ps)
+}
+
+// wrappedStreamingSigner is a StreamingSigner implementation that uses the
+// primary of the underlying primitive set for signing.
+type wrappedStreamingSigner struct {
+	signer      tink.StreamingSigner
+	signerKeyID uint32
+	logger      monitoring.Logger
+}
+
+var _ tink.StreamingSigner = (*wrappedStreamingSigner)(nil)
+
+func newWrappedStreamingSigner(ps *primitiveset.PrimitiveSet[streamingSignerPrimitive]) (*wrappedStreamingSigner, error) {
+	if ps.Primary.FullPrimitive == nil {
+		return nil, fmt.Errorf("public_key_sign_factory: primary key does not support streaming")
+	}
+	logger, err := createSignerLogger(ps)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedStreamingSigner{
+		signer:      ps.Primary.FullPrimitive,
+		signerKeyID: ps.Primary.KeyID,
+		logger:      logger,
+	}, nil
+}
+
+// NewSigningWriter returns a SigningWriter that uses the primary key. The
+// signature returned by Sign carries the output prefix of the primary key.
+func (s *wrappedStreamingSigner) NewSigningWriter() (tink.SigningWriter, error) {
+	w, err := s.signer.NewSigningWriter()
+	if err != nil {
+		s.logger.LogFailure()
+		return nil, err
+	}
+	return &wrappedSigningWriter{
+		writer: w,
+		keyID:  s.signerKeyID,
+		logger: s.logger,
+	}, nil
+}
+
+type wrappedSigningWriter struct {
+	writer  tink.SigningWriter
+	keyID   uint32
+	logger  monitoring.Logger
+	written int
+}
+
+func (w *wrappedSigningWriter) Write(data []byte) (int, error) {
+	n, err := w.writer.Write(data)
+	w.written += n
+	return n, err
+}
+
+func (w *wrappedSigningWriter) Sign() ([]byte, error) {
+	signature, err := w.writer.Sign()
+	if err != nil {
+		w.logger.LogFailure()
+		return nil, err
+	}
+	w.logger.Log(w.keyID, w.written)
+	return signature, nil
+}
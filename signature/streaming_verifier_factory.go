@@ -0,0 +1,149 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/core/cryptofmt"
+	"github.com/tink-crypto/tink-go/v2/internal/internalapi"
+	"github.com/tink-crypto/tink-go/v2/internal/primitiveset"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/monitoring"
+	"github.com/tink-crypto/tink-go/v2/tink"
+)
+
+// streamingVerifierPrimitive is a Verifier that can also verify data
+// incrementally.
+type streamingVerifierPrimitive interface {
+	tink.Verifier
+	tink.StreamingVerifier
+}
+
+// NewStreamingVerifier returns a StreamingVerifier primitive from the given
+// keyset handle.
+//
+// The keyset must only contain ECDSA, Ed25519, RSA-SSA-PKCS1 or RSA-SSA-PSS
+// keys. See [NewStreamingSigner].
+func NewStreamingVerifier(handle *keyset.Handle) (tink.StreamingVerifier, error) {
+	ps, err := keyset.Primitives[streamingVerifierPrimitive](handle, internalapi.Token{})
+	if err != nil {
+		return nil, fmt.Errorf("verifier_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedStreamingVerifier(ps)
+}
+
+// wrappedStreamingVerifier is a StreamingVerifier implementation that uses the
+// underlying primitive set for verifying.
+type wrappedStreamingVerifier struct {
+	verifiers map[string][]streamingVerifierAndID
+	logger    monitoring.Logger
+}
+
+type streamingVerifierAndID struct {
+	verifier tink.StreamingVerifier
+	keyID    uint32
+}
+
+var _ tink.StreamingVerifier = (*wrappedStreamingVerifier)(nil)
+
+func newWrappedStreamingVerifier(ps *primitiveset.PrimitiveSet[streamingVerifierPrimitive]) (*wrappedStreamingVerifier, error) {
+	verifiers := make(map[string][]streamingVerifierAndID)
+	for _, entries := range ps.Entries {
+		for _, entry := range entries {
+			if entry.FullPrimitive == nil {
+				return nil, fmt.Errorf("verifier_factory: key %d does not support streaming", entry.KeyID)
+			}
+			verifiers[entry.Prefix] = append(verifiers[entry.Prefix], streamingVerifierAndID{
+				verifier: entry.FullPrimitive,
+				keyID:    entry.KeyID,
+			})
+		}
+	}
+	logger, err := createVerifierLogger(ps)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedStreamingVerifier{
+		verifiers: verifiers,
+		logger:    logger,
+	}, nil
+}
+
+// NewVerifyingWriter returns a VerifyingWriter that checks signature against
+// all keys that match its prefix, and against all raw keys.
+func (v *wrappedStreamingVerifier) NewVerifyingWriter(signature []byte) (tink.VerifyingWriter, error) {
+	prefixSize := cryptofmt.NonRawPrefixSize
+	if len(signature) < prefixSize {
+		v.logger.LogFailure()
+		return nil, fmt.Errorf("verifier_factory: invalid signature; expected at least %d bytes, got %d", prefixSize, len(signature))
+	}
+	var candidates []verifyingWriterAndID
+	for _, prefix := range []string{string(signature[:prefixSize]), cryptofmt.RawPrefix} {
+		for _, verifier := range v.verifiers[prefix] {
+			// A verifier rejects signatures that are malformed for its key, so
+			// it can't have produced this signature.
+			w, err := verifier.verifier.NewVerifyingWriter(signature)
+			if err != nil {
+				continue
+			}
+			candidates = append(candidates, verifyingWriterAndID{writer: w, keyID: verifier.keyID})
+		}
+	}
+	return &wrappedVerifyingWriter{
+		candidates: candidates,
+		logger:     v.logger,
+	}, nil
+}
+
+type verifyingWriterAndID struct {
+	writer tink.VerifyingWriter
+	keyID  uint32
+}
+
+type wrappedVerifyingWriter struct {
+	candidates []verifyingWriterAndID
+	logger     monitoring.Logger
+	written    int
+	finished   bool
+}
+
+func (w *wrappedVerifyingWriter) Write(data []byte) (int, error) {
+	if w.finished {
+		return 0, fmt.Errorf("verifier_factory: writer already finished")
+	}
+	for _, c := range w.candidates {
+		if _, err := c.writer.Write(data); err != nil {
+			return 0, err
+		}
+	}
+	w.written += len(data)
+	return len(data), nil
+}
+
+func (w *wrappedVerifyingWriter) Verify() error {
+	if w.finished {
+		return fmt.Errorf("verifier_factory: writer already finished")
+	}
+	w.finished = true
+	for _, c := range w.candidates {
+		if err := c.writer.Verify(); err == nil {
+			w.logger.Log(c.keyID, w.written)
+			return nil
+		}
+	}
+	w.logger.LogFailure()
+	return fmt.Errorf("verifier_factory: invalid signature")
+}
@@ -69,7 +69,19 @@ func (e *ECDSASigner) Sign(data []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	return e.SignDigest(hashed)
+}
+
+// NewHash returns a new instance of the hash function used by the signer.
+func (e *ECDSASigner) NewHash() hash.Hash {
+	return e.hashFunc()
+}
+
+// SignDigest computes a signature for the given digest, which must have been
+// computed with the hash returned by NewHash.
+func (e *ECDSASigner) SignDigest(hashed []byte) ([]byte, error) {
 	var signatureBytes []byte
+	var err error
 	switch e.encoding {
 	case "IEEE_P1363":
 		r, s, err := ecdsa.Sign(rand.Reader, e.privateKey, hashed)
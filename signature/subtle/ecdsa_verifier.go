@@ -70,6 +70,17 @@ func (e *ECDSAVerifier) Verify(signatureBytes, data []byte) error {
 	if err != nil {
 		return err
 	}
+	return e.VerifyDigest(signatureBytes, hashed)
+}
+
+// NewHash returns a new instance of the hash function used by the verifier.
+func (e *ECDSAVerifier) NewHash() hash.Hash {
+	return e.hashFunc()
+}
+
+// VerifyDigest verifies whether the given signature is valid for the given
+// digest, which must have been computed with the hash returned by NewHash.
+func (e *ECDSAVerifier) VerifyDigest(signatureBytes, hashed []byte) error {
 	var asn1Signature []byte
 	switch e.encoding {
 	case "DER":
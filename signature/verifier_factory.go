@@ -113,7 +113,7 @@ func newWrappedVerifier(ps *primitiveset.PrimitiveSet[tink.Verifier]) (*wrappedV
 	}, nil
 }
 
-func createVerifierLogger(ps *primitiveset.PrimitiveSet[tink.Verifier]) (monitoring.Logger, error) {
+func createVerifierLogger[T any](ps *primitiveset.PrimitiveSet[T]) (monitoring.Logger, error) {
 	// only keysets which contain annotations are monitored.
 	if len(ps.Annotations) == 0 {
 		return &monitoringutil.DoNothingLogger{}, nil
@@ -0,0 +1,151 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamingaead
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+const (
+	lenEncryptedDEK       = 4
+	maxLengthEncryptedDEK = 4096
+)
+
+// KMSEnvelopeStreamingAEAD is a [tink.StreamingAEAD] that encrypts each stream
+// with a fresh data encryption key (DEK), which is itself encrypted with a key
+// encryption key (KEK), usually held in a remote KMS.
+//
+// The ciphertext starts with a header consisting of the length of the
+// encrypted DEK as a 4-byte big-endian integer followed by the encrypted DEK.
+// The rest of the ciphertext is the streaming ciphertext produced with the
+// DEK.
+type KMSEnvelopeStreamingAEAD struct {
+	dekTemplate *tinkpb.KeyTemplate
+	kekAEAD     tink.AEAD
+}
+
+var _ tink.StreamingAEAD = (*KMSEnvelopeStreamingAEAD)(nil)
+
+var tinkStreamingAEADKeyTypes = map[string]bool{
+	aesGCMHKDFTypeURL: true,
+	aesCTRHMACTypeURL: true,
+}
+
+func isSupportedKMSEnvelopeStreamingDEK(dekKeyTypeURL string) bool {
+	return tinkStreamingAEADKeyTypes[dekKeyTypeURL]
+}
+
+// NewKMSEnvelopeStreamingAEAD creates a new instance of
+// [KMSEnvelopeStreamingAEAD].
+//
+// dekTemplate must be a KeyTemplate for any of these Tink streaming AEAD key
+// types (any other key template will be rejected):
+//   - AesGcmHkdfStreamingKey
+//   - AesCtrHmacStreamingKey
+//
+// keyEncryptionAEAD is used to encrypt the DEK, and is usually a remote AEAD
+// provided by a KMS.
+func NewKMSEnvelopeStreamingAEAD(dekTemplate *tinkpb.KeyTemplate, keyEncryptionAEAD tink.AEAD) (*KMSEnvelopeStreamingAEAD, error) {
+	if !isSupportedKMSEnvelopeStreamingDEK(dekTemplate.GetTypeUrl()) {
+		return nil, fmt.Errorf("kms_envelope_streaming_aead: unsupported DEK key type %s", dekTemplate.GetTypeUrl())
+	}
+	if keyEncryptionAEAD == nil {
+		return nil, errors.New("kms_envelope_streaming_aead: key encryption AEAD is nil")
+	}
+	return &KMSEnvelopeStreamingAEAD{
+		dekTemplate: dekTemplate,
+		kekAEAD:     keyEncryptionAEAD,
+	}, nil
+}
+
+func (a *KMSEnvelopeStreamingAEAD) dekPrimitive(dek []byte) (tink.StreamingAEAD, error) {
+	p, err := registry.Primitive(a.dekTemplate.GetTypeUrl(), dek)
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_streaming_aead: %v", err)
+	}
+	sa, ok := p.(tink.StreamingAEAD)
+	if !ok {
+		return nil, errors.New("kms_envelope_streaming_aead: failed to convert streaming AEAD primitive")
+	}
+	return sa, nil
+}
+
+// NewEncryptingWriter generates a new DEK, encrypts it with the KEK and writes
+// it to w. It returns a writer that encrypts the data written to it with the
+// DEK, using associatedData as associated data.
+func (a *KMSEnvelopeStreamingAEAD) NewEncryptingWriter(w io.Writer, associatedData []byte) (io.WriteCloser, error) {
+	dekKeyData, err := registry.NewKeyData(a.dekTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("kms_envelope_streaming_aead: %v", err)
+	}
+	dek := dekKeyData.GetValue()
+	encryptedDEK, err := a.kekAEAD.Encrypt(dek, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedDEK) == 0 {
+		return nil, errors.New("kms_envelope_streaming_aead: encrypted DEK is empty")
+	}
+	if len(encryptedDEK) > maxLengthEncryptedDEK {
+		return nil, fmt.Errorf(
+			"kms_envelope_streaming_aead: length of encrypted DEK too large; got %d, want at most %d",
+			len(encryptedDEK), maxLengthEncryptedDEK)
+	}
+	sa, err := a.dekPrimitive(dek)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, lenEncryptedDEK+len(encryptedDEK))
+	header = binary.BigEndian.AppendUint32(header, uint32(len(encryptedDEK)))
+	header = append(header, encryptedDEK...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return sa.NewEncryptingWriter(w, associatedData)
+}
+
+// NewDecryptingReader reads the encrypted DEK from r and decrypts it with the
+// KEK. It returns a reader that decrypts the rest of r with the DEK, using
+// associatedData as associated data.
+func (a *KMSEnvelopeStreamingAEAD) NewDecryptingReader(r io.Reader, associatedData []byte) (io.Reader, error) {
+	lenBuf := make([]byte, lenEncryptedDEK)
+	if _, err := io.ReadFull(r, lenBuf); err != nil {
+		return nil, fmt.Errorf("kms_envelope_streaming_aead: failed to read header: %v", err)
+	}
+	encryptedDEKLen := binary.BigEndian.Uint32(lenBuf)
+	if encryptedDEKLen == 0 || encryptedDEKLen > maxLengthEncryptedDEK {
+		return nil, errors.New("kms_envelope_streaming_aead: invalid length of encrypted DEK")
+	}
+	encryptedDEK := make([]byte, encryptedDEKLen)
+	if _, err := io.ReadFull(r, encryptedDEK); err != nil {
+		return nil, fmt.Errorf("kms_envelope_streaming_aead: failed to read header: %v", err)
+	}
+	dek, err := a.kekAEAD.Decrypt(encryptedDEK, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	sa, err := a.dekPrimitive(dek)
+	if err != nil {
+		return nil, err
+	}
+	return sa.NewDecryptingReader(r, associatedData)
+}
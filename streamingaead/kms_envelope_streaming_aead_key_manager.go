@@ -0,0 +1,129 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamingaead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	kmsespb "github.com/tink-crypto/tink-go/v2/proto/kms_envelope_streaming_aead_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	kmsEnvelopeStreamingAEADKeyVersion = 0
+	kmsEnvelopeStreamingAEADTypeURL    = "type.googleapis.com/google.crypto.tink.KmsEnvelopeStreamingAeadKey"
+)
+
+var (
+	errInvalidKMSEnvelopeStreamingAEADKey       = errors.New("kms_envelope_streaming_aead_key_manager: invalid key")
+	errInvalidKMSEnvelopeStreamingAEADKeyFormat = errors.New("kms_envelope_streaming_aead_key_manager: invalid key format")
+)
+
+// kmsEnvelopeStreamingAEADKeyManager is an implementation of KeyManager
+// interface. It generates new KMS envelope streaming AEAD keys and produces new
+// instances of KMSEnvelopeStreamingAEAD.
+type kmsEnvelopeStreamingAEADKeyManager struct{}
+
+// Primitive creates a KMSEnvelopeStreamingAEAD for the given serialized
+// KmsEnvelopeStreamingAeadKey proto.
+func (km *kmsEnvelopeStreamingAEADKeyManager) Primitive(serializedKey []byte) (any, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidKMSEnvelopeStreamingAEADKey
+	}
+	key := new(kmsespb.KmsEnvelopeStreamingAeadKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidKMSEnvelopeStreamingAEADKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, fmt.Errorf("kms_envelope_streaming_aead_key_manager: %v", err)
+	}
+	uri := key.GetParams().GetKekUri()
+	kmsClient, err := registry.GetKMSClient(uri)
+	if err != nil {
+		return nil, err
+	}
+	backend, err := kmsClient.GetAEAD(uri)
+	if err != nil {
+		return nil, errors.New("kms_envelope_streaming_aead_key_manager: invalid aead backend")
+	}
+	return NewKMSEnvelopeStreamingAEAD(key.GetParams().GetStreamingDekTemplate(), backend)
+}
+
+// NewKey creates a new key according to the given serialized
+// KmsEnvelopeStreamingAeadKeyFormat.
+func (km *kmsEnvelopeStreamingAEADKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidKMSEnvelopeStreamingAEADKeyFormat
+	}
+	keyFormat := new(kmsespb.KmsEnvelopeStreamingAeadKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidKMSEnvelopeStreamingAEADKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("kms_envelope_streaming_aead_key_manager: %v", err)
+	}
+	return &kmsespb.KmsEnvelopeStreamingAeadKey{
+		Version: kmsEnvelopeStreamingAEADKeyVersion,
+		Params:  keyFormat,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to the given serialized
+// KmsEnvelopeStreamingAeadKeyFormat.
+// It should be used solely by the key management API.
+func (km *kmsEnvelopeStreamingAEADKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         kmsEnvelopeStreamingAEADTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_REMOTE,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *kmsEnvelopeStreamingAEADKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == kmsEnvelopeStreamingAEADTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *kmsEnvelopeStreamingAEADKeyManager) TypeURL() string {
+	return kmsEnvelopeStreamingAEADTypeURL
+}
+
+func (km *kmsEnvelopeStreamingAEADKeyManager) validateKey(key *kmsespb.KmsEnvelopeStreamingAeadKey) error {
+	if err := keyset.ValidateKeyVersion(key.GetVersion(), kmsEnvelopeStreamingAEADKeyVersion); err != nil {
+		return err
+	}
+	return km.validateKeyFormat(key.GetParams())
+}
+
+func (km *kmsEnvelopeStreamingAEADKeyManager) validateKeyFormat(keyFormat *kmsespb.KmsEnvelopeStreamingAeadKeyFormat) error {
+	dekKeyType := keyFormat.GetStreamingDekTemplate().GetTypeUrl()
+	if !isSupportedKMSEnvelopeStreamingDEK(dekKeyType) {
+		return fmt.Errorf("unsupported DEK key type %s. Only Tink streaming AEAD key types are supported with KMSEnvelopeStreamingAEAD", dekKeyType)
+	}
+	return nil
+}
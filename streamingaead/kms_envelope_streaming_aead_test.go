@@ -0,0 +1,235 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"github.com/tink-crypto/tink-go/v2/aead"
+	"github.com/tink-crypto/tink-go/v2/core/registry"
+	"github.com/tink-crypto/tink-go/v2/keyset"
+	"github.com/tink-crypto/tink-go/v2/streamingaead"
+	"github.com/tink-crypto/tink-go/v2/subtle/random"
+	"github.com/tink-crypto/tink-go/v2/testing/fakekms"
+	"github.com/tink-crypto/tink-go/v2/tink"
+	kmsespb "github.com/tink-crypto/tink-go/v2/proto/kms_envelope_streaming_aead_go_proto"
+	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
+)
+
+func encryptStream(t *testing.T, a tink.StreamingAEAD, plaintext, associatedData []byte) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w, err := a.NewEncryptingWriter(buf, associatedData)
+	if err != nil {
+		t.Fatalf("a.NewEncryptingWriter() err = %v, want nil", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		t.Fatalf("w.Write() err = %v, want nil", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() err = %v, want nil", err)
+	}
+	return buf.Bytes()
+}
+
+func decryptStream(a tink.StreamingAEAD, ciphertext, associatedData []byte) ([]byte, error) {
+	r, err := a.NewDecryptingReader(bytes.NewReader(ciphertext), associatedData)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestKMSEnvelopeStreamingAEADEncryptDecrypt(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	kek, err := fakekms.NewAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewAEAD() err = %v, want nil", err)
+	}
+	plaintext := random.GetRandomBytes(3*4096 + 17)
+	associatedData := []byte("associatedData")
+	for _, tc := range []struct {
+		name        string
+		dekTemplate *tinkpb.KeyTemplate
+	}{
+		{"AES128_GCM_HKDF_4KB", streamingaead.AES128GCMHKDF4KBKeyTemplate()},
+		{"AES256_GCM_HKDF_1MB", streamingaead.AES256GCMHKDF1MBKeyTemplate()},
+		{"AES128_CTR_HMAC_SHA256_4KB", streamingaead.AES128CTRHMACSHA256Segment4KBKeyTemplate()},
+		{"AES256_CTR_HMAC_SHA256_1MB", streamingaead.AES256CTRHMACSHA256Segment1MBKeyTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := streamingaead.NewKMSEnvelopeStreamingAEAD(tc.dekTemplate, kek)
+			if err != nil {
+				t.Fatalf("streamingaead.NewKMSEnvelopeStreamingAEAD() err = %v, want nil", err)
+			}
+			ciphertext := encryptStream(t, a, plaintext, associatedData)
+			decrypted, err := decryptStream(a, ciphertext, associatedData)
+			if err != nil {
+				t.Fatalf("decryptStream() err = %v, want nil", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Error("decryptStream() != plaintext")
+			}
+
+			if _, err := decryptStream(a, ciphertext, []byte("invalid")); err == nil {
+				t.Error("decryptStream() with invalid associated data err = nil, want error")
+			}
+			// Corrupt the encrypted DEK, which follows the 4-byte length.
+			corrupted := bytes.Clone(ciphertext)
+			corrupted[5] ^= 1
+			if _, err := decryptStream(a, corrupted, associatedData); err == nil {
+				t.Error("decryptStream() with corrupted encrypted DEK err = nil, want error")
+			}
+			for _, truncated := range [][]byte{nil, ciphertext[:3], ciphertext[:10]} {
+				if _, err := decryptStream(a, truncated, associatedData); err == nil {
+					t.Errorf("decryptStream() with %d-byte ciphertext err = nil, want error", len(truncated))
+				}
+			}
+		})
+	}
+}
+
+func TestKMSEnvelopeStreamingAEADDecryptHugeEncryptedDEKFails(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	kek, err := fakekms.NewAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewAEAD() err = %v, want nil", err)
+	}
+	a, err := streamingaead.NewKMSEnvelopeStreamingAEAD(streamingaead.AES128GCMHKDF4KBKeyTemplate(), kek)
+	if err != nil {
+		t.Fatalf("streamingaead.NewKMSEnvelopeStreamingAEAD() err = %v, want nil", err)
+	}
+	ciphertext := append([]byte{0x00, 0x00, 0x10, 0x01}, make([]byte, 4097)...)
+	if _, err := a.NewDecryptingReader(bytes.NewReader(ciphertext), nil); err == nil {
+		t.Error("a.NewDecryptingReader() err = nil, want error")
+	}
+}
+
+func TestNewKMSEnvelopeStreamingAEADWithUnsupportedDEKFails(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	kek, err := fakekms.NewAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewAEAD() err = %v, want nil", err)
+	}
+	if _, err := streamingaead.NewKMSEnvelopeStreamingAEAD(aead.AES128GCMKeyTemplate(), kek); err == nil {
+		t.Error("streamingaead.NewKMSEnvelopeStreamingAEAD() with AEAD DEK template err = nil, want error")
+	}
+	if _, err := streamingaead.CreateKMSEnvelopeStreamingAEADKeyTemplate(keyURI, aead.AES128GCMKeyTemplate()); err == nil {
+		t.Error("streamingaead.CreateKMSEnvelopeStreamingAEADKeyTemplate() with AEAD DEK template err = nil, want error")
+	}
+}
+
+func TestKMSEnvelopeStreamingAEADKeyset(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	client, err := fakekms.NewClient(keyURI)
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() err = %v, want nil", err)
+	}
+	registry.RegisterKMSClient(client)
+	defer registry.ClearKMSClients()
+
+	template, err := streamingaead.CreateKMSEnvelopeStreamingAEADKeyTemplate(keyURI, streamingaead.AES256GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("streamingaead.CreateKMSEnvelopeStreamingAEADKeyTemplate() err = %v, want nil", err)
+	}
+	manager := keyset.NewManager()
+	oldKeyID, err := manager.Add(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("manager.Add() err = %v, want nil", err)
+	}
+	if err := manager.SetPrimary(oldKeyID); err != nil {
+		t.Fatalf("manager.SetPrimary() err = %v, want nil", err)
+	}
+	oldHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v, want nil", err)
+	}
+	envelopeKeyID, err := manager.Add(template)
+	if err != nil {
+		t.Fatalf("manager.Add() err = %v, want nil", err)
+	}
+	if err := manager.SetPrimary(envelopeKeyID); err != nil {
+		t.Fatalf("manager.SetPrimary() err = %v, want nil", err)
+	}
+	handle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v, want nil", err)
+	}
+
+	a, err := streamingaead.New(handle)
+	if err != nil {
+		t.Fatalf("streamingaead.New() err = %v, want nil", err)
+	}
+	oldA, err := streamingaead.New(oldHandle)
+	if err != nil {
+		t.Fatalf("streamingaead.New() err = %v, want nil", err)
+	}
+	plaintext := random.GetRandomBytes(10000)
+	associatedData := []byte("associatedData")
+	for _, encrypter := range []tink.StreamingAEAD{a, oldA} {
+		ciphertext := encryptStream(t, encrypter, plaintext, associatedData)
+		decrypted, err := decryptStream(a, ciphertext, associatedData)
+		if err != nil {
+			t.Fatalf("decryptStream() err = %v, want nil", err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Error("decryptStream() != plaintext")
+		}
+	}
+}
+
+func TestKMSEnvelopeStreamingAEADKeyTemplateUsesStreamingKeyFormat(t *testing.T) {
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() err = %v, want nil", err)
+	}
+	dekTemplate := streamingaead.AES256GCMHKDF4KBKeyTemplate()
+	template, err := streamingaead.CreateKMSEnvelopeStreamingAEADKeyTemplate(keyURI, dekTemplate)
+	if err != nil {
+		t.Fatalf("streamingaead.CreateKMSEnvelopeStreamingAEADKeyTemplate() err = %v, want nil", err)
+	}
+	if got, want := template.GetTypeUrl(), "type.googleapis.com/google.crypto.tink.KmsEnvelopeStreamingAeadKey"; got != want {
+		t.Errorf("template.GetTypeUrl() = %q, want %q", got, want)
+	}
+	keyData, err := registry.NewKeyData(template)
+	if err != nil {
+		t.Fatalf("registry.NewKeyData() err = %v, want nil", err)
+	}
+	key := new(kmsespb.KmsEnvelopeStreamingAeadKey)
+	if err := proto.Unmarshal(keyData.GetValue(), key); err != nil {
+		t.Fatalf("proto.Unmarshal() err = %v, want nil", err)
+	}
+	if got := key.GetParams().GetKekUri(); got != keyURI {
+		t.Errorf("key.GetParams().GetKekUri() = %q, want %q", got, keyURI)
+	}
+	if got := key.GetParams().GetStreamingDekTemplate(); !proto.Equal(got, dekTemplate) {
+		t.Errorf("key.GetParams().GetStreamingDekTemplate() = %v, want %v", got, dekTemplate)
+	}
+}
@@ -36,4 +36,8 @@ func init() {
 	if err := registry.RegisterKeyManager(new(aesCTRHMACKeyManager)); err != nil {
 		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
 	}
+
+	if err := registry.RegisterKeyManager(new(kmsEnvelopeStreamingAEADKeyManager)); err != nil {
+		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
+	}
 }
@@ -23,6 +23,7 @@ import (
 	gcmhkdfpb "github.com/tink-crypto/tink-go/v2/proto/aes_gcm_hkdf_streaming_go_proto"
 	commonpb "github.com/tink-crypto/tink-go/v2/proto/common_go_proto"
 	hmacpb "github.com/tink-crypto/tink-go/v2/proto/hmac_go_proto"
+	kmsespb "github.com/tink-crypto/tink-go/v2/proto/kms_envelope_streaming_aead_go_proto"
 	tinkpb "github.com/tink-crypto/tink-go/v2/proto/tink_go_proto"
 )
 
@@ -113,6 +114,40 @@ func AES256CTRHMACSHA256Segment1MBKeyTemplate() *tinkpb.KeyTemplate {
 	return newAESCTRHMACKeyTemplate(32, commonpb.HashType_SHA256, 32, commonpb.HashType_SHA256, 32, 1048576)
 }
 
+// CreateKMSEnvelopeStreamingAEADKeyTemplate returns a key template that
+// generates a KMS envelope streaming AEAD key for a given key encryption key
+// (KEK) in a remote key management service (KMS).
+//
+// Each encrypted stream uses a fresh data encryption key (DEK), which is
+// wrapped by the remote KMS using the KEK and stored in the stream header.
+//
+// dekTemplate must be a KeyTemplate for any of these Tink streaming AEAD key
+// types (any other key template will be rejected):
+//   - AesGcmHkdfStreamingKey
+//   - AesCtrHmacStreamingKey
+//
+// Like for other KMS envelope keys, generating keys with this template does
+// not create new key material, but only a reference to the remote KEK. A KMS
+// client supporting uri must be registered with registry.RegisterKMSClient
+// before the key is used.
+func CreateKMSEnvelopeStreamingAEADKeyTemplate(uri string, dekTemplate *tinkpb.KeyTemplate) (*tinkpb.KeyTemplate, error) {
+	if !isSupportedKMSEnvelopeStreamingDEK(dekTemplate.GetTypeUrl()) {
+		return nil, fmt.Errorf("unsupported DEK key type %s. Only Tink streaming AEAD key types are supported", dekTemplate.GetTypeUrl())
+	}
+	serializedFormat, err := proto.Marshal(&kmsespb.KmsEnvelopeStreamingAeadKeyFormat{
+		KekUri:               uri,
+		StreamingDekTemplate: dekTemplate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key format: %s", err)
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          kmsEnvelopeStreamingAEADTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}, nil
+}
+
 // newAESGCMHKDFKeyTemplate creates a KeyTemplate containing a AesGcmHkdfStreamingKeyFormat with
 // specified parameters.
 func newAESGCMHKDFKeyTemplate(mainKeySize uint32, hkdfHashType commonpb.HashType, derivedKeySize, ciphertextSegmentSize uint32) *tinkpb.KeyTemplate {
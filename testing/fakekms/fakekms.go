@@ -39,7 +39,7 @@ import (
 
 const fakePrefix = "fake-kms://"
 
-var _ registry.KMSClient = (*fakeClient)(nil)
+var _ registry.KMSClientWithContext = (*fakeClient)(nil)
 
 type fakeClient struct {
 	uriPrefix string
@@ -95,6 +95,16 @@ func (c *fakeClient) GetAEAD(keyURI string) (tink.AEAD, error) {
 	return NewAEAD(keyURI)
 }
 
+// GetAEADWithContext returns an AEADWithContext by keyURI.
+//
+// The returned AEADWithContext will fail if the context is canceled.
+func (c *fakeClient) GetAEADWithContext(keyURI string) (tink.AEADWithContext, error) {
+	if !c.Supported(keyURI) {
+		return nil, fmt.Errorf("keyURI must start with prefix %s, but got %s", c.uriPrefix, keyURI)
+	}
+	return NewAEADWithContext(keyURI)
+}
+
 // NewAEAD returns a new [tink.AEAD] for the given keyURI.
 func NewAEAD(keyURI string) (tink.AEAD, error) {
 	encodeKeyset := strings.TrimPrefix(keyURI, fakePrefix)
@@ -0,0 +1,31 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tink
+
+// KeyWrap is the interface for deterministic key wrapping, such as AES-KWP
+// (NIST SP 800-38F, RFC 5649).
+//
+// Key wrapping encrypts and authenticates key material. Unlike [AEAD], it does
+// not take associated data and is deterministic, so it must only be used to
+// wrap secret, high-entropy data such as keys. Its output has no Tink prefix,
+// so that it is compatible with keys wrapped by other systems such as HSMs.
+type KeyWrap interface {
+	// Wrap encrypts and authenticates key.
+	Wrap(key []byte) ([]byte, error)
+
+	// Unwrap decrypts wrapped and verifies its integrity, returning the
+	// wrapped key.
+	Unwrap(wrapped []byte) ([]byte, error)
+}
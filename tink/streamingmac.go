@@ -0,0 +1,53 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tink
+
+import "io"
+
+/*
+StreamingMAC is an interface for computing and verifying MACs over data that
+is too large to be held in memory, such as large files.
+
+The tags are the same as the ones of MAC: a tag computed with a StreamingMAC
+can be verified with a MAC of the same keyset, and vice versa.
+*/
+type StreamingMAC interface {
+	// NewComputer returns a MACComputer that computes a MAC over all the data
+	// written to it.
+	NewComputer() (MACComputer, error)
+
+	// NewVerifier returns a MACVerifier that checks whether mac is a correct
+	// authentication code for all the data written to it.
+	NewVerifier(mac []byte) (MACVerifier, error)
+}
+
+// MACComputer computes a MAC incrementally.
+type MACComputer interface {
+	io.Writer
+
+	// Finish returns the MAC over the data written so far. No data can be
+	// written after Finish has been called.
+	Finish() ([]byte, error)
+}
+
+// MACVerifier verifies a MAC incrementally.
+type MACVerifier interface {
+	io.Writer
+
+	// Verify returns nil if the MAC is a correct authentication code for the
+	// data written so far, otherwise it returns an error. No data can be
+	// written after Verify has been called.
+	Verify() error
+}
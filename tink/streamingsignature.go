@@ -0,0 +1,60 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tink
+
+import "io"
+
+/*
+StreamingSigner is an interface for computing digital signatures over data that
+is too large to be held in memory, such as release artifacts.
+
+The signed data is hashed incrementally and the digest is signed. For
+hash-then-sign schemes such as ECDSA and RSA-SSA the resulting signatures are
+the same as the ones of Signer and can be verified with a Verifier of the same
+keyset. Ed25519 keys instead use the prehash variant Ed25519ph (RFC 8032), whose
+signatures can only be verified by a StreamingVerifier.
+*/
+type StreamingSigner interface {
+	// NewSigningWriter returns a SigningWriter that signs all the data written
+	// to it.
+	NewSigningWriter() (SigningWriter, error)
+}
+
+// SigningWriter computes a signature incrementally.
+type SigningWriter interface {
+	io.Writer
+
+	// Sign returns the signature over the data written so far. No data can be
+	// written after Sign has been called.
+	Sign() ([]byte, error)
+}
+
+// StreamingVerifier is an interface for verifying digital signatures over data
+// that is too large to be held in memory. See StreamingSigner for details.
+type StreamingVerifier interface {
+	// NewVerifyingWriter returns a VerifyingWriter that checks whether
+	// signature is a valid signature for all the data written to it.
+	NewVerifyingWriter(signature []byte) (VerifyingWriter, error)
+}
+
+// VerifyingWriter verifies a signature incrementally.
+type VerifyingWriter interface {
+	io.Writer
+
+	// Verify returns nil if the signature is valid for the data written so far,
+	// otherwise it returns an error. No data can be written after Verify has
+	// been called.
+	Verify() error
+}